@@ -0,0 +1,334 @@
+// Package a2s queries source engine game servers (and others speaking valve's server query protocol) over UDP for
+// their info, players and rules, for showing live server status next to the user logged in with steam.
+//
+// See https://developer.valvesoftware.com/wiki/Server_queries for the protocol.
+package a2s
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"time"
+)
+
+// DefaultTimeout is how long a query waits for the server, if ctx doesn't have a deadline.
+const DefaultTimeout = 3 * time.Second
+
+// ErrBadResponse is returned when a server's response can't be parsed.
+var ErrBadResponse = errors.New("bad response from server")
+
+// maxPacketSize is the biggest packet a server sends.
+const maxPacketSize = 1400
+
+const (
+	headerSingle = 0xFFFFFFFF
+	headerSplit  = 0xFFFFFFFE
+
+	requestInfo    = 'T'
+	requestPlayers = 'U'
+	requestRules   = 'V'
+
+	responseChallenge = 'A'
+	responseInfo      = 'I'
+	responsePlayers   = 'D'
+	responseRules     = 'E'
+)
+
+// Info is a server's A2S_INFO response.
+type Info struct {
+	Protocol   byte
+	Name       string
+	Map        string
+	Folder     string
+	Game       string
+	AppID      uint16
+	Players    int
+	MaxPlayers int
+	Bots       int
+	// ServerType is 'd' for dedicated, 'l' for listen servers and 'p' for SourceTV relays.
+	ServerType byte
+	// Environment is 'l' for linux, 'w' for windows and 'm' or 'o' for mac.
+	Environment byte
+	// Password is true if the server needs a password to join.
+	Password bool
+	VAC      bool
+	Version  string
+
+	// The rest are only set if the server sends them.
+	Port     uint16
+	SteamID  uint64
+	SpecPort uint16
+	SpecName string
+	Keywords string
+	GameID   uint64
+}
+
+// Player is a player on a server, from its A2S_PLAYER response. The protocol doesn't say who players are beyond
+// their name.
+type Player struct {
+	Index    byte
+	Name     string
+	Score    int32
+	Duration time.Duration
+}
+
+// QueryInfo asks the server at addr (ex. "1.2.3.4:27015") for its info.
+func QueryInfo(ctx context.Context, addr string) (*Info, error) {
+	payload := append([]byte("Source Engine Query"), 0)
+	r, err := query(ctx, addr, requestInfo, payload, responseInfo, true)
+	if err != nil {
+		return nil, fmt.Errorf("query info (%s): %w", addr, err)
+	}
+
+	info := &Info{
+		Protocol:    r.byte(),
+		Name:        r.string(),
+		Map:         r.string(),
+		Folder:      r.string(),
+		Game:        r.string(),
+		AppID:       r.uint16(),
+		Players:     int(r.byte()),
+		MaxPlayers:  int(r.byte()),
+		Bots:        int(r.byte()),
+		ServerType:  r.byte(),
+		Environment: r.byte(),
+		Password:    r.byte() == 1,
+		VAC:         r.byte() == 1,
+		Version:     r.string(),
+	}
+
+	if r.short {
+		return nil, fmt.Errorf("query info (%s): %w: too short", addr, ErrBadResponse)
+	}
+
+	// The extra data flag (and the extra data) is optional.
+	if edf := r.byte(); !r.short {
+		if edf&0x80 != 0 {
+			info.Port = r.uint16()
+		}
+		if edf&0x10 != 0 {
+			info.SteamID = r.uint64()
+		}
+		if edf&0x40 != 0 {
+			info.SpecPort = r.uint16()
+			info.SpecName = r.string()
+		}
+		if edf&0x20 != 0 {
+			info.Keywords = r.string()
+		}
+		if edf&0x01 != 0 {
+			info.GameID = r.uint64()
+		}
+	}
+
+	return info, nil
+}
+
+// QueryPlayers asks the server at addr (ex. "1.2.3.4:27015") who's playing on it.
+func QueryPlayers(ctx context.Context, addr string) ([]Player, error) {
+	r, err := query(ctx, addr, requestPlayers, nil, responsePlayers, false)
+	if err != nil {
+		return nil, fmt.Errorf("query players (%s): %w", addr, err)
+	}
+
+	n := int(r.byte())
+	players := make([]Player, 0, n)
+	for range n {
+		p := Player{
+			Index: r.byte(),
+			Name:  r.string(),
+			Score: int32(r.uint32()),
+		}
+		p.Duration = time.Duration(float64(math.Float32frombits(r.uint32())) * float64(time.Second))
+		if r.short {
+			return nil, fmt.Errorf("query players (%s): %w: too short", addr, ErrBadResponse)
+		}
+		players = append(players, p)
+	}
+
+	return players, nil
+}
+
+// QueryRules asks the server at addr (ex. "1.2.3.4:27015") for its rules (the cvars it makes public).
+func QueryRules(ctx context.Context, addr string) (map[string]string, error) {
+	r, err := query(ctx, addr, requestRules, nil, responseRules, false)
+	if err != nil {
+		return nil, fmt.Errorf("query rules (%s): %w", addr, err)
+	}
+
+	n := int(r.uint16())
+	rules := make(map[string]string, n)
+	for range n {
+		name, value := r.string(), r.string()
+		if r.short {
+			// Some servers cut the list off rather than splitting it into packets, keep what we got.
+			break
+		}
+		rules[name] = value
+	}
+
+	return rules, nil
+}
+
+// query sends a request to the server and returns a reader positioned after the response's type byte. Challenges are
+// answered by sending the request again with the challenge appended (or, for requests that need one, in place of
+// the -1 challenge).
+func query(ctx context.Context, addr string, request byte, payload []byte, response byte, challengeAppended bool) (*reader, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultTimeout)
+		defer cancel()
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	// The deadline doesn't notice ctx being cancelled early.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	challenge := []byte{0xFF, 0xFF, 0xFF, 0xFF}
+	if challengeAppended {
+		challenge = nil
+	}
+
+	// Servers can send a challenge for every request, but shouldn't do it more than once.
+	for range 3 {
+		req := binary.LittleEndian.AppendUint32(nil, headerSingle)
+		req = append(req, request)
+		req = append(req, payload...)
+		req = append(req, challenge...)
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+
+		body, err := readResponse(conn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, err
+		}
+
+		r := &reader{b: body}
+		switch typ := r.byte(); typ {
+		case response:
+			return r, nil
+		case responseChallenge:
+			challenge = r.next(4)
+			if r.short {
+				return nil, fmt.Errorf("%w: short challenge", ErrBadResponse)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unexpected response type %q", ErrBadResponse, typ)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: too many challenges", ErrBadResponse)
+}
+
+// readResponse reads a whole response from the server, putting split responses back together, and returns it
+// without its header.
+func readResponse(conn net.Conn) ([]byte, error) {
+	buf := make([]byte, maxPacketSize)
+
+	var parts [][]byte
+	var id uint32
+	for got := 0; ; {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		if n < 4 {
+			return nil, fmt.Errorf("%w: packet too short", ErrBadResponse)
+		}
+
+		r := &reader{b: buf[:n]}
+		switch header := r.uint32(); header {
+		case headerSingle:
+			return bytes.Clone(r.b), nil
+		case headerSplit:
+		default:
+			return nil, fmt.Errorf("%w: unexpected header %#x", ErrBadResponse, header)
+		}
+
+		// Source engine split packets: id, total, number, size, then the payload.
+		packetId, total, number := r.uint32(), int(r.byte()), int(r.byte())
+		r.uint16()
+		if r.short || total == 0 || number >= total {
+			return nil, fmt.Errorf("%w: bad split packet", ErrBadResponse)
+		}
+		if packetId&0x80000000 != 0 {
+			return nil, fmt.Errorf("%w: compressed responses aren't supported", ErrBadResponse)
+		}
+
+		if parts == nil {
+			parts, id = make([][]byte, total), packetId
+		}
+		if packetId != id || total != len(parts) {
+			continue
+		}
+		if parts[number] == nil {
+			parts[number] = bytes.Clone(r.b)
+			got++
+		}
+
+		if got == len(parts) {
+			whole := bytes.Join(parts, nil)
+			if len(whole) < 4 || binary.LittleEndian.Uint32(whole) != headerSingle {
+				return nil, fmt.Errorf("%w: bad split response", ErrBadResponse)
+			}
+
+			return whole[4:], nil
+		}
+	}
+}
+
+// reader reads little endian values from a response, setting short (and returning zeros) if it runs out.
+type reader struct {
+	b     []byte
+	short bool
+}
+
+func (r *reader) next(n int) []byte {
+	if len(r.b) < n {
+		r.short = true
+		r.b = nil
+		return make([]byte, n)
+	}
+
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *reader) byte() byte     { return r.next(1)[0] }
+func (r *reader) uint16() uint16 { return binary.LittleEndian.Uint16(r.next(2)) }
+func (r *reader) uint32() uint32 { return binary.LittleEndian.Uint32(r.next(4)) }
+func (r *reader) uint64() uint64 { return binary.LittleEndian.Uint64(r.next(8)) }
+
+// string reads a null terminated string.
+func (r *reader) string() string {
+	i := bytes.IndexByte(r.b, 0)
+	if i < 0 {
+		r.short = true
+		r.b = nil
+		return ""
+	}
+
+	s := string(r.b[:i])
+	r.b = r.b[i+1:]
+	return s
+}
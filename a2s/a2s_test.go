@@ -0,0 +1,199 @@
+package a2s
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"net"
+	"testing"
+	"time"
+)
+
+var challenge = []byte{1, 2, 3, 4}
+
+// packet builds a response payload.
+type packet struct {
+	bytes.Buffer
+}
+
+func (p *packet) str(s string) *packet {
+	p.WriteString(s)
+	p.WriteByte(0)
+	return p
+}
+
+func (p *packet) le(v any) *packet {
+	binary.Write(&p.Buffer, binary.LittleEndian, v)
+	return p
+}
+
+// fakeServer answers queries on a local udp port like a tf2 server would, sending the rules split into two packets.
+// Every request has to carry the challenge. It returns the server's address.
+func fakeServer(t *testing.T) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, maxPacketSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			req := buf[:n]
+			if n < 5 || binary.LittleEndian.Uint32(req) != headerSingle {
+				continue
+			}
+
+			for _, res := range answer(req[4], req[5:]) {
+				conn.WriteTo(res, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+// answer returns the packets a server sends for a request.
+func answer(request byte, body []byte) [][]byte {
+	single := func(p *packet) [][]byte {
+		return [][]byte{append(binary.LittleEndian.AppendUint32(nil, headerSingle), p.Bytes()...)}
+	}
+
+	if !bytes.HasSuffix(body, challenge) {
+		p := &packet{}
+		p.WriteByte(responseChallenge)
+		p.Write(challenge)
+		return single(p)
+	}
+
+	switch request {
+	case requestInfo:
+		p := &packet{}
+		p.WriteByte(responseInfo)
+		p.WriteByte(17)
+		p.str("A tf2 server").str("ctf_2fort").str("tf").str("Team Fortress")
+		p.le(uint16(440))
+		p.Write([]byte{5, 24, 1, 'd', 'l', 0, 1})
+		p.str("8835751")
+		p.WriteByte(0x80 | 0x10)
+		p.le(uint16(27015)).le(uint64(90071996842377216))
+		return single(p)
+
+	case requestPlayers:
+		p := &packet{}
+		p.WriteByte(responsePlayers)
+		p.WriteByte(2)
+		p.WriteByte(0)
+		p.str("Rabscuttle").le(int32(12)).le(math.Float32bits(90))
+		p.WriteByte(1)
+		p.str("Robin").le(int32(-1)).le(math.Float32bits(1.5))
+		return single(p)
+
+	case requestRules:
+		p := &packet{}
+		p.le(uint32(headerSingle))
+		p.WriteByte(responseRules)
+		p.le(uint16(2))
+		p.str("mp_timelimit").str("30").str("sv_gravity").str("800")
+
+		whole := p.Bytes()
+		half := len(whole) / 2
+		var packets [][]byte
+		for i, part := range [][]byte{whole[half:], whole[:half]} {
+			// sent out of order, to check they're put back together right
+			split := &packet{}
+			split.le(uint32(headerSplit)).le(uint32(7))
+			split.WriteByte(2)
+			split.WriteByte(byte(1 - i))
+			split.le(uint16(maxPacketSize))
+			split.Write(part)
+			packets = append(packets, split.Bytes())
+		}
+		return packets
+	}
+
+	return nil
+}
+
+func TestQueryInfo(t *testing.T) {
+	info, err := QueryInfo(context.Background(), fakeServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Info{
+		Protocol: 17, Name: "A tf2 server", Map: "ctf_2fort", Folder: "tf", Game: "Team Fortress", AppID: 440,
+		Players: 5, MaxPlayers: 24, Bots: 1, ServerType: 'd', Environment: 'l', VAC: true, Version: "8835751",
+		Port: 27015, SteamID: 90071996842377216,
+	}
+	if *info != want {
+		t.Errorf("info = %+v, want %+v", *info, want)
+	}
+}
+
+func TestQueryPlayers(t *testing.T) {
+	players, err := QueryPlayers(context.Background(), fakeServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Player{
+		{Index: 0, Name: "Rabscuttle", Score: 12, Duration: 90 * time.Second},
+		{Index: 1, Name: "Robin", Score: -1, Duration: 1500 * time.Millisecond},
+	}
+	if len(players) != len(want) {
+		t.Fatalf("players = %+v, want %+v", players, want)
+	}
+	for i := range want {
+		if players[i] != want[i] {
+			t.Errorf("players[%d] = %+v, want %+v", i, players[i], want[i])
+		}
+	}
+}
+
+func TestQueryRules(t *testing.T) {
+	rules, err := QueryRules(context.Background(), fakeServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rules) != 2 || rules["mp_timelimit"] != "30" || rules["sv_gravity"] != "800" {
+		t.Errorf("rules = %v, want both from the split response", rules)
+	}
+}
+
+func TestQueryTimeout(t *testing.T) {
+	// a server that never answers
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := QueryInfo(ctx, conn.LocalAddr().String()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestReader(t *testing.T) {
+	r := &reader{b: []byte{'h', 'i', 0, 1, 2}}
+	if s := r.string(); s != "hi" || r.short {
+		t.Fatalf("string = %q (short %v), want hi", s, r.short)
+	}
+	if v := r.uint16(); v != 0x0201 || r.short {
+		t.Fatalf("uint16 = %#x (short %v), want 0x201", v, r.short)
+	}
+	if v := r.uint32(); v != 0 || !r.short {
+		t.Errorf("uint32 past the end = %#x (short %v), want 0 and short", v, r.short)
+	}
+}
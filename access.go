@@ -0,0 +1,114 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrAccessDenied is matched (with errors.Is) by every error an AccessChecker returns to refuse a user.
+var ErrAccessDenied = errors.New("access denied")
+
+// AccessDeniedError is returned by AccessCheckers when a user proved who they are, but isn't allowed in.
+type AccessDeniedError struct {
+	SteamID string
+	// Reason is a short, user presentable explanation.
+	Reason string
+}
+
+func (e *AccessDeniedError) Error() string {
+	return fmt.Sprintf("access denied (%s): %s", e.SteamID, e.Reason)
+}
+
+// Is makes errors.Is(err, ErrAccessDenied) work.
+func (e *AccessDeniedError) Is(target error) bool {
+	return target == ErrAccessDenied
+}
+
+// AccessChecker decides if a user that has just logged in is allowed in. It returns nil to let them in, an error
+// matching ErrAccessDenied to refuse them, and any other error if it couldn't decide.
+type AccessChecker interface {
+	CheckAccess(ctx context.Context, steamid64 string) error
+}
+
+// AccessCheckerFunc lets a plain function be used as an AccessChecker.
+type AccessCheckerFunc func(ctx context.Context, steamid64 string) error
+
+// CheckAccess implements AccessChecker.
+func (f AccessCheckerFunc) CheckAccess(ctx context.Context, steamid64 string) error {
+	return f(ctx, steamid64)
+}
+
+// CheckAccess runs every checker in order, stopping at the first one that doesn't let the user in.
+func CheckAccess(ctx context.Context, steamid64 string, checkers ...AccessChecker) error {
+	for _, c := range checkers {
+		if err := c.CheckAccess(ctx, steamid64); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SteamIDList is a denylist (banned users) or allowlist (closed beta) of steamid64s, which can be changed while in use.
+type SteamIDList struct {
+	allow bool
+
+	mu  sync.RWMutex
+	ids map[string]struct{}
+}
+
+// NewDenylist returns a list that refuses the provided users and lets everyone else in.
+func NewDenylist(steamid64s ...string) *SteamIDList {
+	l := &SteamIDList{ids: make(map[string]struct{})}
+	l.Add(steamid64s...)
+	return l
+}
+
+// NewAllowlist returns a list that only lets the provided users in.
+func NewAllowlist(steamid64s ...string) *SteamIDList {
+	l := NewDenylist(steamid64s...)
+	l.allow = true
+	return l
+}
+
+// Add adds users to the list.
+func (l *SteamIDList) Add(steamid64s ...string) {
+	l.mu.Lock()
+	for _, id := range steamid64s {
+		l.ids[id] = struct{}{}
+	}
+	l.mu.Unlock()
+}
+
+// Remove removes users from the list.
+func (l *SteamIDList) Remove(steamid64s ...string) {
+	l.mu.Lock()
+	for _, id := range steamid64s {
+		delete(l.ids, id)
+	}
+	l.mu.Unlock()
+}
+
+// Contains reports whether the user is on the list.
+func (l *SteamIDList) Contains(steamid64 string) bool {
+	l.mu.RLock()
+	_, ok := l.ids[steamid64]
+	l.mu.RUnlock()
+	return ok
+}
+
+// CheckAccess implements AccessChecker.
+func (l *SteamIDList) CheckAccess(_ context.Context, steamid64 string) error {
+	on := l.Contains(steamid64)
+
+	if l.allow && !on {
+		return &AccessDeniedError{SteamID: steamid64, Reason: "you're not on the list of users allowed in"}
+	}
+	if !l.allow && on {
+		return &AccessDeniedError{SteamID: steamid64, Reason: "you've been banned"}
+	}
+
+	return nil
+}
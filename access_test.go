@@ -0,0 +1,116 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSteamIDList(t *testing.T) {
+	tests := []struct {
+		name       string
+		list       *SteamIDList
+		steamid    string
+		wantDenied bool
+	}{
+		{"denylist, listed", NewDenylist("76561197960287930"), "76561197960287930", true},
+		{"denylist, not listed", NewDenylist("76561197960287930"), "76561197960287931", false},
+		{"allowlist, listed", NewAllowlist("76561197960287930"), "76561197960287930", false},
+		{"allowlist, not listed", NewAllowlist("76561197960287930"), "76561197960287931", true},
+		{"empty allowlist", NewAllowlist(), "76561197960287930", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.list.CheckAccess(context.Background(), tt.steamid)
+			if errors.Is(err, ErrAccessDenied) != tt.wantDenied {
+				t.Fatalf("CheckAccess err = %v, want denied %v", err, tt.wantDenied)
+			}
+
+			var denied *AccessDeniedError
+			if tt.wantDenied && (!errors.As(err, &denied) || denied.SteamID != tt.steamid) {
+				t.Errorf("err = %#v, want an AccessDeniedError for %s", err, tt.steamid)
+			}
+		})
+	}
+}
+
+func TestSteamIDListChanges(t *testing.T) {
+	l := NewDenylist()
+
+	l.Add("76561197960287930")
+	if !l.Contains("76561197960287930") {
+		t.Fatal("added user isn't on the list")
+	}
+
+	l.Remove("76561197960287930")
+	if err := l.CheckAccess(context.Background(), "76561197960287930"); err != nil {
+		t.Errorf("removed user was refused: %v", err)
+	}
+}
+
+func TestCheckAccessStopsAtFirst(t *testing.T) {
+	var ran []string
+	checker := func(name string, err error) AccessChecker {
+		return AccessCheckerFunc(func(context.Context, string) error {
+			ran = append(ran, name)
+			return err
+		})
+	}
+
+	err := CheckAccess(context.Background(), "76561197960287930",
+		checker("a", nil),
+		checker("b", ErrAccessDenied),
+		checker("c", nil),
+	)
+	if !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("err = %v, want ErrAccessDenied", err)
+	}
+	if strings.Join(ran, ",") != "a,b" {
+		t.Errorf("ran %v, want a and b", ran)
+	}
+}
+
+func TestCallbackAccess(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	tests := []struct {
+		name     string
+		access   []AccessChecker
+		want     int
+		wantBody string
+	}{
+		{"no checkers", nil, http.StatusFound, ""},
+		{"allowed", []AccessChecker{NewAllowlist("76561197960287930")}, http.StatusFound, ""},
+		{"banned", []AccessChecker{NewDenylist("76561197960287930")}, http.StatusForbidden, "you've been banned"},
+		{"plain ErrAccessDenied", []AccessChecker{AccessCheckerFunc(func(context.Context, string) error {
+			return ErrAccessDenied
+		})}, http.StatusForbidden, "not allowed"},
+		{"checker failed", []AccessChecker{AccessCheckerFunc(func(context.Context, string) error {
+			return errors.New("database is down")
+		})}, http.StatusInternalServerError, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+			h.Access = tt.access
+
+			rec := httptest.NewRecorder()
+			h.Callback(rec, steamCallback("https://example.com/callback", "76561197960287930"))
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body, tt.want)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", rec.Body, tt.wantBody)
+			}
+			if tt.want != http.StatusFound && len(rec.Result().Cookies()) != 0 {
+				t.Error("refused user got a session")
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package gosteamauth
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// App is an app on steam, as listed by GetAppList and GetStoreAppList.
+type App struct {
+	AppID int    `json:"appid"`
+	Name  string `json:"name"`
+	// LastModified is when the app's store page last changed, as a unix timestamp. Only set by GetStoreAppList.
+	LastModified int64 `json:"last_modified"`
+	// PriceChangeNumber goes up every time the app's price changes. Only set by GetStoreAppList.
+	PriceChangeNumber int `json:"price_change_number"`
+}
+
+// GetAppList gets every app on steam, which is a very big response (hundreds of thousands of apps). GetStoreAppList
+// can be asked for less at a time.
+func (sa *SteamAuther) GetAppList(ctx context.Context) ([]App, error) {
+	var data struct {
+		AppList struct {
+			Apps []App `json:"apps"`
+		} `json:"applist"`
+	}
+	if err := sa.getApi(ctx, "ISteamApps/GetAppList/v2", nil, &data); err != nil {
+		return nil, fmt.Errorf("get app list: %w", err)
+	}
+
+	return data.AppList.Apps, nil
+}
+
+// StoreAppListFilter is what to ask GetStoreAppList for. If none of the Include fields are set, only games are
+// returned.
+type StoreAppListFilter struct {
+	IncludeGames    bool
+	IncludeDLC      bool
+	IncludeSoftware bool
+	IncludeVideos   bool
+	IncludeHardware bool
+	// ModifiedSince only gets apps whose store pages have changed since then.
+	ModifiedSince time.Time
+	// MaxResults is how many apps to get a page, up to 50000. 0 leaves it up to steam (10000).
+	MaxResults int
+}
+
+// StoreAppListPage is a page of apps from GetStoreAppList.
+type StoreAppListPage struct {
+	Apps []App
+	// LastAppID is what to pass to GetStoreAppList to get the next page, or 0 if this is the last one.
+	LastAppID int
+}
+
+// GetStoreAppList gets a page of the apps on the steam store, in appid order. Pass 0 as lastAppId to get the first
+// page, and the page's LastAppID to get the next one. AllStoreApps does this for you.
+func (sa *SteamAuther) GetStoreAppList(ctx context.Context, filter StoreAppListFilter, lastAppId int) (*StoreAppListPage, error) {
+	q := url.Values{}
+	q.Set("include_games", strconv.FormatBool(filter.IncludeGames))
+	q.Set("include_dlc", strconv.FormatBool(filter.IncludeDLC))
+	q.Set("include_software", strconv.FormatBool(filter.IncludeSoftware))
+	q.Set("include_videos", strconv.FormatBool(filter.IncludeVideos))
+	q.Set("include_hardware", strconv.FormatBool(filter.IncludeHardware))
+	if !filter.ModifiedSince.IsZero() {
+		q.Set("if_modified_since", strconv.FormatInt(filter.ModifiedSince.Unix(), 10))
+	}
+	if filter.MaxResults > 0 {
+		q.Set("max_results", strconv.Itoa(filter.MaxResults))
+	}
+	if lastAppId > 0 {
+		q.Set("last_appid", strconv.Itoa(lastAppId))
+	}
+
+	var data struct {
+		Response struct {
+			Apps            []App `json:"apps"`
+			HaveMoreResults bool  `json:"have_more_results"`
+			LastAppID       int   `json:"last_appid"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IStoreService/GetAppList/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get store app list (after %d): %w", lastAppId, err)
+	}
+
+	page := &StoreAppListPage{Apps: data.Response.Apps}
+	if data.Response.HaveMoreResults {
+		page.LastAppID = data.Response.LastAppID
+	}
+
+	return page, nil
+}
+
+// AllStoreApps is every app on the steam store matching filter as an iterator, fetching a page at a time as it's
+// consumed. Errors are yielded with an empty App, and end the iteration.
+func (sa *SteamAuther) AllStoreApps(ctx context.Context, filter StoreAppListFilter) iter.Seq2[App, error] {
+	return func(yield func(App, error) bool) {
+		for lastAppId := 0; ; {
+			page, err := sa.GetStoreAppList(ctx, filter, lastAppId)
+			if err != nil {
+				yield(App{}, err)
+				return
+			}
+
+			for _, app := range page.Apps {
+				if !yield(app, nil) {
+					return
+				}
+			}
+
+			if page.LastAppID == 0 {
+				return
+			}
+			lastAppId = page.LastAppID
+		}
+	}
+}
+
+// AppIndex is an in memory index of apps, for going between names and appids without asking steam every time. Build
+// it from GetAppList or AllStoreApps every so often, the list doesn't change that quickly. It's safe to use from
+// multiple goroutines.
+type AppIndex struct {
+	byId   map[int]App
+	byName map[string]App
+	// names are the apps with their normalized names, for searching.
+	names []indexedApp
+}
+
+type indexedApp struct {
+	name string
+	app  App
+}
+
+// NewAppIndex returns an index of apps.
+func NewAppIndex(apps []App) *AppIndex {
+	ix := &AppIndex{
+		byId:   make(map[int]App, len(apps)),
+		byName: make(map[string]App, len(apps)),
+		names:  make([]indexedApp, 0, len(apps)),
+	}
+
+	for _, app := range apps {
+		name := normalizeAppName(app.Name)
+		if name == "" {
+			continue
+		}
+
+		ix.byId[app.AppID] = app
+		// Lots of apps share names (ex. soundtracks, tests), the lowest appid is usually the one people mean.
+		if other, ok := ix.byName[name]; !ok || app.AppID < other.AppID {
+			ix.byName[name] = app
+		}
+		ix.names = append(ix.names, indexedApp{name: name, app: app})
+	}
+
+	return ix
+}
+
+// Len returns how many apps are in the index.
+func (ix *AppIndex) Len() int {
+	return len(ix.byId)
+}
+
+// App returns the app with the appid, and false if it isn't in the index.
+func (ix *AppIndex) App(appid int) (App, bool) {
+	app, ok := ix.byId[appid]
+	return app, ok
+}
+
+// Find returns the app called name, ignoring case, spacing and punctuation, and false if there isn't one.
+func (ix *AppIndex) Find(name string) (App, bool) {
+	app, ok := ix.byName[normalizeAppName(name)]
+	return app, ok
+}
+
+// Search returns up to limit apps whose names best match query, best first. Exact matches come first, then names
+// starting with query, then names containing it, then names containing its letters in order (so "cs go" finds
+// "Counter-Strike: Global Offensive"). Shorter names win ties.
+func (ix *AppIndex) Search(query string, limit int) []App {
+	query = normalizeAppName(query)
+	if query == "" || limit <= 0 {
+		return nil
+	}
+
+	type match struct {
+		score int
+		app   indexedApp
+	}
+	var matches []match
+	for _, a := range ix.names {
+		if score := appNameScore(a.name, query); score > 0 {
+			matches = append(matches, match{score, a})
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b match) int {
+		return cmp.Or(
+			cmp.Compare(b.score, a.score),
+			cmp.Compare(len(a.app.name), len(b.app.name)),
+			cmp.Compare(a.app.app.AppID, b.app.app.AppID),
+		)
+	})
+
+	apps := make([]App, 0, min(limit, len(matches)))
+	for _, m := range matches[:min(limit, len(matches))] {
+		apps = append(apps, m.app.app)
+	}
+
+	return apps
+}
+
+// appNameScore is how well the normalized name matches the normalized query, 0 for not at all.
+func appNameScore(name, query string) int {
+	switch {
+	case name == query:
+		return 4
+	case strings.HasPrefix(name, query):
+		return 3
+	case strings.Contains(name, query):
+		return 2
+	case isSubsequence(strings.ReplaceAll(query, " ", ""), name):
+		return 1
+	}
+
+	return 0
+}
+
+// isSubsequence reports whether every rune of sub appears in s, in order.
+func isSubsequence(sub, s string) bool {
+	for _, r := range s {
+		if sub == "" {
+			break
+		}
+		if strings.HasPrefix(sub, string(r)) {
+			sub = sub[len(string(r)):]
+		}
+	}
+
+	return sub == ""
+}
+
+// normalizeAppName lowercases name and turns everything but letters and numbers into single spaces, so "Half-Life 2"
+// and "half life 2" are the same.
+func normalizeAppName(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
+
+// UpToDate is whether a build of a game server is the latest, as represented in the response from the UpToDateCheck
+// web api.
+type UpToDate struct {
+	UpToDate bool `json:"up_to_date"`
+	// VersionIsListable is false if the version is too old to show up in the server browser.
+	VersionIsListable bool `json:"version_is_listable"`
+	// RequiredVersion is the latest version, only set if the build isn't up to date.
+	RequiredVersion int `json:"required_version"`
+	// Message is steam's explanation, like "Your server is out of date, please upgrade".
+	Message string `json:"message"`
+}
+
+// UpToDateCheck checks if version of a game (as in the PatchVersion in its steam.inf) is the latest, so server
+// operators can tell when their server needs updating.
+func (sa *SteamAuther) UpToDateCheck(ctx context.Context, appid, version int) (*UpToDate, error) {
+	var data struct {
+		Response struct {
+			UpToDate
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+		} `json:"response"`
+	}
+	q := url.Values{"appid": {strconv.Itoa(appid)}, "version": {strconv.Itoa(version)}}
+	if err := sa.getApi(ctx, "ISteamApps/UpToDateCheck/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("up to date check (%d, %d): %w", appid, version, err)
+	}
+
+	if !data.Response.Success {
+		return nil, fmt.Errorf("up to date check (%d, %d): steam said %q", appid, version, data.Response.Error)
+	}
+
+	return &data.Response.UpToDate, nil
+}
@@ -0,0 +1,252 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+var testApps = []App{
+	{AppID: 10, Name: "Counter-Strike"},
+	{AppID: 220, Name: "Half-Life 2"},
+	{AppID: 440, Name: "Team Fortress 2"},
+	{AppID: 730, Name: "Counter-Strike: Global Offensive"},
+	{AppID: 380, Name: "Half-Life 2: Episode One"},
+	{AppID: 323140, Name: "Half-Life 2"},
+	{AppID: 999, Name: "???"},
+}
+
+func TestGetAppList(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamApps/GetAppList/v2" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"applist":{"apps":[{"appid":10,"name":"Counter-Strike"},{"appid":440,"name":"Team Fortress 2"}]}}`))
+	})
+
+	apps, err := New("key", "https://example.com").GetAppList(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 2 || apps[1] != (App{AppID: 440, Name: "Team Fortress 2"}) {
+		t.Errorf("apps = %+v, want both", apps)
+	}
+}
+
+// storeApps answers IStoreService/GetAppList like steam would, with apps 1 to 5 in pages of max_results.
+func storeApps(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/IStoreService/GetAppList/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+
+		q := r.URL.Query()
+		last, _ := strconv.Atoi(q.Get("last_appid"))
+		max, _ := strconv.Atoi(q.Get("max_results"))
+		if q.Get("include_dlc") != "true" || max == 0 {
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+			return
+		}
+
+		var apps string
+		id := last + 1
+		for ; id <= 5 && id <= last+max; id++ {
+			if apps != "" {
+				apps += ","
+			}
+			apps += `{"appid":` + strconv.Itoa(id) + `,"name":"app ` + strconv.Itoa(id) + `","last_modified":1700000000}`
+		}
+		more := id <= 5
+		w.Write([]byte(`{"response":{"apps":[` + apps + `],"have_more_results":` + strconv.FormatBool(more) + `,"last_appid":` + strconv.Itoa(id-1) + `}}`))
+	}
+}
+
+func TestGetStoreAppList(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, storeApps(&calls))
+	sa := New("key", "https://example.com")
+	filter := StoreAppListFilter{IncludeGames: true, IncludeDLC: true, MaxResults: 2}
+
+	page, err := sa.GetStoreAppList(context.Background(), filter, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Apps) != 2 || page.LastAppID != 2 {
+		t.Errorf("first page = %+v, want apps 1 and 2", page)
+	}
+
+	page, err = sa.GetStoreAppList(context.Background(), filter, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Apps) != 1 || page.LastAppID != 0 {
+		t.Errorf("last page = %+v, want app 5 and no next page", page)
+	}
+}
+
+func TestAllStoreApps(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, storeApps(&calls))
+	sa := New("key", "https://example.com")
+	filter := StoreAppListFilter{IncludeDLC: true, MaxResults: 2}
+
+	var ids []int
+	for app, err := range sa.AllStoreApps(context.Background(), filter) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, app.AppID)
+	}
+	if len(ids) != 5 || calls.Load() != 3 {
+		t.Errorf("got apps %v from %d requests, want 1 to 5 from 3", ids, calls.Load())
+	}
+
+	// breaking out of the loop stops fetching
+	calls.Store(0)
+	for range sa.AllStoreApps(context.Background(), filter) {
+		break
+	}
+	if calls.Load() != 1 {
+		t.Errorf("made %d requests after breaking early, want 1", calls.Load())
+	}
+
+	var errs int
+	for _, err := range sa.AllStoreApps(context.Background(), StoreAppListFilter{}) {
+		if err == nil {
+			t.Fatal("err = nil for a bad request, want an error")
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("yielded %d errors, want 1", errs)
+	}
+}
+
+func TestAppIndex(t *testing.T) {
+	ix := NewAppIndex(testApps)
+
+	if ix.Len() != 6 {
+		t.Errorf("Len = %d, want 6, leaving out the app without a usable name", ix.Len())
+	}
+	if app, ok := ix.App(440); !ok || app.Name != "Team Fortress 2" {
+		t.Errorf("App(440) = %+v, %v, want tf2", app, ok)
+	}
+	if _, ok := ix.App(1); ok {
+		t.Error("App(1) ok, want false")
+	}
+
+	tests := []struct {
+		name string
+		want int
+		ok   bool
+	}{
+		{"team fortress 2", 440, true},
+		{"TEAM-FORTRESS  2", 440, true},
+		// the lowest appid wins for shared names
+		{"Half-Life 2", 220, true},
+		{"half", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, ok := ix.Find(tt.name)
+			if ok != tt.ok || app.AppID != tt.want {
+				t.Errorf("Find(%q) = %+v, %v, want %d, %v", tt.name, app, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAppIndexSearch(t *testing.T) {
+	ix := NewAppIndex(testApps)
+
+	tests := []struct {
+		query string
+		limit int
+		want  []int
+	}{
+		{"half-life 2", 10, []int{220, 323140, 380}},
+		{"counter", 10, []int{10, 730}},
+		{"cs go", 10, []int{730}},
+		{"fortress", 10, []int{440}},
+		{"half-life 2", 1, []int{220}},
+		{"nothing like it", 10, nil},
+		{"", 10, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []int
+			for _, app := range ix.Search(tt.query, tt.limit) {
+				got = append(got, app.AppID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAppListSteamDown(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oh no", http.StatusServiceUnavailable)
+	})
+
+	if _, err := New("key", "https://example.com").GetAppList(context.Background()); !errors.Is(err, ErrSteamUnavailable) {
+		t.Errorf("err = %v, want ErrSteamUnavailable", err)
+	}
+}
+
+func TestUpToDateCheck(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/ISteamApps/UpToDateCheck/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch {
+		case q.Get("appid") != "440":
+			w.Write([]byte(`{"response":{"success":false,"error":"Couldn't get app info for the app specified."}}`))
+		case q.Get("version") == "8000000":
+			w.Write([]byte(`{"response":{"success":true,"up_to_date":true,"version_is_listable":true}}`))
+		default:
+			w.Write([]byte(`{"response":{"success":true,"up_to_date":false,"version_is_listable":false,"required_version":8000000,"message":"Your server is out of date, please upgrade"}}`))
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name    string
+		appid   int
+		version int
+		want    UpToDate
+		wantErr bool
+	}{
+		{"latest", 440, 8000000, UpToDate{UpToDate: true, VersionIsListable: true}, false},
+		{"old", 440, 7000000, UpToDate{RequiredVersion: 8000000, Message: "Your server is out of date, please upgrade"}, false},
+		{"unknown app", 4000, 1, UpToDate{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sa.UpToDateCheck(context.Background(), tt.appid, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("UpToDateCheck = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package gosteamauth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"net/netip"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidAppTicket is returned by DecryptAppTicket when the ticket can't be decrypted or doesn't check out.
+var ErrInvalidAppTicket = errors.New("invalid encrypted app ticket")
+
+// AppTicket is what's inside an encrypted app ticket.
+type AppTicket struct {
+	// SteamID is the steamid64 of the user the ticket was made for.
+	SteamID string
+	AppID   uint32
+	// ExternalIP and InternalIP are the user's public and local ip addresses when the ticket was made.
+	ExternalIP netip.Addr
+	InternalIP netip.Addr
+	// OwnershipFlags are steam's flags about the user's license for the app.
+	OwnershipFlags uint32
+	Issued         time.Time
+	Expires        time.Time
+	// Licenses are the ids of the packages the user owns the app through.
+	Licenses []uint32
+	DLC      []AppTicketDLC
+	// UserData is the data the game passed to ISteamUser::RequestEncryptedAppTicket.
+	UserData []byte
+}
+
+// AppTicketDLC is a dlc of the app the user owns.
+type AppTicketDLC struct {
+	AppID    uint32
+	Licenses []uint32
+}
+
+// Expired reports whether the ticket has expired.
+func (t *AppTicket) Expired() bool {
+	return time.Now().After(t.Expires)
+}
+
+// DecryptAppTicket decrypts and parses an encrypted app ticket from ISteamUser::GetEncryptedAppTicket, so a game's
+// backend can tell who a client is without a web login. key is the app's encrypted app ticket key from the steamworks
+// partner site (it's shown as hex, decode it with hex.DecodeString). It doesn't check if the ticket has expired, see
+// AppTicket.Expired.
+func DecryptAppTicket(ticket, key []byte) (*AppTicket, error) {
+	outer, err := parseEncryptedAppTicket(ticket)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt app ticket: %w: %w", ErrInvalidAppTicket, err)
+	}
+
+	decrypted, err := symmetricDecrypt(outer.encrypted, key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt app ticket: %w: %w", ErrInvalidAppTicket, err)
+	}
+
+	if crc32.ChecksumIEEE(decrypted) != outer.crc {
+		return nil, fmt.Errorf("decrypt app ticket: %w: crc mismatch", ErrInvalidAppTicket)
+	}
+
+	t, err := parseDecryptedAppTicket(decrypted, int(outer.userDataLen))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt app ticket: %w: %w", ErrInvalidAppTicket, err)
+	}
+
+	return t, nil
+}
+
+// encryptedAppTicket is the EncryptedAppTicket protobuf message steam wraps tickets in.
+type encryptedAppTicket struct {
+	crc         uint32
+	userDataLen uint32
+	encrypted   []byte
+}
+
+// parseEncryptedAppTicket decodes the fields of the EncryptedAppTicket message we need, by hand so the package doesn't
+// need a protobuf library.
+func parseEncryptedAppTicket(b []byte) (*encryptedAppTicket, error) {
+	var t encryptedAppTicket
+	for len(b) > 0 {
+		key, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("bad protobuf field key")
+		}
+		b = b[n:]
+
+		field, wireType := key>>3, key&7
+		switch wireType {
+		case 0:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return nil, errors.New("bad protobuf varint")
+			}
+			b = b[n:]
+
+			switch field {
+			case 2:
+				t.crc = uint32(v)
+			case 3:
+				t.userDataLen = uint32(v)
+			}
+		case 2:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return nil, errors.New("bad protobuf length")
+			}
+			v := b[n : n+int(l)]
+			b = b[n+int(l):]
+
+			if field == 5 {
+				t.encrypted = v
+			}
+		default:
+			return nil, fmt.Errorf("unexpected protobuf wire type %d", wireType)
+		}
+	}
+
+	if len(t.encrypted) == 0 {
+		return nil, errors.New("no encrypted ticket")
+	}
+
+	return &t, nil
+}
+
+// symmetricDecrypt undoes steam's symmetric encryption: an AES-ECB encrypted IV, followed by AES-CBC encrypted data
+// with PKCS#7 padding.
+func symmetricDecrypt(b, key []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < 2*aes.BlockSize || len(b)%aes.BlockSize != 0 {
+		return nil, errors.New("encrypted ticket is the wrong size")
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	block.Decrypt(iv, b[:aes.BlockSize])
+
+	out := make([]byte, len(b)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, b[aes.BlockSize:])
+
+	pad := int(out[len(out)-1])
+	if pad == 0 || pad > aes.BlockSize || !bytes.Equal(out[len(out)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, errors.New("bad padding, the key is probably wrong")
+	}
+
+	return out[:len(out)-pad], nil
+}
+
+// parseDecryptedAppTicket parses a decrypted ticket: the user data, then the ownership ticket, then a salted sha1 of
+// the two, which has to be there and match.
+func parseDecryptedAppTicket(b []byte, userDataLen int) (*AppTicket, error) {
+	if userDataLen+4 > len(b) {
+		return nil, errors.New("ticket too short")
+	}
+
+	ownershipLen := int(binary.LittleEndian.Uint32(b[userDataLen:]))
+	end := userDataLen + ownershipLen
+	if ownershipLen < 4 || end > len(b) {
+		return nil, errors.New("bad ownership ticket length")
+	}
+
+	// Steam always hashes the ticket, so one without a hash has been cut short.
+	rest := b[end:]
+	if len(rest) < 8+sha1.Size {
+		return nil, errors.New("missing hash")
+	}
+	salt, sum := rest[:8], rest[8:8+sha1.Size]
+	h := sha1.New()
+	h.Write(b[:end])
+	h.Write(salt)
+	if !bytes.Equal(h.Sum(nil), sum) {
+		return nil, errors.New("hash mismatch")
+	}
+
+	t, err := parseOwnershipTicket(b[userDataLen:end])
+	if err != nil {
+		return nil, err
+	}
+	t.UserData = b[:userDataLen]
+
+	if !isIndividualSteamID64(t.SteamID) {
+		return nil, fmt.Errorf("steamid %s isn't an individual account", t.SteamID)
+	}
+
+	return t, nil
+}
+
+// parseOwnershipTicket parses the app ownership ticket inside an encrypted app ticket. Everything's little endian.
+func parseOwnershipTicket(b []byte) (*AppTicket, error) {
+	r := ticketReader{b: b}
+
+	r.u32() // length
+	r.u32() // version
+	steamid := r.u64()
+	t := &AppTicket{
+		SteamID:        strconv.FormatUint(steamid, 10),
+		AppID:          r.u32(),
+		ExternalIP:     r.ip(),
+		InternalIP:     r.ip(),
+		OwnershipFlags: r.u32(),
+		Issued:         time.Unix(int64(r.u32()), 0),
+		Expires:        time.Unix(int64(r.u32()), 0),
+	}
+
+	t.Licenses = r.licenses()
+	for range r.u16() {
+		dlc := AppTicketDLC{AppID: r.u32()}
+		dlc.Licenses = r.licenses()
+		t.DLC = append(t.DLC, dlc)
+	}
+
+	if r.short {
+		return nil, errors.New("ownership ticket too short")
+	}
+
+	return t, nil
+}
+
+// ticketReader reads little endian values from a ticket, setting short (and returning zeros) if it runs out.
+type ticketReader struct {
+	b     []byte
+	short bool
+}
+
+func (r *ticketReader) next(n int) []byte {
+	if len(r.b) < n {
+		r.short = true
+		r.b = nil
+		return make([]byte, n)
+	}
+
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *ticketReader) u16() uint16 { return binary.LittleEndian.Uint16(r.next(2)) }
+func (r *ticketReader) u32() uint32 { return binary.LittleEndian.Uint32(r.next(4)) }
+func (r *ticketReader) u64() uint64 { return binary.LittleEndian.Uint64(r.next(8)) }
+
+// ip reads an ipv4 address, which steam stores as a little endian uint32.
+func (r *ticketReader) ip() netip.Addr {
+	var a [4]byte
+	binary.BigEndian.PutUint32(a[:], r.u32())
+	return netip.AddrFrom4(a)
+}
+
+// licenses reads a count, then that many license ids.
+func (r *ticketReader) licenses() []uint32 {
+	n := r.u16()
+	licenses := make([]uint32, 0, n)
+	for range n {
+		if r.short {
+			break
+		}
+		licenses = append(licenses, r.u32())
+	}
+
+	return licenses
+}
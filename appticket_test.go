@@ -0,0 +1,186 @@
+package gosteamauth
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+// decryptedAppTicket builds a decrypted ticket for steamid64 with userData in front, hashed if hash is set.
+func decryptedAppTicket(steamid64 uint64, userData []byte, hash bool) []byte {
+	le := binary.LittleEndian
+
+	var own []byte
+	own = le.AppendUint32(own, 0) // length, filled in below
+	own = le.AppendUint32(own, 4) // version
+	own = le.AppendUint64(own, steamid64)
+	own = le.AppendUint32(own, 440)
+	own = le.AppendUint32(own, 0x0100007f) // external ip
+	own = le.AppendUint32(own, 0x0100007f) // internal ip
+	own = le.AppendUint32(own, 0)          // ownership flags
+	own = le.AppendUint32(own, 1700000000) // issued
+	own = le.AppendUint32(own, 1800000000) // expires
+	own = le.AppendUint16(own, 1)          // licenses
+	own = le.AppendUint32(own, 12345)
+	own = le.AppendUint16(own, 0) // dlc
+	le.PutUint32(own, uint32(len(own)))
+
+	b := append(append([]byte{}, userData...), own...)
+	if hash {
+		salt := []byte("saltsalt")
+		h := sha1.New()
+		h.Write(b)
+		h.Write(salt)
+		b = append(append(b, salt...), h.Sum(nil)...)
+	}
+
+	return b
+}
+
+func TestParseDecryptedAppTicket(t *testing.T) {
+	const individual = 76561197960287930
+	userData := []byte("hello")
+
+	tests := []struct {
+		name    string
+		ticket  []byte
+		wantErr bool
+	}{
+		{"valid", decryptedAppTicket(individual, userData, true), false},
+		{"no hash", decryptedAppTicket(individual, userData, false), true},
+		{"bad hash", func() []byte {
+			b := decryptedAppTicket(individual, userData, true)
+			b[len(b)-1] ^= 0xff
+			return b
+		}(), true},
+		{"clan steamid", decryptedAppTicket(103582791429521408, userData, true), true},
+		{"zero steamid", decryptedAppTicket(0, userData, true), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDecryptedAppTicket(tt.ticket, len(userData))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseDecryptedAppTicket() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDecryptedAppTicket() err = %v", err)
+			}
+			if got.SteamID != "76561197960287930" || got.AppID != 440 || string(got.UserData) != "hello" {
+				t.Errorf("parseDecryptedAppTicket() = %+v", got)
+			}
+		})
+	}
+}
+
+// encryptAppTicket does what steam does to a decrypted ticket: encrypts it with key and wraps it in an
+// EncryptedAppTicket message.
+func encryptAppTicket(t *testing.T, decrypted []byte, userDataLen int, key []byte) []byte {
+	t.Helper()
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pad := aes.BlockSize - len(decrypted)%aes.BlockSize
+	plain := append(append([]byte{}, decrypted...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+
+	iv := make([]byte, aes.BlockSize)
+	rand.Read(iv)
+	encrypted := make([]byte, aes.BlockSize+len(plain))
+	block.Encrypt(encrypted, iv)
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted[aes.BlockSize:], plain)
+
+	var msg []byte
+	msg = binary.AppendUvarint(msg, 1<<3|0) // ticket_version_no
+	msg = binary.AppendUvarint(msg, 1)
+	msg = binary.AppendUvarint(msg, 2<<3|0) // crc_encryptedticket
+	msg = binary.AppendUvarint(msg, uint64(crc32.ChecksumIEEE(decrypted)))
+	msg = binary.AppendUvarint(msg, 3<<3|0) // cb_encrypteduserdata
+	msg = binary.AppendUvarint(msg, uint64(userDataLen))
+	msg = binary.AppendUvarint(msg, 5<<3|2) // encrypted_ticket
+	msg = binary.AppendUvarint(msg, uint64(len(encrypted)))
+	return append(msg, encrypted...)
+}
+
+func TestDecryptAppTicket(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	userData := []byte("hello")
+
+	ticket, err := DecryptAppTicket(encryptAppTicket(t, decryptedAppTicket(76561197960287930, userData, true), len(userData), key), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ticket.SteamID != "76561197960287930" || ticket.AppID != 440 || string(ticket.UserData) != "hello" {
+		t.Errorf("ticket = %+v, want the user's ticket for tf2", ticket)
+	}
+	if ticket.ExternalIP != netip.MustParseAddr("1.0.0.127") || len(ticket.Licenses) != 1 || ticket.Licenses[0] != 12345 {
+		t.Errorf("ticket = %+v, want the ip and license read", ticket)
+	}
+	if !ticket.Issued.Equal(time.Unix(1700000000, 0)) || !ticket.Expires.Equal(time.Unix(1800000000, 0)) {
+		t.Errorf("issued %v and expires %v, want them from the ticket", ticket.Issued, ticket.Expires)
+	}
+	if ticket.Expired() {
+		t.Error("Expired = true before the ticket expires")
+	}
+}
+
+func TestDecryptAppTicketTampered(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	userData := []byte("hello")
+	decrypted := decryptedAppTicket(76561197960287930, userData, true)
+
+	// someone who has the key (or a way to get steam to encrypt for them) swapping the steamid for someone else's
+	// still has to get the hash right
+	swapped := append([]byte{}, decrypted...)
+	binary.LittleEndian.PutUint64(swapped[len(userData)+8:], 76561197960287931)
+
+	otherKey := make([]byte, 32)
+	rand.Read(otherKey)
+
+	tests := []struct {
+		name   string
+		ticket func() []byte
+		key    []byte
+	}{
+		{"flipped ciphertext", func() []byte {
+			b := encryptAppTicket(t, decrypted, len(userData), key)
+			b[len(b)-aes.BlockSize-1] ^= 0xff
+			return b
+		}, key},
+		{"swapped steamid", func() []byte {
+			return encryptAppTicket(t, swapped, len(userData), key)
+		}, key},
+		{"wrong key", func() []byte {
+			return encryptAppTicket(t, decrypted, len(userData), key)
+		}, otherKey},
+		{"cut short", func() []byte {
+			return encryptAppTicket(t, decrypted[:len(decrypted)-sha1.Size-8], len(userData), key)
+		}, key},
+		{"not a ticket", func() []byte { return []byte{0xff} }, key},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecryptAppTicket(tt.ticket(), tt.key)
+			if !errors.Is(err, ErrInvalidAppTicket) {
+				t.Errorf("DecryptAppTicket = %+v, %v, want ErrInvalidAppTicket", got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package gosteamauth
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrUnknownAttempt is returned by AttemptTracker.Complete when the attempt doesn't exist, has already been
+// completed, or has expired.
+var ErrUnknownAttempt = errors.New("unknown or expired login attempt")
+
+// DefaultAttemptLifetime is how long a login attempt stays pending before it's considered abandoned.
+const DefaultAttemptLifetime = 10 * time.Minute
+
+// AttemptStateParam is the query parameter added to the return url to tie the callback back to its attempt.
+const AttemptStateParam = "state"
+
+// AttemptCookieName is the cookie Handler.Login sets to tie an attempt to the browser that started it.
+const AttemptCookieName = "steam_login_attempt"
+
+// attemptBoundValue is set in the Values of attempts that need AttemptCookieName at the callback.
+const attemptBoundValue = "gosteamauth.bound"
+
+// Attempt is a login that has been started but not completed yet.
+type Attempt struct {
+	// ID is the random state value that identifies the attempt.
+	ID        string
+	StartedAt time.Time
+	ExpiresAt time.Time
+	// Values is any data the app wants to carry from the start of the flow to the callback.
+	Values map[string]string
+}
+
+// AttemptStats are counters describing what happened to login attempts since the tracker was created.
+type AttemptStats struct {
+	Started   uint64
+	Completed uint64
+	// Abandoned is the number of attempts that expired before the user came back.
+	Abandoned uint64
+	// Pending is the number of attempts currently being tracked.
+	Pending int
+}
+
+// AttemptTracker keeps track of pending login attempts and purges them once they're older than the max lifetime,
+// so users who never finish the flow don't pile up in memory forever.
+type AttemptTracker struct {
+	lifetime time.Duration
+
+	// OnAbandoned, if set, is called with every attempt that expires without being completed.
+	// It's called with the tracker's lock released, so it's fine to be slow here.
+	OnAbandoned func(a Attempt)
+
+	mu       sync.Mutex
+	attempts map[string]Attempt
+	stats    AttemptStats
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewAttemptTracker returns a new AttemptTracker where attempts live for lifetime (DefaultAttemptLifetime if <= 0).
+// Expired attempts are purged in the background until Close is called.
+func NewAttemptTracker(lifetime time.Duration) *AttemptTracker {
+	if lifetime <= 0 {
+		lifetime = DefaultAttemptLifetime
+	}
+
+	t := &AttemptTracker{
+		lifetime: lifetime,
+		attempts: make(map[string]Attempt),
+		stop:     make(chan struct{}),
+	}
+	go t.janitor()
+
+	return t
+}
+
+// janitor purges expired attempts every half lifetime.
+func (t *AttemptTracker) janitor() {
+	tick := time.NewTicker(t.lifetime / 2)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			t.Purge()
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// Close stops the background purging. Attempts can still be started and completed afterwards, they just
+// won't be cleaned up unless Purge is called manually.
+func (t *AttemptTracker) Close() {
+	t.stopOnce.Do(func() { close(t.stop) })
+}
+
+// Begin starts tracking a new attempt carrying values, which may be nil.
+func (t *AttemptTracker) Begin(values map[string]string) (*Attempt, error) {
+	id, err := randomToken(24)
+	if err != nil {
+		return nil, fmt.Errorf("begin attempt: generate id: %w", err)
+	}
+
+	now := time.Now()
+	a := Attempt{
+		ID:        id,
+		StartedAt: now,
+		ExpiresAt: now.Add(t.lifetime),
+		Values:    values,
+	}
+
+	t.mu.Lock()
+	t.attempts[id] = a
+	t.stats.Started++
+	t.mu.Unlock()
+
+	return &a, nil
+}
+
+// Complete stops tracking the attempt with the provided id and returns it.
+// Each attempt can only be completed once; ErrUnknownAttempt is returned if it's missing or expired.
+func (t *AttemptTracker) Complete(id string) (*Attempt, error) {
+	t.mu.Lock()
+	a, ok := t.attempts[id]
+	delete(t.attempts, id)
+
+	expired := ok && time.Now().After(a.ExpiresAt)
+	switch {
+	case expired:
+		t.stats.Abandoned++
+	case ok:
+		t.stats.Completed++
+	}
+	t.mu.Unlock()
+
+	if !ok {
+		return nil, ErrUnknownAttempt
+	}
+
+	if expired {
+		t.abandoned([]Attempt{a})
+		return nil, ErrUnknownAttempt
+	}
+
+	return &a, nil
+}
+
+// Purge removes every expired attempt and returns how many were removed.
+func (t *AttemptTracker) Purge() int {
+	now := time.Now()
+
+	var expired []Attempt
+	t.mu.Lock()
+	for id, a := range t.attempts {
+		if now.After(a.ExpiresAt) {
+			delete(t.attempts, id)
+			expired = append(expired, a)
+		}
+	}
+	t.stats.Abandoned += uint64(len(expired))
+	t.mu.Unlock()
+
+	t.abandoned(expired)
+	return len(expired)
+}
+
+func (t *AttemptTracker) abandoned(as []Attempt) {
+	if t.OnAbandoned == nil {
+		return
+	}
+
+	for _, a := range as {
+		t.OnAbandoned(a)
+	}
+}
+
+// Stats returns a snapshot of the tracker's counters.
+func (t *AttemptTracker) Stats() AttemptStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.stats
+	s.Pending = len(t.attempts)
+	return s
+}
+
+// AddState returns returnUrl with the attempt's id added as the AttemptStateParam query parameter.
+// Steam signs the return url, so the state can't be swapped out on the way back, as long as the callback reads it from
+// the signed openid.return_to (see ValidateCallbackFor) rather than the request's url, like Handler does.
+func (a *Attempt) AddState(returnUrl string) (string, error) {
+	u, err := url.Parse(returnUrl)
+	if err != nil {
+		return "", fmt.Errorf("add state (returnUrl=\"%s\"): %w", returnUrl, err)
+	}
+
+	q := u.Query()
+	q.Set(AttemptStateParam, a.ID)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// signedState returns the AttemptStateParam from the callback's openid.return_to, which steam signs, rather than
+// from the callback url's own query, which anyone can add to. It's empty if there isn't one.
+func signedState(q url.Values) string {
+	u, err := url.Parse(q.Get("openid.return_to"))
+	if err != nil {
+		return ""
+	}
+
+	return u.Query().Get(AttemptStateParam)
+}
@@ -0,0 +1,89 @@
+package gosteamauth
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAttemptTrackerComplete(t *testing.T) {
+	tr := NewAttemptTracker(time.Minute)
+	defer tr.Close()
+
+	a, err := tr.Begin(map[string]string{"next": "/dashboard"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tr.Complete(a.ID)
+	if err != nil {
+		t.Fatalf("Complete err = %v", err)
+	}
+	if got.Values["next"] != "/dashboard" {
+		t.Errorf("values = %v, want next=/dashboard", got.Values)
+	}
+
+	if _, err := tr.Complete(a.ID); !errors.Is(err, ErrUnknownAttempt) {
+		t.Errorf("second Complete err = %v, want ErrUnknownAttempt", err)
+	}
+
+	s := tr.Stats()
+	if s.Started != 1 || s.Completed != 1 || s.Abandoned != 0 || s.Pending != 0 {
+		t.Errorf("stats = %+v, want 1 started and completed", s)
+	}
+}
+
+func TestAttemptTrackerExpired(t *testing.T) {
+	tr := NewAttemptTracker(time.Minute)
+	defer tr.Close()
+
+	var abandoned []string
+	tr.OnAbandoned = func(a Attempt) { abandoned = append(abandoned, a.ID) }
+
+	a, _ := tr.Begin(nil)
+	b, _ := tr.Begin(nil)
+	tr.Begin(nil)
+
+	// move two of the attempts past their lifetime
+	tr.mu.Lock()
+	for _, id := range []string{a.ID, b.ID} {
+		e := tr.attempts[id]
+		e.ExpiresAt = time.Now().Add(-time.Second)
+		tr.attempts[id] = e
+	}
+	tr.mu.Unlock()
+
+	if _, err := tr.Complete(a.ID); !errors.Is(err, ErrUnknownAttempt) {
+		t.Errorf("Complete(expired) err = %v, want ErrUnknownAttempt", err)
+	}
+	if n := tr.Purge(); n != 1 {
+		t.Errorf("Purge = %d, want 1", n)
+	}
+
+	if len(abandoned) != 2 {
+		t.Errorf("abandoned = %v, want both expired attempts", abandoned)
+	}
+
+	s := tr.Stats()
+	if s.Started != 3 || s.Completed != 0 || s.Abandoned != 2 || s.Pending != 1 {
+		t.Errorf("stats = %+v, want 3 started, 2 abandoned and 1 pending", s)
+	}
+}
+
+func TestAttemptAddState(t *testing.T) {
+	a := &Attempt{ID: "abc"}
+
+	got, err := a.AddState("https://example.com/callback?next=%2Fhome")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get(AttemptStateParam) != "abc" || u.Query().Get("next") != "/home" {
+		t.Errorf("AddState = %q, want the state added and next kept", got)
+	}
+}
@@ -0,0 +1,168 @@
+package gosteamauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEventType is what happened in an AuditEvent.
+type AuditEventType string
+
+const (
+	// AuditLoginStarted is a user being sent off to steam.
+	AuditLoginStarted AuditEventType = "login_started"
+	// AuditLoginValidated is a user coming back from steam and being logged in.
+	AuditLoginValidated AuditEventType = "login_validated"
+	// AuditLoginRejected is a callback that didn't end with the user logged in. Reason says why.
+	AuditLoginRejected AuditEventType = "login_rejected"
+	// AuditLoggedOut is a user logging out.
+	AuditLoggedOut AuditEventType = "logged_out"
+)
+
+// AuditEvent is a machine readable record of something that happened to a user's login.
+type AuditEvent struct {
+	Type AuditEventType `json:"type"`
+	Time time.Time      `json:"time"`
+	// SteamID is the user's steamid64, if it's known.
+	SteamID   string `json:"steamid,omitempty"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	// AttemptID is the id of the login attempt, if the Handler tracks Attempts. It ties a login's events together.
+	AttemptID string `json:"attempt_id,omitempty"`
+	// Nonce is steam's openid.response_nonce, for callbacks.
+	Nonce string `json:"nonce,omitempty"`
+	// Reason is why the login was rejected, for AuditLoginRejected.
+	Reason string `json:"reason,omitempty"`
+}
+
+// AuditSink is somewhere audit events go.
+type AuditSink interface {
+	Audit(ctx context.Context, ev *AuditEvent) error
+}
+
+// WriterSink writes audit events to a writer as JSON, one per line.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterSink returns a new WriterSink writing to w.
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// StdoutSink returns a WriterSink writing to stdout, handy when your logs are collected from there.
+func StdoutSink() *WriterSink {
+	return NewWriterSink(os.Stdout)
+}
+
+// OpenFileSink returns a WriterSink appending to the file at path, creating it if needed. Close it when you're done.
+func OpenFileSink(path string) (*WriterSink, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open file sink (%s): %w", path, err)
+	}
+
+	return NewWriterSink(f), nil
+}
+
+// Audit implements AuditSink.
+func (s *WriterSink) Audit(_ context.Context, ev *AuditEvent) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("audit: marshal event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("audit: write event: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the underlying writer, if it can be closed.
+func (s *WriterSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+
+	return nil
+}
+
+// WebhookSink POSTs each audit event as JSON to a url.
+type WebhookSink struct {
+	url string
+
+	// Client is the http client the events are sent with. Defaults to one with a 5 second timeout.
+	Client *http.Client
+	// Header is sent with every request, ex. for an Authorization header.
+	Header http.Header
+}
+
+// NewWebhookSink returns a new WebhookSink posting to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		Client: &http.Client{Timeout: 5 * time.Second},
+		Header: make(http.Header),
+	}
+}
+
+// Audit implements AuditSink. Any non 2xx response is an error.
+func (s *WebhookSink) Audit(ctx context.Context, ev *AuditEvent) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("audit: marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("audit: make request: %w", err)
+	}
+	for k, v := range s.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("audit: post event: %w", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("audit: post event: status code is not 2xx (%s)", res.Status)
+	}
+
+	return nil
+}
+
+// audit fills in the request details of ev and sends it to the Handler's Audit sink, if it has one.
+func (h *Handler) audit(r *http.Request, ev AuditEvent) {
+	if h.Audit == nil {
+		return
+	}
+
+	ev.Time = time.Now()
+	ev.IP = h.ClientIP.ClientIP(r)
+	ev.UserAgent = r.UserAgent()
+	if ev.Type != AuditLoginStarted && ev.Type != AuditLoggedOut {
+		ev.AttemptID = signedState(r.URL.Query())
+		ev.Nonce = r.URL.Query().Get("openid.response_nonce")
+	}
+
+	// The user shouldn't be stopped from logging in (or out) because the audit log is down.
+	if err := h.Audit.Audit(r.Context(), &ev); err != nil && h.AuditErrors != nil {
+		h.AuditErrors(err)
+	}
+}
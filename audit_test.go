@@ -0,0 +1,161 @@
+package gosteamauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordSink keeps every event it's sent.
+type recordSink struct {
+	events []AuditEvent
+}
+
+func (s *recordSink) Audit(_ context.Context, ev *AuditEvent) error {
+	s.events = append(s.events, *ev)
+	return nil
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewWriterSink(&buf)
+
+	s.Audit(context.Background(), &AuditEvent{Type: AuditLoginStarted, AttemptID: "a"})
+	s.Audit(context.Background(), &AuditEvent{Type: AuditLoggedOut, SteamID: "76561197960287930"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrote %q, want one line per event", buf.String())
+	}
+
+	var ev AuditEvent
+	if err := json.Unmarshal([]byte(lines[1]), &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.Type != AuditLoggedOut || ev.SteamID != "76561197960287930" {
+		t.Errorf("second event = %+v", ev)
+	}
+}
+
+func TestOpenFileSinkAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	for range 2 {
+		s, err := OpenFileSink(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := s.Audit(context.Background(), &AuditEvent{Type: AuditLoginStarted}); err != nil {
+			t.Fatal(err)
+		}
+		s.Close()
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(b), "\n"); n != 2 {
+		t.Errorf("file has %d events, want 2", n)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"ok", http.StatusNoContent, false},
+		{"down", http.StatusBadGateway, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got AuditEvent
+			var auth string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("Authorization")
+				json.NewDecoder(r.Body).Decode(&got)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			s := NewWebhookSink(srv.URL)
+			s.Header.Set("Authorization", "Bearer hook")
+
+			err := s.Audit(context.Background(), &AuditEvent{Type: AuditLoginValidated, SteamID: "76561197960287930"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got.SteamID != "76561197960287930" || auth != "Bearer hook" {
+				t.Errorf("webhook got %+v with auth %q", got, auth)
+			}
+		})
+	}
+}
+
+func TestHandlerAudit(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	sink := &recordSink{}
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.Audit = sink
+	h.Attempts = NewAttemptTracker(time.Minute)
+	defer h.Attempts.Close()
+
+	returnTo, cookie := startLogin(t, h)
+	req := steamCallback(returnTo, "76561197960287930")
+	req.AddCookie(cookie)
+	h.Callback(httptest.NewRecorder(), req)
+
+	if len(sink.events) != 2 {
+		t.Fatalf("events = %+v, want started and validated", sink.events)
+	}
+	started, validated := sink.events[0], sink.events[1]
+	if started.Type != AuditLoginStarted || validated.Type != AuditLoginValidated {
+		t.Fatalf("events = %+v, want started and validated", sink.events)
+	}
+	if started.AttemptID == "" || validated.AttemptID != started.AttemptID {
+		t.Errorf("attempt ids = %q, %q, want the same one", started.AttemptID, validated.AttemptID)
+	}
+	if validated.SteamID != "76561197960287930" || validated.Nonce == "" {
+		t.Errorf("validated = %+v, want the steamid and nonce", validated)
+	}
+}
+
+func TestHandlerAuditSignedAttemptID(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	sink := &recordSink{}
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.Audit = sink
+	h.Attempts = NewAttemptTracker(time.Minute)
+	defer h.Attempts.Close()
+
+	returnTo, _ := startLogin(t, h)
+	signed := signedState(url.Values{"openid.return_to": {returnTo}})
+
+	// someone else's state in the callback url's own query doesn't get pinned on this attempt in the log
+	req := steamCallback(returnTo, "76561197960287930")
+	q := req.URL.Query()
+	q.Set(AttemptStateParam, "someone-elses-attempt")
+	req.URL.RawQuery = q.Encode()
+	h.Callback(httptest.NewRecorder(), req)
+
+	last := sink.events[len(sink.events)-1]
+	if last.Type != AuditLoginRejected {
+		t.Fatalf("last event = %+v, want a rejection", last)
+	}
+	if last.AttemptID != signed {
+		t.Errorf("AttemptID = %q, want the signed %q", last.AttemptID, signed)
+	}
+}
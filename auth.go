@@ -2,13 +2,16 @@ package gosteamauth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"time"
 )
 
 // ErrInvalidAuthRequest is returned by ValidateCallback when the auth attempt is invalid, as stated
@@ -27,14 +30,38 @@ type SteamAuther struct {
 	// realm is the openid2 realm.
 	// This should be the base URL of your web application, in most scenarios. For example,
 	realm string
+
+	// PublisherApiKey is a publisher web api key, used instead of the api key for publisher only methods (like
+	// CheckAppOwnership) if it's set. Handy when your normal key isn't a publisher key.
+	PublisherApiKey string
+
+	// Partner sends every web api call to PartnerApiBaseUrl rather than just the publisher only ones, for when the
+	// api key is a publisher key. The PublisherApiKey (if set) is used for every call in partner mode.
+	Partner bool
+
+	// XMLFallback makes GetSteamUser fall back to GetSteamUserXML when the web api errors (ex. during a steam
+	// outage), so names and avatars can still be shown. Without an api key, GetSteamUser always uses the xml.
+	XMLFallback bool
+
+	// BatchConcurrency is how many requests batch methods (like GetSteamUsers) make at once.
+	// Defaults to DefaultBatchConcurrency.
+	BatchConcurrency int
+
+	// MarketPriceInterval is how long GetMarketPrice waits between requests, since steam rate limits the market
+	// heavily. Defaults to DefaultMarketPriceInterval.
+	MarketPriceInterval time.Duration
+	marketThrottle      throttle
 }
 
 // New returns a new SteamAuther with the provided options.
 // apiKey is the steam web api key. realm is the openid 2 realm (typically the base url to your application (ex. http://localhost:8080))
+// or https://*.example.com to allow logins on any subdomain.
 func New(apiKey, realm string) *SteamAuther {
 	return &SteamAuther{
-		apiKey: apiKey,
-		realm:  realm,
+		apiKey:              apiKey,
+		realm:               realm,
+		BatchConcurrency:    DefaultBatchConcurrency,
+		MarketPriceInterval: DefaultMarketPriceInterval,
 	}
 }
 
@@ -45,6 +72,10 @@ const OpenIdLoginUrl = "https://steamcommunity.com/openid/login"
 // The user should be redirected here when you want to start the OAuth2 flow.
 // returnUrl is the url to return the user to once they've signed in. See ValidateCallback for what to do in that handler.
 func (sa *SteamAuther) GetAuthUrl(returnUrl string) (string, error) {
+	if !RealmMatches(sa.realm, returnUrl) {
+		return "", fmt.Errorf("get redirect url (returnUrl=\"%s\"): %w (realm=\"%s\")", returnUrl, ErrReturnUrlOutsideRealm, sa.realm)
+	}
+
 	u, err := url.Parse(OpenIdLoginUrl)
 	if err != nil {
 		return "", fmt.Errorf("get redirect url (returnUrl=\"%s\"): %w", returnUrl, err)
@@ -52,7 +83,7 @@ func (sa *SteamAuther) GetAuthUrl(returnUrl string) (string, error) {
 
 	// Setup openid query params
 	q := u.Query()
-	q.Set("openid.ns", "http://specs.openid.net/auth/2.0")                           // this is an openid 2.0 request
+	q.Set("openid.ns", OpenIdNamespace)                                              // this is an openid 2.0 request
 	q.Set("openid.mode", "checkid_setup")                                            // we're planning on verifying the authentication request ourself
 	q.Set("openid.realm", sa.realm)                                                  // we're doing the authentication
 	q.Set("openid.return_to", returnUrl)                                             // return to our webapp
@@ -65,9 +96,15 @@ func (sa *SteamAuther) GetAuthUrl(returnUrl string) (string, error) {
 
 // ValidateCallback is used to validate the callback at the end of an openid2 flow. This returns the steamid64 or an error.
 // This is used in the route handler that's at the returnUrl given at the start of the flow.
-// The vals correspond to the URL query parameters in the callback request.
+// The vals correspond to the URL query parameters in the callback request. Callbacks whose openid.return_to isn't
+// covered by the realm are rejected, so assertions steam signed for other sites can't be replayed here.
 func (sa *SteamAuther) ValidateCallback(vals url.Values) (string, error) {
-	// To validate the callback, we just take the raw params provided by the user and call back
+	return sa.validateCallback(context.Background(), vals)
+}
+
+// validateCallback is ValidateCallback, giving up on steam when ctx is done.
+func (sa *SteamAuther) validateCallback(ctx context.Context, vals url.Values) (string, error) {
+	// To validate the callback, we just take the openid params provided by the user and call back
 	// to steam to make sure everything is valid. This is required to make sure we're not getting epically pranked by
 	// someone trying to impersonate someone else.
 
@@ -75,13 +112,27 @@ func (sa *SteamAuther) ValidateCallback(vals url.Values) (string, error) {
 		return "", fmt.Errorf("the openid.mode was not expected. got=%x, expected=id_res", vals.Get("openid.mode"))
 	}
 
-	vals.Set("openid.mode", "check_authentication") // tell steam we're trying to validate an auth response
-	res, err := http.Post(OpenIdLoginUrl, "application/x-www-form-urlencoded", bytes.NewReader([]byte(vals.Encode())))
+	check, steamid, err := checkAuthenticationParams(vals, sa.realm)
+	if err != nil {
+		return "", fmt.Errorf("validate callback: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, OpenIdLoginUrl, bytes.NewReader([]byte(check.Encode())))
+	if err != nil {
+		return "", fmt.Errorf("validate callback: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("validate callback: failed making validation request: %w", err)
+		return "", fmt.Errorf("validate callback: failed making validation request: %w: %w", ErrSteamUnavailable, err)
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode >= 500 {
+		return "", fmt.Errorf("validate callback: %w (%s)", ErrSteamUnavailable, res.Status)
+	}
+
 	bodyBytes, err := io.ReadAll(res.Body)
 	if err != nil {
 		return "", fmt.Errorf("validate callback: read all bytes: %w", err)
@@ -91,16 +142,131 @@ func (sa *SteamAuther) ValidateCallback(vals url.Values) (string, error) {
 		return "", ErrInvalidAuthRequest
 	}
 
-	// The callback is ok, so we need to split out the steamid
-	p := strings.Split(vals.Get("openid.claimed_id"), "/")
-	return p[len(p)-1], nil
+	return steamid, nil
+}
+
+// ValidateCallbackFor is ValidateCallback for a callback that has to come back to callbackUrl: openid.return_to has
+// to have the same scheme, host and path, so an assertion steam made for another callback (or another site under the
+// same realm) isn't accepted here. It also returns the query of the return url, which steam signed. Read anything
+// put in the return url (like state) from it, not the request's own url, which anyone can change. The check with steam
+// is given up on when ctx is done.
+func (sa *SteamAuther) ValidateCallbackFor(ctx context.Context, vals url.Values, callbackUrl string) (string, url.Values, error) {
+	returnTo, err := returnToQuery(vals, callbackUrl)
+	if err != nil {
+		return "", nil, fmt.Errorf("validate callback: %w", err)
+	}
+
+	steamid, err := sa.validateCallback(ctx, vals)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return steamid, returnTo, nil
+}
+
+// returnToQuery checks a callback's openid.return_to points at callbackUrl, and returns its query. Failures wrap
+// ErrInvalidAuthRequest.
+func returnToQuery(vals url.Values, callbackUrl string) (url.Values, error) {
+	want, err := url.Parse(callbackUrl)
+	if err != nil {
+		return nil, fmt.Errorf("parse callback url: %w", err)
+	}
+
+	got, err := url.Parse(vals.Get("openid.return_to"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unparseable openid.return_to", ErrInvalidAuthRequest)
+	}
+
+	if !strings.EqualFold(got.Scheme, want.Scheme) || !strings.EqualFold(got.Host, want.Host) || got.Path != want.Path {
+		return nil, fmt.Errorf("%w: openid.return_to %q isn't the callback url", ErrInvalidAuthRequest, vals.Get("openid.return_to"))
+	}
+
+	return got.Query(), nil
+}
+
+// OpenIdNamespace is the openid.ns of every openid 2 message.
+const OpenIdNamespace = "http://specs.openid.net/auth/2.0"
+
+// claimedIdPrefix is what steam's claimed ids start with, followed by the steamid64.
+const claimedIdPrefix = "https://steamcommunity.com/openid/id/"
+
+// callbackParams are the parameters steam sends back in a positive assertion. Nothing else is sent on to steam when
+// validating a callback, so extra parameters can't have a say in the check.
+var callbackParams = []string{
+	"openid.ns",
+	"openid.mode",
+	"openid.op_endpoint",
+	"openid.claimed_id",
+	"openid.identity",
+	"openid.return_to",
+	"openid.response_nonce",
+	"openid.invalidate_handle",
+	"openid.assoc_handle",
+	"openid.signed",
+	"openid.sig",
+}
+
+// checkAuthenticationParams sanity checks a callback and builds the check_authentication request for it, returning the
+// steamid64 it claims. The signed openid.return_to has to be covered by realm, steam would happily vouch for an
+// assertion it made for any other site. Failures wrap ErrInvalidAuthRequest.
+func checkAuthenticationParams(vals url.Values, realm string) (url.Values, string, error) {
+	if vals.Get("openid.ns") != OpenIdNamespace {
+		return nil, "", fmt.Errorf("%w: unexpected openid.ns %q", ErrInvalidAuthRequest, vals.Get("openid.ns"))
+	}
+
+	// Steam checks the signature covers what's in openid.signed, so make sure everything we rely on is in it.
+	signed := strings.Split(vals.Get("openid.signed"), ",")
+	for _, field := range []string{"claimed_id", "identity", "return_to", "response_nonce"} {
+		if !slices.Contains(signed, field) {
+			return nil, "", fmt.Errorf("%w: openid.%s isn't signed", ErrInvalidAuthRequest, field)
+		}
+	}
+
+	if !RealmMatches(realm, vals.Get("openid.return_to")) {
+		return nil, "", fmt.Errorf("%w: openid.return_to %q: %w (realm=%q)", ErrInvalidAuthRequest, vals.Get("openid.return_to"), ErrReturnUrlOutsideRealm, realm)
+	}
+
+	steamid, ok := strings.CutPrefix(vals.Get("openid.claimed_id"), claimedIdPrefix)
+	if !ok || !isIndividualSteamID64(steamid) {
+		return nil, "", fmt.Errorf("%w: unexpected openid.claimed_id %q", ErrInvalidAuthRequest, vals.Get("openid.claimed_id"))
+	}
+
+	check := url.Values{}
+	for _, k := range callbackParams {
+		if v, ok := vals[k]; ok && len(v) > 0 {
+			check.Set(k, v[0])
+		}
+	}
+	check.Set("openid.mode", "check_authentication") // tell steam we're trying to validate an auth response
+
+	return check, steamid, nil
 }
 
 // GetSteamUser gets the steamid user with the steamid64 provided and returns some basic information about them.
 // This is useful to check after using ValidateCallback to get info about the user that's being authenticated.
 // It's a good idea to copy and store this somewhere else to prevent being dependent on steam for every request to
-// your website.
+// your website. Without an api key (or when the web api errors, if XMLFallback is set) the user comes from
+// GetSteamUserXML instead.
 func (sa *SteamAuther) GetSteamUser(steamid64 string) (*SteamUser, error) {
+	if sa.apiKey == "" {
+		return sa.GetSteamUserXML(context.Background(), steamid64)
+	}
+
+	user, err := sa.getSteamUser(steamid64)
+	if err != nil && sa.XMLFallback && !errors.Is(err, ErrNoData) {
+		xmlUser, xmlErr := sa.GetSteamUserXML(context.Background(), steamid64)
+		if xmlErr != nil {
+			return nil, fmt.Errorf("%w (xml fallback: %w)", err, xmlErr)
+		}
+
+		return xmlUser, nil
+	}
+
+	return user, err
+}
+
+// getSteamUser is GetSteamUser from the web api.
+func (sa *SteamAuther) getSteamUser(steamid64 string) (*SteamUser, error) {
 	// First, we need to build the URL that we'll be making the request to.
 	u, err := url.Parse("http://api.steampowered.com/ISteamUser/GetPlayerSummaries/v0002")
 	if err != nil {
@@ -117,9 +283,12 @@ func (sa *SteamAuther) GetSteamUser(steamid64 string) (*SteamUser, error) {
 	// Now we need to *do* the request :)
 	res, err := http.Get(reqUrl)
 	if err != nil {
-		return nil, fmt.Errorf("get steam user (%s): make get request: %w", steamid64, err)
+		return nil, fmt.Errorf("get steam user (%s): make get request: %w: %w", steamid64, ErrSteamUnavailable, err)
 	}
 
+	if res.StatusCode >= 500 {
+		return nil, fmt.Errorf("get steam user (%s): %w (%s)", steamid64, ErrSteamUnavailable, res.Status)
+	}
 	if res.StatusCode != 200 {
 		return nil, fmt.Errorf("get steam user (%s): status code is not 200 (%s)", steamid64, res.Status)
 	}
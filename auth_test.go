@@ -0,0 +1,180 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// assertion returns a positive assertion from steam for steamid64, coming back to returnTo. The signature is made up,
+// only steam could check it.
+func assertion(steamid64, returnTo string) url.Values {
+	return url.Values{
+		"openid.ns":             {OpenIdNamespace},
+		"openid.mode":           {"id_res"},
+		"openid.op_endpoint":    {OpenIdLoginUrl},
+		"openid.claimed_id":     {claimedIdPrefix + steamid64},
+		"openid.identity":       {claimedIdPrefix + steamid64},
+		"openid.return_to":      {returnTo},
+		"openid.response_nonce": {"2024-01-01T00:00:00Zabc"},
+		"openid.assoc_handle":   {"1234567890"},
+		"openid.signed":         {"signed,op_endpoint,claimed_id,identity,return_to,response_nonce,assoc_handle"},
+		"openid.sig":            {"c2lnbmF0dXJl"},
+	}
+}
+
+func TestCheckAuthenticationParams(t *testing.T) {
+	const realm = "https://example.com"
+
+	tests := []struct {
+		name        string
+		modify      func(v url.Values)
+		wantSteamID string
+		wantErr     error
+	}{
+		{"valid", func(url.Values) {}, "76561197960287930", nil},
+		{"wrong namespace", func(v url.Values) { v.Set("openid.ns", "http://openid.net/signon/1.1") }, "", ErrInvalidAuthRequest},
+		{"return_to not signed", func(v url.Values) {
+			v.Set("openid.signed", "signed,op_endpoint,claimed_id,identity,response_nonce,assoc_handle")
+		}, "", ErrInvalidAuthRequest},
+		{"claimed_id not signed", func(v url.Values) {
+			v.Set("openid.signed", "signed,op_endpoint,identity,return_to,response_nonce,assoc_handle")
+		}, "", ErrInvalidAuthRequest},
+		{"return_to on another site", func(v url.Values) { v.Set("openid.return_to", "https://evil.example.net/callback") }, "", ErrReturnUrlOutsideRealm},
+		{"return_to over http", func(v url.Values) { v.Set("openid.return_to", "http://example.com/callback") }, "", ErrReturnUrlOutsideRealm},
+		{"claimed_id from another provider", func(v url.Values) {
+			v.Set("openid.claimed_id", "https://evil.example.net/openid/id/76561197960287930")
+		}, "", ErrInvalidAuthRequest},
+		{"claimed_id not a steamid", func(v url.Values) { v.Set("openid.claimed_id", claimedIdPrefix+"../76561197960287930") }, "", ErrInvalidAuthRequest},
+		{"claimed_id a group", func(v url.Values) { v.Set("openid.claimed_id", claimedIdPrefix+"103582791429521412") }, "", ErrInvalidAuthRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vals := assertion("76561197960287930", "https://example.com/callback?state=abc")
+			tt.modify(vals)
+
+			check, steamid, err := checkAuthenticationParams(vals, realm)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if steamid != tt.wantSteamID {
+				t.Errorf("steamid = %q, want %q", steamid, tt.wantSteamID)
+			}
+			if check.Get("openid.mode") != "check_authentication" {
+				t.Errorf("openid.mode = %q, want check_authentication", check.Get("openid.mode"))
+			}
+		})
+	}
+}
+
+func TestCheckAuthenticationParamsDropsExtraParams(t *testing.T) {
+	vals := assertion("76561197960287930", "https://example.com/callback")
+	vals.Set("state", "abc")
+	vals.Set("openid.extra", "x")
+
+	check, _, err := checkAuthenticationParams(vals, "https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, k := range []string{"state", "openid.extra"} {
+		if check.Has(k) {
+			t.Errorf("check_authentication request has %q", k)
+		}
+	}
+}
+
+func TestReturnToQuery(t *testing.T) {
+	const callbackUrl = "https://example.com/auth/callback"
+
+	tests := []struct {
+		name      string
+		returnTo  string
+		wantState string
+		wantErr   bool
+	}{
+		{"callback", "https://example.com/auth/callback?state=abc", "abc", false},
+		{"host case", "https://EXAMPLE.com/auth/callback?state=abc", "abc", false},
+		{"other path", "https://example.com/auth/other?state=abc", "", true},
+		{"other host", "https://evil.example.net/auth/callback?state=abc", "", true},
+		{"other scheme", "http://example.com/auth/callback?state=abc", "", true},
+		{"missing", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := returnToQuery(url.Values{"openid.return_to": {tt.returnTo}}, callbackUrl)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAuthRequest) {
+					t.Fatalf("err = %v, want ErrInvalidAuthRequest", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+
+			if got := q.Get(AttemptStateParam); got != tt.wantState {
+				t.Errorf("state = %q, want %q", got, tt.wantState)
+			}
+		})
+	}
+}
+
+func TestValidateCallbackFor(t *testing.T) {
+	fakeSteam(t, validOpenId)
+	sa := New("", "https://example.com")
+
+	steamid, returnTo, err := sa.ValidateCallbackFor(context.Background(), assertion("76561197960287930", "https://example.com/auth/callback?state=abc"), "https://example.com/auth/callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steamid != "76561197960287930" || returnTo.Get("state") != "abc" {
+		t.Errorf("ValidateCallbackFor = %q, %v, want the steamid and the signed state", steamid, returnTo)
+	}
+
+	// another callback under the same realm
+	_, _, err = sa.ValidateCallbackFor(context.Background(), assertion("76561197960287930", "https://example.com/other?state=abc"), "https://example.com/auth/callback")
+	if !errors.Is(err, ErrInvalidAuthRequest) {
+		t.Errorf("other callback err = %v, want ErrInvalidAuthRequest", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = sa.ValidateCallbackFor(ctx, assertion("76561197960287930", "https://example.com/auth/callback"), "https://example.com/auth/callback")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled err = %v, want context.Canceled", err)
+	}
+}
+
+func TestCallbackReadsSignedReturnTo(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.Redirects = &RedirectPolicy{}
+
+	returnTo, _ := startLogin(t, h)
+
+	// a next slipped into the request's own url isn't what steam signed, so it's ignored
+	req := steamCallback(returnTo, "76561197960287930")
+	q := req.URL.Query()
+	q.Set(NextParam, "/admin")
+	req.URL.RawQuery = q.Encode()
+
+	rec := httptest.NewRecorder()
+	h.Callback(rec, req)
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/" {
+		t.Errorf("callback = %d to %q, want 302 to /", rec.Code, rec.Header().Get("Location"))
+	}
+}
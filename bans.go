@@ -0,0 +1,182 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// EconomyBanStatus is a user's trading ban status.
+type EconomyBanStatus string
+
+const (
+	EconomyBanNone      EconomyBanStatus = "none"
+	EconomyBanProbation EconomyBanStatus = "probation"
+	EconomyBanBanned    EconomyBanStatus = "banned"
+)
+
+// PlayerBans is a user's ban record, as represented in the response from the GetPlayerBans web api.
+type PlayerBans struct {
+	// SteamID is the "steamid64" of the player.
+	SteamID string `json:"SteamId"`
+	// CommunityBanned is true if the user is banned from the steam community (profiles, comments, groups...).
+	CommunityBanned bool `json:"CommunityBanned"`
+	// VACBanned is true if the user has any VAC bans on record.
+	VACBanned bool `json:"VACBanned"`
+	// NumberOfVACBans is how many VAC bans the user has.
+	NumberOfVACBans int `json:"NumberOfVACBans"`
+	// DaysSinceLastBan is how many days ago the user's last VAC or game ban was. It's 0 if they have none.
+	DaysSinceLastBan int `json:"DaysSinceLastBan"`
+	// NumberOfGameBans is how many game bans (bans by the game's developer) the user has.
+	NumberOfGameBans int `json:"NumberOfGameBans"`
+	// EconomyBan is the user's trading ban status.
+	// See the EconomyBan... enums
+	EconomyBan EconomyBanStatus `json:"EconomyBan"`
+}
+
+// HasBans reports whether the user has any bans on record, including a trade ban or probation.
+func (b *PlayerBans) HasBans() bool {
+	return b.VACBanned || b.NumberOfVACBans > 0 || b.NumberOfGameBans > 0 || b.CommunityBanned ||
+		(b.EconomyBan != "" && b.EconomyBan != EconomyBanNone)
+}
+
+// LastBanAt returns roughly when the user's last VAC or game ban was (steam only gives it in days), and false if
+// they have none.
+func (b *PlayerBans) LastBanAt() (time.Time, bool) {
+	if b.NumberOfVACBans == 0 && b.NumberOfGameBans == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Now().AddDate(0, 0, -b.DaysSinceLastBan), true
+}
+
+// GetPlayerBans gets the ban records of the users with the provided steamid64s, making one request per 100 users,
+// BatchConcurrency of them at a time. Users steam doesn't have a record for are left out. If some requests fail, the
+// records from the rest are still returned, along with a *BatchError saying which ids were left out.
+func (sa *SteamAuther) GetPlayerBans(ctx context.Context, steamid64s ...string) ([]PlayerBans, error) {
+	chunks := chunkIds(steamid64s)
+	results, errs := fetchChunks(ctx, chunks, sa.BatchConcurrency, sa.getPlayerBans)
+
+	var bans []PlayerBans
+	for _, r := range results {
+		bans = append(bans, r...)
+	}
+
+	if err := batchError(chunks, errs); err != nil {
+		return bans, fmt.Errorf("get player bans: %w", err)
+	}
+
+	return bans, nil
+}
+
+// AllPlayerBans is GetPlayerBans as an iterator, see SteamUsers.
+func (sa *SteamAuther) AllPlayerBans(ctx context.Context, steamid64s []string) iter.Seq2[PlayerBans, error] {
+	return chunkSeq(ctx, steamid64s, sa.getPlayerBans, "player bans")
+}
+
+// getPlayerBans gets the ban records of up to 100 users in a single request.
+func (sa *SteamAuther) getPlayerBans(ctx context.Context, steamid64s []string) ([]PlayerBans, error) {
+	var data struct {
+		Players []PlayerBans `json:"players"`
+	}
+	ids := strings.Join(steamid64s, ",")
+	if err := sa.getApi(ctx, "ISteamUser/GetPlayerBans/v1", url.Values{"steamids": {ids}}, &data); err != nil {
+		return nil, fmt.Errorf("get player bans (%s): %w", ids, err)
+	}
+
+	return data.Players, nil
+}
+
+// BanPolicy is which bans RequireNoBans refuses users for.
+type BanPolicy struct {
+	// VAC refuses users with VAC bans.
+	VAC bool
+	// Game refuses users with game bans.
+	Game bool
+	// Community refuses users banned from the steam community.
+	Community bool
+	// Economy refuses users with trade bans. Users on probation are let in.
+	Economy bool
+
+	// Within, if set, only counts VAC and game bans from this long ago or more recently, so old mistakes can be
+	// forgiven. Steam only tells us when the last one was, so a user with an old and a new ban is judged by the new one.
+	Within time.Duration
+
+	// Flag, if set, lets banned users in anyway, calling Flag with their record instead of refusing them.
+	// Use it to keep an eye on users rather than locking them out.
+	Flag func(ctx context.Context, bans *PlayerBans)
+}
+
+// Banned returns why the user breaks the policy, or "" if they don't.
+func (p BanPolicy) Banned(bans *PlayerBans) string {
+	recent := p.Within <= 0 || time.Duration(bans.DaysSinceLastBan)*24*time.Hour <= p.Within
+
+	switch {
+	case p.VAC && bans.NumberOfVACBans > 0 && recent:
+		return "your account has a VAC ban"
+	case p.Game && bans.NumberOfGameBans > 0 && recent:
+		return "your account has a game ban"
+	case p.Community && bans.CommunityBanned:
+		return "your account is banned from the steam community"
+	case p.Economy && bans.EconomyBan == EconomyBanBanned:
+		return "your account is trade banned"
+	}
+
+	return ""
+}
+
+// BanOffender is a user who breaks a BanPolicy, as found by ScreenBans.
+type BanOffender struct {
+	PlayerBans
+	// Reason is why they break the policy, see BanPolicy.Banned.
+	Reason string
+}
+
+// ScreenBans checks the users against the policy (ex. BanPolicy{VAC: true, Within: 365 * 24 * time.Hour} for VAC bans
+// in the last year), returning just the ones who break it, for auditing an existing member list. Ban records are
+// fetched like GetPlayerBans, so if some requests fail the offenders from the rest are still returned, along with a
+// *BatchError saying which ids weren't checked. policy.Flag isn't called.
+func (sa *SteamAuther) ScreenBans(ctx context.Context, steamid64s []string, policy BanPolicy) ([]BanOffender, error) {
+	bans, err := sa.GetPlayerBans(ctx, steamid64s...)
+
+	var offenders []BanOffender
+	for _, b := range bans {
+		if reason := policy.Banned(&b); reason != "" {
+			offenders = append(offenders, BanOffender{PlayerBans: b, Reason: reason})
+		}
+	}
+
+	if err != nil {
+		return offenders, fmt.Errorf("screen bans: %w", err)
+	}
+
+	return offenders, nil
+}
+
+// RequireNoBans returns an AccessChecker that refuses (or flags, see BanPolicy.Flag) users with the bans in policy.
+func (sa *SteamAuther) RequireNoBans(policy BanPolicy) AccessChecker {
+	return AccessCheckerFunc(func(ctx context.Context, steamid64 string) error {
+		players, err := sa.GetPlayerBans(ctx, steamid64)
+		if err != nil {
+			return fmt.Errorf("require no bans: %w", err)
+		}
+		if len(players) < 1 {
+			return fmt.Errorf("require no bans (%s): %w", steamid64, ErrNoData)
+		}
+
+		reason := policy.Banned(&players[0])
+		if reason == "" {
+			return nil
+		}
+
+		if policy.Flag != nil {
+			policy.Flag(ctx, &players[0])
+			return nil
+		}
+
+		return &AccessDeniedError{SteamID: steamid64, Reason: reason}
+	})
+}
@@ -0,0 +1,238 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBanPolicyBanned(t *testing.T) {
+	vac := &PlayerBans{VACBanned: true, NumberOfVACBans: 1, DaysSinceLastBan: 400}
+
+	tests := []struct {
+		name   string
+		policy BanPolicy
+		bans   *PlayerBans
+		want   bool
+	}{
+		{"clean", BanPolicy{VAC: true, Game: true, Community: true, Economy: true}, &PlayerBans{EconomyBan: EconomyBanNone}, false},
+		{"vac", BanPolicy{VAC: true}, vac, true},
+		{"vac not in policy", BanPolicy{Game: true}, vac, false},
+		{"vac too long ago", BanPolicy{VAC: true, Within: 365 * 24 * time.Hour}, vac, false},
+		{"vac recent enough", BanPolicy{VAC: true, Within: 500 * 24 * time.Hour}, vac, true},
+		{"game", BanPolicy{Game: true}, &PlayerBans{NumberOfGameBans: 2}, true},
+		{"community", BanPolicy{Community: true}, &PlayerBans{CommunityBanned: true}, true},
+		{"trade banned", BanPolicy{Economy: true}, &PlayerBans{EconomyBan: EconomyBanBanned}, true},
+		{"trade probation", BanPolicy{Economy: true}, &PlayerBans{EconomyBan: EconomyBanProbation}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Banned(tt.bans); (got != "") != tt.want {
+				t.Errorf("Banned = %q, want banned %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireNoBans(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUser/GetPlayerBans/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Query().Get("steamids") {
+		case "76561197960287930":
+			w.Write([]byte(`{"players":[{"SteamId":"76561197960287930","VACBanned":true,"NumberOfVACBans":1,"EconomyBan":"none"}]}`))
+		case "76561197960287931":
+			w.Write([]byte(`{"players":[{"SteamId":"76561197960287931","EconomyBan":"none"}]}`))
+		default:
+			w.Write([]byte(`{"players":[]}`))
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	var flagged []string
+	flag := func(_ context.Context, bans *PlayerBans) { flagged = append(flagged, bans.SteamID) }
+
+	tests := []struct {
+		name        string
+		policy      BanPolicy
+		steamid     string
+		wantErr     error
+		wantFlagged bool
+	}{
+		{"banned", BanPolicy{VAC: true}, "76561197960287930", ErrAccessDenied, false},
+		{"clean", BanPolicy{VAC: true}, "76561197960287931", nil, false},
+		{"flagged instead", BanPolicy{VAC: true, Flag: flag}, "76561197960287930", nil, true},
+		{"no record", BanPolicy{VAC: true}, "76561197960287932", ErrNoData, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flagged = nil
+
+			err := sa.RequireNoBans(tt.policy).CheckAccess(context.Background(), tt.steamid)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if (len(flagged) > 0) != tt.wantFlagged {
+				t.Errorf("flagged = %v, want flagged %v", flagged, tt.wantFlagged)
+			}
+		})
+	}
+}
+
+func TestPlayerBansHasBans(t *testing.T) {
+	tests := []struct {
+		name string
+		bans PlayerBans
+		want bool
+	}{
+		{"clean", PlayerBans{EconomyBan: EconomyBanNone}, false},
+		{"no economy status", PlayerBans{}, false},
+		{"vac", PlayerBans{VACBanned: true, NumberOfVACBans: 1}, true},
+		{"game", PlayerBans{NumberOfGameBans: 1}, true},
+		{"community", PlayerBans{CommunityBanned: true}, true},
+		{"trade probation", PlayerBans{EconomyBan: EconomyBanProbation}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.bans.HasBans(); got != tt.want {
+				t.Errorf("HasBans = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlayerBansLastBanAt(t *testing.T) {
+	if _, ok := (&PlayerBans{CommunityBanned: true}).LastBanAt(); ok {
+		t.Error("LastBanAt without vac or game bans = ok")
+	}
+
+	at, ok := (&PlayerBans{NumberOfGameBans: 1, DaysSinceLastBan: 10}).LastBanAt()
+	if want := time.Now().AddDate(0, 0, -10); !ok || at.Sub(want).Abs() > time.Minute {
+		t.Errorf("LastBanAt = %v, %v, want about %v", at, ok, want)
+	}
+}
+
+// playerBans answers GetPlayerBans with a clean record for every steamid asked for, except ones ending in 0, which
+// steam has no record of. It counts the requests made in calls.
+func playerBans(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUser/GetPlayerBans/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+
+		var players []string
+		for _, id := range strings.Split(r.URL.Query().Get("steamids"), ",") {
+			if !strings.HasSuffix(id, "0") {
+				players = append(players, `{"SteamId":"`+id+`","EconomyBan":"none"}`)
+			}
+		}
+		w.Write([]byte(`{"players":[` + strings.Join(players, ",") + `]}`))
+	}
+}
+
+func TestGetPlayerBans(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, playerBans(&calls))
+	sa := New("key", "https://example.com")
+
+	bans, err := sa.GetPlayerBans(context.Background(), steamids(250)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bans) != 225 || calls.Load() != 3 {
+		t.Errorf("got %d records from %d requests, want 225 from 3", len(bans), calls.Load())
+	}
+	for _, b := range bans {
+		if b.EconomyBan != EconomyBanNone {
+			t.Errorf("%s economy ban = %q, want none", b.SteamID, b.EconomyBan)
+		}
+	}
+
+	var got int
+	for _, err := range sa.AllPlayerBans(context.Background(), steamids(250)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got++
+	}
+	if got != 225 {
+		t.Errorf("AllPlayerBans yielded %d records, want 225", got)
+	}
+}
+
+func TestScreenBans(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		ids := strings.Split(r.URL.Query().Get("steamids"), ",")
+		// the chunk with the 101st id fails
+		if slices.Contains(ids, steamids(101)[100]) {
+			http.Error(w, "oh no", http.StatusServiceUnavailable)
+			return
+		}
+
+		// ids ending in 5 were VAC banned long ago, ids ending in 7 recently
+		var players []string
+		for _, id := range ids {
+			switch {
+			case strings.HasSuffix(id, "5"):
+				players = append(players, `{"SteamId":"`+id+`","VACBanned":true,"NumberOfVACBans":1,"DaysSinceLastBan":400,"EconomyBan":"none"}`)
+			case strings.HasSuffix(id, "7"):
+				players = append(players, `{"SteamId":"`+id+`","VACBanned":true,"NumberOfVACBans":1,"DaysSinceLastBan":10,"EconomyBan":"none"}`)
+			default:
+				players = append(players, `{"SteamId":"`+id+`","EconomyBan":"none"}`)
+			}
+		}
+		w.Write([]byte(`{"players":[` + strings.Join(players, ",") + `]}`))
+	})
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name   string
+		ids    []string
+		policy BanPolicy
+		want   int
+	}{
+		{"every vac ban", steamids(100), BanPolicy{VAC: true}, 20},
+		{"recent vac bans", steamids(100), BanPolicy{VAC: true, Within: 365 * 24 * time.Hour}, 10},
+		{"game bans", steamids(100), BanPolicy{Game: true}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offenders, err := sa.ScreenBans(context.Background(), tt.ids, tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(offenders) != tt.want {
+				t.Fatalf("got %d offenders, want %d", len(offenders), tt.want)
+			}
+			for _, o := range offenders {
+				if o.Reason == "" || !o.VACBanned {
+					t.Errorf("offender = %+v, want a VAC ban and a reason", o)
+				}
+			}
+		})
+	}
+
+	// offenders from the chunks that worked are still returned
+	offenders, err := sa.ScreenBans(context.Background(), steamids(250), BanPolicy{VAC: true})
+	var be *BatchError
+	if !errors.As(err, &be) || len(be.Chunks) != 1 {
+		t.Fatalf("err = %v, want a BatchError for one chunk", err)
+	}
+	if len(offenders) != 30 {
+		t.Errorf("got %d offenders, want the 30 from the chunks that worked", len(offenders))
+	}
+}
@@ -0,0 +1,127 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"sync"
+)
+
+// DefaultBatchConcurrency is how many chunks of a batch are fetched at once, unless SteamAuther.BatchConcurrency is
+// changed.
+const DefaultBatchConcurrency = 4
+
+// ChunkError is a chunk of a batch that couldn't be fetched.
+type ChunkError struct {
+	// Ids are the ids (usually steamid64s) in the chunk.
+	Ids []string
+	Err error
+}
+
+// BatchError is returned by batch methods (like GetSteamUsers) when some of their chunks failed. The results from the
+// chunks that didn't fail are still returned next to it.
+type BatchError struct {
+	Chunks []ChunkError
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Chunks))
+	for i, c := range e.Chunks {
+		msgs[i] = c.Err.Error()
+	}
+
+	return fmt.Sprintf("%d chunk(s) failed: %s", len(e.Chunks), strings.Join(msgs, "; "))
+}
+
+// Unwrap lets errors.Is and errors.As look at every chunk's error.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Chunks))
+	for i, c := range e.Chunks {
+		errs[i] = c.Err
+	}
+
+	return errs
+}
+
+// fetchChunks calls fetch for every chunk, up to workers at a time, and returns the results and errors in the same
+// order as the chunks. See batchError.
+func fetchChunks[T any](ctx context.Context, chunks [][]string, workers int, fetch func(ctx context.Context, chunk []string) (T, error)) ([]T, []error) {
+	results := make([]T, len(chunks))
+	errs := make([]error, len(chunks))
+
+	sem := make(chan struct{}, max(workers, 1))
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		// select picks at random when both are ready, so ctx is checked again for every chunk that got a worker,
+		// otherwise chunks could still be fetched after it's done.
+		if err := ctx.Err(); err != nil {
+			<-sem
+			errs[i] = err
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i], errs[i] = fetch(ctx, chunk)
+		}()
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+// batchError returns a *BatchError for the chunks that failed, or nil if none did.
+func batchError(chunks [][]string, errs []error) error {
+	var be BatchError
+	for i, err := range errs {
+		if err != nil {
+			be.Chunks = append(be.Chunks, ChunkError{Ids: chunks[i], Err: err})
+		}
+	}
+
+	if len(be.Chunks) == 0 {
+		return nil
+	}
+
+	return &be
+}
+
+// chunkSeq fetches ids a chunk at a time as the iterator is consumed, yielding every item. what describes the items,
+// for errors.
+func chunkSeq[T any](ctx context.Context, ids []string, fetch func(ctx context.Context, chunk []string) ([]T, error), what string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, chunk := range chunkIds(ids) {
+			if err := ctx.Err(); err != nil {
+				var zero T
+				yield(zero, fmt.Errorf("iterate %s: %w", what, err))
+				return
+			}
+
+			items, err := fetch(ctx, chunk)
+			if err != nil {
+				var zero T
+				if !yield(zero, fmt.Errorf("iterate %s: %w", what, err)) {
+					return
+				}
+				continue
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
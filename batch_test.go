@@ -0,0 +1,92 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchChunksConcurrency(t *testing.T) {
+	var running, most atomic.Int32
+	chunks := chunkIds(steamids(1000))
+
+	results, errs := fetchChunks(context.Background(), chunks, 3, func(ctx context.Context, chunk []string) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := most.Load()
+			if n <= m || most.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		return len(chunk), nil
+	})
+
+	if most.Load() > 3 {
+		t.Errorf("%d chunks fetched at once, want at most 3", most.Load())
+	}
+	for i := range chunks {
+		if errs[i] != nil || results[i] != len(chunks[i]) {
+			t.Errorf("chunk %d = %d, %v, want its own result", i, results[i], errs[i])
+		}
+	}
+}
+
+func TestFetchChunksCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks := chunkIds(steamids(1000))
+
+	var fetched atomic.Int32
+	_, errs := fetchChunks(ctx, chunks, 1, func(ctx context.Context, chunk []string) (int, error) {
+		// cancelled while the first chunk is being fetched, so none of the rest should be
+		if fetched.Add(1) == 1 {
+			cancel()
+		}
+		return 0, nil
+	})
+
+	if fetched.Load() != 1 {
+		t.Errorf("fetched %d chunks, want only the first", fetched.Load())
+	}
+	for i, err := range errs[1:] {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("chunk %d err = %v, want context.Canceled", i+1, err)
+		}
+	}
+}
+
+func TestGetSteamUsersPartialFailure(t *testing.T) {
+	var calls atomic.Int32
+	summaries := playerSummaries(&calls)
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		// the chunk with the 101st id fails
+		if strings.Contains(r.URL.Query().Get("steamids"), steamids(101)[100]) {
+			http.Error(w, "oh no", http.StatusServiceUnavailable)
+			return
+		}
+		summaries(w, r)
+	})
+
+	ids := steamids(250)
+	users, err := New("key", "https://example.com").GetSteamUsers(context.Background(), ids)
+
+	var be *BatchError
+	if !errors.As(err, &be) || len(be.Chunks) != 1 || len(be.Chunks[0].Ids) != 100 {
+		t.Fatalf("err = %v, want a BatchError for one chunk of 100", err)
+	}
+	if !errors.Is(err, ErrSteamUnavailable) {
+		t.Errorf("err = %v, want it to wrap ErrSteamUnavailable", err)
+	}
+	if len(users) != 150 {
+		t.Errorf("got %d users, want the 150 from the chunks that worked", len(users))
+	}
+	if _, ok := users[ids[100]]; ok {
+		t.Errorf("users has %s, from the chunk that failed", ids[100])
+	}
+}
@@ -0,0 +1,104 @@
+package gosteamauth
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// ButtonSize picks which of steam's official "Sign in through Steam" button images is used.
+type ButtonSize string
+
+const (
+	// ButtonSizeLarge is the wide, rectangular button.
+	ButtonSizeLarge ButtonSize = "large"
+	// ButtonSizeSmall is the smaller, boxier button.
+	ButtonSizeSmall ButtonSize = "small"
+)
+
+// buttonImages are steam's hosted images for each button size, from https://steamcommunity.com/dev.
+var buttonImages = map[ButtonSize]string{
+	ButtonSizeLarge: "https://community.cloudflare.steamstatic.com/public/images/signinthroughsteam/sits_01.png",
+	ButtonSizeSmall: "https://community.cloudflare.steamstatic.com/public/images/signinthroughsteam/sits_02.png",
+}
+
+var buttonTemplate = template.Must(template.New("button").Parse(
+	`<a class="steam-login-button" href="{{.Href}}"><img src="{{.Src}}" alt="Sign in through Steam"></a>`,
+))
+
+// ButtonHTML renders steam's sign in button, linking to href.
+func ButtonHTML(href string, size ButtonSize) (template.HTML, error) {
+	src, ok := buttonImages[size]
+	if !ok {
+		return "", fmt.Errorf("render button: unknown size %q", size)
+	}
+
+	var b strings.Builder
+	if err := buttonTemplate.Execute(&b, struct{ Href, Src string }{href, src}); err != nil {
+		return "", fmt.Errorf("render button: %w", err)
+	}
+
+	return template.HTML(b.String()), nil
+}
+
+// Button renders steam's sign in button linking straight to steam, with a fresh login attempt (and its state) for
+// every render. Every rendered button counts as a started attempt, so expect the abandoned count from
+// AttemptTracker.Stats to go up with page views. Button has no request to rate limit, so pages rendering it should be
+// behind RateLimit (or render ButtonHTML linking to Login instead) if Attempts is set.
+func (h *Handler) Button(size ButtonSize) (template.HTML, error) {
+	if _, ok := buttonImages[size]; !ok {
+		return "", fmt.Errorf("render button: unknown size %q", size)
+	}
+
+	u, err := h.LoginUrl()
+	if err != nil {
+		return "", err
+	}
+
+	return ButtonHTML(u, size)
+}
+
+// TemplateFuncs returns template functions for rendering the sign in button:
+//
+//	{{ steamLoginButton "large" }}
+func (h *Handler) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"steamLoginButton": func(size string) (template.HTML, error) {
+			return h.Button(ButtonSize(size))
+		},
+	}
+}
+
+// ButtonHandler serves the sign in button on its own as an html fragment, for frontends that fetch it in (htmx, an
+// iframe...). The size comes from the "size" query parameter, and defaults to large. Every render starts an attempt,
+// so it's rate limited like Login.
+func (h *Handler) ButtonHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.limited(w, r) {
+			return
+		}
+
+		size := ButtonSize(r.URL.Query().Get("size"))
+		if size == "" {
+			size = ButtonSizeLarge
+		}
+
+		// Button checks this too, but the caller should get a 400 for it rather than a 500.
+		if _, ok := buttonImages[size]; !ok {
+			http.Error(w, "unknown button size", http.StatusBadRequest)
+			return
+		}
+
+		b, err := h.Button(size)
+		if err != nil {
+			http.Error(w, "failed to render button", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		// Every render carries a single use state, so it can't be cached.
+		w.Header().Set("Cache-Control", "no-store")
+		w.Write([]byte(b))
+	})
+}
@@ -0,0 +1,96 @@
+package gosteamauth
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestButtonHTML(t *testing.T) {
+	tests := []struct {
+		size    ButtonSize
+		wantImg string
+		wantErr bool
+	}{
+		{ButtonSizeLarge, "sits_01.png", false},
+		{ButtonSizeSmall, "sits_02.png", false},
+		{"huge", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.size), func(t *testing.T) {
+			b, err := ButtonHTML(`https://example.com/login?a=1&b="2"`, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ButtonHTML err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !strings.Contains(string(b), tt.wantImg) {
+				t.Errorf("button = %s, want the %s image", b, tt.wantImg)
+			}
+			if strings.Contains(string(b), `"2"`) {
+				t.Errorf("button = %s, want the href escaped", b)
+			}
+		})
+	}
+}
+
+func TestButtonHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		want        int
+		wantStarted uint64
+	}{
+		{"default size", "", http.StatusOK, 1},
+		{"small", "?size=small", http.StatusOK, 1},
+		{"unknown size", "?size=huge", http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+			h.Attempts = NewAttemptTracker(time.Minute)
+			defer h.Attempts.Close()
+
+			rec := httptest.NewRecorder()
+			h.ButtonHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/button"+tt.query, nil))
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if got := h.Attempts.Stats().Started; got != tt.wantStarted {
+				t.Errorf("started attempts = %d, want %d", got, tt.wantStarted)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			if cc := rec.Header().Get("Cache-Control"); cc != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", cc)
+			}
+			if !strings.Contains(rec.Body.String(), "steamcommunity.com/openid/login") || !strings.Contains(rec.Body.String(), AttemptStateParam+"%3D") {
+				t.Errorf("body = %s, want a link to steam carrying the attempt's state", rec.Body)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncsUnknownSize(t *testing.T) {
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.Attempts = NewAttemptTracker(time.Minute)
+	defer h.Attempts.Close()
+
+	render := h.TemplateFuncs()["steamLoginButton"].(func(string) (template.HTML, error))
+	if _, err := render("huge"); err == nil {
+		t.Error("steamLoginButton \"huge\" succeeded")
+	}
+	if got := h.Attempts.Stats().Started; got != 0 {
+		t.Errorf("started attempts = %d, want 0", got)
+	}
+}
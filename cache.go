@@ -0,0 +1,142 @@
+package gosteamauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// cacheExportVersion is bumped whenever the ExportCache format changes in a way ImportCache can't read.
+const cacheExportVersion = 1
+
+// UserCache keeps the results of GetSteamUser in memory, so looking up the same users over and over
+// doesn't hit steam every time.
+type UserCache struct {
+	sa  *SteamAuther
+	ttl time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]cachedUser
+}
+
+type cachedUser struct {
+	User      SteamUser `json:"user"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// cacheExport is the document written by ExportCache.
+type cacheExport struct {
+	Version int          `json:"version"`
+	Users   []cachedUser `json:"users"`
+}
+
+// NewUserCache returns a new UserCache fetching users with sa. Cached users are re-fetched once they're older than ttl.
+func NewUserCache(sa *SteamAuther, ttl time.Duration) *UserCache {
+	return &UserCache{
+		sa:      sa,
+		ttl:     ttl,
+		entries: make(map[string]cachedUser),
+	}
+}
+
+// GetSteamUser returns the cached user with the provided steamid64, fetching it from steam if it isn't cached or is too old.
+func (c *UserCache) GetSteamUser(steamid64 string) (*SteamUser, error) {
+	c.mu.RLock()
+	e, ok := c.entries[steamid64]
+	c.mu.RUnlock()
+
+	if ok && time.Since(e.FetchedAt) < c.ttl {
+		u := e.User
+		return &u, nil
+	}
+
+	u, err := c.sa.GetSteamUser(steamid64)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[steamid64] = cachedUser{User: *u, FetchedAt: time.Now()}
+	c.mu.Unlock()
+
+	return u, nil
+}
+
+// Invalidate removes the user with the provided steamid64 from the cache.
+func (c *UserCache) Invalidate(steamid64 string) {
+	c.mu.Lock()
+	delete(c.entries, steamid64)
+	c.mu.Unlock()
+}
+
+// ExportCache writes every cached user to w as JSON, so it can be loaded somewhere else with ImportCache.
+// This is mostly useful for seeding staging/load testing environments with realistic data without hitting steam.
+// Only the users cached here are exported, logins and sessions are never part of it. Users are anonymized on the way
+// out, see anonymizeUser.
+func (c *UserCache) ExportCache(w io.Writer) error {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("export cache: generate key: %w", err)
+	}
+
+	c.mu.RLock()
+	doc := cacheExport{Version: cacheExportVersion, Users: make([]cachedUser, 0, len(c.entries))}
+	for _, e := range c.entries {
+		doc.Users = append(doc.Users, cachedUser{User: anonymizeUser(e.User, key), FetchedAt: e.FetchedAt})
+	}
+	c.mu.RUnlock()
+
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		return fmt.Errorf("export cache: encode: %w", err)
+	}
+
+	return nil
+}
+
+// ImportCache loads users written by ExportCache into the cache, replacing any that are already cached.
+// Imported users are treated as freshly fetched, so they stay cached for the full ttl.
+func (c *UserCache) ImportCache(r io.Reader) error {
+	var doc cacheExport
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return fmt.Errorf("import cache: decode: %w", err)
+	}
+
+	if doc.Version != cacheExportVersion {
+		return fmt.Errorf("import cache: unsupported version %d (expected %d)", doc.Version, cacheExportVersion)
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	for _, e := range doc.Users {
+		c.entries[e.User.SteamID] = cachedUser{User: e.User, FetchedAt: now}
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// anonymizeUser keeps the shape of u but not who it is. The steamid is swapped for another individual one made from
+// an hmac of it with key, so it's the same for every user in an export, but can't be worked back or matched across
+// exports. Names and avatars are replaced, and the location is cut down to the country.
+func anonymizeUser(u SteamUser, key []byte) SteamUser {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(u.SteamID))
+	// Setting the low bit keeps it off 0, which isn't a valid account id.
+	accountId := binary.BigEndian.Uint32(mac.Sum(nil)) | 1
+
+	id := NewIndividualSteamID(accountId).String()
+	u.SteamID = id
+	u.PersonaName = fmt.Sprintf("user%d", accountId)
+	u.ProfileUrl = fmt.Sprintf("https://steamcommunity.com/profiles/%s/", id)
+	u.Avatar, u.AvatarMedium, u.AvatarFull = "", "", ""
+	u.LocStateCode, u.LocCityID = "", 0
+
+	return u
+}
@@ -0,0 +1,57 @@
+package gosteamauth
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportCacheAnonymizes(t *testing.T) {
+	c := NewUserCache(New("", "https://example.com"), time.Hour)
+	seed, err := json.Marshal(cacheExport{Version: cacheExportVersion, Users: []cachedUser{{User: SteamUser{
+		SteamID:        "76561197960287930",
+		PersonaName:    "Rabscuttle",
+		ProfileUrl:     "https://steamcommunity.com/id/rabscuttle/",
+		AvatarFull:     "https://avatars.steamstatic.com/abc_full.jpg",
+		ProfileState:   ProfileStateConfigured,
+		LocCountryCode: "US",
+		LocStateCode:   "WA",
+		LocCityID:      3961,
+	}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ImportCache(bytes.NewReader(seed)); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := c.ExportCache(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"76561197960287930", "Rabscuttle", "rabscuttle", "abc_full", `"WA"`} {
+		if strings.Contains(out.String(), leak) {
+			t.Errorf("export contains %q: %s", leak, out.String())
+		}
+	}
+
+	var doc cacheExport
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Users) != 1 {
+		t.Fatalf("exported %d users, want 1", len(doc.Users))
+	}
+	u := doc.Users[0].User
+	if !isIndividualSteamID64(u.SteamID) {
+		t.Errorf("steamid = %q, want an individual steamid64", u.SteamID)
+	}
+	if u.ProfileState != ProfileStateConfigured || u.AvatarFull != "" {
+		t.Errorf("user = %+v, want the profile state kept and the avatar dropped", u)
+	}
+	if u.LocCountryCode != "US" || u.LocStateCode != "" || u.LocCityID != 0 {
+		t.Errorf("location = %q/%q/%d, want only the country kept", u.LocCountryCode, u.LocStateCode, u.LocCityID)
+	}
+}
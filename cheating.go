@@ -0,0 +1,193 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CheatingReport is a report that a player is cheating, made by ReportPlayerCheating. Only SteamID and AppID are
+// needed, the rest say where the report came from.
+type CheatingReport struct {
+	// SteamID is the steamid64 of the player being reported.
+	SteamID string
+	AppID   int
+	// ReporterSteamID is the steamid64 of the player who made the report, if it came from a player.
+	ReporterSteamID string
+	// AppData is anything the game wants to keep with the report.
+	AppData uint64
+	// Heuristic, Detection and PlayerReport say whether the report came from a heuristic, an anti-cheat detection or
+	// another player.
+	Heuristic    bool
+	Detection    bool
+	PlayerReport bool
+	// NoReportID doesn't give the report an id, for reports that are only for steam's records.
+	NoReportID bool
+	// GameMode is the game mode the player was in, as the game defines it.
+	GameMode int
+	// SuspicionStart is when the player started being suspected of cheating.
+	SuspicionStart time.Time
+	// Severity is how bad the cheating is, as the game defines it.
+	Severity int
+}
+
+// ReportPlayerCheating reports a player for cheating, returning the report's id for RequestPlayerGameBan. This is a
+// publisher only method, so it needs a publisher api key for the game (see PublisherApiKey). Returns
+// ErrPublisherKeyRequired if steam refuses the key.
+func (sa *SteamAuther) ReportPlayerCheating(ctx context.Context, report CheatingReport) (string, error) {
+	form := url.Values{
+		"steamid":      {report.SteamID},
+		"appid":        {strconv.Itoa(report.AppID)},
+		"heuristic":    {strconv.FormatBool(report.Heuristic)},
+		"detection":    {strconv.FormatBool(report.Detection)},
+		"playerreport": {strconv.FormatBool(report.PlayerReport)},
+		"noreportid":   {strconv.FormatBool(report.NoReportID)},
+		"gamemode":     {strconv.Itoa(report.GameMode)},
+		"severity":     {strconv.Itoa(report.Severity)},
+	}
+	if report.ReporterSteamID != "" {
+		form.Set("steamidreporter", report.ReporterSteamID)
+	}
+	if report.AppData != 0 {
+		form.Set("appdata", strconv.FormatUint(report.AppData, 10))
+	}
+	if !report.SuspicionStart.IsZero() {
+		form.Set("suspicionstarttime", strconv.FormatInt(report.SuspicionStart.Unix(), 10))
+	}
+
+	var data struct {
+		Response struct {
+			ReportID json.Number `json:"reportid"`
+		} `json:"response"`
+	}
+	if err := sa.postPublisherApi(ctx, "ICheatReportingService/ReportPlayerCheating/v1", form, &data); err != nil {
+		return "", fmt.Errorf("report player cheating (%s, %d): %w", report.SteamID, report.AppID, err)
+	}
+
+	return data.Response.ReportID.String(), nil
+}
+
+// GameBanRequest is a request to game ban a player, made by RequestPlayerGameBan.
+type GameBanRequest struct {
+	// SteamID is the steamid64 of the player to ban.
+	SteamID string
+	AppID   int
+	// ReportID is the id of the player's cheating report, from ReportPlayerCheating.
+	ReportID string
+	// CheatDescription says what the player did, it's shown to them.
+	CheatDescription string
+	// Duration is how long the ban lasts, or 0 for a permanent ban.
+	Duration time.Duration
+	// DelayBan holds the ban back for a random amount of time, so cheaters can't tell what got them caught.
+	DelayBan bool
+	// Flags are steam's ban flags, usually 0.
+	Flags int
+}
+
+// RequestPlayerGameBan asks steam to game ban a player that's been reported with ReportPlayerCheating. This is a
+// publisher only method, so it needs a publisher api key for the game (see PublisherApiKey). Returns
+// ErrPublisherKeyRequired if steam refuses the key.
+func (sa *SteamAuther) RequestPlayerGameBan(ctx context.Context, ban GameBanRequest) error {
+	form := url.Values{
+		"steamid":          {ban.SteamID},
+		"appid":            {strconv.Itoa(ban.AppID)},
+		"reportid":         {ban.ReportID},
+		"cheatdescription": {ban.CheatDescription},
+		"duration":         {strconv.FormatInt(int64(ban.Duration/time.Second), 10)},
+		"delayban":         {strconv.FormatBool(ban.DelayBan)},
+		"flags":            {strconv.Itoa(ban.Flags)},
+	}
+
+	var data struct{}
+	if err := sa.postPublisherApi(ctx, "ICheatReportingService/RequestPlayerGameBan/v1", form, &data); err != nil {
+		return fmt.Errorf("request player game ban (%s, %d): %w", ban.SteamID, ban.AppID, err)
+	}
+
+	return nil
+}
+
+// CheatingReportsFilter narrows down the reports GetCheatingReports returns. The zero value gets every report for the
+// game from the last day.
+type CheatingReportsFilter struct {
+	// SteamID only gets reports about the player with this steamid64.
+	SteamID string
+	// From and To are the time range to get reports from. To defaults to now, From to a day before To.
+	From, To time.Time
+	// MinReportID only gets reports with an id past this one, for paging through them.
+	MinReportID string
+	// IncludeReports and IncludeBans get reports that haven't led to a ban and reports that have. Both are got if
+	// neither is set.
+	IncludeReports bool
+	IncludeBans    bool
+}
+
+// CheatingReportEntry is a cheating report made about a player, as represented in the response from the
+// GetCheatingReports web api.
+type CheatingReportEntry struct {
+	ReportID string `json:"reportid"`
+	// SteamID is the steamid64 of the player the report is about.
+	SteamID string `json:"steamid"`
+	// ReporterSteamID is the steamid64 of the player who made the report, if it came from a player.
+	ReporterSteamID string `json:"steamidreporter"`
+	AppID           int    `json:"appid"`
+	// TimeReport is when the report was made, as a unix timestamp.
+	TimeReport   int64 `json:"timereport"`
+	Heuristic    bool  `json:"heuristic"`
+	Detection    bool  `json:"detection"`
+	PlayerReport bool  `json:"playerreport"`
+	GameMode     int   `json:"gamemode"`
+	Severity     int   `json:"severity"`
+	// Banned is true if the report led to a ban.
+	Banned bool `json:"banned"`
+}
+
+// Reported returns when the report was made.
+func (r *CheatingReportEntry) Reported() time.Time {
+	return time.Unix(r.TimeReport, 0)
+}
+
+// GetCheatingReports gets the cheating reports made about players of a game. This is a publisher only method, so it
+// needs a publisher api key for the game (see PublisherApiKey). Returns ErrPublisherKeyRequired if steam refuses the
+// key.
+func (sa *SteamAuther) GetCheatingReports(ctx context.Context, appid int, filter CheatingReportsFilter) ([]CheatingReportEntry, error) {
+	to := filter.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := filter.From
+	if from.IsZero() {
+		from = to.Add(-24 * time.Hour)
+	}
+	includeReports, includeBans := filter.IncludeReports, filter.IncludeBans
+	if !includeReports && !includeBans {
+		includeReports, includeBans = true, true
+	}
+
+	q := url.Values{
+		"appid":          {strconv.Itoa(appid)},
+		"timebegin":      {strconv.FormatInt(from.Unix(), 10)},
+		"timeend":        {strconv.FormatInt(to.Unix(), 10)},
+		"includereports": {strconv.FormatBool(includeReports)},
+		"includebans":    {strconv.FormatBool(includeBans)},
+	}
+	if filter.SteamID != "" {
+		q.Set("steamid", filter.SteamID)
+	}
+	if filter.MinReportID != "" {
+		q.Set("reportidmin", filter.MinReportID)
+	}
+
+	var data struct {
+		Response struct {
+			Reports []CheatingReportEntry `json:"reports"`
+		} `json:"response"`
+	}
+	if err := sa.getPublisherApi(ctx, "ICheatReportingService/GetCheatingReports/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get cheating reports (%d): %w", appid, err)
+	}
+
+	return data.Response.Reports, nil
+}
@@ -0,0 +1,86 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// cheatReporting answers ICheatReportingService like steam would on the partner host for the "pubkey" publisher
+// key, with one report about 76561197960287930 in app 480.
+func cheatReporting(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	if r.Host != "partner.steam-api.com" || r.Form.Get("appid") != "480" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Form.Get("key") != "pubkey" {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/ICheatReportingService/ReportPlayerCheating/v1":
+		if r.PostForm.Get("steamid") != "76561197960287930" || r.PostForm.Get("detection") != "true" || r.PostForm.Get("suspicionstarttime") != "1700000000" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"response":{"steamid":"76561197960287930","reportid":9007199254740993}}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/ICheatReportingService/RequestPlayerGameBan/v1":
+		if r.PostForm.Get("reportid") != "9007199254740993" || r.PostForm.Get("duration") != "86400" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"response":{}}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/ICheatReportingService/GetCheatingReports/v1":
+		if r.Form.Get("includereports") != "true" || r.Form.Get("includebans") != "true" || r.Form.Get("timeend") == "" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"response":{"reports":[{"reportid":"9007199254740993","steamid":"76561197960287930","appid":480,"timereport":1700000000,"detection":true,"severity":3,"banned":true}]}}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestCheatReporting(t *testing.T) {
+	fakeSteam(t, cheatReporting)
+	sa := New("key", "https://example.com")
+	sa.PublisherApiKey = "pubkey"
+	ctx := context.Background()
+
+	// the report id is past what a float64 holds exactly
+	id, err := sa.ReportPlayerCheating(ctx, CheatingReport{SteamID: "76561197960287930", AppID: 480, Detection: true, SuspicionStart: time.Unix(1700000000, 0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "9007199254740993" {
+		t.Errorf("report id = %q, want 9007199254740993", id)
+	}
+
+	if err := sa.RequestPlayerGameBan(ctx, GameBanRequest{SteamID: "76561197960287930", AppID: 480, ReportID: id, Duration: 24 * time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := sa.GetCheatingReports(ctx, 480, CheatingReportsFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].ReportID != id || !reports[0].Banned || reports[0].Reported().Unix() != 1700000000 {
+		t.Errorf("reports = %+v, want the banned report", reports)
+	}
+}
+
+func TestCheatReportingNotPublisher(t *testing.T) {
+	fakeSteam(t, cheatReporting)
+	sa := New("key", "https://example.com")
+
+	if _, err := sa.ReportPlayerCheating(context.Background(), CheatingReport{SteamID: "76561197960287930", AppID: 480}); !errors.Is(err, ErrPublisherKeyRequired) {
+		t.Errorf("report err = %v, want ErrPublisherKeyRequired", err)
+	}
+	if _, err := sa.GetCheatingReports(context.Background(), 480, CheatingReportsFilter{}); !errors.Is(err, ErrPublisherKeyRequired) {
+		t.Errorf("get reports err = %v, want ErrPublisherKeyRequired", err)
+	}
+}
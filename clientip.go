@@ -0,0 +1,110 @@
+package gosteamauth
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ClientIPResolver works out the IP of the client that made a request, looking through X-Forwarded-For (or another
+// header, see Header) when the request came through a trusted proxy (a load balancer, a CDN, ...). Handler uses it
+// for rate limiting, audit events, login events and session and remember me devices, so they all agree.
+type ClientIPResolver struct {
+	// TrustedProxies are the networks whose forwarding headers are believed. Anything else can put whatever it likes
+	// in the header, so it's ignored.
+	TrustedProxies []netip.Prefix
+	// Header is the header trusted proxies put the client's IP in. Defaults to X-Forwarded-For, which is read as a
+	// list of hops. Any other header (ex. X-Real-IP, CF-Connecting-IP) is read as a single IP, set by the proxy
+	// closest to the app.
+	Header string
+}
+
+// NewClientIPResolver returns a new ClientIPResolver trusting proxies in the networks, in CIDR notation
+// (ex. 10.0.0.0/8) or as single IPs.
+func NewClientIPResolver(trustedProxies ...string) (*ClientIPResolver, error) {
+	cr := &ClientIPResolver{}
+	for _, p := range trustedProxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		if !strings.Contains(p, "/") {
+			ip, err := netip.ParseAddr(p)
+			if err != nil {
+				return nil, fmt.Errorf("new client ip resolver: parse trusted proxy %q: %w", p, err)
+			}
+			cr.TrustedProxies = append(cr.TrustedProxies, netip.PrefixFrom(ip.Unmap(), ip.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("new client ip resolver: parse trusted proxy %q: %w", p, err)
+		}
+		cr.TrustedProxies = append(cr.TrustedProxies, prefix.Masked())
+	}
+
+	return cr, nil
+}
+
+// ClientIP returns the client's IP. A nil resolver trusts no proxies.
+func (cr *ClientIPResolver) ClientIP(r *http.Request) string {
+	remote := remoteAddr(r)
+	if cr == nil || !cr.trusted(remote) {
+		return remote.String()
+	}
+
+	if cr.Header != "" && !strings.EqualFold(cr.Header, "X-Forwarded-For") {
+		ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(cr.Header)))
+		if err != nil {
+			return remote.String()
+		}
+
+		return ip.Unmap().String()
+	}
+
+	// Each proxy appends the address it got the request from, so walk backwards from the closest one, stopping at the
+	// first address that isn't one of ours. That's the furthest hop we can vouch for.
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+
+		remote = ip.Unmap()
+		if !cr.trusted(remote) {
+			break
+		}
+	}
+
+	return remote.String()
+}
+
+func (cr *ClientIPResolver) trusted(ip netip.Addr) bool {
+	for _, p := range cr.TrustedProxies {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// remoteAddr returns the address of the other end of the request's connection.
+func remoteAddr(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	return ip.Unmap()
+}
@@ -0,0 +1,113 @@
+package gosteamauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestNewClientIPResolver(t *testing.T) {
+	cr, err := NewClientIPResolver("10.0.0.0/8", " 192.168.1.1 ", "", "::ffff:172.16.0.1", "10.1.2.3/16")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"10.0.0.0/8", "192.168.1.1/32", "172.16.0.1/32", "10.1.0.0/16"}
+	if len(cr.TrustedProxies) != len(want) {
+		t.Fatalf("TrustedProxies = %v, want %v", cr.TrustedProxies, want)
+	}
+	for i, p := range cr.TrustedProxies {
+		if p.String() != want[i] {
+			t.Errorf("TrustedProxies[%d] = %v, want %v", i, p, want[i])
+		}
+	}
+
+	for _, bad := range []string{"nope", "10.0.0.0/99"} {
+		if _, err := NewClientIPResolver(bad); err == nil {
+			t.Errorf("NewClientIPResolver(%q) err = nil, want an error", bad)
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	resolver := &ClientIPResolver{TrustedProxies: []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+	}}
+	realIp := &ClientIPResolver{TrustedProxies: resolver.TrustedProxies, Header: "X-Real-IP"}
+
+	tests := []struct {
+		name     string
+		resolver *ClientIPResolver
+		remote   string
+		header   string
+		value    string
+		want     string
+	}{
+		{"no proxy", nil, "203.0.113.7:1234", "", "", "203.0.113.7"},
+		{"untrusted remote ignores the header", resolver, "203.0.113.7:1234", "X-Forwarded-For", "198.51.100.1", "203.0.113.7"},
+		{"nil resolver ignores the header", nil, "10.0.0.1:1234", "X-Forwarded-For", "198.51.100.1", "10.0.0.1"},
+		{"trusted proxy", resolver, "10.0.0.1:1234", "X-Forwarded-For", "198.51.100.1", "198.51.100.1"},
+		{"chain of trusted proxies", resolver, "10.0.0.1:1234", "X-Forwarded-For", "198.51.100.1, 10.0.0.2", "198.51.100.1"},
+		// the client can put whatever it likes at the front, only the hops we trust count
+		{"spoofed front", resolver, "10.0.0.1:1234", "X-Forwarded-For", "1.2.3.4, 198.51.100.1", "198.51.100.1"},
+		{"garbage hop", resolver, "10.0.0.1:1234", "X-Forwarded-For", "198.51.100.1, nope", "10.0.0.1"},
+		{"mapped v4", nil, "[::ffff:203.0.113.7]:1234", "", "", "203.0.113.7"},
+		{"single ip header", realIp, "10.0.0.1:1234", "X-Real-IP", "198.51.100.1", "198.51.100.1"},
+		{"single ip header ignores forwarded for", realIp, "10.0.0.1:1234", "X-Forwarded-For", "198.51.100.1", "10.0.0.1"},
+		{"single ip header from untrusted remote", realIp, "203.0.113.7:1234", "X-Real-IP", "198.51.100.1", "203.0.113.7"},
+		{"single ip header garbage", realIp, "10.0.0.1:1234", "X-Real-IP", "nope", "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			if got := tt.resolver.ClientIP(req); got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionDevice(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	cr, err := NewClientIPResolver("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ClientIP = cr
+
+	req := steamCallback("https://example.com/callback", "76561197960287930")
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	req.Header.Set("User-Agent", "test")
+	rec := httptest.NewRecorder()
+	h.Callback(rec, req)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	sess, err := h.Sessions().Get(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.Device != (Device{UserAgent: "test", IP: "198.51.100.1"}) {
+		t.Errorf("Device = %+v, want the client behind the proxy", sess.Device)
+	}
+
+	// sessions started outside of a Handler don't know their device
+	sess, err = h.Sessions().Create(context.Background(), httptest.NewRecorder(), "76561197960287930", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.Device != (Device{}) {
+		t.Errorf("Device = %+v, want none", sess.Device)
+	}
+}
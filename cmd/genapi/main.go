@@ -0,0 +1,260 @@
+// Command genapi generates typed methods on gosteamauth.SteamAuther for web api methods, from the list
+// ISteamWebAPIUtil/GetSupportedAPIList returns. It's for adding the less common interfaces mechanically instead of
+// by hand; the generated methods take a params struct and decode the response into whatever out is, since steam
+// doesn't describe what methods return.
+//
+// The output belongs in the gosteamauth package directory, since it calls the package's unexported helpers:
+//
+//	STEAM_API_KEY=... genapi -interfaces IPlayerService,ISteamApps -out ../../webapi_gen.go
+//
+// Or from a saved response, without an api key:
+//
+//	genapi -in apilist.json -interfaces IStoreService -out ../../webapi_gen.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+func main() {
+	in := flag.String("in", "", "read the api list from this file (a saved GetSupportedAPIList response) instead of asking steam")
+	out := flag.String("out", "", "file to write the generated code to, stdout if empty")
+	ifaces := flag.String("interfaces", "", "comma separated interfaces to generate methods for, every one if empty")
+	pkg := flag.String("package", "gosteamauth", "package name of the generated file")
+	flag.Parse()
+
+	list, err := loadList(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *ifaces != "" {
+		want := strings.Split(*ifaces, ",")
+		list = slices.DeleteFunc(list, func(i gosteamauth.ApiInterface) bool { return !slices.Contains(want, i.Name) })
+	}
+
+	src, err := generate(*pkg, list)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadList reads the api list from path, or asks steam for it if path is empty.
+func loadList(path string) ([]gosteamauth.ApiInterface, error) {
+	if path == "" {
+		apiKey, ok := os.LookupEnv("STEAM_API_KEY")
+		if !ok {
+			return nil, fmt.Errorf("STEAM_API_KEY is not set, and there's no -in file")
+		}
+
+		return gosteamauth.New(apiKey, "").GetSupportedAPIList(context.Background())
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var data struct {
+		ApiList struct {
+			Interfaces []gosteamauth.ApiInterface `json:"interfaces"`
+		} `json:"apilist"`
+	}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+
+	return data.ApiList.Interfaces, nil
+}
+
+// generate writes the methods for every method of every interface in list, formatted.
+func generate(pkg string, list []gosteamauth.ApiInterface) ([]byte, error) {
+	var body bytes.Buffer
+	usesStrconv := false
+	for _, iface := range list {
+		for _, m := range iface.Methods {
+			usesStrconv = writeMethod(&body, iface.Name, m) || usesStrconv
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by genapi; DO NOT EDIT.\n\npackage %s\n\nimport (\n\t\"context\"\n\t\"fmt\"\n\t\"net/url\"\n", pkg)
+	if usesStrconv {
+		buf.WriteString("\t\"strconv\"\n")
+	}
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+
+	return src, nil
+}
+
+// param is a web api parameter, as a field of the generated params struct.
+type param struct {
+	name  string
+	field string
+	typ   string
+	array bool
+	gen   gosteamauth.ApiParameter
+}
+
+// writeMethod writes the params struct and method for m, and reports whether it used strconv.
+func writeMethod(w *bytes.Buffer, iface string, m gosteamauth.ApiMethod) bool {
+	funcName := identifier(iface) + identifier(m.Name)
+	if m.Version > 1 {
+		funcName += fmt.Sprintf("V%d", m.Version)
+	}
+	path := fmt.Sprintf("%s/%s/v%d", iface, m.Name, m.Version)
+
+	var params []param
+	seen := map[string]bool{}
+	for _, p := range m.Parameters {
+		if p.Name == "key" {
+			continue
+		}
+
+		name, array := strings.CutSuffix(p.Name, "[0]")
+		field := identifier(name)
+		for seen[field] {
+			field += "_"
+		}
+		seen[field] = true
+
+		params = append(params, param{name: name, field: field, typ: goType(p.Type), array: array, gen: p})
+	}
+
+	fmt.Fprintf(w, "\n// %sParams are the parameters of %s.\ntype %sParams struct {\n", funcName, path, funcName)
+	for _, p := range params {
+		if p.gen.Description != "" {
+			fmt.Fprintf(w, "\t// %s\n", comment(p.gen.Description))
+		}
+
+		typ := p.typ
+		switch {
+		case p.array:
+			typ = "[]" + typ
+		case p.gen.Optional:
+			typ = "*" + typ
+		}
+		fmt.Fprintf(w, "\t%s %s\n", p.field, typ)
+	}
+	w.WriteString("}\n")
+
+	fmt.Fprintf(w, "\n// %s calls %s, decoding the response into out.", funcName, path)
+	if m.Description != "" {
+		fmt.Fprintf(w, " Steam describes it as: %s", comment(m.Description))
+	}
+	fmt.Fprintf(w, "\nfunc (sa *SteamAuther) %s(ctx context.Context, params %sParams, out any) error {\n\tq := url.Values{}\n", funcName, funcName)
+
+	usesStrconv := false
+	for _, p := range params {
+		var set string
+		switch {
+		case p.array:
+			usesStrconv = true
+			set = fmt.Sprintf("for i, v := range params.%s {\n\tq.Set(%q+strconv.Itoa(i)+\"]\", %s)\n}\n", p.field, p.name+"[", formatValue(p.typ, "v"))
+		case p.gen.Optional:
+			set = fmt.Sprintf("if params.%s != nil {\n\tq.Set(%q, %s)\n}\n", p.field, p.name, formatValue(p.typ, "*params."+p.field))
+		default:
+			set = fmt.Sprintf("q.Set(%q, %s)\n", p.name, formatValue(p.typ, "params."+p.field))
+		}
+		if p.typ != "string" {
+			usesStrconv = true
+		}
+		w.WriteString(set)
+	}
+
+	call := "getApi"
+	if strings.EqualFold(m.HttpMethod, "POST") {
+		call = "postApi"
+	}
+	fmt.Fprintf(w, "\n\tif err := sa.%s(ctx, %q, q, out); err != nil {\n\t\treturn fmt.Errorf(%q, err)\n\t}\n\n\treturn nil\n}\n",
+		call, path, "call "+path+": %w")
+
+	return usesStrconv
+}
+
+// goType is the go type for a steam parameter type. Enums, messages and anything else unknown are passed as strings.
+func goType(steamType string) string {
+	switch steamType {
+	case "uint32", "uint64", "int32", "int64", "bool", "string":
+		return steamType
+	case "float":
+		return "float32"
+	}
+
+	return "string"
+}
+
+// formatValue is the go expression formatting v (of type typ) for a url.
+func formatValue(typ, v string) string {
+	switch typ {
+	case "uint64":
+		return "strconv.FormatUint(" + v + ", 10)"
+	case "uint32":
+		return "strconv.FormatUint(uint64(" + v + "), 10)"
+	case "int64":
+		return "strconv.FormatInt(" + v + ", 10)"
+	case "int32":
+		return "strconv.FormatInt(int64(" + v + "), 10)"
+	case "bool":
+		return "strconv.FormatBool(" + v + ")"
+	case "float32":
+		return "strconv.FormatFloat(float64(" + v + "), 'f', -1, 32)"
+	}
+
+	return v
+}
+
+// identifier turns a steam name (ex. "appids_filter", "IEconItems_440") into an exported go identifier.
+func identifier(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "P" + s
+	}
+
+	return s
+}
+
+// comment flattens a description onto one line, for a comment.
+func comment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
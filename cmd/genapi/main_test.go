@@ -0,0 +1,97 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"steamid", "Steamid"},
+		{"appids_filter", "AppidsFilter"},
+		{"IEconItems_440", "IEconItems440"},
+		{"440", "P440"},
+		{"", "P"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := identifier(tt.name); got != tt.want {
+				t.Errorf("identifier(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	list := []gosteamauth.ApiInterface{
+		{Name: "IGameServersService", Methods: []gosteamauth.ApiMethod{
+			{Name: "SetMemo", Version: 1, HttpMethod: "POST", Description: "Changes\nthe memo", Parameters: []gosteamauth.ApiParameter{
+				{Name: "key", Type: "string"},
+				{Name: "steamid", Type: "uint64", Description: "The server"},
+				{Name: "memo", Type: "string"},
+			}},
+		}},
+		{Name: "IPlayerService", Methods: []gosteamauth.ApiMethod{
+			{Name: "GetOwnedGames", Version: 2, HttpMethod: "GET", Parameters: []gosteamauth.ApiParameter{
+				{Name: "include_appinfo", Type: "bool", Optional: true},
+				{Name: "appids_filter[0]", Type: "uint32", Optional: true},
+			}},
+		}},
+	}
+
+	src, err := generate("gosteamauth", list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "webapi_gen.go", src, 0); err != nil {
+		t.Fatalf("generated code doesn't parse: %v\n%s", err, src)
+	}
+
+	for _, want := range []string{
+		"func (sa *SteamAuther) IGameServersServiceSetMemo(ctx context.Context, params IGameServersServiceSetMemoParams, out any) error",
+		"// The server\n\tSteamid uint64",
+		"Steam describes it as: Changes the memo",
+		`sa.postApi(ctx, "IGameServersService/SetMemo/v1", q, out)`,
+		"func (sa *SteamAuther) IPlayerServiceGetOwnedGamesV2(",
+		"IncludeAppinfo *bool",
+		"AppidsFilter   []uint32",
+		`q.Set("appids_filter["+strconv.Itoa(i)+"]", strconv.FormatUint(uint64(v), 10))`,
+		`sa.getApi(ctx, "IPlayerService/GetOwnedGames/v2", q, out)`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code is missing %q:\n%s", want, src)
+		}
+	}
+	if strings.Contains(string(src), "Key ") {
+		t.Errorf("generated code has a key param, which getApi adds:\n%s", src)
+	}
+}
+
+func TestLoadList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "apilist.json")
+	if err := os.WriteFile(path, []byte(`{"apilist":{"interfaces":[{"name":"ISteamApps","methods":[{"name":"GetAppList","version":2,"httpmethod":"GET"}]}]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := loadList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Name != "ISteamApps" || list[0].Methods[0].Name != "GetAppList" {
+		t.Errorf("list = %+v, want ISteamApps", list)
+	}
+
+	if _, err := loadList(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("missing file err = nil, want an error")
+	}
+}
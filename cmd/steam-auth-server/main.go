@@ -0,0 +1,302 @@
+// Command steam-auth-server runs steam login as a standalone service, so it can be deployed next to apps (as a
+// sidecar, or behind the same reverse proxy) instead of embedding the library in every one of them.
+//
+// Every flag can also be set with the environment variable in brackets. STEAM_API_KEY is required, and so is one of
+// -token-secret or -rsa-key.
+//
+// Endpoints:
+//
+//	GET  /login                  send the user to steam
+//	GET  /callback               steam sends the user back here, starts a session
+//	POST /logout                 end the session (and revoke a refresh token, if one is posted)
+//	POST /token                  trade the session for an access + refresh token, or rotate a refresh token
+//	GET  /userinfo               the logged in user's steam profile (session cookie or bearer token)
+//	GET  /.well-known/jwks.json  public keys, when signing with -rsa-key
+//	GET  /healthz                always 200
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+// config is everything the server can be configured with.
+type config struct {
+	addr          string
+	publicUrl     string
+	loginRedirect string
+	tokenSecret   string
+	rsaKeyPath    string
+	keyID         string
+	tokenTTL      time.Duration
+	sessionTTL    time.Duration
+	insecure      bool
+	proxies       string
+	ipHeader      string
+}
+
+func main() {
+	apiKey, ok := os.LookupEnv("STEAM_API_KEY")
+	if !ok {
+		log.Fatal("STEAM_API_KEY is not set")
+	}
+
+	cfg := parseFlags()
+	if err := run(apiKey, cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseFlags reads the config from flags, falling back to environment variables and then defaults.
+func parseFlags() config {
+	var cfg config
+	flag.StringVar(&cfg.addr, "addr", env("ADDR", ":8080"), "address to listen on (ADDR)")
+	flag.StringVar(&cfg.publicUrl, "public-url", env("PUBLIC_URL", "http://localhost:8080"), "url the server is reachable at, used as the openid realm (PUBLIC_URL)")
+	flag.StringVar(&cfg.loginRedirect, "login-redirect", env("LOGIN_REDIRECT", "/"), "where to send users after logging in (LOGIN_REDIRECT)")
+	flag.StringVar(&cfg.tokenSecret, "token-secret", env("TOKEN_SECRET", ""), "secret to sign HS256 tokens with (TOKEN_SECRET)")
+	flag.StringVar(&cfg.rsaKeyPath, "rsa-key", env("RSA_KEY", ""), "path to a PEM encoded RSA private key to sign RS256 tokens with (RSA_KEY)")
+	flag.StringVar(&cfg.keyID, "key-id", env("KEY_ID", "default"), "kid of the RSA key (KEY_ID)")
+	flag.DurationVar(&cfg.tokenTTL, "token-ttl", envDuration("TOKEN_TTL", gosteamauth.DefaultTokenTTL), "how long access tokens last (TOKEN_TTL)")
+	flag.DurationVar(&cfg.sessionTTL, "session-ttl", envDuration("SESSION_TTL", gosteamauth.DefaultSessionTTL), "how long sessions last (SESSION_TTL)")
+	flag.BoolVar(&cfg.insecure, "insecure-cookies", env("INSECURE_COOKIES", "") == "true", "don't mark cookies Secure, for local development over http (INSECURE_COOKIES)")
+	flag.StringVar(&cfg.proxies, "trusted-proxies", env("TRUSTED_PROXIES", ""), "comma separated CIDRs of proxies whose forwarding headers are trusted (TRUSTED_PROXIES)")
+	flag.StringVar(&cfg.ipHeader, "client-ip-header", env("CLIENT_IP_HEADER", "X-Forwarded-For"), "header trusted proxies put the client ip in (CLIENT_IP_HEADER)")
+	flag.Parse()
+
+	cfg.publicUrl = strings.TrimSuffix(cfg.publicUrl, "/")
+	return cfg
+}
+
+func run(apiKey string, cfg config) error {
+	auther := gosteamauth.New(apiKey, cfg.publicUrl)
+	store := gosteamauth.NewMemoryStore()
+	defer store.Close()
+
+	sessions := gosteamauth.NewSessions(store)
+	sessions.TTL = cfg.sessionTTL
+	sessions.Insecure = cfg.insecure
+
+	h := gosteamauth.NewHandler(auther, sessions, cfg.publicUrl+"/callback")
+	h.FetchUser = true
+	h.LoginRedirect = cfg.loginRedirect
+	h.Attempts = gosteamauth.NewAttemptTracker(0)
+	defer h.Attempts.Close()
+
+	resolver, err := gosteamauth.NewClientIPResolver(strings.Split(cfg.proxies, ",")...)
+	if err != nil {
+		return err
+	}
+	resolver.Header = cfg.ipHeader
+	h.ClientIP = resolver
+
+	issuer, verifier, jwks, err := tokens(cfg)
+	if err != nil {
+		return err
+	}
+	refresh := gosteamauth.NewRefreshTokens(store, issuer)
+
+	s := &server{
+		handler:  h,
+		refresh:  refresh,
+		verifier: verifier,
+		users:    gosteamauth.NewUserCache(auther, 5*time.Minute),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /login", h.Login)
+	mux.HandleFunc("GET /callback", h.Callback)
+	mux.HandleFunc("POST /logout", s.logout)
+	mux.HandleFunc("POST /token", s.token)
+	mux.HandleFunc("GET /userinfo", s.userinfo)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	if jwks != nil {
+		mux.Handle("GET /.well-known/jwks.json", jwks)
+	}
+
+	log.Printf("listening on %s (public url %s)", cfg.addr, cfg.publicUrl)
+	return http.ListenAndServe(cfg.addr, mux)
+}
+
+// tokens sets up token signing from the config. The JWKS is only returned when signing with an RSA key.
+func tokens(cfg config) (*gosteamauth.TokenIssuer, *gosteamauth.TokenVerifier, *gosteamauth.JWKS, error) {
+	var (
+		issuer   *gosteamauth.TokenIssuer
+		verifier *gosteamauth.TokenVerifier
+		jwks     *gosteamauth.JWKS
+	)
+
+	switch {
+	case cfg.rsaKeyPath != "":
+		key, err := loadRSAKey(cfg.rsaKeyPath)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		issuer = gosteamauth.NewRS256Issuer(key)
+		issuer.KeyID = cfg.keyID
+		verifier = gosteamauth.NewRS256Verifier(&key.PublicKey)
+		jwks = gosteamauth.NewJWKS()
+		jwks.AddKey(cfg.keyID, &key.PublicKey)
+	case cfg.tokenSecret != "":
+		issuer = gosteamauth.NewHS256Issuer([]byte(cfg.tokenSecret))
+		verifier = gosteamauth.NewHS256Verifier([]byte(cfg.tokenSecret))
+	default:
+		return nil, nil, nil, errors.New("one of -token-secret or -rsa-key is required")
+	}
+
+	issuer.Issuer = cfg.publicUrl
+	issuer.TTL = cfg.tokenTTL
+	verifier.Issuer = cfg.publicUrl
+
+	return issuer, verifier, jwks, nil
+}
+
+func loadRSAKey(path string) (*rsa.PrivateKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("load rsa key: %w", err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("load rsa key: no PEM block found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("load rsa key: %w", err)
+	}
+
+	key, ok := k.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("load rsa key: not an RSA key")
+	}
+
+	return key, nil
+}
+
+type server struct {
+	handler  *gosteamauth.Handler
+	refresh  *gosteamauth.RefreshTokens
+	verifier *gosteamauth.TokenVerifier
+	users    *gosteamauth.UserCache
+}
+
+// token hands out tokens. With grant_type=refresh_token it rotates the posted refresh token, otherwise it needs a
+// session and mints a fresh pair for it.
+func (s *server) token(w http.ResponseWriter, r *http.Request) {
+	if r.PostFormValue("grant_type") == "refresh_token" {
+		pair, err := s.refresh.Refresh(r.Context(), r.PostFormValue("refresh_token"))
+		if errors.Is(err, gosteamauth.ErrInvalidRefreshToken) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+			return
+		}
+
+		writeJSON(w, http.StatusOK, pair)
+		return
+	}
+
+	sess, err := s.handler.Sessions().Get(r)
+	if err != nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "not logged in"})
+		return
+	}
+
+	pair, err := s.refresh.Issue(r.Context(), sess.SteamID, sess.User)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pair)
+}
+
+// logout ends the session and revokes the posted refresh token, if there is one.
+func (s *server) logout(w http.ResponseWriter, r *http.Request) {
+	if tok := r.PostFormValue("refresh_token"); tok != "" {
+		if err := s.refresh.Revoke(r.Context(), tok); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+			return
+		}
+	}
+
+	s.handler.Logout(w, r)
+}
+
+// userinfo returns the logged in user's profile. Bearer tokens are checked first, then the session cookie.
+func (s *server) userinfo(w http.ResponseWriter, r *http.Request) {
+	var steamid string
+	if strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		c, err := s.verifier.Verify(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+			return
+		}
+		steamid = c.Subject
+	} else {
+		sess, err := s.handler.Sessions().Get(r)
+		if err != nil {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "not logged in"})
+			return
+		}
+		steamid = sess.SteamID
+	}
+
+	u, err := s.users.GetSteamUser(steamid)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": "couldn't get steam profile"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, u)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func env(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+
+	return def
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("%s is not a valid duration: %v", key, err)
+	}
+
+	return d
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+const steamid = "76561197960287930"
+
+// fakeSteam sends every request made with the default http client to h instead of steam, for the rest of the test.
+func fakeSteam(t *testing.T, h http.HandlerFunc) {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	old := http.DefaultClient.Transport
+	http.DefaultClient.Transport = rewriteTransport{target: target}
+	t.Cleanup(func() { http.DefaultClient.Transport = old })
+}
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// writeKey writes key to a temporary PEM file and returns its path.
+func writeKey(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTokens(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config
+		wantErr  bool
+		wantJWKS bool
+	}{
+		{"secret", config{tokenSecret: "secret"}, false, false},
+		{"pkcs1 key", config{rsaKeyPath: writeKey(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)), keyID: "k1"}, false, true},
+		{"pkcs8 key", config{rsaKeyPath: writeKey(t, "PRIVATE KEY", pkcs8), keyID: "k1"}, false, true},
+		{"not a key", config{rsaKeyPath: writeKey(t, "PRIVATE KEY", []byte("nope"))}, true, false},
+		{"missing key file", config{rsaKeyPath: filepath.Join(t.TempDir(), "missing.pem")}, true, false},
+		{"nothing", config{}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.publicUrl = "https://auth.example.com"
+			tt.cfg.tokenTTL = time.Hour
+
+			issuer, verifier, jwks, err := tokens(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tokens err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (jwks != nil) != tt.wantJWKS {
+				t.Errorf("jwks = %v, want one %v", jwks, tt.wantJWKS)
+			}
+
+			tok, err := issuer.Issue(steamid, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c, err := verifier.Verify(tok)
+			if err != nil {
+				t.Fatalf("Verify err = %v", err)
+			}
+			if c.Issuer != "https://auth.example.com" {
+				t.Errorf("iss = %q, want the public url", c.Issuer)
+			}
+		})
+	}
+}
+
+// newServer returns a server signing with a secret, and the cookie of a session logged in as steamid.
+func newServer(t *testing.T) (*server, *http.Cookie) {
+	t.Helper()
+
+	auther := gosteamauth.New("key", "https://auth.example.com")
+	store := gosteamauth.NewMemoryStore()
+	t.Cleanup(store.Close)
+
+	sessions := gosteamauth.NewSessions(store)
+	h := gosteamauth.NewHandler(auther, sessions, "https://auth.example.com/callback")
+
+	issuer, verifier, _, err := tokens(config{tokenSecret: "secret", publicUrl: "https://auth.example.com", tokenTTL: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	if _, err := sessions.Create(t.Context(), rec, steamid, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return &server{
+		handler:  h,
+		refresh:  gosteamauth.NewRefreshTokens(store, issuer),
+		verifier: verifier,
+		users:    gosteamauth.NewUserCache(auther, time.Minute),
+	}, rec.Result().Cookies()[0]
+}
+
+func post(h http.HandlerFunc, form url.Values, cookie *http.Cookie) (*httptest.ResponseRecorder, map[string]any) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	h(rec, req)
+
+	var body map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	return rec, body
+}
+
+func TestToken(t *testing.T) {
+	s, cookie := newServer(t)
+
+	rec, body := post(s.token, nil, nil)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("without a session status = %d, want 401", rec.Code)
+	}
+
+	rec, body = post(s.token, nil, cookie)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("with a session status = %d (%v), want 200", rec.Code, body)
+	}
+	refresh, _ := body["refresh_token"].(string)
+
+	rec, body = post(s.token, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refresh}}, nil)
+	if rec.Code != http.StatusOK || body["refresh_token"] == refresh {
+		t.Errorf("refresh status = %d (%v), want 200 and a rotated token", rec.Code, body)
+	}
+
+	rec, body = post(s.token, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {refresh}}, nil)
+	if rec.Code != http.StatusBadRequest || body["error"] != "invalid_grant" {
+		t.Errorf("reused refresh status = %d (%v), want 400 invalid_grant", rec.Code, body)
+	}
+}
+
+func TestUserinfo(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"players":[{"steamid":"` + r.URL.Query().Get("steamids") + `","personaname":"Rabscuttle"}]}}`))
+	})
+
+	s, cookie := newServer(t)
+	_, pair := post(s.token, nil, cookie)
+	access, _ := pair["access_token"].(string)
+
+	tests := []struct {
+		name   string
+		auth   string
+		cookie *http.Cookie
+		want   int
+	}{
+		{"bearer token", "Bearer " + access, nil, http.StatusOK},
+		{"session", "", cookie, http.StatusOK},
+		{"bad bearer token", "Bearer nope", cookie, http.StatusUnauthorized},
+		{"nothing", "", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/userinfo", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+			s.userinfo(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d (%s), want %d", rec.Code, rec.Body, tt.want)
+			}
+			if tt.want == http.StatusOK && !strings.Contains(rec.Body.String(), "Rabscuttle") {
+				t.Errorf("body = %s, want the user's profile", rec.Body)
+			}
+		})
+	}
+}
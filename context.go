@@ -0,0 +1,42 @@
+package gosteamauth
+
+import "context"
+
+type contextKey int
+
+const (
+	steamIDContextKey contextKey = iota
+	claimsContextKey
+	sessionContextKey
+	tenantContextKey
+)
+
+// ContextWithSteamID returns a copy of ctx carrying the authenticated user's steamid64.
+func ContextWithSteamID(ctx context.Context, steamid64 string) context.Context {
+	return context.WithValue(ctx, steamIDContextKey, steamid64)
+}
+
+// SteamIDFromContext returns the authenticated user's steamid64 put in ctx by one of the package's middlewares.
+func SteamIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(steamIDContextKey).(string)
+	return id, ok && id != ""
+}
+
+// ClaimsFromContext returns the claims of the token that authenticated the request, if it was authenticated by
+// TokenVerifier.Middleware.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	c, ok := ctx.Value(claimsContextKey).(*Claims)
+	return c, ok
+}
+
+// SessionFromContext returns the request's session, if it was loaded by one of Handler's middlewares.
+func SessionFromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(sessionContextKey).(*Session)
+	return s, ok
+}
+
+// ContextWithSession returns a copy of ctx carrying sess and its steamid.
+func ContextWithSession(ctx context.Context, sess *Session) context.Context {
+	ctx = context.WithValue(ctx, sessionContextKey, sess)
+	return ContextWithSteamID(ctx, sess.SteamID)
+}
@@ -0,0 +1,74 @@
+// Package chiauth mounts the steam login flow on a chi router:
+//
+//	h := gosteamauth.NewHandler(auther, sessions, "https://example.com/auth/callback")
+//	r.Mount("/auth", chiauth.Routes(h))
+//	r.With(chiauth.RequireAuth(h)).Get("/me", me)
+package chiauth
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+// Routes returns a router serving GET /login, GET /callback and POST /logout with h.
+func Routes(h *gosteamauth.Handler) chi.Router {
+	r := chi.NewRouter()
+	r.Get("/login", h.Login)
+	r.Get("/callback", h.Callback)
+	r.Post("/logout", h.Logout)
+
+	return r
+}
+
+// RequireAuth is h.RequireAuth as a chi middleware, for use with r.Use and r.With.
+func RequireAuth(h *gosteamauth.Handler) func(http.Handler) http.Handler {
+	return h.RequireAuth
+}
+
+// LoadSession is h.LoadSession as a chi middleware, for use with r.Use and r.With.
+func LoadSession(h *gosteamauth.Handler) func(http.Handler) http.Handler {
+	return h.LoadSession
+}
+
+// RequireRole is h.RequireRole as a chi middleware, for use with r.Use and r.With.
+func RequireRole(h *gosteamauth.Handler, roles ...string) func(http.Handler) http.Handler {
+	return h.RequireRole(roles...)
+}
+
+// RequireToken is tv.Middleware as a chi middleware, for routes authenticated with bearer tokens instead of sessions.
+func RequireToken(tv *gosteamauth.TokenVerifier) func(http.Handler) http.Handler {
+	return tv.Middleware
+}
+
+// RequireSelf only lets a request through if the chi URL parameter called param is the logged in user's steamid64,
+// for routes like /users/{steamid}/settings. It has to run after one of the auth middlewares.
+func RequireSelf(param string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, ok := gosteamauth.SteamIDFromContext(r.Context())
+			if !ok {
+				http.Error(w, "you need to log in with steam first", http.StatusUnauthorized)
+				return
+			}
+
+			if chi.URLParam(r, param) != id {
+				http.Error(w, "you can only do this for your own account", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// SteamID returns the logged in user's steamid64, as put in the request context by one of the auth middlewares.
+func SteamID(r *http.Request) (string, bool) {
+	return gosteamauth.SteamIDFromContext(r.Context())
+}
+
+// Session returns the logged in user's session, as put in the request context by RequireAuth or LoadSession.
+func Session(r *http.Request) (*gosteamauth.Session, bool) {
+	return gosteamauth.SessionFromContext(r.Context())
+}
@@ -0,0 +1,10 @@
+module github.com/liondadev/go-steam-auth/contrib/chi
+
+go 1.24.3
+
+require (
+	github.com/go-chi/chi/v5 v5.3.2
+	github.com/liondadev/go-steam-auth v0.0.0-00010101000000-000000000000
+)
+
+replace github.com/liondadev/go-steam-auth => ../..
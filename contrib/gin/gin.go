@@ -0,0 +1,170 @@
+// Package ginauth plugs the steam login flow into gin:
+//
+//	h := gosteamauth.NewHandler(auther, sessions, "https://example.com/auth/callback")
+//	ginauth.Register(r.Group("/auth"), h)
+//	r.GET("/me", ginauth.RequireAuth(h), func(c *gin.Context) {
+//		user, _ := ginauth.User(c)
+//		...
+//	})
+package ginauth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+const (
+	// SteamIDKey is the gin.Context key the logged in user's steamid64 is set under.
+	SteamIDKey = "gosteamauth.steamid"
+	// SessionKey is the gin.Context key the logged in user's *gosteamauth.Session is set under.
+	SessionKey = "gosteamauth.session"
+	// ClaimsKey is the gin.Context key the *gosteamauth.Claims of the request's bearer token are set under.
+	ClaimsKey = "gosteamauth.claims"
+)
+
+// Register adds GET /login, GET /callback and POST /logout routes served by h to rg.
+func Register(rg gin.IRoutes, h *gosteamauth.Handler) {
+	rg.GET("/login", Login(h))
+	rg.GET("/callback", Callback(h))
+	rg.POST("/logout", Logout(h))
+}
+
+// Login returns h.Login as a gin handler.
+func Login(h *gosteamauth.Handler) gin.HandlerFunc {
+	return gin.WrapF(h.Login)
+}
+
+// Callback returns h.Callback as a gin handler.
+func Callback(h *gosteamauth.Handler) gin.HandlerFunc {
+	return gin.WrapF(h.Callback)
+}
+
+// Logout returns h.Logout as a gin handler.
+func Logout(h *gosteamauth.Handler) gin.HandlerFunc {
+	return gin.WrapF(h.Logout)
+}
+
+// RequireAuth aborts requests without a session with a 401. Requests with one have it set on the gin.Context (see
+// Session, SteamID and User) and on the request context, so gosteamauth.SessionFromContext works too.
+func RequireAuth(h *gosteamauth.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if requireSession(c, h) {
+			c.Next()
+		}
+	}
+}
+
+// requireSession sets the request's session on the gin.Context, aborting and returning false if it doesn't have one.
+func requireSession(c *gin.Context, h *gosteamauth.Handler) bool {
+	sess, err := h.CurrentSession(c.Writer, c.Request)
+	if errors.Is(err, gosteamauth.ErrNoSession) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "you need to log in with steam first"})
+		return false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "failed to load session"})
+		return false
+	}
+
+	setSession(c, sess)
+	return true
+}
+
+// RequireRole is RequireAuth, but only lets through users with at least one of the roles. See Handler.Roles.
+func RequireRole(h *gosteamauth.Handler, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !requireSession(c, h) {
+			return
+		}
+
+		sess, _ := Session(c)
+		for _, role := range roles {
+			if sess.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "you don't have permission to do that"})
+	}
+}
+
+// LoadSession sets the request's session on the gin.Context if it has one, and carries on either way.
+func LoadSession(h *gosteamauth.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if sess, err := h.CurrentSession(c.Writer, c.Request); err == nil {
+			setSession(c, sess)
+		}
+
+		c.Next()
+	}
+}
+
+// RequireToken aborts requests without a valid bearer token with a 401, like gosteamauth.TokenVerifier.Middleware.
+func RequireToken(tv *gosteamauth.TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var claims *gosteamauth.Claims
+		tv.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, _ = gosteamauth.ClaimsFromContext(r.Context())
+			c.Request = r
+		})).ServeHTTP(c.Writer, c.Request)
+
+		if claims == nil {
+			// The middleware already wrote the 401.
+			c.Abort()
+			return
+		}
+
+		c.Set(SteamIDKey, claims.Subject)
+		c.Set(ClaimsKey, claims)
+		c.Next()
+	}
+}
+
+func setSession(c *gin.Context, sess *gosteamauth.Session) {
+	c.Set(SessionKey, sess)
+	c.Set(SteamIDKey, sess.SteamID)
+	c.Request = c.Request.WithContext(gosteamauth.ContextWithSession(c.Request.Context(), sess))
+}
+
+// SteamID returns the logged in user's steamid64, set by any of the auth middlewares.
+func SteamID(c *gin.Context) (string, bool) {
+	id := c.GetString(SteamIDKey)
+	return id, id != ""
+}
+
+// Session returns the logged in user's session, set by RequireAuth or LoadSession.
+func Session(c *gin.Context) (*gosteamauth.Session, bool) {
+	v, ok := c.Get(SessionKey)
+	if !ok {
+		return nil, false
+	}
+
+	sess, ok := v.(*gosteamauth.Session)
+	return sess, ok
+}
+
+// User returns the snapshot of the logged in user's profile kept in their session. This is only there if the
+// handler has FetchUser turned on.
+func User(c *gin.Context) (*gosteamauth.SteamUser, bool) {
+	sess, ok := Session(c)
+	if !ok || sess.User == nil {
+		return nil, false
+	}
+
+	return sess.User, true
+}
+
+// Claims returns the claims of the request's bearer token, set by RequireToken.
+func Claims(c *gin.Context) (*gosteamauth.Claims, bool) {
+	v, ok := c.Get(ClaimsKey)
+	if !ok {
+		return nil, false
+	}
+
+	claims, ok := v.(*gosteamauth.Claims)
+	return claims, ok
+}
@@ -0,0 +1,147 @@
+package ginauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+const steamid = "76561197960287930"
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newHandler returns a handler along with the cookie of a session logged in as steamid.
+func newHandler(t *testing.T) (*gosteamauth.Handler, *http.Cookie) {
+	t.Helper()
+
+	h := gosteamauth.NewHandler(gosteamauth.New("", "https://example.com"), gosteamauth.NewSessions(gosteamauth.NewMemoryStore()), "https://example.com/auth/callback")
+
+	rec := httptest.NewRecorder()
+	if _, err := h.Sessions().Create(context.Background(), rec, steamid, &gosteamauth.SteamUser{SteamID: steamid, PersonaName: "Rabscuttle"}); err != nil {
+		t.Fatal(err)
+	}
+
+	return h, rec.Result().Cookies()[0]
+}
+
+// echo writes back the steamid and persona name set on the gin.Context.
+func echo(c *gin.Context) {
+	id, _ := SteamID(c)
+	name := ""
+	if u, ok := User(c); ok {
+		name = u.PersonaName
+	}
+	if _, ok := gosteamauth.SessionFromContext(c.Request.Context()); !ok && name != "" {
+		name = "missing from request context"
+	}
+	c.String(http.StatusOK, id+" "+name)
+}
+
+func TestSessionMiddleware(t *testing.T) {
+	h, cookie := newHandler(t)
+
+	tests := []struct {
+		name       string
+		middleware gin.HandlerFunc
+		cookie     *http.Cookie
+		want       int
+		wantBody   string
+	}{
+		{"RequireAuth with session", RequireAuth(h), cookie, http.StatusOK, steamid + " Rabscuttle"},
+		{"RequireAuth without session", RequireAuth(h), nil, http.StatusUnauthorized, ""},
+		{"RequireAuth with unknown session", RequireAuth(h), &http.Cookie{Name: cookie.Name, Value: "nope"}, http.StatusUnauthorized, ""},
+		{"LoadSession with session", LoadSession(h), cookie, http.StatusOK, steamid + " Rabscuttle"},
+		{"LoadSession without session", LoadSession(h), nil, http.StatusOK, " "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/me", tt.middleware, echo)
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRequireToken(t *testing.T) {
+	tok, err := gosteamauth.NewHS256Issuer([]byte("secret")).Issue(steamid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	r.GET("/api", RequireToken(gosteamauth.NewHS256Verifier([]byte("secret"))), func(c *gin.Context) {
+		claims, ok := Claims(c)
+		if !ok {
+			c.String(http.StatusInternalServerError, "no claims")
+			return
+		}
+		c.String(http.StatusOK, claims.Subject)
+	})
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"valid", "Bearer " + tok, http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong secret", "Bearer " + tok[:len(tok)-2] + "xx", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != steamid {
+				t.Errorf("body = %q, want %q", rec.Body.String(), steamid)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	h, _ := newHandler(t)
+
+	r := gin.New()
+	Register(r.Group("/auth"), h)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/login", nil))
+	if rec.Code != http.StatusFound {
+		t.Errorf("GET /auth/login status = %d, want %d", rec.Code, http.StatusFound)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/logout", nil))
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("POST /auth/logout status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/liondadev/go-steam-auth/contrib/gorillasessions
+
+go 1.24.3
+
+require (
+	github.com/gorilla/sessions v1.4.0
+	github.com/liondadev/go-steam-auth v0.0.0-00010101000000-000000000000
+)
+
+require github.com/gorilla/securecookie v1.1.2 // indirect
+
+replace github.com/liondadev/go-steam-auth => ../..
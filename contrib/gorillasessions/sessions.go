@@ -0,0 +1,232 @@
+// Package gorillasessions stores the result of a steam login in an existing gorilla/sessions session,
+// so apps that already use gorilla don't have to adopt another session system.
+package gorillasessions
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/sessions"
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+const (
+	// SteamIDKey is the session value key the authenticated steamid64 is stored under.
+	SteamIDKey = "gosteamauth.steamid"
+	// UserKey is the session value key the SteamUser snapshot is stored under.
+	UserKey = "gosteamauth.user"
+	// StateKey is the session value key the state of an in progress login is stored under.
+	StateKey = "gosteamauth.state"
+
+	// stateParam is the return url query parameter carrying the login state.
+	stateParam = "state"
+)
+
+// errBadCallback is returned by checkCallback when the callback wasn't started by this session.
+var errBadCallback = errors.New("callback doesn't match the login started by this session")
+
+func init() {
+	// gorilla encodes session values with gob, so it needs to know about our type.
+	gob.Register(gosteamauth.SteamUser{})
+}
+
+// Adapter saves and loads steam logins in a gorilla session.
+type Adapter struct {
+	auther      *gosteamauth.SteamAuther
+	store       sessions.Store
+	sessionName string
+
+	// FetchUser makes CallbackHandler look up the user's profile with GetSteamUser and store a
+	// snapshot of it next to the steamid. This costs one web api request per login.
+	FetchUser bool
+}
+
+// New returns a new Adapter storing logins in the session called sessionName from store.
+func New(auther *gosteamauth.SteamAuther, store sessions.Store, sessionName string) *Adapter {
+	return &Adapter{
+		auther:      auther,
+		store:       store,
+		sessionName: sessionName,
+	}
+}
+
+// Save stores steamid64 (and the user snapshot, if not nil) in the session and writes it to the response.
+func (a *Adapter) Save(w http.ResponseWriter, r *http.Request, steamid64 string, user *gosteamauth.SteamUser) error {
+	s, err := a.store.Get(r, a.sessionName)
+	if err != nil && s == nil {
+		return fmt.Errorf("save session (%s): get session: %w", steamid64, err)
+	}
+
+	s.Values[SteamIDKey] = steamid64
+	if user != nil {
+		s.Values[UserKey] = *user
+	} else {
+		delete(s.Values, UserKey)
+	}
+
+	if err := s.Save(r, w); err != nil {
+		return fmt.Errorf("save session (%s): %w", steamid64, err)
+	}
+
+	return nil
+}
+
+// SteamID returns the steamid64 stored in the request's session, if there is one.
+func (a *Adapter) SteamID(r *http.Request) (string, bool) {
+	s, err := a.store.Get(r, a.sessionName)
+	if err != nil {
+		return "", false
+	}
+
+	id, ok := s.Values[SteamIDKey].(string)
+	return id, ok && id != ""
+}
+
+// User returns the SteamUser snapshot stored in the request's session, if there is one.
+func (a *Adapter) User(r *http.Request) (*gosteamauth.SteamUser, bool) {
+	s, err := a.store.Get(r, a.sessionName)
+	if err != nil {
+		return nil, false
+	}
+
+	u, ok := s.Values[UserKey].(gosteamauth.SteamUser)
+	if !ok {
+		return nil, false
+	}
+
+	return &u, true
+}
+
+// Clear removes the steam login from the session, without touching any other values the app stored in it.
+func (a *Adapter) Clear(w http.ResponseWriter, r *http.Request) error {
+	s, err := a.store.Get(r, a.sessionName)
+	if err != nil && s == nil {
+		return fmt.Errorf("clear session: get session: %w", err)
+	}
+
+	delete(s.Values, SteamIDKey)
+	delete(s.Values, UserKey)
+
+	if err := s.Save(r, w); err != nil {
+		return fmt.Errorf("clear session: %w", err)
+	}
+
+	return nil
+}
+
+// LoginHandler stores a random state in the session and redirects the user to steam to sign in.
+// callbackUrl is where CallbackHandler is mounted, it's used as the openid return url.
+func (a *Adapter) LoginHandler(callbackUrl string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		returnUrl, err := url.Parse(callbackUrl)
+		if err != nil {
+			http.Error(w, "failed to start steam login", http.StatusInternalServerError)
+			return
+		}
+
+		b := make([]byte, 24)
+		if _, err := rand.Read(b); err != nil {
+			http.Error(w, "failed to start steam login", http.StatusInternalServerError)
+			return
+		}
+		state := base64.RawURLEncoding.EncodeToString(b)
+
+		s, err := a.store.Get(r, a.sessionName)
+		if err != nil && s == nil {
+			http.Error(w, "failed to start steam login", http.StatusInternalServerError)
+			return
+		}
+		s.Values[StateKey] = state
+		if err := s.Save(r, w); err != nil {
+			http.Error(w, "failed to save session", http.StatusInternalServerError)
+			return
+		}
+
+		q := returnUrl.Query()
+		q.Set(stateParam, state)
+		returnUrl.RawQuery = q.Encode()
+
+		authUrl, err := a.auther.GetAuthUrl(returnUrl.String())
+		if err != nil {
+			http.Error(w, "failed to start steam login", http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, authUrl, http.StatusFound)
+	})
+}
+
+// checkCallback makes sure the callback carries the state LoginHandler stored in the session, read from returnTo, the
+// query of the return url steam signed. The state is removed from the session either way, so it can only be used once.
+func (a *Adapter) checkCallback(w http.ResponseWriter, r *http.Request, returnTo url.Values) error {
+	s, err := a.store.Get(r, a.sessionName)
+	if err != nil && s == nil {
+		return fmt.Errorf("check callback: get session: %w", err)
+	}
+
+	want, _ := s.Values[StateKey].(string)
+	if want != "" {
+		delete(s.Values, StateKey)
+		if err := s.Save(r, w); err != nil {
+			return fmt.Errorf("check callback: %w", err)
+		}
+	}
+
+	got := returnTo.Get(stateParam)
+	if want == "" || subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+		return errBadCallback
+	}
+
+	return nil
+}
+
+// CallbackHandler validates the openid callback, stores the login in the session and then calls next.
+// This should be mounted at callbackUrl, the same url given to LoginHandler. The login has to have been
+// started with LoginHandler in the same session, otherwise it's rejected. next is usually a redirect to
+// somewhere in your app.
+func (a *Adapter) CallbackHandler(callbackUrl string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		steamid, returnTo, err := a.auther.ValidateCallbackFor(r.Context(), r.URL.Query(), callbackUrl)
+		if err != nil {
+			if errors.Is(err, gosteamauth.ErrInvalidAuthRequest) {
+				http.Error(w, "invalid steam login", http.StatusUnauthorized)
+				return
+			}
+
+			http.Error(w, "failed to validate steam login", http.StatusBadGateway)
+			return
+		}
+
+		if err := a.checkCallback(w, r, returnTo); err != nil {
+			if errors.Is(err, errBadCallback) {
+				http.Error(w, "invalid steam login", http.StatusUnauthorized)
+				return
+			}
+
+			http.Error(w, "failed to check steam login", http.StatusInternalServerError)
+			return
+		}
+
+		var user *gosteamauth.SteamUser
+		if a.FetchUser {
+			user, err = a.auther.GetSteamUser(steamid)
+			if err != nil {
+				http.Error(w, "failed to get steam user", http.StatusBadGateway)
+				return
+			}
+		}
+
+		if err := a.Save(w, r, steamid, user); err != nil {
+			http.Error(w, "failed to save session", http.StatusInternalServerError)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
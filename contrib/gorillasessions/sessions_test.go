@@ -0,0 +1,157 @@
+package gorillasessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/sessions"
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+const callbackUrl = "https://example.com/auth/callback"
+
+// fakeSteam points the default client at a test server answering every openid check with body.
+func fakeSteam(t *testing.T, body string) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	old := http.DefaultClient.Transport
+	http.DefaultClient.Transport = rewriteTransport{target: target}
+	t.Cleanup(func() { http.DefaultClient.Transport = old })
+}
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newAdapter() *Adapter {
+	auther := gosteamauth.New("", "https://example.com")
+	return New(auther, sessions.NewCookieStore([]byte("0123456789abcdef0123456789abcdef")), "test")
+}
+
+// startLogin runs LoginHandler and returns the session cookies and the return_to steam was given.
+func startLogin(t *testing.T, a *Adapter) ([]*http.Cookie, string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	a.LoginHandler(callbackUrl).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/login", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("login status = %d, want %d", rec.Code, http.StatusFound)
+	}
+
+	loc, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("parse location: %v", err)
+	}
+
+	return rec.Result().Cookies(), loc.Query().Get("openid.return_to")
+}
+
+func callback(a *Adapter, cookies []*http.Cookie, returnTo string) *httptest.ResponseRecorder {
+	q := url.Values{
+		"openid.ns":             {gosteamauth.OpenIdNamespace},
+		"openid.mode":           {"id_res"},
+		"openid.claimed_id":     {"https://steamcommunity.com/openid/id/76561197960287930"},
+		"openid.identity":       {"https://steamcommunity.com/openid/id/76561197960287930"},
+		"openid.return_to":      {returnTo},
+		"openid.response_nonce": {"2024-01-01T00:00:00Zabc"},
+		"openid.signed":         {"signed,claimed_id,identity,return_to,response_nonce"},
+		"openid.sig":            {"c2lnbmF0dXJl"},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/callback?"+q.Encode(), nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	rec := httptest.NewRecorder()
+	a.CallbackHandler(callbackUrl, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLoginHandler(t *testing.T) {
+	a := newAdapter()
+	cookies, returnTo := startLogin(t, a)
+
+	if len(cookies) == 0 {
+		t.Fatal("login didn't set a session cookie")
+	}
+
+	u, err := url.Parse(returnTo)
+	if err != nil {
+		t.Fatalf("parse return_to: %v", err)
+	}
+	if u.Host != "example.com" || u.Path != "/auth/callback" {
+		t.Errorf("return_to = %q, want it to point at %q", returnTo, callbackUrl)
+	}
+	if u.Query().Get(stateParam) == "" {
+		t.Errorf("return_to = %q, want a state", returnTo)
+	}
+}
+
+func TestCallbackHandler(t *testing.T) {
+	fakeSteam(t, "ns:http://specs.openid.net/auth/2.0\nis_valid:true\n")
+
+	a := newAdapter()
+	cookies, returnTo := startLogin(t, a)
+	_, otherReturnTo := startLogin(t, a)
+
+	wrongHost, _ := url.Parse(returnTo)
+	wrongHost.Host = "evil.example"
+
+	tests := []struct {
+		name     string
+		cookies  []*http.Cookie
+		returnTo string
+		want     int
+	}{
+		{"valid", cookies, returnTo, http.StatusNoContent},
+		{"no session", nil, returnTo, http.StatusUnauthorized},
+		{"other login's state", cookies, otherReturnTo, http.StatusUnauthorized},
+		{"wrong return host", cookies, wrongHost.String(), http.StatusUnauthorized},
+		{"no state", cookies, callbackUrl, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := callback(a, tt.cookies, tt.returnTo)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallbackHandlerStateSingleUse(t *testing.T) {
+	fakeSteam(t, "ns:http://specs.openid.net/auth/2.0\nis_valid:true\n")
+
+	a := newAdapter()
+	cookies, returnTo := startLogin(t, a)
+
+	rec := callback(a, cookies, returnTo)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("first callback status = %d, want %d", rec.Code, http.StatusNoContent)
+	}
+
+	// replay with the cookies the callback left behind
+	rec = callback(a, rec.Result().Cookies(), returnTo)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("replayed callback status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,671 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: authpb/steamauth.proto
+
+package authpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetAuthURLRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// return_url is where steam sends the user back to after logging in.
+	ReturnUrl     string `protobuf:"bytes,1,opt,name=return_url,json=returnUrl,proto3" json:"return_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthURLRequest) Reset() {
+	*x = GetAuthURLRequest{}
+	mi := &file_authpb_steamauth_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthURLRequest) ProtoMessage() {}
+
+func (x *GetAuthURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthURLRequest.ProtoReflect.Descriptor instead.
+func (*GetAuthURLRequest) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetAuthURLRequest) GetReturnUrl() string {
+	if x != nil {
+		return x.ReturnUrl
+	}
+	return ""
+}
+
+type GetAuthURLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAuthURLResponse) Reset() {
+	*x = GetAuthURLResponse{}
+	mi := &file_authpb_steamauth_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAuthURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAuthURLResponse) ProtoMessage() {}
+
+func (x *GetAuthURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAuthURLResponse.ProtoReflect.Descriptor instead.
+func (*GetAuthURLResponse) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetAuthURLResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type ValidateCallbackRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// query is the raw query string of the callback request, without the leading "?".
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// issue_token asks for an access token to be issued, if the server is configured to issue them.
+	IssueToken bool `protobuf:"varint,2,opt,name=issue_token,json=issueToken,proto3" json:"issue_token,omitempty"`
+	// callback_url is the return_url the login was started with (see GetAuthURLRequest). Callbacks that steam was told
+	// to send somewhere else are rejected, so one site's logins can't be replayed against another.
+	CallbackUrl   string `protobuf:"bytes,3,opt,name=callback_url,json=callbackUrl,proto3" json:"callback_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCallbackRequest) Reset() {
+	*x = ValidateCallbackRequest{}
+	mi := &file_authpb_steamauth_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCallbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCallbackRequest) ProtoMessage() {}
+
+func (x *ValidateCallbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCallbackRequest.ProtoReflect.Descriptor instead.
+func (*ValidateCallbackRequest) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValidateCallbackRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *ValidateCallbackRequest) GetIssueToken() bool {
+	if x != nil {
+		return x.IssueToken
+	}
+	return false
+}
+
+func (x *ValidateCallbackRequest) GetCallbackUrl() string {
+	if x != nil {
+		return x.CallbackUrl
+	}
+	return ""
+}
+
+type ValidateCallbackResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Steamid string                 `protobuf:"bytes,1,opt,name=steamid,proto3" json:"steamid,omitempty"`
+	// access_token is only set if issue_token was set and the server issues tokens.
+	AccessToken   string `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	ExpiresIn     int64  `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateCallbackResponse) Reset() {
+	*x = ValidateCallbackResponse{}
+	mi := &file_authpb_steamauth_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateCallbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateCallbackResponse) ProtoMessage() {}
+
+func (x *ValidateCallbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateCallbackResponse.ProtoReflect.Descriptor instead.
+func (*ValidateCallbackResponse) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateCallbackResponse) GetSteamid() string {
+	if x != nil {
+		return x.Steamid
+	}
+	return ""
+}
+
+func (x *ValidateCallbackResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *ValidateCallbackResponse) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steamid       string                 `protobuf:"bytes,1,opt,name=steamid,proto3" json:"steamid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_authpb_steamauth_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUserRequest) GetSteamid() string {
+	if x != nil {
+		return x.Steamid
+	}
+	return ""
+}
+
+type GetUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *SteamUser             `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserResponse) Reset() {
+	*x = GetUserResponse{}
+	mi := &file_authpb_steamauth_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserResponse) ProtoMessage() {}
+
+func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetUserResponse) GetUser() *SteamUser {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// SteamUser mirrors gosteamauth.SteamUser.
+type SteamUser struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Steamid                  string                 `protobuf:"bytes,1,opt,name=steamid,proto3" json:"steamid,omitempty"`
+	PersonaName              string                 `protobuf:"bytes,2,opt,name=persona_name,json=personaName,proto3" json:"persona_name,omitempty"`
+	PersonaState             int32                  `protobuf:"varint,3,opt,name=persona_state,json=personaState,proto3" json:"persona_state,omitempty"`
+	ProfileUrl               string                 `protobuf:"bytes,4,opt,name=profile_url,json=profileUrl,proto3" json:"profile_url,omitempty"`
+	ProfileState             int32                  `protobuf:"varint,5,opt,name=profile_state,json=profileState,proto3" json:"profile_state,omitempty"`
+	CommunityVisibilityState int32                  `protobuf:"varint,6,opt,name=community_visibility_state,json=communityVisibilityState,proto3" json:"community_visibility_state,omitempty"`
+	CommentPermission        int32                  `protobuf:"varint,7,opt,name=comment_permission,json=commentPermission,proto3" json:"comment_permission,omitempty"`
+	Avatar                   string                 `protobuf:"bytes,8,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	AvatarMedium             string                 `protobuf:"bytes,9,opt,name=avatar_medium,json=avatarMedium,proto3" json:"avatar_medium,omitempty"`
+	AvatarFull               string                 `protobuf:"bytes,10,opt,name=avatar_full,json=avatarFull,proto3" json:"avatar_full,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *SteamUser) Reset() {
+	*x = SteamUser{}
+	mi := &file_authpb_steamauth_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SteamUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SteamUser) ProtoMessage() {}
+
+func (x *SteamUser) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SteamUser.ProtoReflect.Descriptor instead.
+func (*SteamUser) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SteamUser) GetSteamid() string {
+	if x != nil {
+		return x.Steamid
+	}
+	return ""
+}
+
+func (x *SteamUser) GetPersonaName() string {
+	if x != nil {
+		return x.PersonaName
+	}
+	return ""
+}
+
+func (x *SteamUser) GetPersonaState() int32 {
+	if x != nil {
+		return x.PersonaState
+	}
+	return 0
+}
+
+func (x *SteamUser) GetProfileUrl() string {
+	if x != nil {
+		return x.ProfileUrl
+	}
+	return ""
+}
+
+func (x *SteamUser) GetProfileState() int32 {
+	if x != nil {
+		return x.ProfileState
+	}
+	return 0
+}
+
+func (x *SteamUser) GetCommunityVisibilityState() int32 {
+	if x != nil {
+		return x.CommunityVisibilityState
+	}
+	return 0
+}
+
+func (x *SteamUser) GetCommentPermission() int32 {
+	if x != nil {
+		return x.CommentPermission
+	}
+	return 0
+}
+
+func (x *SteamUser) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+func (x *SteamUser) GetAvatarMedium() string {
+	if x != nil {
+		return x.AvatarMedium
+	}
+	return ""
+}
+
+func (x *SteamUser) GetAvatarFull() string {
+	if x != nil {
+		return x.AvatarFull
+	}
+	return ""
+}
+
+type ValidateTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTokenRequest) Reset() {
+	*x = ValidateTokenRequest{}
+	mi := &file_authpb_steamauth_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTokenRequest) ProtoMessage() {}
+
+func (x *ValidateTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTokenRequest.ProtoReflect.Descriptor instead.
+func (*ValidateTokenRequest) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValidateTokenRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ValidateTokenResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Steamid       string                 `protobuf:"bytes,1,opt,name=steamid,proto3" json:"steamid,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	PersonaName   string                 `protobuf:"bytes,3,opt,name=persona_name,json=personaName,proto3" json:"persona_name,omitempty"`
+	Avatar        string                 `protobuf:"bytes,4,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateTokenResponse) Reset() {
+	*x = ValidateTokenResponse{}
+	mi := &file_authpb_steamauth_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateTokenResponse) ProtoMessage() {}
+
+func (x *ValidateTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_authpb_steamauth_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateTokenResponse.ProtoReflect.Descriptor instead.
+func (*ValidateTokenResponse) Descriptor() ([]byte, []int) {
+	return file_authpb_steamauth_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ValidateTokenResponse) GetSteamid() string {
+	if x != nil {
+		return x.Steamid
+	}
+	return ""
+}
+
+func (x *ValidateTokenResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ValidateTokenResponse) GetPersonaName() string {
+	if x != nil {
+		return x.PersonaName
+	}
+	return ""
+}
+
+func (x *ValidateTokenResponse) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+var File_authpb_steamauth_proto protoreflect.FileDescriptor
+
+const file_authpb_steamauth_proto_rawDesc = "" +
+	"\n" +
+	"\x16authpb/steamauth.proto\x12\x0egosteamauth.v1\"2\n" +
+	"\x11GetAuthURLRequest\x12\x1d\n" +
+	"\n" +
+	"return_url\x18\x01 \x01(\tR\treturnUrl\"&\n" +
+	"\x12GetAuthURLResponse\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\"s\n" +
+	"\x17ValidateCallbackRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1f\n" +
+	"\vissue_token\x18\x02 \x01(\bR\n" +
+	"issueToken\x12!\n" +
+	"\fcallback_url\x18\x03 \x01(\tR\vcallbackUrl\"v\n" +
+	"\x18ValidateCallbackResponse\x12\x18\n" +
+	"\asteamid\x18\x01 \x01(\tR\asteamid\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\x12\x1d\n" +
+	"\n" +
+	"expires_in\x18\x03 \x01(\x03R\texpiresIn\"*\n" +
+	"\x0eGetUserRequest\x12\x18\n" +
+	"\asteamid\x18\x01 \x01(\tR\asteamid\"@\n" +
+	"\x0fGetUserResponse\x12-\n" +
+	"\x04user\x18\x01 \x01(\v2\x19.gosteamauth.v1.SteamUserR\x04user\"\xfe\x02\n" +
+	"\tSteamUser\x12\x18\n" +
+	"\asteamid\x18\x01 \x01(\tR\asteamid\x12!\n" +
+	"\fpersona_name\x18\x02 \x01(\tR\vpersonaName\x12#\n" +
+	"\rpersona_state\x18\x03 \x01(\x05R\fpersonaState\x12\x1f\n" +
+	"\vprofile_url\x18\x04 \x01(\tR\n" +
+	"profileUrl\x12#\n" +
+	"\rprofile_state\x18\x05 \x01(\x05R\fprofileState\x12<\n" +
+	"\x1acommunity_visibility_state\x18\x06 \x01(\x05R\x18communityVisibilityState\x12-\n" +
+	"\x12comment_permission\x18\a \x01(\x05R\x11commentPermission\x12\x16\n" +
+	"\x06avatar\x18\b \x01(\tR\x06avatar\x12#\n" +
+	"\ravatar_medium\x18\t \x01(\tR\favatarMedium\x12\x1f\n" +
+	"\vavatar_full\x18\n" +
+	" \x01(\tR\n" +
+	"avatarFull\",\n" +
+	"\x14ValidateTokenRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"\x8b\x01\n" +
+	"\x15ValidateTokenResponse\x12\x18\n" +
+	"\asteamid\x18\x01 \x01(\tR\asteamid\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\x12!\n" +
+	"\fpersona_name\x18\x03 \x01(\tR\vpersonaName\x12\x16\n" +
+	"\x06avatar\x18\x04 \x01(\tR\x06avatar2\xf8\x02\n" +
+	"\x10SteamAuthService\x12S\n" +
+	"\n" +
+	"GetAuthURL\x12!.gosteamauth.v1.GetAuthURLRequest\x1a\".gosteamauth.v1.GetAuthURLResponse\x12e\n" +
+	"\x10ValidateCallback\x12'.gosteamauth.v1.ValidateCallbackRequest\x1a(.gosteamauth.v1.ValidateCallbackResponse\x12J\n" +
+	"\aGetUser\x12\x1e.gosteamauth.v1.GetUserRequest\x1a\x1f.gosteamauth.v1.GetUserResponse\x12\\\n" +
+	"\rValidateToken\x12$.gosteamauth.v1.ValidateTokenRequest\x1a%.gosteamauth.v1.ValidateTokenResponseB8Z6github.com/liondadev/go-steam-auth/contrib/grpc/authpbb\x06proto3"
+
+var (
+	file_authpb_steamauth_proto_rawDescOnce sync.Once
+	file_authpb_steamauth_proto_rawDescData []byte
+)
+
+func file_authpb_steamauth_proto_rawDescGZIP() []byte {
+	file_authpb_steamauth_proto_rawDescOnce.Do(func() {
+		file_authpb_steamauth_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_authpb_steamauth_proto_rawDesc), len(file_authpb_steamauth_proto_rawDesc)))
+	})
+	return file_authpb_steamauth_proto_rawDescData
+}
+
+var file_authpb_steamauth_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_authpb_steamauth_proto_goTypes = []any{
+	(*GetAuthURLRequest)(nil),        // 0: gosteamauth.v1.GetAuthURLRequest
+	(*GetAuthURLResponse)(nil),       // 1: gosteamauth.v1.GetAuthURLResponse
+	(*ValidateCallbackRequest)(nil),  // 2: gosteamauth.v1.ValidateCallbackRequest
+	(*ValidateCallbackResponse)(nil), // 3: gosteamauth.v1.ValidateCallbackResponse
+	(*GetUserRequest)(nil),           // 4: gosteamauth.v1.GetUserRequest
+	(*GetUserResponse)(nil),          // 5: gosteamauth.v1.GetUserResponse
+	(*SteamUser)(nil),                // 6: gosteamauth.v1.SteamUser
+	(*ValidateTokenRequest)(nil),     // 7: gosteamauth.v1.ValidateTokenRequest
+	(*ValidateTokenResponse)(nil),    // 8: gosteamauth.v1.ValidateTokenResponse
+}
+var file_authpb_steamauth_proto_depIdxs = []int32{
+	6, // 0: gosteamauth.v1.GetUserResponse.user:type_name -> gosteamauth.v1.SteamUser
+	0, // 1: gosteamauth.v1.SteamAuthService.GetAuthURL:input_type -> gosteamauth.v1.GetAuthURLRequest
+	2, // 2: gosteamauth.v1.SteamAuthService.ValidateCallback:input_type -> gosteamauth.v1.ValidateCallbackRequest
+	4, // 3: gosteamauth.v1.SteamAuthService.GetUser:input_type -> gosteamauth.v1.GetUserRequest
+	7, // 4: gosteamauth.v1.SteamAuthService.ValidateToken:input_type -> gosteamauth.v1.ValidateTokenRequest
+	1, // 5: gosteamauth.v1.SteamAuthService.GetAuthURL:output_type -> gosteamauth.v1.GetAuthURLResponse
+	3, // 6: gosteamauth.v1.SteamAuthService.ValidateCallback:output_type -> gosteamauth.v1.ValidateCallbackResponse
+	5, // 7: gosteamauth.v1.SteamAuthService.GetUser:output_type -> gosteamauth.v1.GetUserResponse
+	8, // 8: gosteamauth.v1.SteamAuthService.ValidateToken:output_type -> gosteamauth.v1.ValidateTokenResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_authpb_steamauth_proto_init() }
+func file_authpb_steamauth_proto_init() {
+	if File_authpb_steamauth_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_authpb_steamauth_proto_rawDesc), len(file_authpb_steamauth_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_authpb_steamauth_proto_goTypes,
+		DependencyIndexes: file_authpb_steamauth_proto_depIdxs,
+		MessageInfos:      file_authpb_steamauth_proto_msgTypes,
+	}.Build()
+	File_authpb_steamauth_proto = out.File
+	file_authpb_steamauth_proto_goTypes = nil
+	file_authpb_steamauth_proto_depIdxs = nil
+}
@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: authpb/steamauth.proto
+
+package authpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SteamAuthService_GetAuthURL_FullMethodName       = "/gosteamauth.v1.SteamAuthService/GetAuthURL"
+	SteamAuthService_ValidateCallback_FullMethodName = "/gosteamauth.v1.SteamAuthService/ValidateCallback"
+	SteamAuthService_GetUser_FullMethodName          = "/gosteamauth.v1.SteamAuthService/GetUser"
+	SteamAuthService_ValidateToken_FullMethodName    = "/gosteamauth.v1.SteamAuthService/ValidateToken"
+)
+
+// SteamAuthServiceClient is the client API for SteamAuthService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SteamAuthService lets backends in any language delegate steam login to one go service.
+type SteamAuthServiceClient interface {
+	// GetAuthURL returns the url to send a user to, to start logging in with steam.
+	GetAuthURL(ctx context.Context, in *GetAuthURLRequest, opts ...grpc.CallOption) (*GetAuthURLResponse, error)
+	// ValidateCallback validates the query steam sent the user back with, and returns who they are.
+	ValidateCallback(ctx context.Context, in *ValidateCallbackRequest, opts ...grpc.CallOption) (*ValidateCallbackResponse, error)
+	// GetUser returns a user's steam profile.
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+	// ValidateToken checks an access token issued by ValidateCallback.
+	ValidateToken(ctx context.Context, in *ValidateTokenRequest, opts ...grpc.CallOption) (*ValidateTokenResponse, error)
+}
+
+type steamAuthServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSteamAuthServiceClient(cc grpc.ClientConnInterface) SteamAuthServiceClient {
+	return &steamAuthServiceClient{cc}
+}
+
+func (c *steamAuthServiceClient) GetAuthURL(ctx context.Context, in *GetAuthURLRequest, opts ...grpc.CallOption) (*GetAuthURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAuthURLResponse)
+	err := c.cc.Invoke(ctx, SteamAuthService_GetAuthURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *steamAuthServiceClient) ValidateCallback(ctx context.Context, in *ValidateCallbackRequest, opts ...grpc.CallOption) (*ValidateCallbackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateCallbackResponse)
+	err := c.cc.Invoke(ctx, SteamAuthService_ValidateCallback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *steamAuthServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, SteamAuthService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *steamAuthServiceClient) ValidateToken(ctx context.Context, in *ValidateTokenRequest, opts ...grpc.CallOption) (*ValidateTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateTokenResponse)
+	err := c.cc.Invoke(ctx, SteamAuthService_ValidateToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SteamAuthServiceServer is the server API for SteamAuthService service.
+// All implementations must embed UnimplementedSteamAuthServiceServer
+// for forward compatibility.
+//
+// SteamAuthService lets backends in any language delegate steam login to one go service.
+type SteamAuthServiceServer interface {
+	// GetAuthURL returns the url to send a user to, to start logging in with steam.
+	GetAuthURL(context.Context, *GetAuthURLRequest) (*GetAuthURLResponse, error)
+	// ValidateCallback validates the query steam sent the user back with, and returns who they are.
+	ValidateCallback(context.Context, *ValidateCallbackRequest) (*ValidateCallbackResponse, error)
+	// GetUser returns a user's steam profile.
+	GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)
+	// ValidateToken checks an access token issued by ValidateCallback.
+	ValidateToken(context.Context, *ValidateTokenRequest) (*ValidateTokenResponse, error)
+	mustEmbedUnimplementedSteamAuthServiceServer()
+}
+
+// UnimplementedSteamAuthServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSteamAuthServiceServer struct{}
+
+func (UnimplementedSteamAuthServiceServer) GetAuthURL(context.Context, *GetAuthURLRequest) (*GetAuthURLResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAuthURL not implemented")
+}
+func (UnimplementedSteamAuthServiceServer) ValidateCallback(context.Context, *ValidateCallbackRequest) (*ValidateCallbackResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ValidateCallback not implemented")
+}
+func (UnimplementedSteamAuthServiceServer) GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedSteamAuthServiceServer) ValidateToken(context.Context, *ValidateTokenRequest) (*ValidateTokenResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ValidateToken not implemented")
+}
+func (UnimplementedSteamAuthServiceServer) mustEmbedUnimplementedSteamAuthServiceServer() {}
+func (UnimplementedSteamAuthServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeSteamAuthServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SteamAuthServiceServer will
+// result in compilation errors.
+type UnsafeSteamAuthServiceServer interface {
+	mustEmbedUnimplementedSteamAuthServiceServer()
+}
+
+func RegisterSteamAuthServiceServer(s grpc.ServiceRegistrar, srv SteamAuthServiceServer) {
+	// If the following call panics, it indicates UnimplementedSteamAuthServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SteamAuthService_ServiceDesc, srv)
+}
+
+func _SteamAuthService_GetAuthURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAuthURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SteamAuthServiceServer).GetAuthURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SteamAuthService_GetAuthURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SteamAuthServiceServer).GetAuthURL(ctx, req.(*GetAuthURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SteamAuthService_ValidateCallback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateCallbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SteamAuthServiceServer).ValidateCallback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SteamAuthService_ValidateCallback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SteamAuthServiceServer).ValidateCallback(ctx, req.(*ValidateCallbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SteamAuthService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SteamAuthServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SteamAuthService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SteamAuthServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SteamAuthService_ValidateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SteamAuthServiceServer).ValidateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SteamAuthService_ValidateToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SteamAuthServiceServer).ValidateToken(ctx, req.(*ValidateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SteamAuthService_ServiceDesc is the grpc.ServiceDesc for SteamAuthService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SteamAuthService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gosteamauth.v1.SteamAuthService",
+	HandlerType: (*SteamAuthServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAuthURL",
+			Handler:    _SteamAuthService_GetAuthURL_Handler,
+		},
+		{
+			MethodName: "ValidateCallback",
+			Handler:    _SteamAuthService_ValidateCallback_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _SteamAuthService_GetUser_Handler,
+		},
+		{
+			MethodName: "ValidateToken",
+			Handler:    _SteamAuthService_ValidateToken_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "authpb/steamauth.proto",
+}
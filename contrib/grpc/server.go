@@ -0,0 +1,153 @@
+// Package grpcauth serves the steam login flow over gRPC, so backends written in other languages can delegate steam
+// authentication to a single go service. The service is defined in authpb/steamauth.proto; generate clients for
+// other languages from that.
+//
+//	s := grpc.NewServer()
+//	authpb.RegisterSteamAuthServiceServer(s, grpcauth.NewServer(auther, issuer, verifier))
+package grpcauth
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative authpb/steamauth.proto
+
+import (
+	"context"
+	"errors"
+	"net/url"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+	"github.com/liondadev/go-steam-auth/contrib/grpc/authpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements authpb.SteamAuthServiceServer.
+type Server struct {
+	authpb.UnimplementedSteamAuthServiceServer
+
+	auther   *gosteamauth.SteamAuther
+	issuer   *gosteamauth.TokenIssuer
+	verifier *gosteamauth.TokenVerifier
+}
+
+// NewServer returns a new Server. issuer and verifier may be nil, in which case ValidateCallback won't issue tokens
+// and ValidateToken returns Unimplemented.
+func NewServer(auther *gosteamauth.SteamAuther, issuer *gosteamauth.TokenIssuer, verifier *gosteamauth.TokenVerifier) *Server {
+	return &Server{
+		auther:   auther,
+		issuer:   issuer,
+		verifier: verifier,
+	}
+}
+
+// GetAuthURL implements authpb.SteamAuthServiceServer.
+func (s *Server) GetAuthURL(_ context.Context, req *authpb.GetAuthURLRequest) (*authpb.GetAuthURLResponse, error) {
+	if req.GetReturnUrl() == "" {
+		return nil, status.Error(codes.InvalidArgument, "return_url is required")
+	}
+
+	u, err := s.auther.GetAuthUrl(req.GetReturnUrl())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &authpb.GetAuthURLResponse{Url: u}, nil
+}
+
+// ValidateCallback implements authpb.SteamAuthServiceServer.
+func (s *Server) ValidateCallback(ctx context.Context, req *authpb.ValidateCallbackRequest) (*authpb.ValidateCallbackResponse, error) {
+	if req.GetCallbackUrl() == "" {
+		return nil, status.Error(codes.InvalidArgument, "callback_url is required")
+	}
+
+	q, err := url.ParseQuery(req.GetQuery())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "query is not a valid query string")
+	}
+
+	if _, err := url.Parse(req.GetCallbackUrl()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "callback_url is not a valid url")
+	}
+
+	steamid, _, err := s.auther.ValidateCallbackFor(ctx, q, req.GetCallbackUrl())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	res := &authpb.ValidateCallbackResponse{Steamid: steamid}
+	if req.GetIssueToken() && s.issuer != nil {
+		res.AccessToken, err = s.issuer.Issue(steamid, nil)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to issue token")
+		}
+		res.ExpiresIn = int64(s.issuer.TTL.Seconds())
+	}
+
+	return res, nil
+}
+
+// GetUser implements authpb.SteamAuthServiceServer.
+func (s *Server) GetUser(_ context.Context, req *authpb.GetUserRequest) (*authpb.GetUserResponse, error) {
+	if req.GetSteamid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "steamid is required")
+	}
+
+	u, err := s.auther.GetSteamUser(req.GetSteamid())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &authpb.GetUserResponse{User: toProtoUser(u)}, nil
+}
+
+// ValidateToken implements authpb.SteamAuthServiceServer.
+func (s *Server) ValidateToken(_ context.Context, req *authpb.ValidateTokenRequest) (*authpb.ValidateTokenResponse, error) {
+	if s.verifier == nil {
+		return nil, status.Error(codes.Unimplemented, "this server doesn't verify tokens")
+	}
+
+	c, err := s.verifier.Verify(req.GetToken())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &authpb.ValidateTokenResponse{
+		Steamid:     c.Subject,
+		ExpiresAt:   c.ExpiresAt,
+		PersonaName: c.PersonaName,
+		Avatar:      c.Avatar,
+	}, nil
+}
+
+// toStatus maps the package's errors onto gRPC status codes.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, gosteamauth.ErrInvalidAuthRequest), errors.Is(err, gosteamauth.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, gosteamauth.ErrNoData):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, gosteamauth.ErrSteamForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, gosteamauth.ErrPrivateProfile):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, gosteamauth.ErrSteamUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, gosteamauth.ErrSteamRateLimited):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func toProtoUser(u *gosteamauth.SteamUser) *authpb.SteamUser {
+	return &authpb.SteamUser{
+		Steamid:                  u.SteamID,
+		PersonaName:              u.PersonaName,
+		PersonaState:             int32(u.PersonaState),
+		ProfileUrl:               u.ProfileUrl,
+		ProfileState:             int32(u.ProfileState),
+		CommunityVisibilityState: int32(u.CommunityVisibilityStatus),
+		CommentPermission:        int32(u.CommentPermission),
+		Avatar:                   u.Avatar,
+		AvatarMedium:             u.AvatarMedium,
+		AvatarFull:               u.AvatarFull,
+	}
+}
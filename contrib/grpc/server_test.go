@@ -0,0 +1,183 @@
+package grpcauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+	"github.com/liondadev/go-steam-auth/contrib/grpc/authpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	steamid     = "76561197960287930"
+	callbackUrl = "https://example.com/auth/callback"
+)
+
+// fakeSteam sends every request made with the default http client to h instead of steam, for the rest of the test.
+func fakeSteam(t *testing.T, h http.HandlerFunc) {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	old := http.DefaultClient.Transport
+	http.DefaultClient.Transport = rewriteTransport{target: target}
+	t.Cleanup(func() { http.DefaultClient.Transport = old })
+}
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func callbackQuery(returnTo string) string {
+	return url.Values{
+		"openid.ns":             {"http://specs.openid.net/auth/2.0"},
+		"openid.mode":           {"id_res"},
+		"openid.claimed_id":     {"https://steamcommunity.com/openid/id/" + steamid},
+		"openid.identity":       {"https://steamcommunity.com/openid/id/" + steamid},
+		"openid.return_to":      {returnTo},
+		"openid.response_nonce": {"2024-01-01T00:00:00Zabc"},
+		"openid.signed":         {"signed,claimed_id,identity,return_to,response_nonce"},
+		"openid.sig":            {"c2lnbmF0dXJl"},
+	}.Encode()
+}
+
+func TestValidateCallback(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:true\n"))
+	})
+
+	s := NewServer(gosteamauth.New("", "https://example.com"), gosteamauth.NewHS256Issuer([]byte("secret")), nil)
+
+	tests := []struct {
+		name     string
+		req      *authpb.ValidateCallbackRequest
+		wantCode codes.Code
+	}{
+		{"valid", &authpb.ValidateCallbackRequest{Query: callbackQuery(callbackUrl), CallbackUrl: callbackUrl, IssueToken: true}, codes.OK},
+		{"no callback url", &authpb.ValidateCallbackRequest{Query: callbackQuery(callbackUrl)}, codes.InvalidArgument},
+		{"bad query", &authpb.ValidateCallbackRequest{Query: "%zz", CallbackUrl: callbackUrl}, codes.InvalidArgument},
+		{"other site's callback", &authpb.ValidateCallbackRequest{Query: callbackQuery("https://other.example.com/auth/callback"), CallbackUrl: callbackUrl}, codes.Unauthenticated},
+		{"other path", &authpb.ValidateCallbackRequest{Query: callbackQuery("https://example.com/other"), CallbackUrl: callbackUrl}, codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := s.ValidateCallback(context.Background(), tt.req)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %v (%v), want %v", got, err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+
+			if res.GetSteamid() != steamid {
+				t.Errorf("steamid = %q, want %q", res.GetSteamid(), steamid)
+			}
+			if res.GetAccessToken() == "" || res.GetExpiresIn() == 0 {
+				t.Errorf("res = %v, want an access token", res)
+			}
+		})
+	}
+}
+
+func TestValidateCallbackRejectedBySteam(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:false\n"))
+	})
+
+	s := NewServer(gosteamauth.New("", "https://example.com"), nil, nil)
+	_, err := s.ValidateCallback(context.Background(), &authpb.ValidateCallbackRequest{Query: callbackQuery(callbackUrl), CallbackUrl: callbackUrl})
+	if got := status.Code(err); got != codes.Unauthenticated {
+		t.Errorf("code = %v (%v), want Unauthenticated", got, err)
+	}
+}
+
+func TestGetAuthURL(t *testing.T) {
+	s := NewServer(gosteamauth.New("", "https://example.com"), nil, nil)
+
+	if _, err := s.GetAuthURL(context.Background(), &authpb.GetAuthURLRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("empty return_url code = %v, want InvalidArgument", status.Code(err))
+	}
+
+	res, err := s.GetAuthURL(context.Background(), &authpb.GetAuthURLRequest{ReturnUrl: callbackUrl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(res.GetUrl())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("openid.return_to") != callbackUrl {
+		t.Errorf("url = %q, want it to return to %q", res.GetUrl(), callbackUrl)
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	tok, err := gosteamauth.NewHS256Issuer([]byte("secret")).Issue(steamid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		s        *Server
+		token    string
+		wantCode codes.Code
+	}{
+		{"valid", NewServer(nil, nil, gosteamauth.NewHS256Verifier([]byte("secret"))), tok, codes.OK},
+		{"wrong secret", NewServer(nil, nil, gosteamauth.NewHS256Verifier([]byte("other"))), tok, codes.Unauthenticated},
+		{"no verifier", NewServer(nil, nil, nil), tok, codes.Unimplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tt.s.ValidateToken(context.Background(), &authpb.ValidateTokenRequest{Token: tt.token})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %v (%v), want %v", got, err, tt.wantCode)
+			}
+			if err == nil && res.GetSteamid() != steamid {
+				t.Errorf("steamid = %q, want %q", res.GetSteamid(), steamid)
+			}
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{gosteamauth.ErrInvalidAuthRequest, codes.Unauthenticated},
+		{fmt.Errorf("verify: %w", gosteamauth.ErrInvalidToken), codes.Unauthenticated},
+		{gosteamauth.ErrNoData, codes.NotFound},
+		{fmt.Errorf("get user group list: %w", gosteamauth.ErrSteamForbidden), codes.PermissionDenied},
+		{fmt.Errorf("get user group list: %w", gosteamauth.ErrPrivateProfile), codes.FailedPrecondition},
+		{fmt.Errorf("validate callback: %w: %w", gosteamauth.ErrSteamUnavailable, errors.New("dial tcp: connection refused")), codes.Unavailable},
+		{fmt.Errorf("get inventory: %w", gosteamauth.ErrSteamRateLimited), codes.ResourceExhausted},
+		{errors.New("decode response body: unexpected EOF"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if got := status.Code(toStatus(tt.err)); got != tt.want {
+				t.Errorf("toStatus(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// EconAsset is an item in an inventory or trade, as represented in the responses from the economy web apis.
+type EconAsset struct {
+	AppID     int    `json:"appid"`
+	ContextID string `json:"contextid"`
+	AssetID   string `json:"assetid"`
+	// ClassID and InstanceID say which EconDescription describes the item.
+	ClassID    string `json:"classid"`
+	InstanceID string `json:"instanceid"`
+	// Amount is how many of the item there are, for stackable items.
+	Amount string `json:"amount"`
+	// Missing is true if the item is no longer in the inventory it was offered from.
+	Missing bool `json:"missing"`
+}
+
+// EconDescription is what an item looks like and can do, shared by every item of its class and instance.
+type EconDescription struct {
+	AppID      int    `json:"appid"`
+	ClassID    string `json:"classid"`
+	InstanceID string `json:"instanceid"`
+	// IconUrl and IconUrlLarge are paths, see EconDescription.ImageUrl.
+	IconUrl         string `json:"icon_url"`
+	IconUrlLarge    string `json:"icon_url_large"`
+	Name            string `json:"name"`
+	NameColor       string `json:"name_color"`
+	BackgroundColor string `json:"background_color"`
+	// Type is the item's type line (ex. "Mil-Spec Grade Rifle").
+	Type string `json:"type"`
+	// MarketName is the item's name on the community market, and MarketHashName the name to look it up by.
+	MarketName     string `json:"market_name"`
+	MarketHashName string `json:"market_hash_name"`
+	Tradable       int    `json:"tradable"`
+	Marketable     int    `json:"marketable"`
+	Commodity      int    `json:"commodity"`
+	Descriptions   []struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+		Color string `json:"color"`
+	} `json:"descriptions"`
+	Tags []struct {
+		Category              string `json:"category"`
+		InternalName          string `json:"internal_name"`
+		LocalizedCategoryName string `json:"localized_category_name"`
+		LocalizedTagName      string `json:"localized_tag_name"`
+	} `json:"tags"`
+}
+
+// EconImagesBaseUrl is where item icons are, EconDescription's icon paths are relative to it.
+const EconImagesBaseUrl = "https://community.fastly.steamstatic.com/economy/image/"
+
+// ImageUrl turns one of the description's icon paths into a full url, or returns "" if the path is empty.
+func (*EconDescription) ImageUrl(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	return EconImagesBaseUrl + path
+}
+
+// TradeOfferState is where a trade offer is at, steam's ETradeOfferState.
+type TradeOfferState int
+
+const (
+	TradeOfferStateInvalid                  TradeOfferState = 1
+	TradeOfferStateActive                   TradeOfferState = 2
+	TradeOfferStateAccepted                 TradeOfferState = 3
+	TradeOfferStateCountered                TradeOfferState = 4
+	TradeOfferStateExpired                  TradeOfferState = 5
+	TradeOfferStateCanceled                 TradeOfferState = 6
+	TradeOfferStateDeclined                 TradeOfferState = 7
+	TradeOfferStateInvalidItems             TradeOfferState = 8
+	TradeOfferStateCreatedNeedsConfirmation TradeOfferState = 9
+	TradeOfferStateCanceledBySecondFactor   TradeOfferState = 10
+	TradeOfferStateInEscrow                 TradeOfferState = 11
+)
+
+// TradeOffer is a trade offer, as represented in the responses from the IEconService web apis.
+type TradeOffer struct {
+	TradeOfferID string `json:"tradeofferid"`
+	// AccountIDOther is the account id of who the offer is with, see TradeOffer.OtherSteamID.
+	AccountIDOther uint32          `json:"accountid_other"`
+	Message        string          `json:"message"`
+	State          TradeOfferState `json:"trade_offer_state"`
+	ItemsToGive    []EconAsset     `json:"items_to_give"`
+	ItemsToReceive []EconAsset     `json:"items_to_receive"`
+	// IsOurOffer is true if the api key's account sent the offer.
+	IsOurOffer bool `json:"is_our_offer"`
+	// ExpirationTime, TimeCreated, TimeUpdated and EscrowEndDate are unix timestamps.
+	ExpirationTime int64 `json:"expiration_time"`
+	TimeCreated    int64 `json:"time_created"`
+	TimeUpdated    int64 `json:"time_updated"`
+	EscrowEndDate  int64 `json:"escrow_end_date"`
+	// TradeID is the id of the trade the offer turned into, once it's accepted.
+	TradeID            string `json:"tradeid"`
+	FromRealTimeTrade  bool   `json:"from_real_time_trade"`
+	ConfirmationMethod int    `json:"confirmation_method"`
+}
+
+// OtherSteamID returns the steamid64 of who the offer is with.
+func (o *TradeOffer) OtherSteamID() string {
+	return NewIndividualSteamID(o.AccountIDOther).String()
+}
+
+// Expires returns when the offer expires.
+func (o *TradeOffer) Expires() time.Time {
+	return time.Unix(o.ExpirationTime, 0)
+}
+
+// TradeOffersFilter is what to ask GetTradeOffers for.
+type TradeOffersFilter struct {
+	Sent     bool
+	Received bool
+	// ActiveOnly only gets offers that are still active, and ones that changed since HistoricalCutoff.
+	ActiveOnly       bool
+	HistoricalOnly   bool
+	HistoricalCutoff time.Time
+	// Descriptions gets the descriptions of the offers' items too, in Language (ex. "english").
+	Descriptions bool
+	Language     string
+}
+
+// TradeOffers is a page of the api key's account's trade offers, as represented in the response from the
+// GetTradeOffers web api.
+type TradeOffers struct {
+	Sent     []TradeOffer `json:"trade_offers_sent"`
+	Received []TradeOffer `json:"trade_offers_received"`
+	// Descriptions are the descriptions of the offers' items, if they were asked for. See TradeOffers.Description.
+	Descriptions []EconDescription `json:"descriptions"`
+	// NextCursor is what to pass to GetTradeOffers to get the next page, or 0 if this is the last one.
+	NextCursor int `json:"next_cursor"`
+}
+
+// Description returns the description of the item, and false if there isn't one.
+func (t *TradeOffers) Description(asset EconAsset) (*EconDescription, bool) {
+	return findDescription(t.Descriptions, asset)
+}
+
+// GetTradeOffers gets a page of the trade offers of the account the api key belongs to. Pass 0 as cursor to get the
+// first page, and the page's NextCursor to get the next one.
+func (sa *SteamAuther) GetTradeOffers(ctx context.Context, filter TradeOffersFilter, cursor int) (*TradeOffers, error) {
+	q := url.Values{}
+	q.Set("get_sent_offers", strconv.FormatBool(filter.Sent))
+	q.Set("get_received_offers", strconv.FormatBool(filter.Received))
+	q.Set("active_only", strconv.FormatBool(filter.ActiveOnly))
+	q.Set("historical_only", strconv.FormatBool(filter.HistoricalOnly))
+	q.Set("get_descriptions", strconv.FormatBool(filter.Descriptions))
+	if !filter.HistoricalCutoff.IsZero() {
+		q.Set("time_historical_cutoff", strconv.FormatInt(filter.HistoricalCutoff.Unix(), 10))
+	}
+	if filter.Language != "" {
+		q.Set("language", filter.Language)
+	}
+	if cursor > 0 {
+		q.Set("cursor", strconv.Itoa(cursor))
+	}
+
+	var data struct {
+		Response TradeOffers `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IEconService/GetTradeOffers/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get trade offers: %w", err)
+	}
+
+	return &data.Response, nil
+}
+
+// GetTradeOffer gets one of the trade offers of the account the api key belongs to, and the descriptions of its items
+// in lang (ex. "english"). Returns ErrNoData if there's no such offer.
+func (sa *SteamAuther) GetTradeOffer(ctx context.Context, tradeOfferId, lang string) (*TradeOffer, []EconDescription, error) {
+	q := url.Values{"tradeofferid": {tradeOfferId}, "get_descriptions": {"true"}}
+	if lang != "" {
+		q.Set("language", lang)
+	}
+
+	var data struct {
+		Response struct {
+			Offer        *TradeOffer       `json:"offer"`
+			Descriptions []EconDescription `json:"descriptions"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IEconService/GetTradeOffer/v1", q, &data); err != nil {
+		return nil, nil, fmt.Errorf("get trade offer (%s): %w", tradeOfferId, err)
+	}
+
+	if data.Response.Offer == nil {
+		return nil, nil, fmt.Errorf("get trade offer (%s): %w", tradeOfferId, ErrNoData)
+	}
+
+	return data.Response.Offer, data.Response.Descriptions, nil
+}
+
+// DeclineTradeOffer declines a trade offer sent to the account the api key belongs to.
+func (sa *SteamAuther) DeclineTradeOffer(ctx context.Context, tradeOfferId string) error {
+	var data struct{}
+	if err := sa.postApi(ctx, "IEconService/DeclineTradeOffer/v1", url.Values{"tradeofferid": {tradeOfferId}}, &data); err != nil {
+		return fmt.Errorf("decline trade offer (%s): %w", tradeOfferId, err)
+	}
+
+	return nil
+}
+
+// CancelTradeOffer cancels a trade offer sent by the account the api key belongs to.
+func (sa *SteamAuther) CancelTradeOffer(ctx context.Context, tradeOfferId string) error {
+	var data struct{}
+	if err := sa.postApi(ctx, "IEconService/CancelTradeOffer/v1", url.Values{"tradeofferid": {tradeOfferId}}, &data); err != nil {
+		return fmt.Errorf("cancel trade offer (%s): %w", tradeOfferId, err)
+	}
+
+	return nil
+}
+
+// findDescription finds the description of asset in descriptions.
+func findDescription(descriptions []EconDescription, asset EconAsset) (*EconDescription, bool) {
+	for i, d := range descriptions {
+		if d.ClassID == asset.ClassID && d.InstanceID == asset.InstanceID {
+			return &descriptions[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// TradeHoldDurations is how long items would be held in escrow if a trade went ahead now.
+type TradeHoldDurations struct {
+	// Mine is the hold on the api key's account's side, Theirs on the other user's, and Both how long the trade
+	// would be held for.
+	Mine   time.Duration
+	Theirs time.Duration
+	Both   time.Duration
+}
+
+// GetTradeHoldDurations gets how long a trade between the account the api key belongs to and the user would be held
+// in escrow, so it can be shown before the trade is sent. accessToken is the token from the user's trade url, and can
+// be "" if they're friends with the account.
+func (sa *SteamAuther) GetTradeHoldDurations(ctx context.Context, steamid64, accessToken string) (*TradeHoldDurations, error) {
+	q := url.Values{"steamid_target": {steamid64}}
+	if accessToken != "" {
+		q.Set("trade_offer_access_token", accessToken)
+	}
+
+	type escrow struct {
+		Seconds int64 `json:"escrow_end_duration_seconds"`
+	}
+	var data struct {
+		Response struct {
+			MyEscrow    escrow `json:"my_escrow"`
+			TheirEscrow escrow `json:"their_escrow"`
+			BothEscrow  escrow `json:"both_escrow"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IEconService/GetTradeHoldDurations/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get trade hold durations (%s): %w", steamid64, err)
+	}
+
+	return &TradeHoldDurations{
+		Mine:   time.Duration(data.Response.MyEscrow.Seconds) * time.Second,
+		Theirs: time.Duration(data.Response.TheirEscrow.Seconds) * time.Second,
+		Both:   time.Duration(data.Response.BothEscrow.Seconds) * time.Second,
+	}, nil
+}
+
+// AssetPrice is an item's price in a game's in-game store, as represented in the response from the GetAssetPrices web
+// api.
+type AssetPrice struct {
+	ClassID string `json:"classid"`
+	Name    string `json:"name"`
+	// Prices and OriginalPrices are keyed by currency code (ex. "USD"), in the currency's smallest unit (ex. cents).
+	// OriginalPrices are the prices before any sale.
+	Prices         map[string]int `json:"prices"`
+	OriginalPrices map[string]int `json:"original_prices"`
+	// Date is when the item was added to the store (ex. "2012/01/01").
+	Date  string `json:"date"`
+	Class []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"class"`
+}
+
+// GetAssetPrices gets the prices of the items in a game's in-game store. currency only gets prices in that currency
+// (ex. "USD"), and lang is the language to get item names in (ex. "english"), either can be "".
+func (sa *SteamAuther) GetAssetPrices(ctx context.Context, appid int, currency, lang string) ([]AssetPrice, error) {
+	q := url.Values{"appid": {strconv.Itoa(appid)}}
+	if currency != "" {
+		q.Set("currency", currency)
+	}
+	if lang != "" {
+		q.Set("language", lang)
+	}
+
+	var data struct {
+		Result struct {
+			Success bool         `json:"success"`
+			Error   string       `json:"error"`
+			Assets  []AssetPrice `json:"assets"`
+		} `json:"result"`
+	}
+	if err := sa.getApi(ctx, "ISteamEconomy/GetAssetPrices/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get asset prices (%d): %w", appid, err)
+	}
+
+	if !data.Result.Success {
+		return nil, fmt.Errorf("get asset prices (%d): steam said %q", appid, data.Result.Error)
+	}
+
+	return data.Result.Assets, nil
+}
+
+// AssetClassInfo is what a class of item is called and looks like, as represented in the response from the
+// GetAssetClassInfo web api. Steam sends the flags as strings ("0" or "1").
+type AssetClassInfo struct {
+	ClassID string `json:"classid"`
+	// IconUrl and IconUrlLarge are paths relative to EconImagesBaseUrl.
+	IconUrl         string `json:"icon_url"`
+	IconUrlLarge    string `json:"icon_url_large"`
+	Name            string `json:"name"`
+	MarketName      string `json:"market_name"`
+	MarketHashName  string `json:"market_hash_name"`
+	NameColor       string `json:"name_color"`
+	BackgroundColor string `json:"background_color"`
+	Type            string `json:"type"`
+	Tradable        string `json:"tradable"`
+	Marketable      string `json:"marketable"`
+	Commodity       string `json:"commodity"`
+}
+
+// GetAssetClassInfo gets the names and icons of classes of a game's items, keyed by class id, in lang (ex. "english").
+// Class ids steam doesn't know about are left out.
+func (sa *SteamAuther) GetAssetClassInfo(ctx context.Context, appid int, lang string, classIds ...string) (map[string]*AssetClassInfo, error) {
+	q := url.Values{"appid": {strconv.Itoa(appid)}, "class_count": {strconv.Itoa(len(classIds))}}
+	for i, id := range classIds {
+		q.Set("classid"+strconv.Itoa(i), id)
+	}
+	if lang != "" {
+		q.Set("language", lang)
+	}
+
+	// The classes are keyed by class id, right next to a success field.
+	var data struct {
+		Result map[string]json.RawMessage `json:"result"`
+	}
+	if err := sa.getApi(ctx, "ISteamEconomy/GetAssetClassInfo/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get asset class info (%d): %w", appid, err)
+	}
+
+	var success bool
+	if err := json.Unmarshal(data.Result["success"], &success); err != nil || !success {
+		return nil, fmt.Errorf("get asset class info (%d): steam said it wasn't successful", appid)
+	}
+
+	classes := make(map[string]*AssetClassInfo, len(classIds))
+	for _, id := range classIds {
+		raw, ok := data.Result[id]
+		if !ok {
+			continue
+		}
+
+		var info AssetClassInfo
+		if err := json.Unmarshal(raw, &info); err != nil {
+			return nil, fmt.Errorf("get asset class info (%d): decode class %s: %w", appid, id, err)
+		}
+		classes[id] = &info
+	}
+
+	return classes, nil
+}
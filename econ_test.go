@@ -0,0 +1,272 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// tradeOffers answers the IEconService trade offer methods like steam would, for an account with one sent offer
+// (1) and one received offer (2), in pages of one.
+func tradeOffers(w http.ResponseWriter, r *http.Request) {
+	const sent = `{"tradeofferid":"1","accountid_other":2165,"trade_offer_state":2,"is_our_offer":true,"expiration_time":1700000000,
+		"items_to_give":[{"appid":440,"contextid":"2","assetid":"100","classid":"10","instanceid":"0","amount":"1"}]}`
+	const received = `{"tradeofferid":"2","accountid_other":1,"trade_offer_state":2,
+		"items_to_receive":[{"appid":440,"contextid":"2","assetid":"200","classid":"20","instanceid":"5","amount":"1"}]}`
+	const descriptions = `"descriptions":[
+		{"appid":440,"classid":"10","instanceid":"0","name":"Mann Co. Supply Crate Key","icon_url":"key.png","tradable":1},
+		{"appid":440,"classid":"20","instanceid":"5","name":"Strange Rocket Launcher","tradable":1}
+	]`
+
+	switch r.URL.Path {
+	case "/IEconService/GetTradeOffers/v1":
+		q := r.URL.Query()
+		var offers []string
+		if q.Get("get_sent_offers") == "true" && q.Get("cursor") == "" {
+			offers = append(offers, `"trade_offers_sent":[`+sent+`],"next_cursor":1`)
+		}
+		if q.Get("get_received_offers") == "true" && (q.Get("cursor") == "1" || q.Get("get_sent_offers") != "true") {
+			offers = append(offers, `"trade_offers_received":[`+received+`]`)
+		}
+		if q.Get("get_descriptions") == "true" {
+			offers = append(offers, descriptions)
+		}
+		body := `{"response":{`
+		for i, o := range offers {
+			if i > 0 {
+				body += ","
+			}
+			body += o
+		}
+		w.Write([]byte(body + `}}`))
+	case "/IEconService/GetTradeOffer/v1":
+		switch r.URL.Query().Get("tradeofferid") {
+		case "1":
+			w.Write([]byte(`{"response":{"offer":` + sent + `,` + descriptions + `}}`))
+		default:
+			w.Write([]byte(`{"response":{}}`))
+		}
+	case "/IEconService/DeclineTradeOffer/v1", "/IEconService/CancelTradeOffer/v1":
+		if r.Method != http.MethodPost || r.PostFormValue("tradeofferid") != "1" {
+			http.Error(w, "no such offer", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"response":{}}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestGetTradeOffers(t *testing.T) {
+	fakeSteam(t, tradeOffers)
+	sa := New("key", "https://example.com")
+	filter := TradeOffersFilter{Sent: true, Received: true, ActiveOnly: true, Descriptions: true, Language: "english"}
+
+	page, err := sa.GetTradeOffers(context.Background(), filter, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Sent) != 1 || len(page.Received) != 0 || page.NextCursor != 1 {
+		t.Fatalf("first page = %+v, want the sent offer and a cursor", page)
+	}
+
+	o := page.Sent[0]
+	if o.OtherSteamID() != "76561197960267893" {
+		t.Errorf("OtherSteamID = %s, want the steamid64 of account 2165", o.OtherSteamID())
+	}
+	if !o.Expires().Equal(time.Unix(1700000000, 0)) || o.State != TradeOfferStateActive {
+		t.Errorf("offer = %+v, want an active offer expiring at 1700000000", o)
+	}
+	d, ok := page.Description(o.ItemsToGive[0])
+	if !ok || d.Name != "Mann Co. Supply Crate Key" {
+		t.Errorf("Description = %+v, %v, want the key", d, ok)
+	}
+	if got := d.ImageUrl(d.IconUrl); got != EconImagesBaseUrl+"key.png" {
+		t.Errorf("ImageUrl = %q, want it under EconImagesBaseUrl", got)
+	}
+	if got := d.ImageUrl(d.IconUrlLarge); got != "" {
+		t.Errorf("ImageUrl of an empty path = %q, want \"\"", got)
+	}
+
+	page, err = sa.GetTradeOffers(context.Background(), filter, page.NextCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Received) != 1 || page.NextCursor != 0 {
+		t.Errorf("second page = %+v, want the received offer and no cursor", page)
+	}
+	if _, ok := page.Description(EconAsset{ClassID: "20", InstanceID: "6"}); ok {
+		t.Error("Description ok for another instance of the class, want false")
+	}
+}
+
+func TestGetTradeOffer(t *testing.T) {
+	fakeSteam(t, tradeOffers)
+	sa := New("key", "https://example.com")
+
+	o, descriptions, err := sa.GetTradeOffer(context.Background(), "1", "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.TradeOfferID != "1" || len(descriptions) != 2 {
+		t.Errorf("offer = %+v with %d descriptions, want offer 1 with both", o, len(descriptions))
+	}
+
+	if _, _, err := sa.GetTradeOffer(context.Background(), "3", ""); !errors.Is(err, ErrNoData) {
+		t.Errorf("missing offer err = %v, want ErrNoData", err)
+	}
+}
+
+func TestDeclineAndCancelTradeOffer(t *testing.T) {
+	fakeSteam(t, tradeOffers)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name string
+		do   func(context.Context, string) error
+	}{
+		{"decline", sa.DeclineTradeOffer},
+		{"cancel", sa.CancelTradeOffer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.do(context.Background(), "1"); err != nil {
+				t.Errorf("err = %v, want nil", err)
+			}
+			if err := tt.do(context.Background(), "3"); !errors.Is(err, ErrSteamUnavailable) {
+				t.Errorf("missing offer err = %v, want ErrSteamUnavailable", err)
+			}
+		})
+	}
+}
+
+// the key has to go in the form for POSTs, steam ignores it in the query
+func TestPostApiSendsKeyInForm(t *testing.T) {
+	var form url.Values
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		form = r.PostForm
+		if r.URL.RawQuery != "" || r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"response":{}}`))
+	})
+
+	if err := New("key", "https://example.com").DeclineTradeOffer(context.Background(), "1"); err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("key") != "key" || form.Get("tradeofferid") != "1" {
+		t.Errorf("form = %v, want the key and offer id", form)
+	}
+}
+
+func TestGetTradeHoldDurations(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/IEconService/GetTradeHoldDurations/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		// steam wants the trade url token unless they're friends
+		switch {
+		case q.Get("steamid_target") == "76561197960287930" && q.Get("trade_offer_access_token") == "abc123":
+			w.Write([]byte(`{"response":{"my_escrow":{"escrow_end_duration_seconds":0},"their_escrow":{"escrow_end_duration_seconds":1296000},"both_escrow":{"escrow_end_duration_seconds":1296000}}}`))
+		case q.Get("steamid_target") == "76561197960287931" && !q.Has("trade_offer_access_token"):
+			w.Write([]byte(`{"response":{"my_escrow":{"escrow_end_duration_seconds":0},"their_escrow":{"escrow_end_duration_seconds":0},"both_escrow":{"escrow_end_duration_seconds":0}}}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name    string
+		steamid string
+		token   string
+		want    TradeHoldDurations
+		wantErr error
+	}{
+		{"no mobile authenticator", "76561197960287930", "abc123", TradeHoldDurations{Theirs: 15 * 24 * time.Hour, Both: 15 * 24 * time.Hour}, nil},
+		{"friends", "76561197960287931", "", TradeHoldDurations{}, nil},
+		{"wrong token", "76561197960287930", "nope", TradeHoldDurations{}, ErrSteamForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sa.GetTradeHoldDurations(context.Background(), tt.steamid, tt.token)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("GetTradeHoldDurations = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAssetPrices(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/ISteamEconomy/GetAssetPrices/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if q.Get("appid") != "440" {
+			w.Write([]byte(`{"result":{"success":false,"error":"Failed to get asset prices"}}`))
+			return
+		}
+		w.Write([]byte(`{"result":{"success":true,"assets":[
+			{"prices":{"USD":249,"GBP":189},"original_prices":{"USD":499,"GBP":379},"name":"5002","date":"2012/01/01","class":[{"name":"def_index","value":"5002"}],"classid":"5002"}
+		]}}`))
+	})
+	sa := New("key", "https://example.com")
+
+	prices, err := sa.GetAssetPrices(context.Background(), 440, "", "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(prices) != 1 || prices[0].Prices["USD"] != 249 || prices[0].OriginalPrices["GBP"] != 379 || prices[0].Class[0].Value != "5002" {
+		t.Errorf("prices = %+v, want the discounted item", prices)
+	}
+
+	if _, err := sa.GetAssetPrices(context.Background(), 4000, "USD", ""); err == nil {
+		t.Error("err = nil for a game without a store, want an error")
+	}
+}
+
+func TestGetAssetClassInfo(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/ISteamEconomy/GetAssetClassInfo/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if q.Get("appid") != "440" || q.Get("class_count") != "2" || q.Get("classid0") != "101785959" {
+			w.Write([]byte(`{"result":{"success":false,"error":"Invalid class count"}}`))
+			return
+		}
+		// steam leaves out classes it doesn't know about
+		w.Write([]byte(`{"result":{"101785959":{"classid":"101785959","icon_url":"key.png","name":"Mann Co. Supply Crate Key","type":"Level 5 Tool","tradable":"1","marketable":"1","commodity":"1"},"success":true}}`))
+	})
+	sa := New("key", "https://example.com")
+
+	classes, err := sa.GetAssetClassInfo(context.Background(), 440, "english", "101785959", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 1 || classes["101785959"].Name != "Mann Co. Supply Crate Key" || classes["101785959"].Tradable != "1" {
+		t.Errorf("classes = %+v, want the key's", classes)
+	}
+
+	if _, err := sa.GetAssetClassInfo(context.Background(), 440, ""); err == nil {
+		t.Error("err = nil for no classes, want an error")
+	}
+}
@@ -0,0 +1,155 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// SchemaItem is an item definition from a game's item schema, as represented in the response from the
+// IEconItems_<appid>/GetSchemaItems web api. Only games with their own item servers (ex. tf2) have these.
+type SchemaItem struct {
+	// Name is the item's internal name, ItemName the one shown to users.
+	Name            string `json:"name"`
+	DefIndex        int    `json:"defindex"`
+	ItemClass       string `json:"item_class"`
+	ItemTypeName    string `json:"item_type_name"`
+	ItemName        string `json:"item_name"`
+	ItemDescription string `json:"item_description"`
+	ProperName      bool   `json:"proper_name"`
+	ItemQuality     int    `json:"item_quality"`
+	ImageUrl        string `json:"image_url"`
+	ImageUrlLarge   string `json:"image_url_large"`
+	CraftClass      string `json:"craft_class"`
+	MinIlevel       int    `json:"min_ilevel"`
+	MaxIlevel       int    `json:"max_ilevel"`
+	// Capabilities are what can be done with the item (ex. "nameable": true).
+	Capabilities map[string]bool `json:"capabilities"`
+	Attributes   []struct {
+		Name  string `json:"name"`
+		Class string `json:"class"`
+		Value any    `json:"value"`
+	} `json:"attributes"`
+}
+
+// SchemaItemsPage is a page of a game's item schema.
+type SchemaItemsPage struct {
+	Items []SchemaItem
+	// Next is what to pass to GetSchemaItems to get the next page, or 0 if this is the last one.
+	Next int
+}
+
+// GetSchemaItems gets a page of a game's item definitions, in lang (ex. "english"). Pass 0 as start to get the first
+// page, and the page's Next to get the next one. AllSchemaItems does this for you.
+func (sa *SteamAuther) GetSchemaItems(ctx context.Context, appid int, lang string, start int) (*SchemaItemsPage, error) {
+	q := url.Values{}
+	if lang != "" {
+		q.Set("language", lang)
+	}
+	if start > 0 {
+		q.Set("start", strconv.Itoa(start))
+	}
+
+	var data struct {
+		Result struct {
+			Status int          `json:"status"`
+			Note   string       `json:"note"`
+			Items  []SchemaItem `json:"items"`
+			Next   int          `json:"next"`
+		} `json:"result"`
+	}
+	if err := sa.getApi(ctx, econItemsMethod(appid, "GetSchemaItems"), q, &data); err != nil {
+		return nil, fmt.Errorf("get schema items (%d, from %d): %w", appid, start, err)
+	}
+
+	if data.Result.Status != 1 {
+		return nil, fmt.Errorf("get schema items (%d, from %d): steam said %q", appid, start, data.Result.Note)
+	}
+
+	return &SchemaItemsPage{Items: data.Result.Items, Next: data.Result.Next}, nil
+}
+
+// AllSchemaItems is every item definition in a game's schema as an iterator, fetching a page at a time as it's
+// consumed, since the whole schema is too big to want in memory at once. Errors are yielded with an empty SchemaItem,
+// and end the iteration.
+func (sa *SteamAuther) AllSchemaItems(ctx context.Context, appid int, lang string) iter.Seq2[SchemaItem, error] {
+	return func(yield func(SchemaItem, error) bool) {
+		for start := 0; ; {
+			page, err := sa.GetSchemaItems(ctx, appid, lang, start)
+			if err != nil {
+				yield(SchemaItem{}, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if page.Next == 0 {
+				return
+			}
+			start = page.Next
+		}
+	}
+}
+
+// SchemaAttribute is an attribute items can have, from a game's schema overview.
+type SchemaAttribute struct {
+	Name              string `json:"name"`
+	DefIndex          int    `json:"defindex"`
+	AttributeClass    string `json:"attribute_class"`
+	DescriptionString string `json:"description_string"`
+	DescriptionFormat string `json:"description_format"`
+	EffectType        string `json:"effect_type"`
+	Hidden            bool   `json:"hidden"`
+	StoredAsInteger   bool   `json:"stored_as_integer"`
+}
+
+// SchemaOverview is everything in a game's item schema but the items, as represented in the response from the
+// IEconItems_<appid>/GetSchemaOverview web api.
+type SchemaOverview struct {
+	// ItemsGameUrl is where to download the game's full items_game.txt.
+	ItemsGameUrl string `json:"items_game_url"`
+	// Qualities maps quality names to their ids, and QualityNames maps the names to what they're shown as.
+	Qualities    map[string]int    `json:"qualities"`
+	QualityNames map[string]string `json:"qualityNames"`
+	OriginNames  []struct {
+		Origin int    `json:"origin"`
+		Name   string `json:"name"`
+	} `json:"originNames"`
+	Attributes []SchemaAttribute `json:"attributes"`
+}
+
+// GetSchemaOverview gets a game's item qualities, origins and attributes, in lang (ex. "english").
+func (sa *SteamAuther) GetSchemaOverview(ctx context.Context, appid int, lang string) (*SchemaOverview, error) {
+	q := url.Values{}
+	if lang != "" {
+		q.Set("language", lang)
+	}
+
+	var data struct {
+		Result struct {
+			SchemaOverview
+			Status int    `json:"status"`
+			Note   string `json:"note"`
+		} `json:"result"`
+	}
+	if err := sa.getApi(ctx, econItemsMethod(appid, "GetSchemaOverview"), q, &data); err != nil {
+		return nil, fmt.Errorf("get schema overview (%d): %w", appid, err)
+	}
+
+	if data.Result.Status != 1 {
+		return nil, fmt.Errorf("get schema overview (%d): steam said %q", appid, data.Result.Note)
+	}
+
+	return &data.Result.SchemaOverview, nil
+}
+
+// econItemsMethod is the name of a method on a game's IEconItems interface, which has the game's appid in its name.
+func econItemsMethod(appid int, method string) string {
+	return "IEconItems_" + strconv.Itoa(appid) + "/" + method + "/v1"
+}
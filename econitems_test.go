@@ -0,0 +1,106 @@
+package gosteamauth
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// schemaItems answers IEconItems_440/GetSchemaItems like steam would, with 3 items over 2 pages. Other games don't
+// have item servers. It counts the requests made in calls.
+func schemaItems(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/IEconItems_440/GetSchemaItems/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+
+		switch r.URL.Query().Get("start") {
+		case "":
+			w.Write([]byte(`{"result":{"status":1,"items":[
+				{"name":"TF_WEAPON_BAT","defindex":0,"item_class":"tf_weapon_bat","item_name":"#TF_Weapon_Bat","capabilities":{"nameable":true}},
+				{"name":"TF_WEAPON_BOTTLE","defindex":1,"item_class":"tf_weapon_bottle","item_name":"#TF_Weapon_Bottle"}
+			],"next":2}}`))
+		case "2":
+			w.Write([]byte(`{"result":{"status":1,"items":[
+				{"name":"TF_WEAPON_FIREAXE","defindex":2,"item_class":"tf_weapon_fireaxe","attributes":[{"name":"damage bonus","class":"mult_dmg","value":1.2}]}
+			]}}`))
+		default:
+			w.Write([]byte(`{"result":{"status":2,"note":"start is past the end"}}`))
+		}
+	}
+}
+
+func TestGetSchemaItems(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, schemaItems(&calls))
+	sa := New("key", "https://example.com")
+
+	page, err := sa.GetSchemaItems(context.Background(), 440, "english", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Next != 2 || !page.Items[0].Capabilities["nameable"] {
+		t.Fatalf("first page = %+v, want 2 items and a next page", page)
+	}
+
+	page, err = sa.GetSchemaItems(context.Background(), 440, "english", 2)
+	if err != nil || len(page.Items) != 1 || page.Next != 0 || page.Items[0].Attributes[0].Value != 1.2 {
+		t.Errorf("last page = %+v, %v, want the fire axe and no next page", page, err)
+	}
+
+	if _, err := sa.GetSchemaItems(context.Background(), 440, "english", 10); err == nil {
+		t.Error("err = nil past the end, want an error")
+	}
+}
+
+func TestAllSchemaItems(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, schemaItems(&calls))
+	sa := New("key", "https://example.com")
+
+	var defs []int
+	for item, err := range sa.AllSchemaItems(context.Background(), 440, "") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		defs = append(defs, item.DefIndex)
+	}
+	if len(defs) != 3 || defs[2] != 2 || calls.Load() != 2 {
+		t.Errorf("got %v from %d requests, want defindexes 0 to 2 from 2", defs, calls.Load())
+	}
+
+	var errs int
+	for _, err := range sa.AllSchemaItems(context.Background(), 730, "") {
+		if err == nil {
+			t.Fatal("err = nil for a game without an item server, want an error")
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("yielded %d errors, want 1", errs)
+	}
+}
+
+func TestGetSchemaOverview(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/IEconItems_440/GetSchemaOverview/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"result":{"status":1,"items_game_url":"https://example.com/items_game.txt",
+			"qualities":{"Normal":0,"Unique":6},"qualityNames":{"Normal":"Normal","Unique":"Unique"},
+			"originNames":[{"origin":0,"name":"Timed Drop"}],
+			"attributes":[{"name":"damage bonus","defindex":2,"attribute_class":"mult_dmg","description_string":"+%s1% damage bonus","effect_type":"positive","hidden":false}]}}`))
+	})
+
+	o, err := New("key", "https://example.com").GetSchemaOverview(context.Background(), 440, "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Qualities["Unique"] != 6 || o.OriginNames[0].Name != "Timed Drop" || o.Attributes[0].AttributeClass != "mult_dmg" || o.ItemsGameUrl == "" {
+		t.Errorf("overview = %+v, want tf2's", o)
+	}
+}
@@ -0,0 +1,121 @@
+package gosteamauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ErrSteamUnavailable is wrapped by errors from talking to steam when steam couldn't be reached or had a problem on
+// its end, rather than saying no.
+var ErrSteamUnavailable = errors.New("steam is unavailable")
+
+// ErrorKind classifies why one of Handler's endpoints failed.
+type ErrorKind string
+
+const (
+	// ErrorKindBadRequest is a request that doesn't make sense, like a callback for an unknown login attempt.
+	ErrorKindBadRequest ErrorKind = "bad_request"
+	// ErrorKindInvalidAssertion is a callback steam didn't vouch for. Either it was tampered with, or the user
+	// cancelled on steam's side.
+	ErrorKindInvalidAssertion ErrorKind = "invalid_assertion"
+	// ErrorKindSteamUnavailable is steam being down or unreachable.
+	ErrorKindSteamUnavailable ErrorKind = "steam_unavailable"
+	// ErrorKindUnauthenticated is a request to a page behind RequireAuth (or a middleware built on it) without a
+	// session.
+	ErrorKindUnauthenticated ErrorKind = "unauthenticated"
+	// ErrorKindDenied is a user refused by one of the Handler's AccessCheckers, or without the role RequireRole wants.
+	ErrorKindDenied ErrorKind = "denied"
+	// ErrorKindRateLimited is a client over the Handler's RateLimit. Retry-After is already set on the response.
+	ErrorKindRateLimited ErrorKind = "rate_limited"
+	// ErrorKindInternal is anything else going wrong on our end.
+	ErrorKindInternal ErrorKind = "internal"
+)
+
+// HandlerError is the error Handler's endpoints pass to the ErrorHandler.
+type HandlerError struct {
+	Kind ErrorKind
+	// Status is the http status code the default ErrorHandler responds with.
+	Status int
+	// Message is a short explanation that's safe to show the user.
+	Message string
+	// Err is what actually went wrong, if there's more to it than Message. Don't show it to users.
+	Err error
+}
+
+func (e *HandlerError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return e.Err.Error()
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKindOf returns the kind of a HandlerError, and ErrorKindInternal for anything else.
+func ErrorKindOf(err error) ErrorKind {
+	var he *HandlerError
+	if errors.As(err, &he) {
+		return he.Kind
+	}
+
+	return ErrorKindInternal
+}
+
+// ErrorHandler renders errors from Handler's Login, Callback and Logout endpoints, and from its RequireAuth,
+// RequireAccess and RequireRole middlewares. err is always a *HandlerError.
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+// DefaultErrorHandler responds with the HandlerError's status and message as plain text.
+func DefaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	he := asHandlerError(err)
+	http.Error(w, he.Message, he.Status)
+}
+
+// JSONErrorHandler responds with the HandlerError's status, and its kind and message as JSON:
+//
+//	{"error": "denied", "message": "you're not allowed in here: you've been banned"}
+func JSONErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	he := asHandlerError(err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(he.Status)
+	json.NewEncoder(w).Encode(map[string]string{"error": string(he.Kind), "message": he.Message})
+}
+
+// asHandlerError returns err as a HandlerError, treating anything else as an internal error.
+func asHandlerError(err error) *HandlerError {
+	var he *HandlerError
+	if errors.As(err, &he) {
+		return he
+	}
+
+	return &HandlerError{Kind: ErrorKindInternal, Status: http.StatusInternalServerError, Message: "something went wrong", Err: err}
+}
+
+// fail renders a HandlerError with the Handler's ErrorHandler.
+func (h *Handler) fail(w http.ResponseWriter, r *http.Request, he *HandlerError) {
+	if h.ErrorHandler != nil {
+		h.ErrorHandler(w, r, he)
+		return
+	}
+
+	DefaultErrorHandler(w, r, he)
+}
+
+// callbackError classifies an error from one of the callback's steps (or RequireAccess's checks) into a HandlerError.
+func callbackError(err error, kind ErrorKind, status int, msg string) *HandlerError {
+	switch {
+	case errors.Is(err, ErrAccessDenied):
+		status, msg := accessStatus(err)
+		return &HandlerError{Kind: ErrorKindDenied, Status: status, Message: msg, Err: err}
+	case errors.Is(err, ErrSteamUnavailable):
+		return &HandlerError{Kind: ErrorKindSteamUnavailable, Status: http.StatusBadGateway, Message: "steam isn't responding, please try again later", Err: err}
+	}
+
+	return &HandlerError{Kind: kind, Status: status, Message: msg, Err: err}
+}
@@ -0,0 +1,151 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorKindOf(t *testing.T) {
+	he := &HandlerError{Kind: ErrorKindDenied, Status: http.StatusForbidden, Message: "no", Err: ErrAccessDenied}
+
+	if got := ErrorKindOf(fmt.Errorf("wrapped: %w", he)); got != ErrorKindDenied {
+		t.Errorf("ErrorKindOf(wrapped) = %q, want denied", got)
+	}
+	if got := ErrorKindOf(errors.New("nope")); got != ErrorKindInternal {
+		t.Errorf("ErrorKindOf(plain) = %q, want internal", got)
+	}
+	if !errors.Is(he, ErrAccessDenied) {
+		t.Error("HandlerError doesn't unwrap to its Err")
+	}
+}
+
+func TestJSONErrorHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	JSONErrorHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil), &HandlerError{
+		Kind:    ErrorKindBadRequest,
+		Status:  http.StatusBadRequest,
+		Message: "bad",
+		Err:     errors.New("secret detail"),
+	})
+
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["error"] != "bad_request" || body["message"] != "bad" || len(body) != 2 {
+		t.Errorf("body = %v, want the kind and message only", body)
+	}
+}
+
+func TestCallbackErrorKinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		steam  http.HandlerFunc
+		access []AccessChecker
+		want   ErrorKind
+		status int
+	}{
+		{"steam says no", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:false\n"))
+		}, nil, ErrorKindInvalidAssertion, http.StatusUnauthorized},
+		{"steam is down", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, nil, ErrorKindSteamUnavailable, http.StatusBadGateway},
+		{"refused", validOpenId, []AccessChecker{NewDenylist("76561197960287930")}, ErrorKindDenied, http.StatusForbidden},
+		{"checker failed", validOpenId, []AccessChecker{AccessCheckerFunc(func(context.Context, string) error {
+			return errors.New("database is down")
+		})}, ErrorKindInternal, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSteam(t, tt.steam)
+
+			var got error
+			h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+			h.Access = tt.access
+			h.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+				got = err
+				DefaultErrorHandler(w, r, err)
+			}
+
+			rec := httptest.NewRecorder()
+			h.Callback(rec, steamCallback("https://example.com/callback", "76561197960287930"))
+
+			if kind := ErrorKindOf(got); kind != tt.want {
+				t.Errorf("kind = %q (%v), want %q", kind, got, tt.want)
+			}
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestMiddlewareErrorHandler(t *testing.T) {
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.ErrorHandler = JSONErrorHandler
+	h.Roles = StaticRoles(nil)
+
+	rec := httptest.NewRecorder()
+	sess, err := h.Sessions().Create(context.Background(), rec, "76561197960287930", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.resolveRoles(context.Background(), sess); err != nil {
+		t.Fatal(err)
+	}
+	cookie := rec.Result().Cookies()[0]
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		cookie     *http.Cookie
+		want       ErrorKind
+		status     int
+	}{
+		{"RequireAuth without a session", h.RequireAuth, nil, ErrorKindUnauthenticated, http.StatusUnauthorized},
+		{"RequireAccess refused", h.RequireAccess(NewDenylist("76561197960287930")), cookie, ErrorKindDenied, http.StatusForbidden},
+		{"RequireRole without the role", h.RequireRole("admin"), cookie, ErrorKindDenied, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+			tt.middleware(ok).ServeHTTP(rec, req)
+
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q isn't json: %v", rec.Body, err)
+			}
+			if rec.Code != tt.status || body["error"] != string(tt.want) {
+				t.Errorf("status = %d, body = %v, want %d %s", rec.Code, body, tt.status, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebApiUnavailable(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := New("key", "https://example.com").GetUserGroupList(context.Background(), "76561197960287930")
+	if !errors.Is(err, ErrSteamUnavailable) {
+		t.Errorf("err = %v, want ErrSteamUnavailable", err)
+	}
+}
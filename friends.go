@@ -0,0 +1,152 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Friend is an entry in a user's friend list, as represented in the response from the GetFriendList web api.
+type Friend struct {
+	// SteamID is the "steamid64" of the friend.
+	SteamID string `json:"steamid"`
+	// Relationship is always "friend".
+	Relationship string `json:"relationship"`
+	// FriendSince is when they became friends, as a unix timestamp.
+	FriendSince int64 `json:"friend_since"`
+
+	// User is the friend's profile, if the friend list was hydrated. It's nil if steam didn't return one.
+	User *SteamUser `json:"user,omitempty"`
+}
+
+// Since returns when they became friends.
+func (f *Friend) Since() time.Time {
+	return time.Unix(f.FriendSince, 0)
+}
+
+// GetFriendList gets the user's friends. If hydrate is set, every friend's profile is fetched too (with
+// GetSteamUsers), which is what you want for "friends also playing here" features. Returns ErrPrivateProfile if the
+// user's friend list is private.
+func (sa *SteamAuther) GetFriendList(ctx context.Context, steamid64 string, hydrate bool) ([]Friend, error) {
+	var data struct {
+		FriendsList struct {
+			Friends []Friend `json:"friends"`
+		} `json:"friendslist"`
+	}
+	q := url.Values{"steamid": {steamid64}, "relationship": {"friend"}}
+	if err := sa.getApi(ctx, "ISteamUser/GetFriendList/v1", q, &data); err != nil {
+		// steam answers a private friend list with a 401.
+		return nil, fmt.Errorf("get friend list (%s): %w", steamid64, privateOn(err, http.StatusUnauthorized))
+	}
+
+	friends := data.FriendsList.Friends
+	if !hydrate || len(friends) == 0 {
+		return friends, nil
+	}
+
+	ids := make([]string, len(friends))
+	for i, f := range friends {
+		ids[i] = f.SteamID
+	}
+
+	users, err := sa.GetSteamUsers(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("get friend list (%s): hydrate: %w", steamid64, err)
+	}
+
+	for i := range friends {
+		friends[i].User = users[friends[i].SteamID]
+	}
+
+	return friends, nil
+}
+
+// errStopIteration is returned from inside a stream when the consumer of an iterator breaks out of its loop.
+var errStopIteration = errors.New("stop iteration")
+
+// AllFriends is GetFriendList as an iterator. The friend list is decoded as it comes in, and hydrated (if hydrate is
+// set) 100 friends at a time, so accounts with thousands of friends don't have to be held in memory all at once.
+// Errors are yielded with a zero Friend, and end the iteration.
+func (sa *SteamAuther) AllFriends(ctx context.Context, steamid64 string, hydrate bool) iter.Seq2[Friend, error] {
+	return func(yield func(Friend, error) bool) {
+		var batch []Friend
+
+		// flush hydrates and yields the batch, returning false if the loop was broken out of.
+		flush := func() (bool, error) {
+			if len(batch) == 0 {
+				return true, nil
+			}
+
+			if hydrate {
+				ids := make([]string, len(batch))
+				for i, f := range batch {
+					ids[i] = f.SteamID
+				}
+
+				users, err := sa.getPlayerSummaries(ctx, ids)
+				if err != nil {
+					return false, fmt.Errorf("hydrate: %w", err)
+				}
+
+				byId := make(map[string]*SteamUser, len(users))
+				for i := range users {
+					byId[users[i].SteamID] = &users[i]
+				}
+				for i := range batch {
+					batch[i].User = byId[batch[i].SteamID]
+				}
+			}
+
+			for _, f := range batch {
+				if !yield(f, nil) {
+					return false, nil
+				}
+			}
+			batch = batch[:0]
+
+			return true, nil
+		}
+
+		q := url.Values{"steamid": {steamid64}, "relationship": {"friend"}}
+		err := sa.streamApi(ctx, "ISteamUser/GetFriendList/v1", q, func(dec *json.Decoder) error {
+			if err := seekJSONArray(dec, "friendslist", "friends"); err != nil {
+				return err
+			}
+
+			for dec.More() {
+				var f Friend
+				if err := dec.Decode(&f); err != nil {
+					return err
+				}
+
+				batch = append(batch, f)
+				if len(batch) < MaxSteamIdsPerRequest {
+					continue
+				}
+
+				more, err := flush()
+				if err != nil {
+					return err
+				}
+				if !more {
+					return errStopIteration
+				}
+			}
+
+			return nil
+		})
+		if err == nil {
+			_, err = flush()
+		}
+		if err == nil || errors.Is(err, errStopIteration) {
+			return
+		}
+
+		yield(Friend{}, fmt.Errorf("iterate friends (%s): %w", steamid64, privateOn(err, http.StatusUnauthorized)))
+	}
+}
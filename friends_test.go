@@ -0,0 +1,94 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// friendLists answers GetFriendList like steam would: 76561197960287930 has two friends, one of which doesn't
+// exist anymore, and everyone else's friend list is private. Profiles are answered with playerSummaries.
+func friendLists(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/ISteamUser/GetFriendList/v1" {
+		var calls atomic.Int32
+		playerSummaries(&calls)(w, r)
+		return
+	}
+
+	if r.URL.Query().Get("steamid") != "76561197960287930" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	w.Write([]byte(`{"friendslist":{"friends":[
+		{"steamid":"76561197960287931","relationship":"friend","friend_since":1500000000},
+		{"steamid":"76561197960287940","relationship":"friend","friend_since":1600000000}
+	]}}`))
+}
+
+func TestGetFriendList(t *testing.T) {
+	fakeSteam(t, friendLists)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name      string
+		hydrate   bool
+		wantUsers []bool
+	}{
+		{"plain", false, []bool{false, false}},
+		{"hydrated", true, []bool{true, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			friends, err := sa.GetFriendList(context.Background(), "76561197960287930", tt.hydrate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(friends) != 2 || friends[0].Since().Unix() != 1500000000 {
+				t.Fatalf("friends = %+v, want both friends", friends)
+			}
+			for i, f := range friends {
+				if (f.User != nil) != tt.wantUsers[i] {
+					t.Errorf("%s user = %+v, want one %v", f.SteamID, f.User, tt.wantUsers[i])
+				}
+			}
+		})
+	}
+
+	if _, err := sa.GetFriendList(context.Background(), "76561197960287931", false); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private friend list err = %v, want ErrPrivateProfile", err)
+	}
+}
+
+func TestAllFriends(t *testing.T) {
+	fakeSteam(t, friendLists)
+	sa := New("key", "https://example.com")
+
+	var got []Friend
+	for f, err := range sa.AllFriends(context.Background(), "76561197960287930", true) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, f)
+	}
+	if len(got) != 2 || got[0].User == nil || got[1].User != nil {
+		t.Errorf("friends = %+v, want both, with the profile of the one that exists", got)
+	}
+
+	var n int
+	for range sa.AllFriends(context.Background(), "76561197960287930", false) {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("yielded %d friends after breaking, want 1", n)
+	}
+
+	for _, err := range sa.AllFriends(context.Background(), "76561197960287931", false) {
+		if !errors.Is(err, ErrPrivateProfile) {
+			t.Errorf("private friend list err = %v, want ErrPrivateProfile", err)
+		}
+	}
+}
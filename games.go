@@ -0,0 +1,262 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// OwnedGame is a game in a user's library, as represented in the response from the GetOwnedGames web api.
+type OwnedGame struct {
+	// AppID is the game's steam app id.
+	AppID int `json:"appid"`
+	// Name is the game's name. Only set if the app info was asked for.
+	Name string `json:"name"`
+	// PlaytimeForever is how long the user has played the game, in minutes.
+	PlaytimeForever int `json:"playtime_forever"`
+	// PlaytimeTwoWeeks is how long the user has played the game in the last two weeks, in minutes.
+	PlaytimeTwoWeeks int `json:"playtime_2weeks"`
+	// PlaytimeWindows, PlaytimeMac, PlaytimeLinux and PlaytimeDeck split PlaytimeForever up by platform, in minutes.
+	PlaytimeWindows int `json:"playtime_windows_forever"`
+	PlaytimeMac     int `json:"playtime_mac_forever"`
+	PlaytimeLinux   int `json:"playtime_linux_forever"`
+	PlaytimeDeck    int `json:"playtime_deck_forever"`
+	// PlaytimeDisconnected is how long the user has played the game while offline, in minutes.
+	PlaytimeDisconnected int `json:"playtime_disconnected"`
+	// ImgIconUrl is the hash of the game's icon, not a full url. Only set if the app info was asked for.
+	ImgIconUrl string `json:"img_icon_url"`
+	// HasCommunityVisibleStats is true if the game has stats or achievements that show up on the user's profile.
+	HasCommunityVisibleStats bool `json:"has_community_visible_stats"`
+	// RTimeLastPlayed is when the user last played the game, as a unix timestamp.
+	RTimeLastPlayed int64 `json:"rtime_last_played"`
+}
+
+// OwnedGamesFilter is what to ask GetOwnedGamesFiltered for.
+type OwnedGamesFilter struct {
+	// IncludeAppInfo fills in each game's Name and ImgIconUrl.
+	IncludeAppInfo bool
+	// IncludePlayedFreeGames includes free games the user has played. Otherwise, only games they own are returned.
+	IncludePlayedFreeGames bool
+	// AppIDs only returns these games, if the user has them. Leave it empty for the whole library.
+	AppIDs []int
+}
+
+// GetOwnedGames gets the games in the user's library, including free games they've played.
+// Returns ErrPrivateProfile if the user's game details are private.
+func (sa *SteamAuther) GetOwnedGames(ctx context.Context, steamid64 string) ([]OwnedGame, error) {
+	return sa.GetOwnedGamesFiltered(ctx, steamid64, OwnedGamesFilter{IncludeAppInfo: true, IncludePlayedFreeGames: true})
+}
+
+// GetOwnedGamesFiltered is GetOwnedGames, with control over what steam sends back. Asking for less (like leaving out
+// the app info, or only the games you care about) makes the response a lot smaller for users with big libraries.
+// Returns ErrPrivateProfile if the user's game details are private.
+func (sa *SteamAuther) GetOwnedGamesFiltered(ctx context.Context, steamid64 string, filter OwnedGamesFilter) ([]OwnedGame, error) {
+	games, err := sa.ownedGames(ctx, steamid64, filter)
+	if err != nil {
+		return nil, fmt.Errorf("get owned games (%s): %w", steamid64, err)
+	}
+
+	return games, nil
+}
+
+// ownedGames makes the GetOwnedGames request.
+func (sa *SteamAuther) ownedGames(ctx context.Context, steamid64 string, filter OwnedGamesFilter) ([]OwnedGame, error) {
+	q := url.Values{}
+	q.Set("steamid", steamid64)
+	q.Set("include_appinfo", strconv.FormatBool(filter.IncludeAppInfo))
+	q.Set("include_played_free_games", strconv.FormatBool(filter.IncludePlayedFreeGames))
+	for i, appid := range filter.AppIDs {
+		q.Set("appids_filter["+strconv.Itoa(i)+"]", strconv.Itoa(appid))
+	}
+
+	var data struct {
+		Response struct {
+			// Steam sends back an empty response for private profiles, rather than saying so.
+			GameCount *int        `json:"game_count"`
+			Games     []OwnedGame `json:"games"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IPlayerService/GetOwnedGames/v1", q, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Response.GameCount == nil {
+		return nil, ErrPrivateProfile
+	}
+
+	return data.Response.Games, nil
+}
+
+// RecentlyPlayedGame is a game the user played in the last two weeks, as represented in the response from the
+// GetRecentlyPlayedGames web api.
+type RecentlyPlayedGame struct {
+	// AppID is the game's steam app id.
+	AppID int `json:"appid"`
+	// Name is the game's name.
+	Name string `json:"name"`
+	// PlaytimeTwoWeeks is how long the user has played the game in the last two weeks, in minutes.
+	PlaytimeTwoWeeks int `json:"playtime_2weeks"`
+	// PlaytimeForever is how long the user has played the game, in minutes.
+	PlaytimeForever int `json:"playtime_forever"`
+	// PlaytimeWindows, PlaytimeMac, PlaytimeLinux and PlaytimeDeck split PlaytimeForever up by platform, in minutes.
+	PlaytimeWindows int `json:"playtime_windows_forever"`
+	PlaytimeMac     int `json:"playtime_mac_forever"`
+	PlaytimeLinux   int `json:"playtime_linux_forever"`
+	PlaytimeDeck    int `json:"playtime_deck_forever"`
+	// ImgIconUrl is the hash of the game's icon, not a full url.
+	ImgIconUrl string `json:"img_icon_url"`
+}
+
+// GetRecentlyPlayedGames gets the games the user has played in the last two weeks, most played first. count limits how
+// many are returned, 0 returns them all. Returns ErrPrivateProfile if the user's game details are private.
+func (sa *SteamAuther) GetRecentlyPlayedGames(ctx context.Context, steamid64 string, count int) ([]RecentlyPlayedGame, error) {
+	q := url.Values{"steamid": {steamid64}}
+	if count > 0 {
+		q.Set("count", strconv.Itoa(count))
+	}
+
+	var data struct {
+		Response struct {
+			// Steam sends back an empty response for private profiles, rather than saying so.
+			TotalCount *int                 `json:"total_count"`
+			Games      []RecentlyPlayedGame `json:"games"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IPlayerService/GetRecentlyPlayedGames/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get recently played games (%s): %w", steamid64, err)
+	}
+
+	if data.Response.TotalCount == nil {
+		return nil, fmt.Errorf("get recently played games (%s): %w", steamid64, ErrPrivateProfile)
+	}
+
+	return data.Response.Games, nil
+}
+
+// IsPlayingSharedGame checks if the user is playing the app through family sharing, returning the steamid64 of who
+// they're borrowing it from, or "" if they're playing their own copy (or aren't playing it at all).
+func (sa *SteamAuther) IsPlayingSharedGame(ctx context.Context, steamid64 string, appid int) (string, error) {
+	var data struct {
+		Response struct {
+			LenderSteamID string `json:"lender_steamid"`
+		} `json:"response"`
+	}
+	q := url.Values{"steamid": {steamid64}, "appid_playing": {strconv.Itoa(appid)}}
+	if err := sa.getApi(ctx, "IPlayerService/IsPlayingSharedGame/v1", q, &data); err != nil {
+		return "", fmt.Errorf("is playing shared game (%s, %d): %w", steamid64, appid, err)
+	}
+
+	if data.Response.LenderSteamID == "0" {
+		return "", nil
+	}
+
+	return data.Response.LenderSteamID, nil
+}
+
+// AppOwnership is whether a user owns an app, as represented in the response from the CheckAppOwnership web api.
+type AppOwnership struct {
+	// OwnsApp is true if the user can play the app, including through family sharing.
+	OwnsApp bool `json:"ownsapp"`
+	// Permanent is false for temporary licenses, like free weekends.
+	Permanent bool `json:"permanent"`
+	// Timestamp is when the license was granted, see AppOwnership.Granted.
+	Timestamp string `json:"timestamp"`
+	// OwnerSteamID is the steamid64 of whoever owns the app. It's someone else if the user is borrowing it through
+	// family sharing.
+	OwnerSteamID string `json:"ownersteamid"`
+	// SiteLicense is true if the user has access through a site license (ex. an internet cafe).
+	SiteLicense bool `json:"sitelicense"`
+}
+
+// Granted returns when the license was granted, and false if steam didn't say.
+func (o *AppOwnership) Granted() (time.Time, bool) {
+	t, err := time.Parse(time.RFC3339, o.Timestamp)
+	return t, err == nil
+}
+
+// CheckAppOwnership checks if the user owns the app. This is a publisher only method, so it needs a publisher api key
+// for the app (see PublisherApiKey), but unlike GetOwnedGames it works for private profiles. Returns
+// ErrPublisherKeyRequired if steam refuses the key.
+func (sa *SteamAuther) CheckAppOwnership(ctx context.Context, steamid64 string, appid int) (*AppOwnership, error) {
+	var data struct {
+		AppOwnership struct {
+			AppOwnership
+			Result string `json:"result"`
+		} `json:"appownership"`
+	}
+	q := url.Values{"steamid": {steamid64}, "appid": {strconv.Itoa(appid)}}
+	if err := sa.getPublisherApi(ctx, "ISteamUser/CheckAppOwnership/v4", q, &data); err != nil {
+		return nil, fmt.Errorf("check app ownership (%s, %d): %w", steamid64, appid, err)
+	}
+
+	if data.AppOwnership.Result != "OK" {
+		return nil, fmt.Errorf("check app ownership (%s, %d): steam said %q", steamid64, appid, data.AppOwnership.Result)
+	}
+
+	return &data.AppOwnership.AppOwnership, nil
+}
+
+// OwnsApp reports whether the user has the app. If the SteamAuther has a PublisherApiKey it asks CheckAppOwnership,
+// which works for private profiles and counts family sharing. Otherwise it goes off GetOwnedGames, and returns
+// ErrPrivateProfile if the user's game details are private.
+func (sa *SteamAuther) OwnsApp(ctx context.Context, steamid64 string, appid int) (bool, error) {
+	if sa.PublisherApiKey != "" {
+		own, err := sa.CheckAppOwnership(ctx, steamid64, appid)
+		if err != nil {
+			return false, err
+		}
+
+		return own.OwnsApp, nil
+	}
+
+	games, err := sa.ownedGames(ctx, steamid64, OwnedGamesFilter{IncludePlayedFreeGames: true, AppIDs: []int{appid}})
+	if err != nil {
+		return false, fmt.Errorf("owns app (%s, %d): %w", steamid64, appid, err)
+	}
+
+	return slices.ContainsFunc(games, func(g OwnedGame) bool { return g.AppID == appid }), nil
+}
+
+// RequireOwnsApp returns an AccessChecker that only lets in users with the app in their library. It goes off
+// GetOwnedGames, so users with private game details are refused too. If you publish the app, RequireOwnsAppPublisher
+// doesn't have that problem.
+func (sa *SteamAuther) RequireOwnsApp(appid int) AccessChecker {
+	return AccessCheckerFunc(func(ctx context.Context, steamid64 string) error {
+		games, err := sa.ownedGames(ctx, steamid64, OwnedGamesFilter{IncludePlayedFreeGames: true, AppIDs: []int{appid}})
+		if errors.Is(err, ErrPrivateProfile) {
+			return &AccessDeniedError{SteamID: steamid64, Reason: "your game details are private, so we can't see if you own the game"}
+		}
+		if err != nil {
+			return fmt.Errorf("require owns app (%d): %w", appid, err)
+		}
+
+		for _, g := range games {
+			if g.AppID == appid {
+				return nil
+			}
+		}
+
+		return &AccessDeniedError{SteamID: steamid64, Reason: "you need to own the game"}
+	})
+}
+
+// RequireOwnsAppPublisher is RequireOwnsApp, backed by CheckAppOwnership. It needs a publisher api key for the app (see
+// PublisherApiKey). Users borrowing the app through family sharing are let in.
+func (sa *SteamAuther) RequireOwnsAppPublisher(appid int) AccessChecker {
+	return AccessCheckerFunc(func(ctx context.Context, steamid64 string) error {
+		own, err := sa.CheckAppOwnership(ctx, steamid64, appid)
+		if err != nil {
+			return fmt.Errorf("require owns app (%d): %w", appid, err)
+		}
+
+		if !own.OwnsApp {
+			return &AccessDeniedError{SteamID: steamid64, Reason: "you need to own the game"}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,376 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ownedGames answers GetOwnedGames like steam would for a user owning appid 440, or with an empty response for
+// anyone else, which is what steam does for private game details.
+func ownedGames(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/IPlayerService/GetOwnedGames/v1" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.URL.Query().Get("steamid") != "76561197960287930" {
+		w.Write([]byte(`{"response":{}}`))
+		return
+	}
+	if f := r.URL.Query().Get("appids_filter[0]"); f != "" && f != "440" {
+		w.Write([]byte(`{"response":{"game_count":0}}`))
+		return
+	}
+	name := ""
+	if r.URL.Query().Get("include_appinfo") == "true" {
+		name = `"name":"Team Fortress 2",`
+	}
+	w.Write([]byte(`{"response":{"game_count":1,"games":[{"appid":440,` + name + `"playtime_forever":60,"playtime_linux_forever":45,"playtime_deck_forever":15}]}}`))
+}
+
+// appOwnership answers CheckAppOwnership like steam would on the partner host for the "pubkey" publisher key, for a
+// user owning appid 440 and one borrowing it from them. Any other key is refused.
+func appOwnership(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if r.URL.Path != "/ISteamUser/CheckAppOwnership/v4" || r.Host != "partner.steam-api.com" {
+		http.NotFound(w, r)
+		return
+	}
+	if q.Get("key") != "pubkey" {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case q.Get("appid") != "440":
+		w.Write([]byte(`{"appownership":{"ownsapp":false,"permanent":false,"timestamp":"","ownersteamid":"0","sitelicense":false,"result":"OK"}}`))
+	case q.Get("steamid") == "76561197960287930":
+		w.Write([]byte(`{"appownership":{"ownsapp":true,"permanent":true,"timestamp":"2011-06-23T12:00:00Z","ownersteamid":"76561197960287930","sitelicense":false,"result":"OK"}}`))
+	case q.Get("steamid") == "76561197960287932":
+		w.Write([]byte(`{"appownership":{"ownsapp":true,"permanent":false,"timestamp":"2020-01-01T00:00:00Z","ownersteamid":"76561197960287930","sitelicense":false,"result":"OK"}}`))
+	default:
+		w.Write([]byte(`{"appownership":{"result":"Failure"}}`))
+	}
+}
+
+func TestGetOwnedGames(t *testing.T) {
+	fakeSteam(t, ownedGames)
+	sa := New("key", "https://example.com")
+
+	games, err := sa.GetOwnedGames(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 || games[0].AppID != 440 || games[0].PlaytimeForever != 60 {
+		t.Errorf("games = %+v, want tf2", games)
+	}
+
+	if _, err := sa.GetOwnedGames(context.Background(), "76561197960287931"); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private profile err = %v, want ErrPrivateProfile", err)
+	}
+}
+
+func TestRequireOwnsApp(t *testing.T) {
+	fakeSteam(t, ownedGames)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name       string
+		steamid    string
+		appid      int
+		wantDenied bool
+	}{
+		{"owns it", "76561197960287930", 440, false},
+		{"doesn't own it", "76561197960287930", 570, true},
+		{"private", "76561197960287931", 440, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sa.RequireOwnsApp(tt.appid).CheckAccess(context.Background(), tt.steamid)
+			if errors.Is(err, ErrAccessDenied) != tt.wantDenied || (err != nil && !tt.wantDenied) {
+				t.Errorf("err = %v, want denied %v", err, tt.wantDenied)
+			}
+		})
+	}
+}
+
+func TestGetOwnedGamesFiltered(t *testing.T) {
+	fakeSteam(t, ownedGames)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name      string
+		filter    OwnedGamesFilter
+		wantGames int
+		wantName  string
+	}{
+		{"everything", OwnedGamesFilter{IncludeAppInfo: true}, 1, "Team Fortress 2"},
+		{"no app info", OwnedGamesFilter{}, 1, ""},
+		{"only tf2", OwnedGamesFilter{AppIDs: []int{440}}, 1, ""},
+		{"only dota", OwnedGamesFilter{AppIDs: []int{570}}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			games, err := sa.GetOwnedGamesFiltered(context.Background(), "76561197960287930", tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(games) != tt.wantGames {
+				t.Fatalf("games = %+v, want %d", games, tt.wantGames)
+			}
+			if len(games) > 0 && (games[0].Name != tt.wantName || games[0].PlaytimeLinux != 45 || games[0].PlaytimeDeck != 15) {
+				t.Errorf("game = %+v, want name %q and the per platform playtime", games[0], tt.wantName)
+			}
+		})
+	}
+}
+
+func TestOwnsApp(t *testing.T) {
+	fakeSteam(t, ownedGames)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name    string
+		steamid string
+		appid   int
+		want    bool
+		wantErr error
+	}{
+		{"owns it", "76561197960287930", 440, true, nil},
+		{"doesn't own it", "76561197960287930", 570, false, nil},
+		{"private", "76561197960287931", 440, false, ErrPrivateProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sa.OwnsApp(context.Background(), tt.steamid, tt.appid)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("OwnsApp = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwnsAppPublisher(t *testing.T) {
+	fakeSteam(t, appOwnership)
+	sa := New("key", "https://example.com")
+	sa.PublisherApiKey = "pubkey"
+
+	tests := []struct {
+		name    string
+		steamid string
+		appid   int
+		want    bool
+	}{
+		{"owns it", "76561197960287930", 440, true},
+		{"borrowing it", "76561197960287932", 440, true},
+		{"doesn't own it", "76561197960287930", 570, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sa.OwnsApp(context.Background(), tt.steamid, tt.appid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("OwnsApp = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAppOwnership(t *testing.T) {
+	fakeSteam(t, appOwnership)
+	sa := New("key", "https://example.com")
+	sa.PublisherApiKey = "pubkey"
+
+	own, err := sa.CheckAppOwnership(context.Background(), "76561197960287932", 440)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !own.OwnsApp || own.Permanent || own.OwnerSteamID != "76561197960287930" {
+		t.Errorf("ownership = %+v, want borrowed from 76561197960287930", own)
+	}
+	if granted, ok := own.Granted(); !ok || !granted.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Granted = %v, %v, want 2020-01-01", granted, ok)
+	}
+
+	if _, err := sa.CheckAppOwnership(context.Background(), "76561197960287931", 440); err == nil {
+		t.Error("err = nil, want an error when steam doesn't say OK")
+	}
+
+	// without a publisher key the normal one is sent, which steam refuses
+	sa.PublisherApiKey = ""
+	if _, err := sa.CheckAppOwnership(context.Background(), "76561197960287930", 440); !errors.Is(err, ErrPublisherKeyRequired) {
+		t.Errorf("err = %v, want ErrPublisherKeyRequired", err)
+	}
+}
+
+func TestPartnerMode(t *testing.T) {
+	type call struct {
+		host, key string
+	}
+	var got []call
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		got = append(got, call{r.Host, r.Form.Get("key")})
+		w.Write([]byte(`{"response":{}}`))
+	})
+
+	tests := []struct {
+		name      string
+		partner   bool
+		publisher string
+		want      call
+	}{
+		{"normal", false, "pubkey", call{"api.steampowered.com", "key"}},
+		{"partner", true, "", call{"partner.steam-api.com", "key"}},
+		{"partner with a publisher key", true, "pubkey", call{"partner.steam-api.com", "pubkey"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := New("key", "https://example.com")
+			sa.Partner = tt.partner
+			sa.PublisherApiKey = tt.publisher
+
+			got = nil
+			sa.GetOwnedGames(context.Background(), "76561197960287930")
+			sa.DeclineTradeOffer(context.Background(), "1")
+			if len(got) != 2 || got[0] != tt.want || got[1] != tt.want {
+				t.Errorf("calls = %v, want a get and a post to %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppOwnershipGranted(t *testing.T) {
+	if _, ok := (&AppOwnership{}).Granted(); ok {
+		t.Error("Granted ok = true without a timestamp")
+	}
+}
+
+func TestRequireOwnsAppPublisher(t *testing.T) {
+	fakeSteam(t, appOwnership)
+	sa := New("key", "https://example.com")
+	sa.PublisherApiKey = "pubkey"
+
+	tests := []struct {
+		name       string
+		steamid    string
+		appid      int
+		wantDenied bool
+	}{
+		{"owns it", "76561197960287930", 440, false},
+		{"borrowing it", "76561197960287932", 440, false},
+		{"doesn't own it", "76561197960287930", 570, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sa.RequireOwnsAppPublisher(tt.appid).CheckAccess(context.Background(), tt.steamid)
+			if errors.Is(err, ErrAccessDenied) != tt.wantDenied || (err != nil && !tt.wantDenied) {
+				t.Errorf("err = %v, want denied %v", err, tt.wantDenied)
+			}
+		})
+	}
+}
+
+func TestGetRecentlyPlayedGames(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/IPlayerService/GetRecentlyPlayedGames/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("steamid") != "76561197960287930" {
+			w.Write([]byte(`{"response":{}}`))
+			return
+		}
+
+		games := `{"appid":440,"name":"Team Fortress 2","playtime_2weeks":90,"playtime_forever":600},{"appid":570,"name":"Dota 2","playtime_2weeks":30,"playtime_forever":60}`
+		if r.URL.Query().Get("count") == "1" {
+			games = games[:strings.Index(games, "},")+1]
+		}
+		w.Write([]byte(`{"response":{"total_count":2,"games":[` + games + `]}}`))
+	})
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name    string
+		steamid string
+		count   int
+		want    int
+		wantErr error
+	}{
+		{"all", "76561197960287930", 0, 2, nil},
+		{"most played", "76561197960287930", 1, 1, nil},
+		{"private", "76561197960287931", 0, 0, ErrPrivateProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			games, err := sa.GetRecentlyPlayedGames(context.Background(), tt.steamid, tt.count)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if len(games) != tt.want {
+				t.Fatalf("games = %+v, want %d", games, tt.want)
+			}
+			if tt.want > 0 && (games[0].AppID != 440 || games[0].PlaytimeTwoWeeks != 90) {
+				t.Errorf("games[0] = %+v, want tf2 first", games[0])
+			}
+		})
+	}
+}
+
+func TestIsPlayingSharedGame(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/IPlayerService/IsPlayingSharedGame/v1" || r.URL.Query().Get("appid_playing") != "4000" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Query().Get("steamid") {
+		case "76561197960287930":
+			w.Write([]byte(`{"response":{"lender_steamid":"76561197960287931"}}`))
+		case "76561197960287932":
+			w.Write([]byte(`{"response":{"lender_steamid":"0"}}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name    string
+		steamid string
+		want    string
+		wantErr error
+	}{
+		{"borrowed", "76561197960287930", "76561197960287931", nil},
+		{"their own copy", "76561197960287932", "", nil},
+		{"steam is down", "76561197960287933", "", ErrSteamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sa.IsPlayingSharedGame(context.Background(), tt.steamid, 4000)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("lender = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
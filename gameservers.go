@@ -0,0 +1,170 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GameServerAccount is a game server account (and its login token, or GSLT) owned by the account the api key belongs
+// to, as represented in the response from the GetAccountList web api.
+type GameServerAccount struct {
+	// SteamID is the server's steamid64.
+	SteamID    string `json:"steamid"`
+	AppID      int    `json:"appid"`
+	LoginToken string `json:"login_token"`
+	Memo       string `json:"memo"`
+	IsDeleted  bool   `json:"is_deleted"`
+	// IsExpired is true once the token hasn't been used in a while, it can be brought back with ResetLoginToken.
+	IsExpired bool `json:"is_expired"`
+	// RTLastLogon is when a server last logged in with the token, as a unix timestamp.
+	RTLastLogon int64 `json:"rt_last_logon"`
+}
+
+// LastLogon returns when a server last logged in with the token.
+func (a *GameServerAccount) LastLogon() time.Time {
+	return time.Unix(a.RTLastLogon, 0)
+}
+
+// GetGameServerAccountList gets the game server accounts owned by the account the api key belongs to.
+func (sa *SteamAuther) GetGameServerAccountList(ctx context.Context) ([]GameServerAccount, error) {
+	var data struct {
+		Response struct {
+			Servers []GameServerAccount `json:"servers"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IGameServersService/GetAccountList/v1", nil, &data); err != nil {
+		return nil, fmt.Errorf("get game server account list: %w", err)
+	}
+
+	return data.Response.Servers, nil
+}
+
+// CreateGameServerAccount makes a game server account for a game, returning its steamid64 and login token. memo is a
+// note to remember what it's for (ex. the server's name).
+func (sa *SteamAuther) CreateGameServerAccount(ctx context.Context, appid int, memo string) (steamid64, loginToken string, err error) {
+	var data struct {
+		Response struct {
+			SteamID    string `json:"steamid"`
+			LoginToken string `json:"login_token"`
+		} `json:"response"`
+	}
+	params := url.Values{"appid": {strconv.Itoa(appid)}, "memo": {memo}}
+	if err := sa.postApi(ctx, "IGameServersService/CreateAccount/v1", params, &data); err != nil {
+		return "", "", fmt.Errorf("create game server account (%d): %w", appid, err)
+	}
+
+	return data.Response.SteamID, data.Response.LoginToken, nil
+}
+
+// DeleteGameServerAccount deletes a game server account, so its login token stops working.
+func (sa *SteamAuther) DeleteGameServerAccount(ctx context.Context, steamid64 string) error {
+	var data struct{}
+	if err := sa.postApi(ctx, "IGameServersService/DeleteAccount/v1", url.Values{"steamid": {steamid64}}, &data); err != nil {
+		return fmt.Errorf("delete game server account (%s): %w", steamid64, err)
+	}
+
+	return nil
+}
+
+// SetGameServerMemo changes the memo of a game server account.
+func (sa *SteamAuther) SetGameServerMemo(ctx context.Context, steamid64, memo string) error {
+	var data struct{}
+	if err := sa.postApi(ctx, "IGameServersService/SetMemo/v1", url.Values{"steamid": {steamid64}, "memo": {memo}}, &data); err != nil {
+		return fmt.Errorf("set game server memo (%s): %w", steamid64, err)
+	}
+
+	return nil
+}
+
+// ResetGameServerLoginToken makes a new login token for a game server account, returning it. The old one stops
+// working.
+func (sa *SteamAuther) ResetGameServerLoginToken(ctx context.Context, steamid64 string) (string, error) {
+	var data struct {
+		Response struct {
+			LoginToken string `json:"login_token"`
+		} `json:"response"`
+	}
+	if err := sa.postApi(ctx, "IGameServersService/ResetLoginToken/v1", url.Values{"steamid": {steamid64}}, &data); err != nil {
+		return "", fmt.Errorf("reset game server login token (%s): %w", steamid64, err)
+	}
+
+	return data.Response.LoginToken, nil
+}
+
+// LoginTokenStatus is what steam knows about a game server login token, as represented in the response from the
+// QueryLoginToken web api.
+type LoginTokenStatus struct {
+	IsBanned bool `json:"is_banned"`
+	// Expires is when the token stops working, as a unix timestamp.
+	Expires int64 `json:"expires"`
+	// SteamID is the steamid64 of the token's game server account.
+	SteamID string `json:"steamid"`
+}
+
+// QueryLoginToken checks a game server login token, which needn't belong to the account the api key belongs to.
+func (sa *SteamAuther) QueryLoginToken(ctx context.Context, loginToken string) (*LoginTokenStatus, error) {
+	var data struct {
+		Response LoginTokenStatus `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IGameServersService/QueryLoginToken/v1", url.Values{"login_token": {loginToken}}, &data); err != nil {
+		return nil, fmt.Errorf("query login token: %w", err)
+	}
+
+	return &data.Response, nil
+}
+
+// GameServer is a live game server steam knows about, as represented in the responses from the
+// GetServerSteamIDsByIP and GetServerIPsBySteamID web apis.
+type GameServer struct {
+	// Addr is the server's ip and query port (ex. "1.2.3.4:27015").
+	Addr string `json:"addr"`
+	// SteamID is the server's steamid64.
+	SteamID  string `json:"steamid"`
+	AppID    int    `json:"appid"`
+	GameDir  string `json:"gamedir"`
+	Region   int    `json:"region"`
+	Secure   bool   `json:"secure"`
+	Lan      bool   `json:"lan"`
+	GamePort int    `json:"gameport"`
+	SpecPort int    `json:"specport"`
+}
+
+// GetServerSteamIDsByIP looks up the live game servers at addrs (ex. "1.2.3.4:27015"). Servers steam doesn't know
+// about are left out.
+func (sa *SteamAuther) GetServerSteamIDsByIP(ctx context.Context, addrs ...string) ([]GameServer, error) {
+	servers, err := sa.getGameServers(ctx, "IGameServersService/GetServerSteamIDsByIP/v1", "server_ips", addrs)
+	if err != nil {
+		return nil, fmt.Errorf("get server steamids by ip: %w", err)
+	}
+
+	return servers, nil
+}
+
+// GetServerIPsBySteamID looks up the live game servers with the steamid64s. Servers steam doesn't know about (or
+// that aren't running) are left out.
+func (sa *SteamAuther) GetServerIPsBySteamID(ctx context.Context, steamid64s ...string) ([]GameServer, error) {
+	servers, err := sa.getGameServers(ctx, "IGameServersService/GetServerIPsBySteamID/v1", "server_steamids", steamid64s)
+	if err != nil {
+		return nil, fmt.Errorf("get server ips by steamid: %w", err)
+	}
+
+	return servers, nil
+}
+
+// getGameServers calls one of the game server lookups, which take a comma separated list as param.
+func (sa *SteamAuther) getGameServers(ctx context.Context, method, param string, list []string) ([]GameServer, error) {
+	var data struct {
+		Response struct {
+			Servers []GameServer `json:"servers"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, method, url.Values{param: {strings.Join(list, ",")}}, &data); err != nil {
+		return nil, err
+	}
+
+	return data.Response.Servers, nil
+}
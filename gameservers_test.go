@@ -0,0 +1,186 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// gameServers answers IGameServersService like steam would, keeping the accounts made with it in memory.
+func gameServers() http.HandlerFunc {
+	accounts := map[string]*GameServerAccount{}
+	next := 85568392920000000
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		acct := accounts[r.Form.Get("steamid")]
+
+		var res any
+		switch r.URL.Path {
+		case "/IGameServersService/GetAccountList/v1":
+			servers := []GameServerAccount{}
+			for _, a := range accounts {
+				servers = append(servers, *a)
+			}
+			res = map[string]any{"servers": servers}
+		case "/IGameServersService/CreateAccount/v1":
+			next++
+			appid, _ := strconv.Atoi(r.PostForm.Get("appid"))
+			acct = &GameServerAccount{SteamID: strconv.Itoa(next), AppID: appid, Memo: r.PostForm.Get("memo"), LoginToken: "token" + strconv.Itoa(next)}
+			accounts[acct.SteamID] = acct
+			res = map[string]any{"steamid": acct.SteamID, "login_token": acct.LoginToken}
+		case "/IGameServersService/SetMemo/v1":
+			if acct == nil {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			acct.Memo = r.PostForm.Get("memo")
+			res = map[string]any{}
+		case "/IGameServersService/ResetLoginToken/v1":
+			if acct == nil {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			acct.LoginToken += "-reset"
+			res = map[string]any{"login_token": acct.LoginToken}
+		case "/IGameServersService/DeleteAccount/v1":
+			delete(accounts, r.PostForm.Get("steamid"))
+			res = map[string]any{}
+		case "/IGameServersService/QueryLoginToken/v1":
+			res = map[string]any{}
+			for _, a := range accounts {
+				if a.LoginToken == r.Form.Get("login_token") {
+					res = map[string]any{"is_banned": false, "expires": 0, "steamid": a.SteamID}
+				}
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{"response": res})
+	}
+}
+
+func TestGameServerAccounts(t *testing.T) {
+	fakeSteam(t, gameServers())
+	sa := New("key", "https://example.com")
+	ctx := context.Background()
+
+	steamid, token, err := sa.CreateGameServerAccount(ctx, 440, "server one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steamid == "" || token == "" {
+		t.Fatalf("created %q with token %q, want both", steamid, token)
+	}
+
+	if err := sa.SetGameServerMemo(ctx, steamid, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	reset, err := sa.ResetGameServerLoginToken(ctx, steamid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reset == token {
+		t.Errorf("reset token = %q, want a new one", reset)
+	}
+
+	accounts, err := sa.GetGameServerAccountList(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || accounts[0].AppID != 440 || accounts[0].Memo != "renamed" || accounts[0].LoginToken != reset {
+		t.Errorf("accounts = %+v, want the renamed tf2 server with the reset token", accounts)
+	}
+
+	status, err := sa.QueryLoginToken(ctx, reset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.SteamID != steamid {
+		t.Errorf("token belongs to %q, want %q", status.SteamID, steamid)
+	}
+
+	if err := sa.DeleteGameServerAccount(ctx, steamid); err != nil {
+		t.Fatal(err)
+	}
+	if accounts, _ := sa.GetGameServerAccountList(ctx); len(accounts) != 0 {
+		t.Errorf("accounts = %+v after deleting, want none", accounts)
+	}
+	if err := sa.SetGameServerMemo(ctx, steamid, "gone"); err == nil {
+		t.Error("setting the memo of a deleted account err = nil, want an error")
+	}
+}
+
+func TestGameServerAccountLastLogon(t *testing.T) {
+	a := GameServerAccount{RTLastLogon: 1700000000}
+	if got := a.LastLogon().Unix(); got != 1700000000 {
+		t.Errorf("LastLogon = %d, want 1700000000", got)
+	}
+}
+
+// liveServers answers the game server lookups like steam would, knowing about one tf2 server.
+func liveServers(w http.ResponseWriter, r *http.Request) {
+	const server = `{"addr":"1.2.3.4:27015","steamid":"90071996842377216","appid":440,"gamedir":"tf","region":255,"secure":true,"lan":false,"gameport":27015,"specport":0}`
+
+	var known []string
+	switch r.URL.Path {
+	case "/IGameServersService/GetServerSteamIDsByIP/v1":
+		known = strings.Split(r.URL.Query().Get("server_ips"), ",")
+	case "/IGameServersService/GetServerIPsBySteamID/v1":
+		known = strings.Split(r.URL.Query().Get("server_steamids"), ",")
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if slices.Contains(known, "1.2.3.4:27015") || slices.Contains(known, "90071996842377216") {
+		w.Write([]byte(`{"response":{"servers":[` + server + `]}}`))
+		return
+	}
+	w.Write([]byte(`{"response":{}}`))
+}
+
+func TestGameServerLookups(t *testing.T) {
+	fakeSteam(t, liveServers)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name   string
+		lookup func() ([]GameServer, error)
+		want   int
+	}{
+		{"by ip", func() ([]GameServer, error) {
+			return sa.GetServerSteamIDsByIP(context.Background(), "5.6.7.8:27015", "1.2.3.4:27015")
+		}, 1},
+		{"unknown ip", func() ([]GameServer, error) {
+			return sa.GetServerSteamIDsByIP(context.Background(), "5.6.7.8:27015")
+		}, 0},
+		{"by steamid", func() ([]GameServer, error) {
+			return sa.GetServerIPsBySteamID(context.Background(), "90071996842377216")
+		}, 1},
+		{"unknown steamid", func() ([]GameServer, error) {
+			return sa.GetServerIPsBySteamID(context.Background(), "90071996842377217")
+		}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			servers, err := tt.lookup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(servers) != tt.want {
+				t.Fatalf("servers = %+v, want %d", servers, tt.want)
+			}
+			if tt.want > 0 && (servers[0].Addr != "1.2.3.4:27015" || servers[0].SteamID != "90071996842377216" || servers[0].GamePort != 27015 || !servers[0].Secure) {
+				t.Errorf("server = %+v, want the tf2 server", servers[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// CommunityBaseUrl is the base url of the steam community site, for the few things only it has (like group member
+// lists).
+const CommunityBaseUrl = "https://steamcommunity.com"
+
+// GroupSummary is a steam group's public details.
+type GroupSummary struct {
+	// GroupID is the group's clan steamid64.
+	GroupID string
+	Name    string
+	// Url is the group's vanity url name, as in https://steamcommunity.com/groups/<Url>.
+	Url      string
+	Headline string
+	// Summary is the group's description, as html.
+	Summary      string
+	AvatarIcon   string
+	AvatarMedium string
+	AvatarFull   string
+
+	MemberCount   int
+	MembersInChat int
+	MembersInGame int
+	MembersOnline int
+}
+
+// GroupMembersPage is a page of a group's member list. Steam hands them out 1000 members at a time.
+type GroupMembersPage struct {
+	// GroupID is the group's steamid64.
+	GroupID string
+	// MemberCount is how many members the group has in total.
+	MemberCount int
+	// Page is the page number asked for, starting at 1.
+	Page       int
+	TotalPages int
+	// Group is the group's details, which come along with every page.
+	Group *GroupSummary
+	// Members are the steamid64s of the members on this page. It's empty for pages past TotalPages.
+	Members []string
+	// NextPage is the number of the next page, or 0 if this is the last one.
+	NextPage int
+}
+
+// memberListXML is the parts of the memberslistxml document we use.
+type memberListXML struct {
+	XMLName      xml.Name `xml:"memberList"`
+	GroupID64    string   `xml:"groupID64"`
+	GroupDetails struct {
+		GroupName     string `xml:"groupName"`
+		GroupURL      string `xml:"groupURL"`
+		Headline      string `xml:"headline"`
+		Summary       string `xml:"summary"`
+		AvatarIcon    string `xml:"avatarIcon"`
+		AvatarMedium  string `xml:"avatarMedium"`
+		AvatarFull    string `xml:"avatarFull"`
+		MembersInChat int    `xml:"membersInChat"`
+		MembersInGame int    `xml:"membersInGame"`
+		MembersOnline int    `xml:"membersOnline"`
+	} `xml:"groupDetails"`
+	MemberCount int      `xml:"memberCount"`
+	TotalPages  int      `xml:"totalPages"`
+	CurrentPage int      `xml:"currentPage"`
+	Members     []string `xml:"members>steamID64"`
+}
+
+// GetGroupMembersPage gets a page of the group's member list. groupId can be either the group's steamid64 or its
+// account id (see ClanSteamID64), and pages start at 1. There's no web api for this, so it comes from the steam
+// community site.
+func (sa *SteamAuther) GetGroupMembersPage(ctx context.Context, groupId string, page int) (*GroupMembersPage, error) {
+	doc, err := sa.getMemberList(ctx, groupId, page)
+	if err != nil {
+		return nil, fmt.Errorf("get group members page (%s, %d): %w", groupId, page, err)
+	}
+
+	// Steam answers pages past the end with the last page again, and its currentPage along with it, so the page is
+	// kept track of here rather than trusted from the document.
+	page = max(page, 1)
+	p := &GroupMembersPage{
+		GroupID:     doc.GroupID64,
+		MemberCount: doc.MemberCount,
+		Page:        page,
+		TotalPages:  doc.TotalPages,
+		Group:       doc.summary(),
+	}
+	if page <= doc.TotalPages {
+		p.Members = doc.Members
+	}
+	if page < doc.TotalPages {
+		p.NextPage = page + 1
+	}
+
+	return p, nil
+}
+
+// GetGroupSummary gets the group's name, avatar, headline and member counts. groupId can be either the group's
+// steamid64 or its account id (see ClanSteamID64). There's no web api for this either, so it comes from the first
+// page of the group's member list on the steam community site.
+func (sa *SteamAuther) GetGroupSummary(ctx context.Context, groupId string) (*GroupSummary, error) {
+	doc, err := sa.getMemberList(ctx, groupId, 1)
+	if err != nil {
+		return nil, fmt.Errorf("get group summary (%s): %w", groupId, err)
+	}
+
+	return doc.summary(), nil
+}
+
+// summary pulls the group's details out of the member list.
+func (doc *memberListXML) summary() *GroupSummary {
+	d := doc.GroupDetails
+	return &GroupSummary{
+		GroupID:       doc.GroupID64,
+		Name:          d.GroupName,
+		Url:           d.GroupURL,
+		Headline:      d.Headline,
+		Summary:       d.Summary,
+		AvatarIcon:    d.AvatarIcon,
+		AvatarMedium:  d.AvatarMedium,
+		AvatarFull:    d.AvatarFull,
+		MemberCount:   doc.MemberCount,
+		MembersInChat: d.MembersInChat,
+		MembersInGame: d.MembersInGame,
+		MembersOnline: d.MembersOnline,
+	}
+}
+
+// getMemberList gets and decodes a page of a group's memberslistxml.
+func (sa *SteamAuther) getMemberList(ctx context.Context, groupId string, page int) (*memberListXML, error) {
+	groupUrl, err := GroupUrl(groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := sa.get(ctx, groupUrl+"/memberslistxml/", url.Values{"xml": {"1"}, "p": {strconv.Itoa(max(page, 1))}})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	// Unknown groups get a 200 with an error document, which fails to decode as a memberList.
+	var doc memberListXML
+	if err := xml.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode member list: %w: %w", ErrNoData, err)
+	}
+
+	return &doc, nil
+}
+
+// AllGroupMembers is the group's whole member list as an iterator, fetching one page at a time as it's consumed, so
+// groups with hundreds of thousands of members don't have to be held in memory all at once. Errors are yielded with
+// an empty steamid, and end the iteration.
+func (sa *SteamAuther) AllGroupMembers(ctx context.Context, groupId string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for page, total := 1, 1; page <= total; page++ {
+			p, err := sa.GetGroupMembersPage(ctx, groupId, page)
+			if err != nil {
+				yield("", err)
+				return
+			}
+			total = p.TotalPages
+
+			for _, id := range p.Members {
+				if !yield(id, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// GetGroupMembers gets the group's whole member list, for syncing it somewhere. progress (if not nil) is called after
+// every page with how many members have been fetched so far and how many the group has in total, since big groups
+// take a while. Steam's member count can drift while the pages are fetched, so the two might not match at the end.
+func (sa *SteamAuther) GetGroupMembers(ctx context.Context, groupId string, progress func(fetched, total int)) ([]string, error) {
+	var members []string
+	for page := 1; page != 0; {
+		p, err := sa.GetGroupMembersPage(ctx, groupId, page)
+		if err != nil {
+			return nil, fmt.Errorf("get group members: %w", err)
+		}
+
+		if members == nil {
+			members = make([]string, 0, p.MemberCount)
+		}
+		members = append(members, p.Members...)
+		if progress != nil {
+			progress(len(members), p.MemberCount)
+		}
+
+		page = p.NextPage
+	}
+
+	return members, nil
+}
@@ -0,0 +1,148 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// memberLists answers memberslistxml like the steam community site would for group 103582791429521412, which has 3
+// pages of 2 members. Like steam, pages past the end get the last page again. Every other group gets steam's error
+// document. It counts the requests made in calls.
+func memberLists(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		if r.URL.Path != "/gid/103582791429521412/memberslistxml/" {
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><response><error><![CDATA[No group could be retrieved for the given URL.]]></error></response>`))
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("p"))
+		page = min(max(page, 1), 3)
+
+		var members strings.Builder
+		for i := range 2 {
+			fmt.Fprintf(&members, "<steamID64>%d</steamID64>", 76561197960265728+(page-1)*2+i+1)
+		}
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><memberList><groupID64>103582791429521412</groupID64>`+
+			`<groupDetails><groupName><![CDATA[Steamworks Development]]></groupName><groupURL><![CDATA[steamworks]]></groupURL>`+
+			`<headline><![CDATA[Steamworks]]></headline><membersInChat>1</membersInChat><membersInGame>2</membersInGame><membersOnline>3</membersOnline></groupDetails>`+
+			`<memberCount>6</memberCount><totalPages>3</totalPages><currentPage>%d</currentPage><members>%s</members></memberList>`, page, members.String())
+	}
+}
+
+func TestGetGroupMembersPage(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, memberLists(&calls))
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		page        int
+		wantPage    int
+		wantMembers int
+		wantNext    int
+	}{
+		{0, 1, 2, 2},
+		{1, 1, 2, 2},
+		{3, 3, 2, 0},
+		// steam sends the last page again, which mustn't look like more members
+		{5, 5, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.page), func(t *testing.T) {
+			p, err := sa.GetGroupMembersPage(context.Background(), "103582791429521412", tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.Page != tt.wantPage || len(p.Members) != tt.wantMembers || p.NextPage != tt.wantNext || p.TotalPages != 3 {
+				t.Errorf("page = %+v, want page %d with %d members and next %d", p, tt.wantPage, tt.wantMembers, tt.wantNext)
+			}
+		})
+	}
+
+	if _, err := sa.GetGroupMembersPage(context.Background(), "5", 1); !errors.Is(err, ErrNoData) {
+		t.Errorf("unknown group err = %v, want ErrNoData", err)
+	}
+}
+
+func TestAllGroupMembers(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, memberLists(&calls))
+	sa := New("key", "https://example.com")
+
+	// by account id this time
+	seen := make(map[string]bool)
+	for id, err := range sa.AllGroupMembers(context.Background(), "4") {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Errorf("%s yielded twice", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != 6 || calls.Load() != 3 {
+		t.Errorf("yielded %d members from %d requests, want 6 from 3", len(seen), calls.Load())
+	}
+
+	for _, err := range sa.AllGroupMembers(context.Background(), "5") {
+		if !errors.Is(err, ErrNoData) {
+			t.Errorf("unknown group err = %v, want ErrNoData", err)
+		}
+	}
+}
+
+func TestGetGroupMembers(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, memberLists(&calls))
+	sa := New("key", "https://example.com")
+
+	var progress []string
+	members, err := sa.GetGroupMembers(context.Background(), "103582791429521412", func(fetched, total int) {
+		progress = append(progress, fmt.Sprintf("%d/%d", fetched, total))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 6 || calls.Load() != 3 {
+		t.Errorf("got %d members from %d requests, want 6 from 3", len(members), calls.Load())
+	}
+	if strings.Join(progress, " ") != "2/6 4/6 6/6" {
+		t.Errorf("progress = %v, want a call per page", progress)
+	}
+
+	// progress is optional
+	if _, err := sa.GetGroupMembers(context.Background(), "103582791429521412", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := sa.GetGroupMembers(context.Background(), "5", nil); !errors.Is(err, ErrNoData) {
+		t.Errorf("unknown group err = %v, want ErrNoData", err)
+	}
+}
+
+func TestGetGroupSummary(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, memberLists(&calls))
+	sa := New("key", "https://example.com")
+
+	g, err := sa.GetGroupSummary(context.Background(), "4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := GroupSummary{GroupID: "103582791429521412", Name: "Steamworks Development", Url: "steamworks", Headline: "Steamworks", MemberCount: 6, MembersInChat: 1, MembersInGame: 2, MembersOnline: 3}
+	if *g != want {
+		t.Errorf("GetGroupSummary = %+v, want %+v", *g, want)
+	}
+
+	if _, err := sa.GetGroupSummary(context.Background(), "5"); !errors.Is(err, ErrNoData) {
+		t.Errorf("unknown group err = %v, want ErrNoData", err)
+	}
+}
@@ -0,0 +1,158 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GetUserGroupList returns the account ids (the "gid" steam uses) of the groups the user is a member of.
+// Returns ErrPrivateProfile if the user's profile is private.
+func (sa *SteamAuther) GetUserGroupList(ctx context.Context, steamid64 string) ([]string, error) {
+	var data struct {
+		Response struct {
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+			Groups  []struct {
+				Gid string `json:"gid"`
+			} `json:"groups"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "ISteamUser/GetUserGroupList/v1", url.Values{"steamid": {steamid64}}, &data); err != nil {
+		return nil, fmt.Errorf("get user group list (%s): %w", steamid64, err)
+	}
+
+	if !data.Response.Success {
+		return nil, fmt.Errorf("get user group list (%s): %w (%s)", steamid64, ErrPrivateProfile, data.Response.Error)
+	}
+
+	groups := make([]string, len(data.Response.Groups))
+	for i, g := range data.Response.Groups {
+		groups[i] = g.Gid
+	}
+
+	return groups, nil
+}
+
+// ClanAccountId turns a group id, either its clan steamid64, its steam3 id ("[g:1:4]") or its account id, into the
+// account id (the 32-bit "gid" GetUserGroupList returns).
+func ClanAccountId(groupId string) (string, error) {
+	id, err := parseClanID(groupId)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatUint(uint64(id.AccountID()), 10), nil
+}
+
+// ClanSteamID64 turns a group id, either its account id (like the gids GetUserGroupList returns), its steam3 id
+// ("[g:1:4]") or its clan steamid64, into the clan steamid64.
+func ClanSteamID64(groupId string) (string, error) {
+	id, err := parseClanID(groupId)
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// GroupUrl returns the group's page on the steam community site. groupId can be any of the forms ClanSteamID64 takes.
+func GroupUrl(groupId string) (string, error) {
+	id, err := parseClanID(groupId)
+	if err != nil {
+		return "", err
+	}
+
+	return CommunityBaseUrl + "/gid/" + id.String(), nil
+}
+
+// parseClanID parses a group id, either its account id, its steam3 id or its clan steamid64.
+func parseClanID(groupId string) (SteamID, error) {
+	if n, err := strconv.ParseUint(groupId, 10, 32); err == nil {
+		return NewClanSteamID(uint32(n)), nil
+	}
+
+	parse := ParseSteamID64
+	if strings.HasPrefix(groupId, "[") {
+		parse = ParseSteam3
+	}
+	id, err := parse(groupId)
+	if err != nil {
+		return 0, fmt.Errorf("parse group id %q: %w", groupId, err)
+	}
+	if !id.IsClan() || !id.IsValid() {
+		return 0, fmt.Errorf("parse group id %q: %w: not a group id", groupId, ErrInvalidSteamID)
+	}
+
+	return id, nil
+}
+
+// GetUserClanSteamIDs is GetUserGroupList, but returns the groups' clan steamid64s instead of their account ids.
+func (sa *SteamAuther) GetUserClanSteamIDs(ctx context.Context, steamid64 string) ([]string, error) {
+	groups, err := sa.GetUserGroupList(ctx, steamid64)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, gid := range groups {
+		if groups[i], err = ClanSteamID64(gid); err != nil {
+			return nil, fmt.Errorf("get user group list (%s): %w", steamid64, err)
+		}
+	}
+
+	return groups, nil
+}
+
+// IsGroupMember reports whether the user is a member of the group. groupId can be either the group's clan steamid64
+// or its account id. Returns ErrPrivateProfile if the user's profile is private.
+func (sa *SteamAuther) IsGroupMember(ctx context.Context, steamid64, groupId string) (bool, error) {
+	gid, err := ClanAccountId(groupId)
+	if err != nil {
+		return false, err
+	}
+
+	groups, err := sa.GetUserGroupList(ctx, steamid64)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(groups, gid), nil
+}
+
+// RequireGroup returns an AccessChecker that only lets in members of the group. groupId can be either the group's
+// steamid64 (ex. 103582791429521408) or its account id. Users with private profiles can't show they're members,
+// so they're refused too. Returns an error if groupId isn't a number, see MustRequireGroup for hardcoded ones.
+func (sa *SteamAuther) RequireGroup(groupId string) (AccessChecker, error) {
+	if _, err := ClanAccountId(groupId); err != nil {
+		return nil, fmt.Errorf("require group: %w", err)
+	}
+
+	return AccessCheckerFunc(func(ctx context.Context, steamid64 string) error {
+		member, err := sa.IsGroupMember(ctx, steamid64, groupId)
+		if errors.Is(err, ErrPrivateProfile) {
+			return &AccessDeniedError{SteamID: steamid64, Reason: "your profile is private, so we can't see your groups"}
+		}
+		if err != nil {
+			return fmt.Errorf("require group: %w", err)
+		}
+		if !member {
+			return &AccessDeniedError{SteamID: steamid64, Reason: "you need to be a member of the steam group"}
+		}
+
+		return nil
+	}), nil
+}
+
+// MustRequireGroup is RequireGroup, panicking if groupId isn't a number.
+func (sa *SteamAuther) MustRequireGroup(groupId string) AccessChecker {
+	c, err := sa.RequireGroup(groupId)
+	if err != nil {
+		panic("gosteamauth: " + err.Error())
+	}
+
+	return c
+}
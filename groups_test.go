@@ -0,0 +1,234 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// groupList answers GetUserGroupList with body, or with just status if it isn't 200.
+func groupList(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUser/GetUserGroupList/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(body))
+	}
+}
+
+func TestGetUserGroupList(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    int
+		wantErr error
+	}{
+		{"member of two", http.StatusOK, `{"response":{"success":true,"groups":[{"gid":"4"},{"gid":"7"}]}}`, 2, nil},
+		{"private", http.StatusOK, `{"response":{"success":false,"error":"Private profile"}}`, 0, ErrPrivateProfile},
+		// a 403 from the web api is the key's fault, not the user hiding their groups
+		{"bad key", http.StatusForbidden, "", 0, ErrSteamForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSteam(t, groupList(tt.status, tt.body))
+
+			groups, err := New("key", "https://example.com").GetUserGroupList(context.Background(), "76561197960287930")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrSteamForbidden) && errors.Is(err, ErrPrivateProfile) {
+				t.Errorf("err = %v is both forbidden and private", err)
+			}
+			if len(groups) != tt.want {
+				t.Errorf("groups = %v, want %d of them", groups, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireGroupIds(t *testing.T) {
+	sa := New("", "https://example.com")
+
+	for _, groupId := range []string{"103582791429521412", "4"} {
+		if _, err := sa.RequireGroup(groupId); err != nil {
+			t.Errorf("RequireGroup(%q) err = %v", groupId, err)
+		}
+	}
+
+	for _, groupId := range []string{"", "abc", "-4"} {
+		if _, err := sa.RequireGroup(groupId); err == nil {
+			t.Errorf("RequireGroup(%q) err = nil, want an error", groupId)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustRequireGroup(\"abc\") didn't panic")
+		}
+	}()
+	sa.MustRequireGroup("abc")
+}
+
+func TestRequireGroup(t *testing.T) {
+	tests := []struct {
+		name       string
+		groupId    string
+		status     int
+		body       string
+		wantDenied bool
+		wantErr    bool
+	}{
+		{"member by account id", "4", http.StatusOK, `{"response":{"success":true,"groups":[{"gid":"4"}]}}`, false, false},
+		{"member by steamid64", "103582791429521412", http.StatusOK, `{"response":{"success":true,"groups":[{"gid":"4"}]}}`, false, false},
+		{"not a member", "5", http.StatusOK, `{"response":{"success":true,"groups":[{"gid":"4"}]}}`, true, false},
+		{"private profile", "4", http.StatusOK, `{"response":{"success":false,"error":"Private profile"}}`, true, false},
+		{"bad key", "4", http.StatusForbidden, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSteam(t, groupList(tt.status, tt.body))
+
+			err := New("key", "https://example.com").MustRequireGroup(tt.groupId).CheckAccess(context.Background(), "76561197960287930")
+			if got := errors.Is(err, ErrAccessDenied); got != tt.wantDenied {
+				t.Errorf("err = %v, want denied %v", err, tt.wantDenied)
+			}
+			if got := err != nil && !errors.Is(err, ErrAccessDenied); got != tt.wantErr {
+				t.Errorf("err = %v, want a failed check %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRequireAccess(t *testing.T) {
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+
+	rec := httptest.NewRecorder()
+	if _, err := h.Sessions().Create(context.Background(), rec, "76561197960287930", nil); err != nil {
+		t.Fatal(err)
+	}
+	cookie := rec.Result().Cookies()[0]
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		name   string
+		list   *SteamIDList
+		cookie *http.Cookie
+		want   int
+	}{
+		{"allowed", NewAllowlist("76561197960287930"), cookie, http.StatusOK},
+		{"banned since logging in", NewDenylist("76561197960287930"), cookie, http.StatusForbidden},
+		{"not logged in", NewAllowlist("76561197960287930"), nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+			h.RequireAccess(tt.list)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestClanIds(t *testing.T) {
+	tests := []struct {
+		groupId     string
+		wantAccount string
+		wantSteamID string
+		wantErr     bool
+	}{
+		{"4", "4", "103582791429521412", false},
+		{"103582791429521412", "4", "103582791429521412", false},
+		{"0", "0", "103582791429521408", false},
+		{"[g:1:4]", "4", "103582791429521412", false},
+		// steam3 ids have to be a group's too
+		{"[U:1:4]", "", "", true},
+		{"[g:1:4", "", "", true},
+		// a user's steamid64 isn't a group's
+		{"76561197960287930", "", "", true},
+		{"4294967296", "", "", true},
+		{"abc", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupId, func(t *testing.T) {
+			account, err := ClanAccountId(tt.groupId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ClanAccountId err = %v, want error %v", err, tt.wantErr)
+			}
+			steamid, err := ClanSteamID64(tt.groupId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ClanSteamID64 err = %v, want error %v", err, tt.wantErr)
+			}
+			if account != tt.wantAccount || steamid != tt.wantSteamID {
+				t.Errorf("ids = %q, %q, want %q, %q", account, steamid, tt.wantAccount, tt.wantSteamID)
+			}
+		})
+	}
+}
+
+func TestGroupUrl(t *testing.T) {
+	for _, groupId := range []string{"4", "103582791429521412", "[g:1:4]"} {
+		got, err := GroupUrl(groupId)
+		if err != nil {
+			t.Fatalf("GroupUrl(%q) err = %v", groupId, err)
+		}
+		if want := "https://steamcommunity.com/gid/103582791429521412"; got != want {
+			t.Errorf("GroupUrl(%q) = %q, want %q", groupId, got, want)
+		}
+	}
+
+	if _, err := GroupUrl("[U:1:4]"); !errors.Is(err, ErrInvalidSteamID) {
+		t.Errorf("GroupUrl of a user err = %v, want ErrInvalidSteamID", err)
+	}
+}
+
+func TestIsGroupMember(t *testing.T) {
+	fakeSteam(t, groupList(http.StatusOK, `{"response":{"success":true,"groups":[{"gid":"4"},{"gid":"7"}]}}`))
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		groupId string
+		want    bool
+	}{
+		{"4", true},
+		{"103582791429521412", true},
+		{"5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.groupId, func(t *testing.T) {
+			got, err := sa.IsGroupMember(context.Background(), "76561197960287930", tt.groupId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("IsGroupMember = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	ids, err := sa.GetUserClanSteamIDs(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "103582791429521412" || ids[1] != "103582791429521415" {
+		t.Errorf("GetUserClanSteamIDs = %v, want the clan steamid64s of groups 4 and 7", ids)
+	}
+}
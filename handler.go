@@ -0,0 +1,465 @@
+package gosteamauth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Handler runs the whole steam login flow over http: sending users off to steam, validating the callback and keeping
+// them logged in with a session. Mount Login, Callback and Logout wherever you like, as long as Callback ends up
+// at the callbackUrl given to NewHandler.
+type Handler struct {
+	auther      *SteamAuther
+	sessions    *Sessions
+	callbackUrl string
+
+	// Tenant is the id of the tenant the Handler logs users into, set by TenantRegistry. Sessions are stamped with it,
+	// and sessions stamped with anything else are ignored.
+	Tenant string
+	// Attempts, if set, ties every callback to a login started by Login, and to the browser that started it with a
+	// short-lived cookie (AttemptCookieName), rejecting callbacks that aren't. This stops someone from logging a victim
+	// into the attacker's account by sending them a callback link. Attempts started by LoginUrl (and so Button) can't
+	// set the cookie, so they're only tied to the login: if an attacker can get hold of those, so can their callbacks.
+	Attempts *AttemptTracker
+	// Access is checked right after the user comes back from steam, before their session is started.
+	// Users that are refused get a 403 with the reason from the AccessDeniedError.
+	Access []AccessChecker
+	// FetchUser makes the callback look up the user's profile and keep a snapshot of it in the session.
+	// This costs one web api request per login.
+	FetchUser bool
+	// Roles, if set, resolves the user's roles when they log in, keeping them in their session. See RequireRole.
+	Roles RoleResolver
+	// RolesTTL, if set, resolves roles again on the first request after they're this old, so role changes reach
+	// users without them logging in again. If resolving fails, the old roles are kept.
+	RolesTTL time.Duration
+	// CallbackMemoTTL, if set, remembers successful callbacks for this long, so a user refreshing the callback page
+	// is sent on to where they were going instead of getting an error (steam only vouches for a callback once).
+	// Repeats only count from the browser that got the session out of the first one. Something like a minute is plenty.
+	CallbackMemoTTL time.Duration
+	// LoginRedirect is where users are sent after logging in. Defaults to "/".
+	LoginRedirect string
+	// Redirects, if set, lets Login take a NextParam query parameter for where to send the user after logging in,
+	// instead of LoginRedirect. It's checked when the login starts and again when the user comes back.
+	Redirects *RedirectPolicy
+	// Remember, if set, lets users tick "remember me" (see RememberParam) to be logged back in when their session runs
+	// out. Remembered users go through Access again when they come back.
+	Remember *RememberMe
+	// LogoutRedirect is where users are sent after logging out. Defaults to "/".
+	LogoutRedirect string
+	// RateLimit, if set, throttles Login, Callback and ButtonHandler per client IP, so bots can't hammer them.
+	RateLimit *RateLimiter
+	// OnLoginSuccess, if set, is called after every successful login, once the user's session has started.
+	OnLoginSuccess func(ev *LoginEvent)
+	// OnLoginFailure, if set, is called whenever a callback fails, with ev.Err saying why.
+	// Both hooks run before the response is sent, so keep them quick or hand the work off.
+	OnLoginFailure func(ev *LoginEvent)
+	// AllowImpersonation turns on Impersonate and ImpersonateHandler. Leave it off in production unless you really
+	// mean it.
+	AllowImpersonation bool
+	// Audit, if set, is sent an AuditEvent when a login starts, is validated or rejected, and when a user logs out.
+	Audit AuditSink
+	// AuditErrors, if set, is called with errors from Audit. Failing to audit never stops a login.
+	AuditErrors func(err error)
+	// ErrorHandler renders failures from Login, Callback, Logout and the RequireAuth, RequireAccess and RequireRole
+	// middlewares, ex. as a branded error page. Defaults to DefaultErrorHandler. See HandlerError for what it's given.
+	ErrorHandler ErrorHandler
+	// ClientIP works out client IPs for RateLimit, Audit, LoginEvents and the devices recorded on sessions and
+	// remember me tokens. Leave it nil to use the connection's address, which is only right if nothing sits between
+	// the app and the internet.
+	ClientIP *ClientIPResolver
+}
+
+// NewHandler returns a new Handler. callbackUrl is the full url the Callback handler is reachable at
+// (ex. http://localhost:8080/auth/callback).
+func NewHandler(auther *SteamAuther, sessions *Sessions, callbackUrl string) *Handler {
+	return &Handler{
+		auther:         auther,
+		sessions:       sessions,
+		callbackUrl:    callbackUrl,
+		LoginRedirect:  "/",
+		LogoutRedirect: "/",
+	}
+}
+
+// Sessions returns the Sessions the handler keeps users logged in with.
+func (h *Handler) Sessions() *Sessions {
+	return h.sessions
+}
+
+// Login sends the user off to steam to log in.
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	if h.limited(w, r) {
+		return
+	}
+
+	var next string
+	if h.Redirects != nil {
+		// Anything not allowed is quietly dropped, the user still gets to log in.
+		next, _ = h.Redirects.Check(r.URL.Query().Get(NextParam))
+	}
+
+	remember := h.Remember != nil && r.URL.Query().Get(RememberParam) != ""
+
+	u, attemptId, err := h.loginUrl(next, remember, true)
+	if err != nil {
+		h.fail(w, r, &HandlerError{Kind: ErrorKindInternal, Status: http.StatusInternalServerError, Message: "failed to start steam login", Err: err})
+		return
+	}
+	if attemptId != "" {
+		h.setAttemptCookie(w, attemptId, time.Now().Add(h.Attempts.lifetime))
+	}
+
+	h.audit(r, AuditEvent{Type: AuditLoginStarted, AttemptID: attemptId})
+
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+// LoginUrl starts a login attempt (if Attempts is set) and returns the steam url for it.
+// Login redirects to this, but it's also useful for linking straight to steam, see Button.
+func (h *Handler) LoginUrl() (string, error) {
+	u, _, err := h.loginUrl("", false, false)
+	return u, err
+}
+
+// loginUrl is LoginUrl, carrying next and remember through to the callback if they're set. It also returns the
+// attempt's id, if there is one. bound marks the attempt as needing the attempt cookie at the callback.
+func (h *Handler) loginUrl(next string, remember, bound bool) (string, string, error) {
+	returnUrl := h.callbackUrl
+	if next != "" || remember {
+		u, err := url.Parse(returnUrl)
+		if err != nil {
+			return "", "", fmt.Errorf("login url: %w", err)
+		}
+
+		q := u.Query()
+		if next != "" {
+			q.Set(NextParam, next)
+		}
+		if remember {
+			q.Set(RememberParam, "1")
+		}
+		u.RawQuery = q.Encode()
+		returnUrl = u.String()
+	}
+
+	var attemptId string
+	if h.Attempts != nil {
+		var values map[string]string
+		if bound {
+			values = map[string]string{attemptBoundValue: "1"}
+		}
+		a, err := h.Attempts.Begin(values)
+		if err != nil {
+			return "", "", fmt.Errorf("login url: %w", err)
+		}
+
+		attemptId = a.ID
+		returnUrl, err = a.AddState(returnUrl)
+		if err != nil {
+			return "", "", fmt.Errorf("login url: %w", err)
+		}
+	}
+
+	u, err := h.auther.GetAuthUrl(returnUrl)
+	if err != nil {
+		return "", "", fmt.Errorf("login url: %w", err)
+	}
+
+	return u, attemptId, nil
+}
+
+// Callback validates the user coming back from steam and starts their session.
+func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
+	if h.limited(w, r) {
+		return
+	}
+
+	q := r.URL.Query()
+	if h.CallbackMemoTTL > 0 {
+		if target, ok := h.repeatedCallback(r, q); ok {
+			http.Redirect(w, r, target, http.StatusFound)
+			return
+		}
+	}
+
+	ev := h.loginEvent(r)
+
+	// Everything we put in the return url is read back from the copy steam signed, not the request's url.
+	returnTo, err := returnToQuery(q, h.callbackUrl)
+	if err != nil {
+		h.loginFailed(w, ev, callbackError(err, ErrorKindInvalidAssertion, http.StatusUnauthorized, "invalid steam login"))
+		return
+	}
+
+	if h.Attempts != nil {
+		state := returnTo.Get(AttemptStateParam)
+		a, err := h.Attempts.Complete(state)
+		if err != nil {
+			h.loginFailed(w, ev, callbackError(err, ErrorKindBadRequest, http.StatusBadRequest, "unknown or expired login attempt, please try again"))
+			return
+		}
+
+		if a.Values[attemptBoundValue] != "" {
+			h.setAttemptCookie(w, "", time.Unix(0, 0))
+			if c, err := r.Cookie(AttemptCookieName); err != nil || subtle.ConstantTimeCompare([]byte(c.Value), []byte(state)) != 1 {
+				h.loginFailed(w, ev, callbackError(ErrUnknownAttempt, ErrorKindBadRequest, http.StatusBadRequest, "this login was started in another browser, please try again"))
+				return
+			}
+		}
+	}
+
+	steamid, err := h.auther.validateCallback(r.Context(), q)
+	if err != nil {
+		// Anything steam didn't answer with is on steam, the rest is the callback not adding up.
+		h.loginFailed(w, ev, callbackError(err, ErrorKindInvalidAssertion, http.StatusUnauthorized, "invalid steam login"))
+		return
+	}
+	ev.SteamID = steamid
+
+	if err := CheckAccess(r.Context(), steamid, h.Access...); err != nil {
+		h.loginFailed(w, ev, callbackError(err, ErrorKindInternal, http.StatusInternalServerError, "failed to check access"))
+		return
+	}
+
+	if h.FetchUser {
+		ev.User, err = h.auther.GetSteamUser(steamid)
+		if err != nil {
+			h.loginFailed(w, ev, callbackError(err, ErrorKindSteamUnavailable, http.StatusBadGateway, "failed to get steam user"))
+			return
+		}
+	}
+
+	sess, err := h.sessions.create(r.Context(), w, steamid, ev.User, h.device(r), h.Tenant)
+	if err != nil {
+		h.loginFailed(w, ev, callbackError(err, ErrorKindInternal, http.StatusInternalServerError, "failed to start session"))
+		return
+	}
+
+	if h.Roles != nil {
+		if err := h.resolveRoles(r.Context(), sess); err != nil {
+			h.loginFailed(w, ev, callbackError(err, ErrorKindInternal, http.StatusInternalServerError, "failed to work out your roles"))
+			return
+		}
+	}
+
+	if h.Remember != nil && returnTo.Get(RememberParam) != "" {
+		// The user is logged in either way, they'll just have to log in again once the session runs out.
+		_, _ = h.Remember.Issue(r.Context(), w, steamid, h.device(r))
+	}
+
+	h.audit(r, AuditEvent{Type: AuditLoginValidated, SteamID: steamid})
+	if h.OnLoginSuccess != nil {
+		h.OnLoginSuccess(ev)
+	}
+
+	target := h.redirectTarget(returnTo)
+	if h.CallbackMemoTTL > 0 {
+		h.rememberCallback(r.Context(), q, steamid, target)
+	}
+
+	http.Redirect(w, r, target, http.StatusFound)
+}
+
+// redirectTarget is where to send the user after the callback, checking the next parameter all over again in case
+// someone crafted their own callback url.
+func (h *Handler) redirectTarget(q url.Values) string {
+	if h.Redirects != nil {
+		if next, ok := h.Redirects.Check(q.Get(NextParam)); ok {
+			return next
+		}
+	}
+
+	return h.LoginRedirect
+}
+
+// setAttemptCookie sets (or with an empty value, clears) the cookie tying a login attempt to the browser. It's
+// scoped like the session cookie.
+func (h *Handler) setAttemptCookie(w http.ResponseWriter, value string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     AttemptCookieName,
+		Value:    value,
+		Path:     h.sessions.Path,
+		Domain:   h.sessions.Domain,
+		Expires:  expires,
+		Secure:   !h.sessions.Insecure,
+		HttpOnly: true,
+		// Lax still sends it on the top-level redirect back from steam.
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// limited writes a 429 and returns true if the client is over the rate limit.
+func (h *Handler) limited(w http.ResponseWriter, r *http.Request) bool {
+	if h.RateLimit == nil {
+		return false
+	}
+
+	ok, wait := h.RateLimit.Allow(h.ClientIP.ClientIP(r))
+	if ok {
+		return false
+	}
+
+	w.Header().Set("Retry-After", retryAfter(wait))
+	h.fail(w, r, &HandlerError{Kind: ErrorKindRateLimited, Status: http.StatusTooManyRequests, Message: "too many requests, slow down"})
+	return true
+}
+
+// Logout ends the user's session. Mount this on a POST route, so other sites can't log your users out with a link.
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var steamid string
+	if sess, err := h.sessions.Get(r); err == nil {
+		steamid = sess.SteamID
+	}
+
+	if err := h.sessions.Destroy(w, r); err != nil {
+		h.fail(w, r, &HandlerError{Kind: ErrorKindInternal, Status: http.StatusInternalServerError, Message: "failed to log out", Err: err})
+		return
+	}
+
+	if h.Remember != nil {
+		if err := h.Remember.Forget(w, r); err != nil {
+			h.fail(w, r, &HandlerError{Kind: ErrorKindInternal, Status: http.StatusInternalServerError, Message: "failed to log out", Err: err})
+			return
+		}
+	}
+
+	if steamid != "" {
+		h.audit(r, AuditEvent{Type: AuditLoggedOut, SteamID: steamid})
+	}
+
+	http.Redirect(w, r, h.LogoutRedirect, http.StatusSeeOther)
+}
+
+// LoadSession puts the request's session in its context, if it has one, and always calls next.
+// Use this for pages that work logged out, but look different logged in. See SessionFromContext.
+func (h *Handler) LoadSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess, err := h.CurrentSession(w, r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithSession(r.Context(), sess)))
+	})
+}
+
+// RequireAuth only lets requests with a session through to next, and puts the session in the request context.
+// Everything else gets a 401.
+func (h *Handler) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess, err := h.CurrentSession(w, r)
+		if errors.Is(err, ErrNoSession) {
+			h.fail(w, r, &HandlerError{Kind: ErrorKindUnauthenticated, Status: http.StatusUnauthorized, Message: "you need to log in with steam first", Err: err})
+			return
+		}
+		if err != nil {
+			h.fail(w, r, &HandlerError{Kind: ErrorKindInternal, Status: http.StatusInternalServerError, Message: "failed to load session", Err: err})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithSession(r.Context(), sess)))
+	})
+}
+
+// CurrentSession returns the request's session, or ErrNoSession. Unlike Sessions().Get, it logs users back in with
+// their remember me token if Remember is set, and keeps roles fresh if RolesTTL is. Middlewares use this, use it too
+// if you're writing your own.
+func (h *Handler) CurrentSession(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	sess, err := h.sessions.Get(r)
+	if err == nil && sess.Tenant != h.Tenant {
+		sess, err = nil, ErrNoSession
+	}
+	if err == nil && h.rolesStale(sess) {
+		// Keep the old roles if this fails, rather than locking everyone out while the resolver is down.
+		_ = h.resolveRoles(r.Context(), sess)
+	}
+	if !errors.Is(err, ErrNoSession) || h.Remember == nil {
+		return sess, err
+	}
+
+	tok, err := h.Remember.Restore(w, r, h.device(r))
+	if errors.Is(err, ErrInvalidRememberToken) {
+		return nil, ErrNoSession
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Whatever kept the user out at login still applies. Refused users lose the token, so they aren't checked
+	// again on every request.
+	if err := CheckAccess(r.Context(), tok.SteamID, h.Access...); err != nil {
+		if errors.Is(err, ErrAccessDenied) {
+			_ = h.Remember.Forget(w, r)
+			return nil, ErrNoSession
+		}
+		return nil, err
+	}
+
+	var user *SteamUser
+	if h.FetchUser {
+		// The user can do without an up to date profile, it's not worth logging them out over.
+		user, _ = h.auther.GetSteamUser(tok.SteamID)
+	}
+
+	sess, err = h.sessions.create(r.Context(), w, tok.SteamID, user, h.device(r), h.Tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Roles != nil {
+		if err := h.resolveRoles(r.Context(), sess); err != nil {
+			return nil, err
+		}
+	}
+
+	return sess, nil
+}
+
+// device describes the request's device, for sessions and remember me tokens.
+func (h *Handler) device(r *http.Request) Device {
+	return Device{UserAgent: r.UserAgent(), IP: h.ClientIP.ClientIP(r)}
+}
+
+// RequireAccess is RequireAuth, but also runs checkers against the logged in user on every request, so users who
+// stop passing them (leaving a group, getting banned) are cut off without waiting for their session to end.
+// Checks that call the web api cost a request each time, so consider caching them or only checking in Access.
+func (h *Handler) RequireAccess(checkers ...AccessChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return h.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sess, _ := SessionFromContext(r.Context())
+			if !h.checkAccess(w, r, sess.SteamID, checkers) {
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+// checkAccess runs checkers, writing the error response and returning false if the user isn't let in.
+func (h *Handler) checkAccess(w http.ResponseWriter, r *http.Request, steamid string, checkers []AccessChecker) bool {
+	if err := CheckAccess(r.Context(), steamid, checkers...); err != nil {
+		h.fail(w, r, callbackError(err, ErrorKindInternal, http.StatusInternalServerError, "failed to check access"))
+		return false
+	}
+
+	return true
+}
+
+// accessStatus returns the status code and message to respond with for an error from CheckAccess.
+func accessStatus(err error) (int, string) {
+	var denied *AccessDeniedError
+	if errors.As(err, &denied) {
+		return http.StatusForbidden, "you're not allowed in here: " + denied.Reason
+	}
+	if errors.Is(err, ErrAccessDenied) {
+		return http.StatusForbidden, "you're not allowed in here"
+	}
+
+	return http.StatusInternalServerError, "failed to check access"
+}
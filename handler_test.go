@@ -0,0 +1,173 @@
+package gosteamauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeSteam sends every request made with the default http client to h instead of steam, for the rest of the test.
+func fakeSteam(t *testing.T, h http.HandlerFunc) {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := http.DefaultClient.Transport
+	http.DefaultClient.Transport = rewriteTransport{target: target}
+	t.Cleanup(func() { http.DefaultClient.Transport = old })
+}
+
+// rewriteTransport sends requests to target, keeping their path and query.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// validOpenId answers every check_authentication request as valid.
+func validOpenId(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:true\n"))
+}
+
+// startLogin runs h.Login and returns the return_to steam was given, and the attempt cookie if one was set.
+func startLogin(t *testing.T, h *Handler) (string, *http.Cookie) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.Login(rec, httptest.NewRequest(http.MethodGet, "https://example.com/login", nil))
+	steamUrl, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cookie *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == AttemptCookieName {
+			cookie = c
+		}
+	}
+
+	return steamUrl.Query().Get("openid.return_to"), cookie
+}
+
+// steamCallback builds the callback steam would send the user back with after logging in as steamid64.
+func steamCallback(returnTo, steamid64 string) *http.Request {
+	q := url.Values{
+		"openid.ns":             {"http://specs.openid.net/auth/2.0"},
+		"openid.mode":           {"id_res"},
+		"openid.op_endpoint":    {OpenIdLoginUrl},
+		"openid.claimed_id":     {"https://steamcommunity.com/openid/id/" + steamid64},
+		"openid.identity":       {"https://steamcommunity.com/openid/id/" + steamid64},
+		"openid.return_to":      {returnTo},
+		"openid.response_nonce": {"2024-01-01T00:00:00Zabc"},
+		"openid.assoc_handle":   {"1234567890"},
+		"openid.signed":         {"signed,op_endpoint,claimed_id,identity,return_to,response_nonce,assoc_handle"},
+		"openid.sig":            {"c2lnbmF0dXJl"},
+	}
+
+	// steam sends the user back to return_to, so its query (the state) ends up next to the openid params
+	if u, err := url.Parse(returnTo); err == nil {
+		for k, v := range u.Query() {
+			q[k] = v
+		}
+	}
+
+	return httptest.NewRequest(http.MethodGet, "https://example.com/callback?"+q.Encode(), nil)
+}
+
+func TestCallbackRequiresAttemptCookie(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	tests := []struct {
+		name string
+		// cookie picks the attempt cookie sent with the callback, given the one Login set.
+		cookie      func(c *http.Cookie) *http.Cookie
+		want        int
+		wantSession bool
+	}{
+		{"same browser", func(c *http.Cookie) *http.Cookie { return c }, http.StatusFound, true},
+		// the attacker's own callback, sent to a victim's browser which never started the login
+		{"other browser", func(c *http.Cookie) *http.Cookie { return nil }, http.StatusBadRequest, false},
+		{"other attempt's cookie", func(c *http.Cookie) *http.Cookie {
+			return &http.Cookie{Name: AttemptCookieName, Value: "someone-elses-attempt"}
+		}, http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+			h.Attempts = NewAttemptTracker(time.Minute)
+			defer h.Attempts.Close()
+
+			returnTo, cookie := startLogin(t, h)
+			if cookie == nil {
+				t.Fatal("login didn't set the attempt cookie")
+			}
+
+			req := steamCallback(returnTo, "76561197960287930")
+			if c := tt.cookie(cookie); c != nil {
+				req.AddCookie(c)
+			}
+			rec := httptest.NewRecorder()
+			h.Callback(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+
+			var gotSession bool
+			for _, c := range rec.Result().Cookies() {
+				if c.Name == h.Sessions().CookieName && c.Value != "" {
+					gotSession = true
+				}
+			}
+			if gotSession != tt.wantSession {
+				t.Errorf("started a session = %v, want %v", gotSession, tt.wantSession)
+			}
+		})
+	}
+}
+
+func TestSessionsSaveExpired(t *testing.T) {
+	s := NewSessions(NewMemoryStore())
+	rec := httptest.NewRecorder()
+
+	sess, err := s.Create(t.Context(), rec, "76561197960287930", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sess.ExpiresAt = time.Now().Add(-time.Second)
+	if err := s.Save(t.Context(), sess); err == nil {
+		t.Fatal("Save of an expired session succeeded")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(rec.Result().Cookies()[0])
+	if got, err := s.Get(req); err == nil && got.ExpiresAt.Before(time.Now()) {
+		t.Errorf("Get returned the expired session")
+	}
+}
+
+func TestSessionsZeroTTL(t *testing.T) {
+	s := NewSessions(NewMemoryStore())
+	s.TTL = 0
+
+	// a ttl of 0 would otherwise keep the session in the store forever
+	if _, err := s.Create(t.Context(), httptest.NewRecorder(), "76561197960287930", nil); err == nil {
+		t.Error("Create with a zero TTL succeeded")
+	}
+}
@@ -0,0 +1,50 @@
+package gosteamauth
+
+import (
+	"net/http"
+	"time"
+)
+
+// LoginEvent describes a login attempt, for Handler's OnLoginSuccess and OnLoginFailure hooks.
+type LoginEvent struct {
+	// SteamID is the user's steamid64. It's empty if the login failed before steam said who they are.
+	SteamID string
+	// User is the user's profile, if the Handler has FetchUser set and it got that far.
+	User *SteamUser
+	// Err is why the login failed, and nil for successful logins. It's a *HandlerError, see ErrorKindOf.
+	Err error
+
+	// Nonce is steam's openid.response_nonce for the callback.
+	Nonce string
+
+	// Time is when the callback was received.
+	Time time.Time
+	// ClientIP is the client's IP, as worked out by the Handler's ClientIP resolver.
+	ClientIP string
+	// UserAgent is the client's user agent.
+	UserAgent string
+	// Request is the callback request, for anything else you need from it.
+	Request *http.Request
+}
+
+// loginEvent starts the LoginEvent for a callback request.
+func (h *Handler) loginEvent(r *http.Request) *LoginEvent {
+	return &LoginEvent{
+		Time:      time.Now(),
+		ClientIP:  h.ClientIP.ClientIP(r),
+		UserAgent: r.UserAgent(),
+		Nonce:     r.URL.Query().Get("openid.response_nonce"),
+		Request:   r,
+	}
+}
+
+// loginFailed fires OnLoginFailure and renders the error.
+func (h *Handler) loginFailed(w http.ResponseWriter, ev *LoginEvent, he *HandlerError) {
+	ev.Err = he
+	h.audit(ev.Request, AuditEvent{Type: AuditLoginRejected, SteamID: ev.SteamID, Reason: he.Error()})
+	if h.OnLoginFailure != nil {
+		h.OnLoginFailure(ev)
+	}
+
+	h.fail(w, ev.Request, he)
+}
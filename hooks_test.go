@@ -0,0 +1,61 @@
+package gosteamauth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLoginHooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		steam       http.HandlerFunc
+		access      []AccessChecker
+		wantSuccess bool
+		wantErr     error
+	}{
+		{"success", validOpenId, nil, true, nil},
+		{"steam says no", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:false\n"))
+		}, nil, false, ErrInvalidAuthRequest},
+		{"refused", validOpenId, []AccessChecker{NewDenylist("76561197960287930")}, false, ErrAccessDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSteam(t, tt.steam)
+
+			h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+			h.Access = tt.access
+
+			var success, failure *LoginEvent
+			h.OnLoginSuccess = func(ev *LoginEvent) { success = ev }
+			h.OnLoginFailure = func(ev *LoginEvent) { failure = ev }
+
+			req := steamCallback("https://example.com/callback", "76561197960287930")
+			req.RemoteAddr = "203.0.113.7:1234"
+			req.Header.Set("User-Agent", "test")
+			h.Callback(httptest.NewRecorder(), req)
+
+			ev := failure
+			if tt.wantSuccess {
+				ev = success
+			}
+			if ev == nil || (success != nil) == (failure != nil) {
+				t.Fatalf("success = %+v, failure = %+v, want only success %v", success, failure, tt.wantSuccess)
+			}
+
+			if !errors.Is(ev.Err, tt.wantErr) || (tt.wantErr == nil) != (ev.Err == nil) {
+				t.Errorf("Err = %v, want %v", ev.Err, tt.wantErr)
+			}
+			if ev.ClientIP != "203.0.113.7" || ev.UserAgent != "test" || ev.Time.IsZero() {
+				t.Errorf("ev = %+v, want the client's details", ev)
+			}
+			// steam only vouches for the steamid once the callback has been validated
+			if wantId := tt.wantErr != ErrInvalidAuthRequest; (ev.SteamID != "") != wantId {
+				t.Errorf("SteamID = %q, want one %v", ev.SteamID, wantId)
+			}
+		})
+	}
+}
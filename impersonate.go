@@ -0,0 +1,84 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrImpersonationDisabled is returned by Handler.Impersonate unless Handler.AllowImpersonation is turned on.
+var ErrImpersonationDisabled = errors.New("impersonation is disabled")
+
+// AuditImpersonated is someone starting a session as another user. SteamID is the user being impersonated, and
+// Reason says who by.
+const AuditImpersonated AuditEventType = "impersonated"
+
+// Impersonate starts a session on w for steamid64 without them logging in, so support staff and developers can see
+// what they see. by is the steamid64 of whoever is doing it, which is kept in the session's ImpersonatedBy.
+// Access isn't checked, but roles are resolved as normal, so whoever can impersonate can act as anyone, admins
+// included. Returns ErrImpersonationDisabled unless AllowImpersonation is on.
+func (h *Handler) Impersonate(ctx context.Context, w http.ResponseWriter, steamid64, by string) (*Session, error) {
+	if !h.AllowImpersonation {
+		return nil, ErrImpersonationDisabled
+	}
+
+	if id, err := ParseSteamID64(steamid64); err != nil || !id.IsValid() || !id.IsIndividual() {
+		return nil, fmt.Errorf("impersonate (%s): %w", steamid64, ErrInvalidSteamID)
+	}
+
+	var user *SteamUser
+	if h.FetchUser {
+		u, err := h.auther.GetSteamUser(steamid64)
+		if err != nil {
+			return nil, fmt.Errorf("impersonate (%s): %w", steamid64, err)
+		}
+		user = u
+	}
+
+	sess, err := h.sessions.create(ctx, w, steamid64, user, Device{}, h.Tenant)
+	if err != nil {
+		return nil, fmt.Errorf("impersonate (%s): %w", steamid64, err)
+	}
+
+	sess.ImpersonatedBy = by
+	if err := h.sessions.Save(ctx, sess); err != nil {
+		return nil, fmt.Errorf("impersonate (%s): %w", steamid64, err)
+	}
+
+	if h.Roles != nil {
+		if err := h.resolveRoles(ctx, sess); err != nil {
+			return nil, fmt.Errorf("impersonate (%s): %w", steamid64, err)
+		}
+	}
+
+	return sess, nil
+}
+
+// ImpersonateHandler is an endpoint for Impersonate, only usable by logged in users with the role. POST it a form
+// with the "steamid" to impersonate, and the caller's session is swapped for one as that user. Log out to stop.
+// Responds with a 404 unless AllowImpersonation is on, so it doesn't advertise itself.
+func (h *Handler) ImpersonateHandler(role string) http.Handler {
+	return h.RequireRole(role)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.AllowImpersonation {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		admin, _ := SessionFromContext(r.Context())
+		target := r.PostFormValue("steamid")
+
+		if _, err := h.Impersonate(r.Context(), w, target, admin.SteamID); err != nil {
+			http.Error(w, "failed to impersonate user", http.StatusBadRequest)
+			return
+		}
+
+		h.audit(r, AuditEvent{Type: AuditImpersonated, SteamID: target, Reason: "impersonated by " + admin.SteamID})
+		http.Redirect(w, r, h.LoginRedirect, http.StatusSeeOther)
+	}))
+}
@@ -0,0 +1,124 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestImpersonate(t *testing.T) {
+	tests := []struct {
+		name    string
+		allow   bool
+		target  string
+		wantErr bool
+		wantIs  error
+	}{
+		{"allowed", true, "76561197960287931", false, nil},
+		{"disabled", false, "76561197960287931", true, ErrImpersonationDisabled},
+		{"not a steamid", true, "alice", true, ErrInvalidSteamID},
+		{"a group", true, "103582791429521412", true, ErrInvalidSteamID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+			h.AllowImpersonation = tt.allow
+			h.Roles = StaticRoles(map[string][]string{"76561197960287931": {"member"}})
+
+			sess, err := h.Impersonate(context.Background(), httptest.NewRecorder(), tt.target, "76561197960287930")
+			if (err != nil) != tt.wantErr || (tt.wantIs != nil && !errors.Is(err, tt.wantIs)) {
+				t.Fatalf("err = %v, want error %v (%v)", err, tt.wantErr, tt.wantIs)
+			}
+			if err != nil {
+				return
+			}
+
+			if sess.SteamID != tt.target || sess.ImpersonatedBy != "76561197960287930" {
+				t.Errorf("sess = %+v, want %s impersonated by 76561197960287930", sess, tt.target)
+			}
+			if !sess.HasRole("member") {
+				t.Errorf("roles = %v, want the target's roles", sess.Roles)
+			}
+		})
+	}
+}
+
+func TestImpersonateHandler(t *testing.T) {
+	newHandler := func(allow bool) (*Handler, *http.Cookie, *http.Cookie, *recordSink) {
+		h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+		h.AllowImpersonation = allow
+		h.Roles = StaticRoles(map[string][]string{"76561197960287930": {"support"}})
+		sink := &recordSink{}
+		h.Audit = sink
+
+		cookie := func(steamid64 string) *http.Cookie {
+			rec := httptest.NewRecorder()
+			sess, err := h.Sessions().Create(context.Background(), rec, steamid64, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := h.resolveRoles(context.Background(), sess); err != nil {
+				t.Fatal(err)
+			}
+			return rec.Result().Cookies()[0]
+		}
+
+		return h, cookie("76561197960287930"), cookie("76561197960287932"), sink
+	}
+
+	tests := []struct {
+		name   string
+		allow  bool
+		method string
+		// support picks the support user's cookie, rather than a user without the role.
+		support bool
+		want    int
+	}{
+		{"support staff", true, http.MethodPost, true, http.StatusSeeOther},
+		{"without the role", true, http.MethodPost, false, http.StatusForbidden},
+		{"disabled", false, http.MethodPost, true, http.StatusNotFound},
+		{"GET", true, http.MethodGet, true, http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, support, user, sink := newHandler(tt.allow)
+
+			req := httptest.NewRequest(tt.method, "/impersonate", strings.NewReader(url.Values{"steamid": {"76561197960287931"}}.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.support {
+				req.AddCookie(support)
+			} else {
+				req.AddCookie(user)
+			}
+			rec := httptest.NewRecorder()
+			h.ImpersonateHandler("support").ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want != http.StatusSeeOther {
+				return
+			}
+
+			if len(sink.events) != 1 || sink.events[0].Type != AuditImpersonated || sink.events[0].SteamID != "76561197960287931" {
+				t.Errorf("audit = %+v, want the impersonation", sink.events)
+			}
+
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+			req.AddCookie(rec.Result().Cookies()[0])
+			sess, err := h.Sessions().Get(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sess.SteamID != "76561197960287931" || sess.ImpersonatedBy != "76561197960287930" {
+				t.Errorf("new session = %+v, want the target impersonated by support", sess)
+			}
+		})
+	}
+}
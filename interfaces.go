@@ -0,0 +1,421 @@
+package gosteamauth
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// The sub-clients below group the wrapped web api methods by the steam interface they're on, named as steam names
+// them, so the methods are easy to find next to steam's docs:
+//
+//	games, err := auther.IPlayerService().GetOwnedGames(ctx, id, gosteamauth.OwnedGamesFilter{IncludeAppInfo: true})
+//
+// They're thin views over the SteamAuther (sharing its key and config), so they're cheap to make and the methods
+// on SteamAuther itself keep working the same.
+
+// WebApiInterface is a web api interface, for calling the methods on it that the package doesn't wrap. Every
+// sub-client has one.
+type WebApiInterface struct {
+	sa   *SteamAuther
+	name string
+}
+
+// Name returns the interface's name (ex. "ISteamUser").
+func (i WebApiInterface) Name() string {
+	return i.name
+}
+
+// Call is SteamAuther.Call for a method on the interface.
+func (i WebApiInterface) Call(ctx context.Context, method string, version int, params url.Values, out any) error {
+	return i.sa.Call(ctx, i.name, method, version, params, out)
+}
+
+// CallPost is SteamAuther.CallPost for a method on the interface.
+func (i WebApiInterface) CallPost(ctx context.Context, method string, version int, params url.Values, out any) error {
+	return i.sa.CallPost(ctx, i.name, method, version, params, out)
+}
+
+// ISteamUserClient is the ISteamUser interface.
+type ISteamUserClient struct{ WebApiInterface }
+
+// ISteamUser returns the ISteamUser interface.
+func (sa *SteamAuther) ISteamUser() ISteamUserClient {
+	return ISteamUserClient{WebApiInterface{sa, "ISteamUser"}}
+}
+
+// GetPlayerSummaries is SteamAuther.GetSteamUsers.
+func (c ISteamUserClient) GetPlayerSummaries(ctx context.Context, steamid64s []string) (map[string]*SteamUser, error) {
+	return c.sa.GetSteamUsers(ctx, steamid64s)
+}
+
+// GetPlayerBans is SteamAuther.GetPlayerBans.
+func (c ISteamUserClient) GetPlayerBans(ctx context.Context, steamid64s ...string) ([]PlayerBans, error) {
+	return c.sa.GetPlayerBans(ctx, steamid64s...)
+}
+
+// GetFriendList is SteamAuther.GetFriendList.
+func (c ISteamUserClient) GetFriendList(ctx context.Context, steamid64 string, hydrate bool) ([]Friend, error) {
+	return c.sa.GetFriendList(ctx, steamid64, hydrate)
+}
+
+// GetUserGroupList is SteamAuther.GetUserGroupList.
+func (c ISteamUserClient) GetUserGroupList(ctx context.Context, steamid64 string) ([]string, error) {
+	return c.sa.GetUserGroupList(ctx, steamid64)
+}
+
+// ResolveVanityURL is SteamAuther.ResolveVanity.
+func (c ISteamUserClient) ResolveVanityURL(ctx context.Context, vanity string, typ VanityType) (*VanityResult, error) {
+	return c.sa.ResolveVanity(ctx, vanity, typ)
+}
+
+// CheckAppOwnership is SteamAuther.CheckAppOwnership.
+func (c ISteamUserClient) CheckAppOwnership(ctx context.Context, steamid64 string, appid int) (*AppOwnership, error) {
+	return c.sa.CheckAppOwnership(ctx, steamid64, appid)
+}
+
+// ISteamUserAuthClient is the ISteamUserAuth interface.
+type ISteamUserAuthClient struct{ WebApiInterface }
+
+// ISteamUserAuth returns the ISteamUserAuth interface.
+func (sa *SteamAuther) ISteamUserAuth() ISteamUserAuthClient {
+	return ISteamUserAuthClient{WebApiInterface{sa, "ISteamUserAuth"}}
+}
+
+// AuthenticateUserTicket is SteamAuther.AuthenticateUserTicket.
+func (c ISteamUserAuthClient) AuthenticateUserTicket(ctx context.Context, appid int, ticket, identity string) (*UserTicket, error) {
+	return c.sa.AuthenticateUserTicket(ctx, appid, ticket, identity)
+}
+
+// IPlayerServiceClient is the IPlayerService interface.
+type IPlayerServiceClient struct{ WebApiInterface }
+
+// IPlayerService returns the IPlayerService interface.
+func (sa *SteamAuther) IPlayerService() IPlayerServiceClient {
+	return IPlayerServiceClient{WebApiInterface{sa, "IPlayerService"}}
+}
+
+// GetOwnedGames is SteamAuther.GetOwnedGamesFiltered.
+func (c IPlayerServiceClient) GetOwnedGames(ctx context.Context, steamid64 string, filter OwnedGamesFilter) ([]OwnedGame, error) {
+	return c.sa.GetOwnedGamesFiltered(ctx, steamid64, filter)
+}
+
+// GetRecentlyPlayedGames is SteamAuther.GetRecentlyPlayedGames.
+func (c IPlayerServiceClient) GetRecentlyPlayedGames(ctx context.Context, steamid64 string, count int) ([]RecentlyPlayedGame, error) {
+	return c.sa.GetRecentlyPlayedGames(ctx, steamid64, count)
+}
+
+// GetSteamLevel is SteamAuther.GetSteamLevel.
+func (c IPlayerServiceClient) GetSteamLevel(ctx context.Context, steamid64 string) (int, error) {
+	return c.sa.GetSteamLevel(ctx, steamid64)
+}
+
+// GetBadges is SteamAuther.GetBadges.
+func (c IPlayerServiceClient) GetBadges(ctx context.Context, steamid64 string) (*Badges, error) {
+	return c.sa.GetBadges(ctx, steamid64)
+}
+
+// GetCommunityBadgeProgress is SteamAuther.GetCommunityBadgeProgress.
+func (c IPlayerServiceClient) GetCommunityBadgeProgress(ctx context.Context, steamid64 string, badgeid int) ([]BadgeQuest, error) {
+	return c.sa.GetCommunityBadgeProgress(ctx, steamid64, badgeid)
+}
+
+// GetAnimatedAvatar is SteamAuther.GetAnimatedAvatar.
+func (c IPlayerServiceClient) GetAnimatedAvatar(ctx context.Context, steamid64 string) (*ProfileItem, error) {
+	return c.sa.GetAnimatedAvatar(ctx, steamid64)
+}
+
+// GetAvatarFrame is SteamAuther.GetAvatarFrame.
+func (c IPlayerServiceClient) GetAvatarFrame(ctx context.Context, steamid64 string) (*ProfileItem, error) {
+	return c.sa.GetAvatarFrame(ctx, steamid64)
+}
+
+// GetProfileBackground is SteamAuther.GetProfileBackground.
+func (c IPlayerServiceClient) GetProfileBackground(ctx context.Context, steamid64 string) (*ProfileItem, error) {
+	return c.sa.GetProfileBackground(ctx, steamid64)
+}
+
+// GetMiniProfileBackground is SteamAuther.GetMiniProfileBackground.
+func (c IPlayerServiceClient) GetMiniProfileBackground(ctx context.Context, steamid64 string) (*ProfileItem, error) {
+	return c.sa.GetMiniProfileBackground(ctx, steamid64)
+}
+
+// GetProfileItemsEquipped is SteamAuther.GetProfileItemsEquipped.
+func (c IPlayerServiceClient) GetProfileItemsEquipped(ctx context.Context, steamid64 string) (*ProfileItemsEquipped, error) {
+	return c.sa.GetProfileItemsEquipped(ctx, steamid64)
+}
+
+// IsPlayingSharedGame is SteamAuther.IsPlayingSharedGame.
+func (c IPlayerServiceClient) IsPlayingSharedGame(ctx context.Context, steamid64 string, appid int) (string, error) {
+	return c.sa.IsPlayingSharedGame(ctx, steamid64, appid)
+}
+
+// ISteamUserStatsClient is the ISteamUserStats interface.
+type ISteamUserStatsClient struct{ WebApiInterface }
+
+// ISteamUserStats returns the ISteamUserStats interface.
+func (sa *SteamAuther) ISteamUserStats() ISteamUserStatsClient {
+	return ISteamUserStatsClient{WebApiInterface{sa, "ISteamUserStats"}}
+}
+
+// GetPlayerAchievements is SteamAuther.GetPlayerAchievements.
+func (c ISteamUserStatsClient) GetPlayerAchievements(ctx context.Context, steamid64 string, appid int, lang string) (*PlayerAchievements, error) {
+	return c.sa.GetPlayerAchievements(ctx, steamid64, appid, lang)
+}
+
+// GetUserStatsForGame is SteamAuther.GetUserStatsForGame.
+func (c ISteamUserStatsClient) GetUserStatsForGame(ctx context.Context, steamid64 string, appid int) (*UserStats, error) {
+	return c.sa.GetUserStatsForGame(ctx, steamid64, appid)
+}
+
+// GetSchemaForGame is SteamAuther.GetSchemaForGame.
+func (c ISteamUserStatsClient) GetSchemaForGame(ctx context.Context, appid int, lang string) (*GameSchema, error) {
+	return c.sa.GetSchemaForGame(ctx, appid, lang)
+}
+
+// GetGlobalAchievementPercentagesForApp is SteamAuther.GetGlobalAchievementPercentagesForApp.
+func (c ISteamUserStatsClient) GetGlobalAchievementPercentagesForApp(ctx context.Context, appid int) ([]AchievementPercentage, error) {
+	return c.sa.GetGlobalAchievementPercentagesForApp(ctx, appid)
+}
+
+// GetGlobalStatsForGame is SteamAuther.GetGlobalStatsForGame.
+func (c ISteamUserStatsClient) GetGlobalStatsForGame(ctx context.Context, appid int, names []string, from, to time.Time) (map[string]GlobalStat, error) {
+	return c.sa.GetGlobalStatsForGame(ctx, appid, names, from, to)
+}
+
+// GetNumberOfCurrentPlayers is SteamAuther.GetNumberOfCurrentPlayers.
+func (c ISteamUserStatsClient) GetNumberOfCurrentPlayers(ctx context.Context, appid int) (int, error) {
+	return c.sa.GetNumberOfCurrentPlayers(ctx, appid)
+}
+
+// ISteamAppsClient is the ISteamApps interface.
+type ISteamAppsClient struct{ WebApiInterface }
+
+// ISteamApps returns the ISteamApps interface.
+func (sa *SteamAuther) ISteamApps() ISteamAppsClient {
+	return ISteamAppsClient{WebApiInterface{sa, "ISteamApps"}}
+}
+
+// GetAppList is SteamAuther.GetAppList.
+func (c ISteamAppsClient) GetAppList(ctx context.Context) ([]App, error) {
+	return c.sa.GetAppList(ctx)
+}
+
+// UpToDateCheck is SteamAuther.UpToDateCheck.
+func (c ISteamAppsClient) UpToDateCheck(ctx context.Context, appid, version int) (*UpToDate, error) {
+	return c.sa.UpToDateCheck(ctx, appid, version)
+}
+
+// IStoreServiceClient is the IStoreService interface.
+type IStoreServiceClient struct{ WebApiInterface }
+
+// IStoreService returns the IStoreService interface.
+func (sa *SteamAuther) IStoreService() IStoreServiceClient {
+	return IStoreServiceClient{WebApiInterface{sa, "IStoreService"}}
+}
+
+// GetAppList is SteamAuther.GetStoreAppList.
+func (c IStoreServiceClient) GetAppList(ctx context.Context, filter StoreAppListFilter, lastAppId int) (*StoreAppListPage, error) {
+	return c.sa.GetStoreAppList(ctx, filter, lastAppId)
+}
+
+// ISteamNewsClient is the ISteamNews interface.
+type ISteamNewsClient struct{ WebApiInterface }
+
+// ISteamNews returns the ISteamNews interface.
+func (sa *SteamAuther) ISteamNews() ISteamNewsClient {
+	return ISteamNewsClient{WebApiInterface{sa, "ISteamNews"}}
+}
+
+// GetNewsForApp is SteamAuther.GetNewsForApp.
+func (c ISteamNewsClient) GetNewsForApp(ctx context.Context, appid int, filter NewsFilter) ([]NewsItem, error) {
+	return c.sa.GetNewsForApp(ctx, appid, filter)
+}
+
+// IEconServiceClient is the IEconService interface.
+type IEconServiceClient struct{ WebApiInterface }
+
+// IEconService returns the IEconService interface.
+func (sa *SteamAuther) IEconService() IEconServiceClient {
+	return IEconServiceClient{WebApiInterface{sa, "IEconService"}}
+}
+
+// GetTradeOffers is SteamAuther.GetTradeOffers.
+func (c IEconServiceClient) GetTradeOffers(ctx context.Context, filter TradeOffersFilter, cursor int) (*TradeOffers, error) {
+	return c.sa.GetTradeOffers(ctx, filter, cursor)
+}
+
+// GetTradeOffer is SteamAuther.GetTradeOffer.
+func (c IEconServiceClient) GetTradeOffer(ctx context.Context, tradeOfferId, lang string) (*TradeOffer, []EconDescription, error) {
+	return c.sa.GetTradeOffer(ctx, tradeOfferId, lang)
+}
+
+// DeclineTradeOffer is SteamAuther.DeclineTradeOffer.
+func (c IEconServiceClient) DeclineTradeOffer(ctx context.Context, tradeOfferId string) error {
+	return c.sa.DeclineTradeOffer(ctx, tradeOfferId)
+}
+
+// CancelTradeOffer is SteamAuther.CancelTradeOffer.
+func (c IEconServiceClient) CancelTradeOffer(ctx context.Context, tradeOfferId string) error {
+	return c.sa.CancelTradeOffer(ctx, tradeOfferId)
+}
+
+// GetTradeHoldDurations is SteamAuther.GetTradeHoldDurations.
+func (c IEconServiceClient) GetTradeHoldDurations(ctx context.Context, steamid64, accessToken string) (*TradeHoldDurations, error) {
+	return c.sa.GetTradeHoldDurations(ctx, steamid64, accessToken)
+}
+
+// ISteamEconomyClient is the ISteamEconomy interface.
+type ISteamEconomyClient struct{ WebApiInterface }
+
+// ISteamEconomy returns the ISteamEconomy interface.
+func (sa *SteamAuther) ISteamEconomy() ISteamEconomyClient {
+	return ISteamEconomyClient{WebApiInterface{sa, "ISteamEconomy"}}
+}
+
+// GetAssetPrices is SteamAuther.GetAssetPrices.
+func (c ISteamEconomyClient) GetAssetPrices(ctx context.Context, appid int, currency, lang string) ([]AssetPrice, error) {
+	return c.sa.GetAssetPrices(ctx, appid, currency, lang)
+}
+
+// GetAssetClassInfo is SteamAuther.GetAssetClassInfo.
+func (c ISteamEconomyClient) GetAssetClassInfo(ctx context.Context, appid int, lang string, classIds ...string) (map[string]*AssetClassInfo, error) {
+	return c.sa.GetAssetClassInfo(ctx, appid, lang, classIds...)
+}
+
+// IEconItemsClient is a game's IEconItems_<appid> interface.
+type IEconItemsClient struct {
+	WebApiInterface
+	appid int
+}
+
+// IEconItems returns the game's IEconItems_<appid> interface.
+func (sa *SteamAuther) IEconItems(appid int) IEconItemsClient {
+	return IEconItemsClient{WebApiInterface{sa, "IEconItems_" + strconv.Itoa(appid)}, appid}
+}
+
+// GetSchemaItems is SteamAuther.GetSchemaItems.
+func (c IEconItemsClient) GetSchemaItems(ctx context.Context, lang string, start int) (*SchemaItemsPage, error) {
+	return c.sa.GetSchemaItems(ctx, c.appid, lang, start)
+}
+
+// GetSchemaOverview is SteamAuther.GetSchemaOverview.
+func (c IEconItemsClient) GetSchemaOverview(ctx context.Context, lang string) (*SchemaOverview, error) {
+	return c.sa.GetSchemaOverview(ctx, c.appid, lang)
+}
+
+// IGameServersServiceClient is the IGameServersService interface.
+type IGameServersServiceClient struct{ WebApiInterface }
+
+// IGameServersService returns the IGameServersService interface.
+func (sa *SteamAuther) IGameServersService() IGameServersServiceClient {
+	return IGameServersServiceClient{WebApiInterface{sa, "IGameServersService"}}
+}
+
+// GetAccountList is SteamAuther.GetGameServerAccountList.
+func (c IGameServersServiceClient) GetAccountList(ctx context.Context) ([]GameServerAccount, error) {
+	return c.sa.GetGameServerAccountList(ctx)
+}
+
+// CreateAccount is SteamAuther.CreateGameServerAccount.
+func (c IGameServersServiceClient) CreateAccount(ctx context.Context, appid int, memo string) (steamid64, loginToken string, err error) {
+	return c.sa.CreateGameServerAccount(ctx, appid, memo)
+}
+
+// DeleteAccount is SteamAuther.DeleteGameServerAccount.
+func (c IGameServersServiceClient) DeleteAccount(ctx context.Context, steamid64 string) error {
+	return c.sa.DeleteGameServerAccount(ctx, steamid64)
+}
+
+// SetMemo is SteamAuther.SetGameServerMemo.
+func (c IGameServersServiceClient) SetMemo(ctx context.Context, steamid64, memo string) error {
+	return c.sa.SetGameServerMemo(ctx, steamid64, memo)
+}
+
+// ResetLoginToken is SteamAuther.ResetGameServerLoginToken.
+func (c IGameServersServiceClient) ResetLoginToken(ctx context.Context, steamid64 string) (string, error) {
+	return c.sa.ResetGameServerLoginToken(ctx, steamid64)
+}
+
+// QueryLoginToken is SteamAuther.QueryLoginToken.
+func (c IGameServersServiceClient) QueryLoginToken(ctx context.Context, loginToken string) (*LoginTokenStatus, error) {
+	return c.sa.QueryLoginToken(ctx, loginToken)
+}
+
+// GetServerSteamIDsByIP is SteamAuther.GetServerSteamIDsByIP.
+func (c IGameServersServiceClient) GetServerSteamIDsByIP(ctx context.Context, addrs ...string) ([]GameServer, error) {
+	return c.sa.GetServerSteamIDsByIP(ctx, addrs...)
+}
+
+// GetServerIPsBySteamID is SteamAuther.GetServerIPsBySteamID.
+func (c IGameServersServiceClient) GetServerIPsBySteamID(ctx context.Context, steamid64s ...string) ([]GameServer, error) {
+	return c.sa.GetServerIPsBySteamID(ctx, steamid64s...)
+}
+
+// ISteamRemoteStorageClient is the ISteamRemoteStorage interface.
+type ISteamRemoteStorageClient struct{ WebApiInterface }
+
+// ISteamRemoteStorage returns the ISteamRemoteStorage interface.
+func (sa *SteamAuther) ISteamRemoteStorage() ISteamRemoteStorageClient {
+	return ISteamRemoteStorageClient{WebApiInterface{sa, "ISteamRemoteStorage"}}
+}
+
+// GetPublishedFileDetails is SteamAuther.GetPublishedFileDetails.
+func (c ISteamRemoteStorageClient) GetPublishedFileDetails(ctx context.Context, ids []string) (map[string]*PublishedFile, error) {
+	return c.sa.GetPublishedFileDetails(ctx, ids)
+}
+
+// GetUGCFileDetails is SteamAuther.GetUGCFileDetails.
+func (c ISteamRemoteStorageClient) GetUGCFileDetails(ctx context.Context, appid int, ugcid, steamid64 string) (*UGCFile, error) {
+	return c.sa.GetUGCFileDetails(ctx, appid, ugcid, steamid64)
+}
+
+// IPublishedFileServiceClient is the IPublishedFileService interface.
+type IPublishedFileServiceClient struct{ WebApiInterface }
+
+// IPublishedFileService returns the IPublishedFileService interface.
+func (sa *SteamAuther) IPublishedFileService() IPublishedFileServiceClient {
+	return IPublishedFileServiceClient{WebApiInterface{sa, "IPublishedFileService"}}
+}
+
+// QueryFiles is SteamAuther.QueryFiles.
+func (c IPublishedFileServiceClient) QueryFiles(ctx context.Context, query WorkshopQuery, cursor string) (*WorkshopPage, error) {
+	return c.sa.QueryFiles(ctx, query, cursor)
+}
+
+// ISteamWebAPIUtilClient is the ISteamWebAPIUtil interface.
+type ISteamWebAPIUtilClient struct{ WebApiInterface }
+
+// ISteamWebAPIUtil returns the ISteamWebAPIUtil interface.
+func (sa *SteamAuther) ISteamWebAPIUtil() ISteamWebAPIUtilClient {
+	return ISteamWebAPIUtilClient{WebApiInterface{sa, "ISteamWebAPIUtil"}}
+}
+
+// GetSupportedAPIList is SteamAuther.GetSupportedAPIList.
+func (c ISteamWebAPIUtilClient) GetSupportedAPIList(ctx context.Context) ([]ApiInterface, error) {
+	return c.sa.GetSupportedAPIList(ctx)
+}
+
+// ICheatReportingServiceClient is the ICheatReportingService interface.
+type ICheatReportingServiceClient struct{ WebApiInterface }
+
+// ICheatReportingService returns the ICheatReportingService interface.
+func (sa *SteamAuther) ICheatReportingService() ICheatReportingServiceClient {
+	return ICheatReportingServiceClient{WebApiInterface{sa, "ICheatReportingService"}}
+}
+
+// ReportPlayerCheating is SteamAuther.ReportPlayerCheating.
+func (c ICheatReportingServiceClient) ReportPlayerCheating(ctx context.Context, report CheatingReport) (string, error) {
+	return c.sa.ReportPlayerCheating(ctx, report)
+}
+
+// RequestPlayerGameBan is SteamAuther.RequestPlayerGameBan.
+func (c ICheatReportingServiceClient) RequestPlayerGameBan(ctx context.Context, ban GameBanRequest) error {
+	return c.sa.RequestPlayerGameBan(ctx, ban)
+}
+
+// GetCheatingReports is SteamAuther.GetCheatingReports.
+func (c ICheatReportingServiceClient) GetCheatingReports(ctx context.Context, appid int, filter CheatingReportsFilter) ([]CheatingReportEntry, error) {
+	return c.sa.GetCheatingReports(ctx, appid, filter)
+}
@@ -0,0 +1,109 @@
+package gosteamauth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWebApiInterfaceCall(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/IPlayerService/GetSteamLevel/v1":
+			w.Write([]byte(`{"response":{"player_level":12}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/IEconItems_440/SetItemPosition/v1":
+			w.Write([]byte(`{"result":{"status":1}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	var level struct {
+		Response struct {
+			PlayerLevel int `json:"player_level"`
+		} `json:"response"`
+	}
+	if err := sa.IPlayerService().Call(context.Background(), "GetSteamLevel", 1, url.Values{"steamid": {"76561197960287930"}}, &level); err != nil {
+		t.Fatal(err)
+	}
+	if level.Response.PlayerLevel != 12 {
+		t.Errorf("level = %d, want 12", level.Response.PlayerLevel)
+	}
+
+	var res struct {
+		Result struct {
+			Status int `json:"status"`
+		} `json:"result"`
+	}
+	if err := sa.IEconItems(440).CallPost(context.Background(), "SetItemPosition", 1, nil, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Result.Status != 1 {
+		t.Errorf("status = %d, want 1", res.Result.Status)
+	}
+}
+
+func TestSubClients(t *testing.T) {
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{sa.ISteamUser().Name(), "ISteamUser"},
+		{sa.ISteamUserAuth().Name(), "ISteamUserAuth"},
+		{sa.IPlayerService().Name(), "IPlayerService"},
+		{sa.ISteamUserStats().Name(), "ISteamUserStats"},
+		{sa.ISteamApps().Name(), "ISteamApps"},
+		{sa.IStoreService().Name(), "IStoreService"},
+		{sa.ISteamNews().Name(), "ISteamNews"},
+		{sa.IEconService().Name(), "IEconService"},
+		{sa.ISteamEconomy().Name(), "ISteamEconomy"},
+		{sa.IEconItems(440).Name(), "IEconItems_440"},
+		{sa.IGameServersService().Name(), "IGameServersService"},
+		{sa.ISteamRemoteStorage().Name(), "ISteamRemoteStorage"},
+		{sa.IPublishedFileService().Name(), "IPublishedFileService"},
+		{sa.ISteamWebAPIUtil().Name(), "ISteamWebAPIUtil"},
+		{sa.ICheatReportingService().Name(), "ICheatReportingService"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Name() = %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestSubClientsDelegate(t *testing.T) {
+	var calls atomic.Int32
+	items := schemaItems(&calls)
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/IPlayerService/GetSteamLevel/v1" {
+			steamLevels(w, r)
+			return
+		}
+		items(w, r)
+	})
+	sa := New("key", "https://example.com")
+
+	level, err := sa.IPlayerService().GetSteamLevel(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level != 12 {
+		t.Errorf("level = %d, want 12", level)
+	}
+
+	// the appid comes from the interface
+	page, err := sa.IEconItems(440).GetSchemaItems(context.Background(), "en", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) == 0 || calls.Load() != 1 {
+		t.Errorf("page = %+v from %d calls, want tf2's first page", page, calls.Load())
+	}
+}
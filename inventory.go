@@ -0,0 +1,148 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// inventoryRetries is how many times inventory requests are retried when steam rate limits them, waiting twice as
+// long each time starting from inventoryRetryWait.
+const inventoryRetries = 3
+
+// inventoryRetryWait is a var so tests don't have to wait.
+var inventoryRetryWait = 2 * time.Second
+
+// InventoryItem is an item in a user's inventory, with its description.
+type InventoryItem struct {
+	EconAsset
+	// Description is nil if steam didn't send one for the item.
+	Description *EconDescription
+}
+
+// InventoryPage is a page of a user's inventory.
+type InventoryPage struct {
+	Items []InventoryItem
+	// Total is how many items are in the inventory, across every page.
+	Total int
+	// LastAssetID is what to pass to GetInventoryPage to get the next page, or "" if this is the last one.
+	LastAssetID string
+}
+
+// GetInventoryPage gets a page of the user's inventory for a game and context (ex. 730 and "2" for cs2 items), with
+// item names and such in lang (ex. "english"). Pass "" as startAssetId to get the first page, and the page's
+// LastAssetID to get the next one. Requests steam rate limits are retried a few times, backing off in between.
+// Returns ErrPrivateProfile if the user's inventory is private.
+func (sa *SteamAuther) GetInventoryPage(ctx context.Context, steamid64 string, appid int, contextId, lang, startAssetId string) (*InventoryPage, error) {
+	q := url.Values{"count": {"2000"}}
+	if lang != "" {
+		q.Set("l", lang)
+	}
+	if startAssetId != "" {
+		q.Set("start_assetid", startAssetId)
+	}
+
+	var data struct {
+		Assets       []EconAsset       `json:"assets"`
+		Descriptions []EconDescription `json:"descriptions"`
+		MoreItems    int               `json:"more_items"`
+		LastAssetID  string            `json:"last_assetid"`
+		Total        int               `json:"total_inventory_count"`
+		Success      int               `json:"success"`
+	}
+	rawUrl := CommunityBaseUrl + "/inventory/" + steamid64 + "/" + strconv.Itoa(appid) + "/" + contextId
+	if err := sa.getInventory(ctx, rawUrl, q, &data); err != nil {
+		// There's no api key to get wrong here, so a 401 or 403 can only mean the inventory is hidden.
+		return nil, fmt.Errorf("get inventory (%s, %d, %s): %w", steamid64, appid, contextId, privateOn(err, http.StatusUnauthorized, http.StatusForbidden))
+	}
+
+	if data.Success != 1 {
+		return nil, fmt.Errorf("get inventory (%s, %d, %s): steam said it wasn't successful", steamid64, appid, contextId)
+	}
+
+	descriptions := make(map[[2]string]*EconDescription, len(data.Descriptions))
+	for i, d := range data.Descriptions {
+		descriptions[[2]string{d.ClassID, d.InstanceID}] = &data.Descriptions[i]
+	}
+
+	page := &InventoryPage{Items: make([]InventoryItem, len(data.Assets)), Total: data.Total}
+	for i, a := range data.Assets {
+		page.Items[i] = InventoryItem{EconAsset: a, Description: descriptions[[2]string{a.ClassID, a.InstanceID}]}
+	}
+	if data.MoreItems == 1 {
+		page.LastAssetID = data.LastAssetID
+	}
+
+	return page, nil
+}
+
+// getInventory gets and decodes an inventory page, retrying if steam rate limits it.
+func (sa *SteamAuther) getInventory(ctx context.Context, rawUrl string, q url.Values, out any) error {
+	wait := inventoryRetryWait
+	for try := 0; ; try++ {
+		res, err := sa.get(ctx, rawUrl, q)
+		if errors.Is(err, ErrSteamRateLimited) && try < inventoryRetries {
+			select {
+			case <-time.After(wait):
+				wait *= 2
+				continue
+			case <-ctx.Done():
+				return fmt.Errorf("%w: %w", err, ctx.Err())
+			}
+		}
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode response body: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// Inventory is the user's whole inventory for a game and context as an iterator, fetching a page at a time as it's
+// consumed. Errors are yielded with an empty InventoryItem, and end the iteration.
+func (sa *SteamAuther) Inventory(ctx context.Context, steamid64 string, appid int, contextId, lang string) iter.Seq2[InventoryItem, error] {
+	return func(yield func(InventoryItem, error) bool) {
+		for start := ""; ; {
+			page, err := sa.GetInventoryPage(ctx, steamid64, appid, contextId, lang, start)
+			if err != nil {
+				yield(InventoryItem{}, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if page.LastAssetID == "" {
+				return
+			}
+			start = page.LastAssetID
+		}
+	}
+}
+
+// GetInventory is Inventory, collected into a slice.
+func (sa *SteamAuther) GetInventory(ctx context.Context, steamid64 string, appid int, contextId, lang string) ([]InventoryItem, error) {
+	var items []InventoryItem
+	for item, err := range sa.Inventory(ctx, steamid64, appid, contextId, lang) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
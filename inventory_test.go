@@ -0,0 +1,150 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// inventories answers community inventory requests like steam would: 76561197960287930 has 3 cs2 items, sent 2 a
+// page, and anyone else's inventory is private. It counts the requests made in calls.
+func inventories(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		switch r.URL.Path {
+		case "/inventory/76561197960287930/730/2":
+		case "/inventory/76561197960287931/730/2":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("null"))
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		const descriptions = `"descriptions":[
+			{"appid":730,"classid":"1","instanceid":"0","name":"AK-47 | Redline","market_hash_name":"AK-47 | Redline (Field-Tested)"},
+			{"appid":730,"classid":"2","instanceid":"0","name":"Operation Pass"}
+		]`
+		if r.URL.Query().Get("start_assetid") == "" {
+			w.Write([]byte(`{"assets":[
+				{"appid":730,"contextid":"2","assetid":"100","classid":"1","instanceid":"0","amount":"1"},
+				{"appid":730,"contextid":"2","assetid":"101","classid":"2","instanceid":"0","amount":"1"}
+			],` + descriptions + `,"more_items":1,"last_assetid":"101","total_inventory_count":3,"success":1}`))
+			return
+		}
+		w.Write([]byte(`{"assets":[
+			{"appid":730,"contextid":"2","assetid":"102","classid":"3","instanceid":"0","amount":"1"}
+		],"descriptions":[],"total_inventory_count":3,"success":1}`))
+	}
+}
+
+func TestGetInventoryPage(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, inventories(&calls))
+	sa := New("key", "https://example.com")
+
+	page, err := sa.GetInventoryPage(context.Background(), "76561197960287930", 730, "2", "english", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Total != 3 || page.LastAssetID != "101" {
+		t.Fatalf("first page = %+v, want 2 of 3 items", page)
+	}
+	if d := page.Items[0].Description; d == nil || d.MarketHashName != "AK-47 | Redline (Field-Tested)" {
+		t.Errorf("description = %+v, want the ak's", d)
+	}
+
+	page, err = sa.GetInventoryPage(context.Background(), "76561197960287930", 730, "2", "english", page.LastAssetID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.LastAssetID != "" || page.Items[0].Description != nil {
+		t.Errorf("last page = %+v, want the item without a description and no next page", page)
+	}
+
+	if _, err := sa.GetInventoryPage(context.Background(), "76561197960287931", 730, "2", "", ""); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private inventory err = %v, want ErrPrivateProfile", err)
+	}
+}
+
+func TestGetInventory(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, inventories(&calls))
+	sa := New("key", "https://example.com")
+
+	items, err := sa.GetInventory(context.Background(), "76561197960287930", 730, "2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[2].AssetID != "102" || calls.Load() != 2 {
+		t.Errorf("got %d items from %d requests, want 3 from 2", len(items), calls.Load())
+	}
+
+	// breaking out of the loop stops fetching
+	calls.Store(0)
+	for range sa.Inventory(context.Background(), "76561197960287930", 730, "2", "") {
+		break
+	}
+	if calls.Load() != 1 {
+		t.Errorf("made %d requests after breaking early, want 1", calls.Load())
+	}
+
+	if _, err := sa.GetInventory(context.Background(), "76561197960287931", 730, "2", ""); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private inventory err = %v, want ErrPrivateProfile", err)
+	}
+}
+
+func TestGetInventoryRateLimited(t *testing.T) {
+	old := inventoryRetryWait
+	inventoryRetryWait = time.Millisecond
+	t.Cleanup(func() { inventoryRetryWait = old })
+
+	tests := []struct {
+		name      string
+		limited   int32
+		wantErr   error
+		wantCalls int32
+	}{
+		{"retried", 2, nil, 3},
+		{"gave up", 10, ErrSteamRateLimited, 1 + inventoryRetries},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls, limited atomic.Int32
+			inventory := inventories(&calls)
+			fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+				if limited.Add(1) <= tt.limited {
+					calls.Add(1)
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				inventory(w, r)
+			})
+
+			_, err := New("key", "https://example.com").GetInventoryPage(context.Background(), "76561197960287930", 730, "2", "", "")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("made %d requests, want %d", calls.Load(), tt.wantCalls)
+			}
+		})
+	}
+
+	// a cancelled ctx stops the waiting
+	inventoryRetryWait = time.Hour
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := New("key", "https://example.com").GetInventoryPage(ctx, "76561197960287930", 730, "2", "", ""); !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, ErrSteamRateLimited) {
+		t.Errorf("err = %v, want it rate limited and past the deadline", err)
+	}
+}
@@ -0,0 +1,81 @@
+package gosteamauth
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// JWK is an RSA public key in JSON Web Key form (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS is a set of RS256 public keys, served as a JSON Web Key Set so other services can verify tokens minted by a
+// TokenIssuer without ever seeing the private key.
+//
+// To rotate keys, add the new key and switch the issuer's KeyID over to it, then remove the old key once every
+// token signed with it has expired.
+type JWKS struct {
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewJWKS returns a new, empty JWKS.
+func NewJWKS() *JWKS {
+	return &JWKS{keys: make(map[string]*rsa.PublicKey)}
+}
+
+// AddKey publishes key under kid, replacing any key already published under it.
+// kid should match the KeyID of the TokenIssuer signing with the private half of the key.
+func (j *JWKS) AddKey(kid string, key *rsa.PublicKey) {
+	j.mu.Lock()
+	j.keys[kid] = key
+	j.mu.Unlock()
+}
+
+// RemoveKey stops publishing the key with the provided kid.
+func (j *JWKS) RemoveKey(kid string) {
+	j.mu.Lock()
+	delete(j.keys, kid)
+	j.mu.Unlock()
+}
+
+// Keys returns every published key as a JWK, sorted by kid.
+func (j *JWKS) Keys() []JWK {
+	j.mu.RLock()
+	out := make([]JWK, 0, len(j.keys))
+	for kid, k := range j.keys {
+		out = append(out, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: AlgRS256,
+			Kid: kid,
+			N:   b64.EncodeToString(k.N.Bytes()),
+			E:   b64.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		})
+	}
+	j.mu.RUnlock()
+
+	sort.Slice(out, func(a, b int) bool { return out[a].Kid < out[b].Kid })
+	return out
+}
+
+// ServeHTTP writes the key set as JSON. This is usually mounted at /.well-known/jwks.json.
+// Consumers are allowed to cache it for a few minutes, so keep new keys published for a bit before signing with them.
+func (j *JWKS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	json.NewEncoder(w).Encode(struct {
+		Keys []JWK `json:"keys"`
+	}{j.Keys()})
+}
@@ -0,0 +1,67 @@
+package gosteamauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestJWKSServeHTTP(t *testing.T) {
+	a, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := NewJWKS()
+	j.AddKey("b", &b.PublicKey)
+	j.AddKey("a", &a.PublicKey)
+	j.AddKey("old", &b.PublicKey)
+	j.RemoveKey("old")
+
+	rec := httptest.NewRecorder()
+	j.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("content type = %q, want application/json", ct)
+	}
+
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &set); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]*rsa.PublicKey{"a": &a.PublicKey, "b": &b.PublicKey}
+	if len(set.Keys) != len(want) || set.Keys[0].Kid != "a" || set.Keys[1].Kid != "b" {
+		t.Fatalf("keys = %+v, want a and b sorted by kid", set.Keys)
+	}
+
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || k.Alg != AlgRS256 || k.Use != "sig" {
+			t.Errorf("key %q = %+v, want an RS256 signing key", k.Kid, k)
+		}
+
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if !got.Equal(want[k.Kid]) {
+			t.Errorf("key %q doesn't round trip to the published public key", k.Kid)
+		}
+	}
+}
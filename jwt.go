@@ -0,0 +1,459 @@
+package gosteamauth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned by TokenVerifier.Verify when a token can't be trusted for any reason.
+var ErrInvalidToken = errors.New("invalid token")
+
+// ErrTokenExpired is returned (wrapped with ErrInvalidToken) by TokenVerifier.Verify when a token is past its expiry.
+var ErrTokenExpired = errors.New("token expired")
+
+const (
+	// AlgHS256 is HMAC-SHA256, signed and verified with one shared secret.
+	AlgHS256 = "HS256"
+	// AlgRS256 is RSASSA-PKCS1-v1_5 with SHA256, signed with a private key and verified with the public key.
+	AlgRS256 = "RS256"
+)
+
+// TypIDToken is the "typ" header of OpenID Connect ID tokens signed with SignIDToken. TokenVerifier rejects tokens
+// with it, so an ID token can't be used as an access token.
+const TypIDToken = "id_token+jwt"
+
+// DefaultTokenTTL is how long tokens minted by a TokenIssuer are valid for, unless TTL is changed.
+const DefaultTokenTTL = time.Hour
+
+// Claims are the claims in a token minted by a TokenIssuer.
+type Claims struct {
+	// Subject is the steamid64 of the authenticated user.
+	Subject   string `json:"sub"`
+	Issuer    string `json:"iss,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+	IssuedAt  int64  `json:"iat"`
+	// ID is a random, unique identifier for the token.
+	ID string `json:"jti,omitempty"`
+
+	// PersonaName is the user's profile name at the time the token was issued.
+	PersonaName string `json:"name,omitempty"`
+	// Avatar is the user's full size avatar URL at the time the token was issued.
+	Avatar string `json:"picture,omitempty"`
+
+	// Extra holds any additional claims. These are flattened into the token alongside the ones above, and
+	// can't override them: keys named like one of them are left out, even when it's empty.
+	Extra map[string]any `json:"-"`
+}
+
+// reservedClaims are the claims with a field on Claims.
+var reservedClaims = []string{"sub", "iss", "aud", "exp", "iat", "jti", "name", "picture"}
+
+// MarshalJSON flattens Extra into the claims object.
+func (c Claims) MarshalJSON() ([]byte, error) {
+	type plain Claims
+	b, err := json.Marshal(plain(c))
+	if err != nil || len(c.Extra) == 0 {
+		return b, err
+	}
+
+	// Empty fields are left out of the token, so Extra could fill them in (ex. an "aud" the issuer never set) if
+	// reserved keys weren't skipped.
+	m := make(map[string]any, len(c.Extra)+8)
+	for k, v := range c.Extra {
+		if slices.Contains(reservedClaims, k) {
+			continue
+		}
+		m[k] = v
+	}
+
+	var known map[string]any
+	if err := json.Unmarshal(b, &known); err != nil {
+		return nil, err
+	}
+	for k, v := range known {
+		m[k] = v
+	}
+
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON fills in the known claims, and puts everything else in Extra.
+func (c *Claims) UnmarshalJSON(b []byte) error {
+	type plain Claims
+	var p plain
+	if err := json.Unmarshal(b, &p); err != nil {
+		return err
+	}
+
+	var all map[string]any
+	if err := json.Unmarshal(b, &all); err != nil {
+		return err
+	}
+	for _, k := range reservedClaims {
+		delete(all, k)
+	}
+	if len(all) > 0 {
+		p.Extra = all
+	}
+
+	*c = Claims(p)
+	return nil
+}
+
+// TokenSigner turns claims into a token. JWTs are built in, see the paseto subpackage for an alternative.
+type TokenSigner interface {
+	Sign(c *Claims) (string, error)
+}
+
+// TokenParser authenticates a token made by the matching TokenSigner and returns its claims.
+// The registered claims (exp, iss, aud...) are checked by the TokenVerifier afterwards, so parsers don't need to.
+type TokenParser interface {
+	Parse(token string) (*Claims, error)
+}
+
+// TokenIssuer mints signed JWTs for users who have logged in with steam, so SPAs and mobile apps can use the
+// result of ValidateCallback without a cookie session.
+type TokenIssuer struct {
+	alg     string
+	hmacKey []byte
+	rsaKey  *rsa.PrivateKey
+	signer  TokenSigner
+
+	// KeyID is put in the "kid" header of every token, if set.
+	KeyID string
+	// Issuer is put in the "iss" claim of every token, if set.
+	Issuer string
+	// Audience is put in the "aud" claim of every token, if set.
+	Audience string
+	// TTL is how long tokens are valid for. Defaults to DefaultTokenTTL.
+	TTL time.Duration
+
+	// CustomizeClaims, if set, is called before every token is signed so extra claims can be added or the
+	// defaults changed. user is nil if Issue was called without one.
+	CustomizeClaims func(c *Claims, user *SteamUser) error
+}
+
+// NewHS256Issuer returns a new TokenIssuer signing tokens with HS256 and the provided secret.
+// The secret should be at least 32 random bytes.
+func NewHS256Issuer(secret []byte) *TokenIssuer {
+	return &TokenIssuer{
+		alg:     AlgHS256,
+		hmacKey: secret,
+		TTL:     DefaultTokenTTL,
+	}
+}
+
+// NewRS256Issuer returns a new TokenIssuer signing tokens with RS256 and the provided private key.
+func NewRS256Issuer(key *rsa.PrivateKey) *TokenIssuer {
+	return &TokenIssuer{
+		alg:    AlgRS256,
+		rsaKey: key,
+		TTL:    DefaultTokenTTL,
+	}
+}
+
+// NewIssuer returns a new TokenIssuer minting tokens with signer instead of the built in JWT signing.
+// KeyID is ignored by issuers created this way, since the signer decides what the token looks like.
+func NewIssuer(signer TokenSigner) *TokenIssuer {
+	return &TokenIssuer{
+		signer: signer,
+		TTL:    DefaultTokenTTL,
+	}
+}
+
+// Issue mints a token for steamid64. If user is not nil, their persona name and avatar are included.
+func (ti *TokenIssuer) Issue(steamid64 string, user *SteamUser) (string, error) {
+	now := time.Now()
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", fmt.Errorf("issue token (%s): generate id: %w", steamid64, err)
+	}
+
+	c := &Claims{
+		Subject:   steamid64,
+		Issuer:    ti.Issuer,
+		Audience:  ti.Audience,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ti.TTL).Unix(),
+		ID:        jti,
+	}
+	if user != nil {
+		c.PersonaName = user.PersonaName
+		c.Avatar = user.AvatarFull
+	}
+
+	if ti.CustomizeClaims != nil {
+		if err := ti.CustomizeClaims(c, user); err != nil {
+			return "", fmt.Errorf("issue token (%s): customize claims: %w", steamid64, err)
+		}
+	}
+
+	tok, err := ti.sign(c)
+	if err != nil {
+		return "", fmt.Errorf("issue token (%s): %w", steamid64, err)
+	}
+
+	return tok, nil
+}
+
+// SignClaims signs c exactly as provided, without filling in any defaults or calling CustomizeClaims.
+// This is for tokens that don't fit Issue, like OpenID Connect ID tokens.
+func (ti *TokenIssuer) SignClaims(c *Claims) (string, error) {
+	tok, err := ti.sign(c)
+	if err != nil {
+		return "", fmt.Errorf("sign claims (%s): %w", c.Subject, err)
+	}
+
+	return tok, nil
+}
+
+// SignIDToken is SignClaims for OpenID Connect ID tokens, with TypIDToken as the "typ" header so TokenVerifier won't
+// take them as access tokens. It only works with the built in JWT signing.
+func (ti *TokenIssuer) SignIDToken(c *Claims) (string, error) {
+	if ti.signer != nil {
+		return "", fmt.Errorf("sign id token (%s): custom signers can't sign id tokens", c.Subject)
+	}
+
+	tok, err := ti.signJWT(c, TypIDToken)
+	if err != nil {
+		return "", fmt.Errorf("sign id token (%s): %w", c.Subject, err)
+	}
+
+	return tok, nil
+}
+
+// Algorithm returns the JWT "alg" the issuer signs with, or an empty string if it uses a custom TokenSigner.
+func (ti *TokenIssuer) Algorithm() string {
+	return ti.alg
+}
+
+// sign encodes and signs the claims as a compact JWT, unless the issuer has a custom signer.
+func (ti *TokenIssuer) sign(c *Claims) (string, error) {
+	if ti.signer != nil {
+		return ti.signer.Sign(c)
+	}
+
+	return ti.signJWT(c, "JWT")
+}
+
+// signJWT encodes and signs the claims as a compact JWT with typ as its "typ" header.
+func (ti *TokenIssuer) signJWT(c *Claims, typ string) (string, error) {
+	header := map[string]string{"alg": ti.alg, "typ": typ}
+	if ti.KeyID != "" {
+		header["kid"] = ti.KeyID
+	}
+
+	hb, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("encode header: %w", err)
+	}
+	cb, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("encode claims: %w", err)
+	}
+
+	signingInput := b64.EncodeToString(hb) + "." + b64.EncodeToString(cb)
+
+	var sig []byte
+	switch ti.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, ti.hmacKey)
+		mac.Write([]byte(signingInput))
+		sig = mac.Sum(nil)
+	case AlgRS256:
+		sum := sha256.Sum256([]byte(signingInput))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, ti.rsaKey, crypto.SHA256, sum[:])
+		if err != nil {
+			return "", fmt.Errorf("sign: %w", err)
+		}
+	default:
+		return "", errors.New("unsupported signing algorithm " + ti.alg)
+	}
+
+	return signingInput + "." + b64.EncodeToString(sig), nil
+}
+
+// b64 is the unpadded base64url encoding JWTs use.
+var b64 = base64.RawURLEncoding
+
+// randomToken returns n random bytes, base64url encoded.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return b64.EncodeToString(b), nil
+}
+
+// TokenVerifier checks tokens minted by a TokenIssuer.
+type TokenVerifier struct {
+	alg     string
+	hmacKey []byte
+	rsaKeys map[string]*rsa.PublicKey
+	parser  TokenParser
+
+	// Issuer, if set, must match the "iss" claim of every token.
+	Issuer string
+	// Audience, if set, must match the "aud" claim of every token.
+	Audience string
+	// Leeway is how far past "exp" a token is still accepted, to account for clock skew between servers.
+	Leeway time.Duration
+}
+
+// NewHS256Verifier returns a new TokenVerifier for HS256 tokens signed with secret.
+func NewHS256Verifier(secret []byte) *TokenVerifier {
+	return &TokenVerifier{
+		alg:     AlgHS256,
+		hmacKey: secret,
+	}
+}
+
+// NewRS256Verifier returns a new TokenVerifier for RS256 tokens signed by the private half of key.
+// This key is used for any token whose "kid" header hasn't been added with AddKey.
+func NewRS256Verifier(key *rsa.PublicKey) *TokenVerifier {
+	return &TokenVerifier{
+		alg:     AlgRS256,
+		rsaKeys: map[string]*rsa.PublicKey{"": key},
+	}
+}
+
+// NewVerifier returns a new TokenVerifier checking tokens with parser instead of the built in JWT verification.
+func NewVerifier(parser TokenParser) *TokenVerifier {
+	return &TokenVerifier{parser: parser}
+}
+
+// AddKey adds an RS256 public key used for tokens with the provided "kid" header.
+// Tokens with a kid that hasn't been added fall back to the key passed to NewRS256Verifier, if any.
+// Only RS256 verifiers use these keys.
+func (tv *TokenVerifier) AddKey(kid string, key *rsa.PublicKey) {
+	if tv.rsaKeys == nil {
+		tv.rsaKeys = make(map[string]*rsa.PublicKey)
+	}
+	tv.rsaKeys[kid] = key
+}
+
+// Verify checks the token's signature, expiry, issuer and audience, and returns its claims. ID tokens (see
+// SignIDToken) are rejected. All failures wrap ErrInvalidToken, and expired tokens also wrap ErrTokenExpired.
+func (tv *TokenVerifier) Verify(token string) (*Claims, error) {
+	if tv.parser != nil {
+		c, err := tv.parser.Parse(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+		}
+
+		if err := tv.checkClaims(c); err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidToken)
+	}
+
+	hb, err := b64.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode header: %v", ErrInvalidToken, err)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+		Typ string `json:"typ"`
+	}
+	if err := json.Unmarshal(hb, &header); err != nil {
+		return nil, fmt.Errorf("%w: decode header: %v", ErrInvalidToken, err)
+	}
+
+	// ID tokens are signed with the same key, but they're for the client, not for calling us with.
+	if strings.EqualFold(header.Typ, TypIDToken) {
+		return nil, fmt.Errorf("%w: id tokens aren't access tokens", ErrInvalidToken)
+	}
+
+	// Never trust the header's alg to pick how to verify, that's how "alg: none" and friends happen.
+	if header.Alg != tv.alg {
+		return nil, fmt.Errorf("%w: unexpected alg %q", ErrInvalidToken, header.Alg)
+	}
+
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode signature: %v", ErrInvalidToken, err)
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	switch tv.alg {
+	case AlgHS256:
+		mac := hmac.New(sha256.New, tv.hmacKey)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	case AlgRS256:
+		key, ok := tv.rsaKeys[header.Kid]
+		if !ok {
+			key, ok = tv.rsaKeys[""]
+		}
+		if !ok || key == nil {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, header.Kid)
+		}
+
+		sum := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig); err != nil {
+			return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported alg %q", ErrInvalidToken, tv.alg)
+	}
+
+	cb, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: decode claims: %v", ErrInvalidToken, err)
+	}
+
+	var c Claims
+	if err := json.Unmarshal(cb, &c); err != nil {
+		return nil, fmt.Errorf("%w: decode claims: %v", ErrInvalidToken, err)
+	}
+
+	if err := tv.checkClaims(&c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// checkClaims checks the registered claims of an already authenticated token.
+func (tv *TokenVerifier) checkClaims(c *Claims) error {
+	if time.Now().After(time.Unix(c.ExpiresAt, 0).Add(tv.Leeway)) {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, ErrTokenExpired)
+	}
+
+	if tv.Issuer != "" && c.Issuer != tv.Issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, c.Issuer)
+	}
+
+	if tv.Audience != "" && c.Audience != tv.Audience {
+		return fmt.Errorf("%w: unexpected audience %q", ErrInvalidToken, c.Audience)
+	}
+
+	if c.Subject == "" {
+		return fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package gosteamauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClaimsExtraCantSetReservedClaims(t *testing.T) {
+	c := Claims{
+		Subject:   "76561197960287930",
+		ExpiresAt: 100,
+		Extra:     map[string]any{"aud": "spoofed", "iss": "spoofed", "sub": "spoofed", "role": "admin"},
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["aud"]; ok {
+		t.Errorf("aud = %v, want it left out", got["aud"])
+	}
+	if _, ok := got["iss"]; ok {
+		t.Errorf("iss = %v, want it left out", got["iss"])
+	}
+	if got["sub"] != "76561197960287930" {
+		t.Errorf("sub = %v, want 76561197960287930", got["sub"])
+	}
+	if got["role"] != "admin" {
+		t.Errorf("role = %v, want admin", got["role"])
+	}
+}
+
+func TestIssueHS256(t *testing.T) {
+	ti := NewHS256Issuer([]byte("secret"))
+	ti.Issuer = "https://example.com"
+	ti.CustomizeClaims = func(c *Claims, _ *SteamUser) error {
+		c.Extra = map[string]any{"aud": "https://other.example.com", "role": "admin"}
+		return nil
+	}
+
+	tok, err := ti.Issue("76561197960287930", &SteamUser{PersonaName: "Rabscuttle"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(tok, ".")
+	if len(parts) != 3 {
+		t.Fatalf("token has %d parts, want 3", len(parts))
+	}
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if b64.EncodeToString(mac.Sum(nil)) != parts[2] {
+		t.Error("signature doesn't match the signing input")
+	}
+
+	payload, err := b64.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c Claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Subject != "76561197960287930" || c.Issuer != "https://example.com" || c.PersonaName != "Rabscuttle" {
+		t.Errorf("claims = %+v, want the subject, issuer and name set", c)
+	}
+	if c.Audience != "" {
+		t.Errorf("aud = %q, want it left out", c.Audience)
+	}
+	if c.Extra["role"] != "admin" {
+		t.Errorf("role = %v, want admin", c.Extra["role"])
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ti := NewHS256Issuer([]byte("secret"))
+	ti.Issuer = "https://example.com"
+	tok, err := ti.Issue("76561197960287930", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiredIssuer := NewHS256Issuer([]byte("secret"))
+	expiredIssuer.TTL = -time.Minute
+	expired, err := expiredIssuer.Issue("76561197960287930", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		tv      *TokenVerifier
+		token   string
+		wantErr error
+	}{
+		{"valid", NewHS256Verifier([]byte("secret")), tok, nil},
+		{"wrong secret", NewHS256Verifier([]byte("other")), tok, ErrInvalidToken},
+		{"wrong issuer", &TokenVerifier{alg: AlgHS256, hmacKey: []byte("secret"), Issuer: "https://other.example.com"}, tok, ErrInvalidToken},
+		{"wrong alg", NewRS256Verifier(nil), tok, ErrInvalidToken},
+		{"expired", NewHS256Verifier([]byte("secret")), expired, ErrTokenExpired},
+		{"malformed", NewHS256Verifier([]byte("secret")), "not.a-token", ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.tv.Verify(tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify err = %v", err)
+			}
+			if c.Subject != "76561197960287930" {
+				t.Errorf("sub = %q, want 76561197960287930", c.Subject)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	ti := NewHS256Issuer([]byte("secret"))
+	tok, err := ti.Issue("76561197960287930", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewHS256Verifier([]byte("secret")).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := SteamIDFromContext(r.Context())
+		w.Write([]byte(id))
+	}))
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"valid", "Bearer " + tok, http.StatusOK},
+		{"lowercase scheme", "bearer " + tok, http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"basic auth", "Basic dXNlcjpwYXNz", http.StatusUnauthorized},
+		{"bad token", "Bearer " + tok + "x", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != "76561197960287930" {
+				t.Errorf("body = %q, want the steamid from the token", rec.Body.String())
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("missing WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsIDTokens(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ti := NewRS256Issuer(key)
+	tv := NewRS256Verifier(&key.PublicKey)
+
+	access, err := ti.Issue("76561197960287930", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tv.Verify(access); err != nil {
+		t.Errorf("Verify(access token) err = %v", err)
+	}
+
+	idToken, err := ti.SignIDToken(&Claims{Subject: "76561197960287930", Audience: "client", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tv.Verify(idToken); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify(id token) err = %v, want ErrInvalidToken", err)
+	}
+}
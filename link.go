@@ -0,0 +1,167 @@
+package gosteamauth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrInvalidLinkState is returned by Linker.Complete when the link state is missing, forged, expired or was started
+// for a different local user.
+var ErrInvalidLinkState = errors.New("invalid or expired link state")
+
+// LinkStateParam is the query parameter carrying the signed link state through steam and back.
+const LinkStateParam = "link_state"
+
+// DefaultLinkTTL is how long users have to finish linking their account.
+const DefaultLinkTTL = 10 * time.Minute
+
+// LinkResult is a steam account that has been proven to belong to a local user. Unlike a login, no session is
+// started, it's up to you to store the link.
+type LinkResult struct {
+	// LocalUserID is the id of the local account, as given to LinkUrl.
+	LocalUserID string
+	// SteamID is the steamid64 of the steam account being linked.
+	SteamID string
+	// LinkedAt is when the callback was verified.
+	LinkedAt time.Time
+}
+
+// Linker uses the steam flow to link a steam account to an account the user is already logged into, rather than to
+// log them in. The local user's id is carried through steam in a signed state, and checked against whoever is logged
+// in when they come back, so nobody can get their steam account linked to someone else.
+type Linker struct {
+	auther      *SteamAuther
+	key         []byte
+	callbackUrl string
+
+	// TTL is how long users have to finish linking their account. Defaults to DefaultLinkTTL.
+	TTL time.Duration
+}
+
+// NewLinker returns a new Linker. key signs the link state, and should be at least 32 random bytes. callbackUrl is the
+// full url the link callback is reachable at (ex. http://localhost:8080/settings/steam/callback).
+func NewLinker(auther *SteamAuther, key []byte, callbackUrl string) *Linker {
+	return &Linker{
+		auther:      auther,
+		key:         key,
+		callbackUrl: callbackUrl,
+		TTL:         DefaultLinkTTL,
+	}
+}
+
+// linkState is what's signed into the link state.
+type linkState struct {
+	LocalUserID string `json:"uid"`
+	ExpiresAt   int64  `json:"exp"`
+	Nonce       string `json:"n"`
+}
+
+// LinkUrl returns the steam url to send localUserId to, to link their steam account. Only call this for the user
+// that's currently logged in.
+func (l *Linker) LinkUrl(localUserId string) (string, error) {
+	nonce, err := randomToken(16)
+	if err != nil {
+		return "", fmt.Errorf("link url (%s): generate nonce: %w", localUserId, err)
+	}
+
+	payload, err := json.Marshal(linkState{
+		LocalUserID: localUserId,
+		ExpiresAt:   time.Now().Add(l.TTL).Unix(),
+		Nonce:       nonce,
+	})
+	if err != nil {
+		return "", fmt.Errorf("link url (%s): marshal state: %w", localUserId, err)
+	}
+
+	u, err := url.Parse(l.callbackUrl)
+	if err != nil {
+		return "", fmt.Errorf("link url (%s): parse callback url: %w", localUserId, err)
+	}
+
+	state := b64.EncodeToString(payload)
+	q := u.Query()
+	q.Set(LinkStateParam, state+"."+b64.EncodeToString(l.sign(state)))
+	u.RawQuery = q.Encode()
+
+	steamUrl, err := l.auther.GetAuthUrl(u.String())
+	if err != nil {
+		return "", fmt.Errorf("link url (%s): %w", localUserId, err)
+	}
+
+	return steamUrl, nil
+}
+
+// Complete verifies the link callback for localUserId, the user that's currently logged in, and returns the steam
+// account to link to them. Returns ErrInvalidLinkState if the link wasn't started by localUserId (or is forged or
+// expired), and ErrInvalidAuthRequest if steam doesn't vouch for the callback.
+func (l *Linker) Complete(r *http.Request, localUserId string) (*LinkResult, error) {
+	q := r.URL.Query()
+
+	// The state is read from the return url steam signed, not the request's url.
+	returnTo, err := returnToQuery(q, l.callbackUrl)
+	if err != nil {
+		return nil, fmt.Errorf("complete link (%s): %w", localUserId, err)
+	}
+
+	st, err := l.verifyState(returnTo.Get(LinkStateParam))
+	if err != nil {
+		return nil, fmt.Errorf("complete link (%s): %w", localUserId, err)
+	}
+
+	if !hmac.Equal([]byte(st.LocalUserID), []byte(localUserId)) {
+		return nil, fmt.Errorf("complete link (%s): started for another user: %w", localUserId, ErrInvalidLinkState)
+	}
+
+	steamid, err := l.auther.validateCallback(r.Context(), q)
+	if err != nil {
+		return nil, fmt.Errorf("complete link (%s): %w", localUserId, err)
+	}
+
+	return &LinkResult{
+		LocalUserID: localUserId,
+		SteamID:     steamid,
+		LinkedAt:    time.Now(),
+	}, nil
+}
+
+// verifyState checks the signature and expiry of a link state.
+func (l *Linker) verifyState(raw string) (*linkState, error) {
+	state, sig, ok := strings.Cut(raw, ".")
+	if !ok {
+		return nil, ErrInvalidLinkState
+	}
+
+	gotSig, err := b64.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotSig, l.sign(state)) {
+		return nil, ErrInvalidLinkState
+	}
+
+	payload, err := b64.DecodeString(state)
+	if err != nil {
+		return nil, ErrInvalidLinkState
+	}
+
+	var st linkState
+	if err := json.Unmarshal(payload, &st); err != nil {
+		return nil, ErrInvalidLinkState
+	}
+
+	if time.Now().Unix() > st.ExpiresAt {
+		return nil, ErrInvalidLinkState
+	}
+
+	return &st, nil
+}
+
+func (l *Linker) sign(state string) []byte {
+	mac := hmac.New(sha256.New, l.key)
+	mac.Write([]byte(LinkStateParam + ":" + state))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,100 @@
+package gosteamauth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// linkCallback starts linking localUserId and returns the callback steam would send back for steamid64.
+func linkCallback(t *testing.T, l *Linker, localUserId, steamid64 string) *http.Request {
+	t.Helper()
+
+	steamUrl, err := l.LinkUrl(localUserId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(steamUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return steamCallback(u.Query().Get("openid.return_to"), steamid64)
+}
+
+// tamperReturnTo changes the query of a callback's openid.return_to.
+func tamperReturnTo(r *http.Request, change func(q url.Values)) {
+	q := r.URL.Query()
+	u, _ := url.Parse(q.Get("openid.return_to"))
+	rq := u.Query()
+	change(rq)
+	u.RawQuery = rq.Encode()
+	q.Set("openid.return_to", u.String())
+	r.URL.RawQuery = q.Encode()
+}
+
+func TestLinker(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		// tamper changes the callback before it's completed.
+		tamper  func(r *http.Request)
+		user    string
+		wantErr error
+	}{
+		{"same user", time.Minute, nil, "alice", nil},
+		{"other user", time.Minute, nil, "mallory", ErrInvalidLinkState},
+		{"expired", -time.Minute, nil, "alice", ErrInvalidLinkState},
+		// the state is read from the return_to steam signed, so that's where it has to be tampered with
+		{"forged state", time.Minute, func(r *http.Request) {
+			tamperReturnTo(r, func(q url.Values) {
+				state, sig, _ := strings.Cut(q.Get(LinkStateParam), ".")
+				q.Set(LinkStateParam, state+"x."+sig)
+			})
+		}, "alice", ErrInvalidLinkState},
+		{"no state", time.Minute, func(r *http.Request) {
+			tamperReturnTo(r, func(q url.Values) { q.Del(LinkStateParam) })
+		}, "alice", ErrInvalidLinkState},
+		{"other callback url", time.Minute, func(r *http.Request) {
+			q := r.URL.Query()
+			q.Set("openid.return_to", strings.Replace(q.Get("openid.return_to"), "/settings/steam/callback", "/other", 1))
+			r.URL.RawQuery = q.Encode()
+		}, "alice", ErrInvalidAuthRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLinker(New("", "https://example.com"), []byte("0123456789abcdef0123456789abcdef"), "https://example.com/settings/steam/callback")
+			l.TTL = tt.ttl
+
+			req := linkCallback(t, l, "alice", "76561197960287930")
+			if tt.tamper != nil {
+				tt.tamper(req)
+			}
+
+			res, err := l.Complete(req, tt.user)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (res.LocalUserID != "alice" || res.SteamID != "76561197960287930") {
+				t.Errorf("res = %+v, want alice linked to 76561197960287930", res)
+			}
+		})
+	}
+}
+
+func TestLinkerSteamSaysNo(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:false\n"))
+	})
+
+	l := NewLinker(New("", "https://example.com"), []byte("0123456789abcdef0123456789abcdef"), "https://example.com/settings/steam/callback")
+	if _, err := l.Complete(linkCallback(t, l, "alice", "76561197960287930"), "alice"); !errors.Is(err, ErrInvalidAuthRequest) {
+		t.Errorf("err = %v, want ErrInvalidAuthRequest", err)
+	}
+}
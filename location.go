@@ -0,0 +1,123 @@
+package gosteamauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Locations turns the location codes on a SteamUser (LocCountryCode, LocStateCode and LocCityID) into names.
+// DefaultLocations knows every country and the states of the US and Canada, steam's full list (with every state and
+// city) can be loaded with LoadLocations.
+type Locations struct {
+	countries map[string]locationCountry
+}
+
+type locationCountry struct {
+	name   string
+	states map[string]locationState
+}
+
+type locationState struct {
+	name   string
+	cities map[int]string
+}
+
+// DefaultLocations is the bundled list of locations, used by SteamUser.Location.
+var DefaultLocations = defaultLocations()
+
+func defaultLocations() *Locations {
+	l := &Locations{countries: make(map[string]locationCountry, len(countryNames))}
+	for code, name := range countryNames {
+		country := locationCountry{name: name}
+		if states, ok := stateNames[code]; ok {
+			country.states = make(map[string]locationState, len(states))
+			for stateCode, stateName := range states {
+				country.states[stateCode] = locationState{name: stateName}
+			}
+		}
+		l.countries[code] = country
+	}
+
+	return l
+}
+
+// LoadLocations loads a list of locations in the format of the steam_countries.json that's pulled out of the steam
+// client (and kept up to date in a few places online), which has every state and city steam knows about:
+//
+//	{"US": {"name": "United States", "states": {"WA": {"name": "Washington", "cities": {"3961": {"name": "Seattle"}}}}}}
+//
+// Anything else in the file (like coordinates) is ignored.
+func LoadLocations(r io.Reader) (*Locations, error) {
+	type named struct {
+		Name string `json:"name"`
+	}
+	var data map[string]struct {
+		named
+		States map[string]struct {
+			named
+			Cities map[string]named `json:"cities"`
+		} `json:"states"`
+	}
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, fmt.Errorf("load locations: %w", err)
+	}
+
+	l := &Locations{countries: make(map[string]locationCountry, len(data))}
+	for code, c := range data {
+		country := locationCountry{name: c.Name, states: make(map[string]locationState, len(c.States))}
+		for stateCode, s := range c.States {
+			state := locationState{name: s.Name, cities: make(map[int]string, len(s.Cities))}
+			for cityId, city := range s.Cities {
+				id, err := strconv.Atoi(cityId)
+				if err != nil {
+					return nil, fmt.Errorf("load locations: city id %q in %s/%s: %w", cityId, code, stateCode, err)
+				}
+				state.cities[id] = city.Name
+			}
+			country.states[stateCode] = state
+		}
+		l.countries[code] = country
+	}
+
+	return l, nil
+}
+
+// Country returns the name of the country with the code (ex. "US" is "United States").
+func (l *Locations) Country(countryCode string) (string, bool) {
+	c, ok := l.countries[countryCode]
+	return c.name, ok
+}
+
+// State returns the name of the state with the code in the country (ex. "US", "WA" is "Washington").
+func (l *Locations) State(countryCode, stateCode string) (string, bool) {
+	s, ok := l.countries[countryCode].states[stateCode]
+	return s.name, ok
+}
+
+// City returns the name of the city with the id in the state. Only lists loaded with LoadLocations have cities.
+func (l *Locations) City(countryCode, stateCode string, cityId int) (string, bool) {
+	name, ok := l.countries[countryCode].states[stateCode].cities[cityId]
+	return name, ok
+}
+
+// Describe returns the location as steam shows it on profiles (ex. "Seattle, Washington, United States"), leaving
+// out the parts that are unset or unknown. Returns "" if even the country isn't known.
+func (l *Locations) Describe(countryCode, stateCode string, cityId int) string {
+	country, ok := l.Country(countryCode)
+	if !ok {
+		return ""
+	}
+
+	parts := []string{country}
+	if state, ok := l.State(countryCode, stateCode); ok {
+		parts = append([]string{state}, parts...)
+		if city, ok := l.City(countryCode, stateCode, cityId); ok {
+			parts = append([]string{city}, parts...)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
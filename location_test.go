@@ -0,0 +1,62 @@
+package gosteamauth
+
+import (
+	"strings"
+	"testing"
+)
+
+const steamCountries = `{
+	"US": {"name": "United States", "coordinates": "39.76,-98.50", "states": {
+		"WA": {"name": "Washington", "cities": {"3961": {"name": "Seattle", "coordinates": "47.60,-122.33"}}}
+	}},
+	"SE": {"name": "Sweden"}
+}`
+
+func TestDescribeLocation(t *testing.T) {
+	full, err := LoadLocations(strings.NewReader(steamCountries))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		locations *Locations
+		country   string
+		state     string
+		city      int
+		want      string
+	}{
+		{"country", DefaultLocations, "SE", "", 0, "Sweden"},
+		{"state", DefaultLocations, "US", "WA", 0, "Washington, United States"},
+		{"city without the full list", DefaultLocations, "US", "WA", 3961, "Washington, United States"},
+		{"city", full, "US", "WA", 3961, "Seattle, Washington, United States"},
+		{"unknown city", full, "US", "WA", 1, "Washington, United States"},
+		{"unknown state", DefaultLocations, "US", "ZZ", 0, "United States"},
+		{"unknown country", DefaultLocations, "ZZ", "", 0, ""},
+		{"not set", DefaultLocations, "", "", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.locations.Describe(tt.country, tt.state, tt.city); got != tt.want {
+				t.Errorf("Describe(%q, %q, %d) = %q, want %q", tt.country, tt.state, tt.city, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLocationsBadCity(t *testing.T) {
+	if _, err := LoadLocations(strings.NewReader(`{"US": {"states": {"WA": {"cities": {"seattle": {}}}}}}`)); err == nil {
+		t.Error("err = nil, want an error for a city id that isn't a number")
+	}
+	if _, err := LoadLocations(strings.NewReader(`nope`)); err == nil {
+		t.Error("err = nil, want an error for a file that isn't json")
+	}
+}
+
+func TestSteamUserLocation(t *testing.T) {
+	u := SteamUser{LocCountryCode: "CA", LocStateCode: "BC", LocCityID: 1}
+	if got := u.Location(); got != "British Columbia, Canada" {
+		t.Errorf("Location = %q, want British Columbia, Canada", got)
+	}
+}
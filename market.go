@@ -0,0 +1,93 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMarketPriceInterval is how long GetMarketPrice waits between requests, unless
+// SteamAuther.MarketPriceInterval is changed. Steam allows about 20 market requests a minute.
+const DefaultMarketPriceInterval = 3 * time.Second
+
+// MarketPrice is an item's price on the community market, as represented in the response from the market's
+// priceoverview api. Prices are formatted in the currency asked for (ex. "$1.23"), and are "" if there's nothing for
+// sale or nothing sold recently.
+type MarketPrice struct {
+	LowestPrice string
+	MedianPrice string
+	// Volume is how many were sold in the last 24 hours.
+	Volume int
+}
+
+// GetMarketPrice gets the price of an item on the community market by its market hash name (ex. "AK-47 | Redline
+// (Field-Tested)"). currency is steam's currency code (1 for USD, 2 for GBP, 3 for EUR, ...). Requests are spaced out
+// by MarketPriceInterval across the whole SteamAuther, so this blocks until it's its turn. Returns ErrNoData if the
+// market doesn't have the item.
+func (sa *SteamAuther) GetMarketPrice(ctx context.Context, appid int, marketHashName string, currency int) (*MarketPrice, error) {
+	if err := sa.marketThrottle.wait(ctx, sa.MarketPriceInterval); err != nil {
+		return nil, fmt.Errorf("get market price (%d, %s): %w", appid, marketHashName, err)
+	}
+
+	q := url.Values{
+		"appid":            {strconv.Itoa(appid)},
+		"market_hash_name": {marketHashName},
+		"currency":         {strconv.Itoa(currency)},
+	}
+	res, err := sa.get(ctx, CommunityBaseUrl+"/market/priceoverview/", q)
+	if err != nil {
+		return nil, fmt.Errorf("get market price (%d, %s): %w", appid, marketHashName, err)
+	}
+	defer res.Body.Close()
+
+	var data struct {
+		Success     bool   `json:"success"`
+		LowestPrice string `json:"lowest_price"`
+		MedianPrice string `json:"median_price"`
+		Volume      string `json:"volume"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("get market price (%d, %s): decode response body: %w", appid, marketHashName, err)
+	}
+
+	if !data.Success {
+		return nil, fmt.Errorf("get market price (%d, %s): %w", appid, marketHashName, ErrNoData)
+	}
+
+	// Volume comes formatted, like "1,234".
+	volume, _ := strconv.Atoi(strings.ReplaceAll(data.Volume, ",", ""))
+
+	return &MarketPrice{LowestPrice: data.LowestPrice, MedianPrice: data.MedianPrice, Volume: volume}, nil
+}
+
+// throttle spaces calls out so they're at least an interval apart.
+type throttle struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// wait blocks until it's the caller's turn, or ctx is done.
+func (t *throttle) wait(ctx context.Context, interval time.Duration) error {
+	t.mu.Lock()
+	at := time.Now()
+	if t.next.After(at) {
+		at = t.next
+	}
+	t.next = at.Add(interval)
+	t.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
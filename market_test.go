@@ -0,0 +1,67 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// marketPrices answers the market priceoverview api like steam would, knowing only about the Redline.
+func marketPrices(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if r.URL.Path != "/market/priceoverview/" || q.Has("key") {
+		http.NotFound(w, r)
+		return
+	}
+
+	if q.Get("appid") != "730" || q.Get("market_hash_name") != "AK-47 | Redline (Field-Tested)" || q.Get("currency") != "1" {
+		w.Write([]byte(`{"success":false}`))
+		return
+	}
+	w.Write([]byte(`{"success":true,"lowest_price":"$12.34","volume":"1,234","median_price":"$12.50"}`))
+}
+
+func TestGetMarketPrice(t *testing.T) {
+	fakeSteam(t, marketPrices)
+	sa := New("key", "https://example.com")
+	sa.MarketPriceInterval = 0
+
+	p, err := sa.GetMarketPrice(context.Background(), 730, "AK-47 | Redline (Field-Tested)", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *p != (MarketPrice{LowestPrice: "$12.34", MedianPrice: "$12.50", Volume: 1234}) {
+		t.Errorf("price = %+v, want the Redline's", p)
+	}
+
+	if _, err := sa.GetMarketPrice(context.Background(), 730, "AK-47 | Not A Skin", 1); !errors.Is(err, ErrNoData) {
+		t.Errorf("unknown item err = %v, want ErrNoData", err)
+	}
+}
+
+func TestGetMarketPriceThrottled(t *testing.T) {
+	fakeSteam(t, marketPrices)
+	sa := New("key", "https://example.com")
+	sa.MarketPriceInterval = 20 * time.Millisecond
+
+	start := time.Now()
+	for range 3 {
+		if _, err := sa.GetMarketPrice(context.Background(), 730, "AK-47 | Redline (Field-Tested)", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if took := time.Since(start); took < 40*time.Millisecond {
+		t.Errorf("3 requests took %v, want them at least 20ms apart", took)
+	}
+
+	// waiting for a turn gives up with ctx
+	sa.MarketPriceInterval = time.Hour
+	sa.GetMarketPrice(context.Background(), 730, "AK-47 | Redline (Field-Tested)", 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := sa.GetMarketPrice(ctx, 730, "AK-47 | Redline (Field-Tested)", 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,69 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// callbackMemo is what's remembered about a successful callback.
+type callbackMemo struct {
+	SteamID string `json:"steamid"`
+	Target  string `json:"target"`
+}
+
+// repeatedCallback returns where the first go at this callback sent the user, if it's a repeat of a successful
+// callback (ex. the user refreshed the page) from the browser that got the session out of it. Steam only vouches for
+// a callback once, so without this the repeat would fail.
+func (h *Handler) repeatedCallback(r *http.Request, q url.Values) (string, bool) {
+	key, ok := callbackMemoKey(q)
+	if !ok {
+		return "", false
+	}
+
+	b, err := h.sessions.store.Get(r.Context(), key)
+	if err != nil {
+		return "", false
+	}
+
+	var memo callbackMemo
+	if err := json.Unmarshal(b, &memo); err != nil {
+		return "", false
+	}
+
+	// Anyone else with the url (it could have leaked through logs or a Referer) isn't let in on the strength of it.
+	sess, err := h.sessions.Get(r)
+	if err != nil || sess.SteamID != memo.SteamID {
+		return "", false
+	}
+
+	return memo.Target, true
+}
+
+// rememberCallback remembers a successful callback for CallbackMemoTTL.
+func (h *Handler) rememberCallback(ctx context.Context, q url.Values, steamid, target string) {
+	key, ok := callbackMemoKey(q)
+	if !ok {
+		return
+	}
+
+	b, err := json.Marshal(callbackMemo{SteamID: steamid, Target: target})
+	if err != nil {
+		return
+	}
+
+	// Failing to remember just means a refresh gets an error page, like it would without memoizing.
+	_ = h.sessions.store.Set(ctx, key, b, h.CallbackMemoTTL)
+}
+
+// callbackMemoKey is where a callback is remembered in the store, keyed by steam's response nonce and signature so
+// only an identical callback matches.
+func callbackMemoKey(q url.Values) (string, bool) {
+	nonce, sig := q.Get("openid.response_nonce"), q.Get("openid.sig")
+	if nonce == "" || sig == "" {
+		return "", false
+	}
+
+	return "callback:" + hashToken(nonce+"|"+sig), true
+}
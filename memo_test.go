@@ -0,0 +1,79 @@
+package gosteamauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCallbackMemo(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		session bool
+		want    int
+	}{
+		{"refreshed", time.Minute, true, http.StatusFound},
+		// the url leaked to someone else, who doesn't have the session the first callback started
+		{"other browser", time.Minute, false, http.StatusUnauthorized},
+		{"not memoized", 0, true, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// steam only vouches for a callback once
+			checked := 0
+			fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+				checked++
+				if checked > 1 {
+					w.Write([]byte("ns:http://specs.openid.net/auth/2.0\nis_valid:false\n"))
+					return
+				}
+				validOpenId(w, r)
+			})
+
+			h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+			h.LoginRedirect = "/home"
+			h.CallbackMemoTTL = tt.ttl
+
+			returnTo, _ := startLogin(t, h)
+			rec := httptest.NewRecorder()
+			h.Callback(rec, steamCallback(returnTo, "76561197960287930"))
+			if rec.Code != http.StatusFound {
+				t.Fatalf("first callback status = %d, want 302", rec.Code)
+			}
+
+			req := steamCallback(returnTo, "76561197960287930")
+			if tt.session {
+				for _, c := range rec.Result().Cookies() {
+					if c.Name == h.Sessions().CookieName {
+						req.AddCookie(c)
+					}
+				}
+			}
+			rec = httptest.NewRecorder()
+			h.Callback(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("repeated callback status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusFound && rec.Header().Get("Location") != "/home" {
+				t.Errorf("repeated callback went to %q, want /home", rec.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestValidateCallbackLeavesValues(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	req := steamCallback("https://example.com/callback", "76561197960287930")
+	q := req.URL.Query()
+	if _, err := New("", "https://example.com").ValidateCallback(q); err != nil {
+		t.Fatal(err)
+	}
+	if got := q.Get("openid.mode"); got != "id_res" {
+		t.Errorf("openid.mode = %q after validating, want id_res", got)
+	}
+}
@@ -0,0 +1,51 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Middleware only lets requests with a valid "Authorization: Bearer <token>" header through to next.
+// The token's steamid64 and claims are put in the request context, see SteamIDFromContext and ClaimsFromContext.
+// Everything else gets a 401 with a WWW-Authenticate header, as described in RFC 6750.
+func (tv *TokenVerifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tok, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer`)
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		c, err := tv.Verify(tok)
+		if err != nil {
+			desc := "the token is invalid"
+			if errors.Is(err, ErrTokenExpired) {
+				desc = "the token has expired"
+			}
+
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="`+desc+`"`)
+			http.Error(w, desc, http.StatusUnauthorized)
+			return
+		}
+
+		ctx := ContextWithSteamID(r.Context(), c.Subject)
+		ctx = context.WithValue(ctx, claimsContextKey, c)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// bearerToken pulls the token out of the request's Authorization header.
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+
+	scheme, tok, ok := strings.Cut(h, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	tok = strings.TrimSpace(tok)
+	return tok, tok != ""
+}
@@ -0,0 +1,79 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// MiniProfile is the card steam shows when hovering over a user, as represented in the response from the community
+// miniprofile json.
+type MiniProfile struct {
+	Level int `json:"level"`
+	// LevelClass is the css class steam styles the level with (ex. "friendPlayerLevelNum lvl_10").
+	LevelClass  string `json:"level_class"`
+	PersonaName string `json:"persona_name"`
+	AvatarUrl   string `json:"avatar_url"`
+	// AvatarFrame is the url of the user's avatar frame, or "" if they don't have one.
+	AvatarFrame string `json:"avatar_frame"`
+	// ProfileBackground is the user's mini profile background, or nil if they don't have one.
+	ProfileBackground *MiniProfileBackground `json:"profile_background"`
+	// FavoriteBadge is the badge the user shows off, or nil if they haven't picked one.
+	FavoriteBadge *MiniProfileBadge `json:"favorite_badge"`
+	// InGame is what the user is playing, or nil if they aren't in a game.
+	InGame *MiniProfileGame `json:"in_game"`
+}
+
+// MiniProfileBackground is the video urls of a user's mini profile background.
+type MiniProfileBackground struct {
+	WebM string `json:"video/webm"`
+	MP4  string `json:"video/mp4"`
+}
+
+// MiniProfileBadge is the badge a user shows off on their mini profile.
+type MiniProfileBadge struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Icon is the url of the badge's icon.
+	Icon  string `json:"icon"`
+	Level int    `json:"level"`
+	XP    string `json:"xp"`
+}
+
+// MiniProfileGame is the game a user is playing, as shown on their mini profile.
+type MiniProfileGame struct {
+	Name string `json:"name"`
+	// IsNonSteam is true for games added to steam as shortcuts.
+	IsNonSteam bool `json:"is_non_steam"`
+	// Logo is the url of the game's logo.
+	Logo string `json:"logo"`
+	// RichPresence is what the game says the user is up to (ex. "Competitive - Mirage").
+	RichPresence string `json:"rich_presence"`
+}
+
+// GetMiniProfile gets the user's mini profile, the card steam shows when hovering over them, for showing the same
+// sort of thing. steamid is either the user's steamid64 or their account id (see AccountId). There's no web api for
+// this, so it comes from the steam community site.
+func (sa *SteamAuther) GetMiniProfile(ctx context.Context, steamid string) (*MiniProfile, error) {
+	accountId, err := AccountId(steamid)
+	if err != nil {
+		return nil, fmt.Errorf("get mini profile: %w", err)
+	}
+
+	res, err := sa.get(ctx, fmt.Sprintf("%s/miniprofile/%s/json", CommunityBaseUrl, accountId), nil)
+	if err != nil {
+		return nil, fmt.Errorf("get mini profile (%s): %w", steamid, err)
+	}
+	defer res.Body.Close()
+
+	var profile MiniProfile
+	if err := json.NewDecoder(res.Body).Decode(&profile); err != nil {
+		return nil, fmt.Errorf("get mini profile (%s): decode response body: %w", steamid, err)
+	}
+
+	if profile.PersonaName == "" {
+		return nil, fmt.Errorf("get mini profile (%s): %w", steamid, ErrNoData)
+	}
+
+	return &profile, nil
+}
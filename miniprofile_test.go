@@ -0,0 +1,59 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// miniProfiles answers the community miniprofile json like steam would for account 22202, who's in a game. Anyone
+// else gets the empty profile steam sends for accounts that don't exist.
+func miniProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/miniprofile/22202/json" {
+		w.Write([]byte(`{"level":0,"level_class":"friendPlayerLevelNum lvl_0","avatar_url":""}`))
+		return
+	}
+
+	w.Write([]byte(`{"level":42,"level_class":"friendPlayerLevelNum lvl_40","avatar_url":"https://avatars.steamstatic.com/abc_full.jpg","persona_name":"Rabscuttle",
+		"favorite_badge":{"name":"Years of Service","xp":"250 XP","level":5,"description":"Member since 2003.","icon":"https://community.steamstatic.com/badge.png"},
+		"in_game":{"name":"Team Fortress 2","is_non_steam":false,"logo":"https://cdn.steamstatic.com/tf2.jpg","rich_presence":"Playing on ctf_2fort"}}`))
+}
+
+func TestGetMiniProfile(t *testing.T) {
+	fakeSteam(t, miniProfiles)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name    string
+		steamid string
+		wantErr error
+	}{
+		{"steamid64", "76561197960287930", nil},
+		{"account id", "22202", nil},
+		{"doesn't exist", "76561197960287931", ErrNoData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := sa.GetMiniProfile(context.Background(), tt.steamid)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if p.Level != 42 || p.PersonaName != "Rabscuttle" || p.AvatarFrame != "" || p.ProfileBackground != nil {
+				t.Errorf("profile = %+v, want Rabscuttle without a frame or background", p)
+			}
+			if p.FavoriteBadge == nil || p.FavoriteBadge.Level != 5 || p.InGame == nil || p.InGame.RichPresence != "Playing on ctf_2fort" {
+				t.Errorf("badge = %+v, in game = %+v, want both", p.FavoriteBadge, p.InGame)
+			}
+		})
+	}
+
+	if _, err := sa.GetMiniProfile(context.Background(), "103582791429521412"); err == nil {
+		t.Error("group steamid err = nil, want an error")
+	}
+}
@@ -0,0 +1,81 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewsItem is a news post for a game, as represented in the response from the GetNewsForApp web api.
+type NewsItem struct {
+	Gid   string `json:"gid"`
+	Title string `json:"title"`
+	Url   string `json:"url"`
+	// IsExternalUrl is true if Url isn't on steam.
+	IsExternalUrl bool   `json:"is_external_url"`
+	Author        string `json:"author"`
+	// Contents is the post's body, which can have html or bbcode in it depending on the feed.
+	Contents string `json:"contents"`
+	// FeedLabel is the feed's display name, and FeedName its name for NewsFilter.Feeds.
+	FeedLabel string `json:"feedlabel"`
+	FeedName  string `json:"feedname"`
+	// FeedType is 1 for posts from steam's own announcements, 0 for ones from external feeds.
+	FeedType int `json:"feed_type"`
+	// Date is when the post was made, as a unix timestamp.
+	Date  int64    `json:"date"`
+	AppID int      `json:"appid"`
+	Tags  []string `json:"tags"`
+}
+
+// Posted returns when the post was made.
+func (n NewsItem) Posted() time.Time {
+	return time.Unix(n.Date, 0)
+}
+
+// NewsFilter is what to ask GetNewsForApp for. The zero value gets steam's defaults.
+type NewsFilter struct {
+	// Count is how many posts to get.
+	Count int
+	// MaxLength cuts Contents down to this many characters, 0 gets the whole thing.
+	MaxLength int
+	// Before only gets posts from before this time.
+	Before time.Time
+	// Feeds only gets posts from these feeds (ex. "steam_community_announcements").
+	Feeds []string
+	// Tags only gets posts with all of these tags (ex. "patchnotes").
+	Tags []string
+}
+
+// GetNewsForApp gets the latest news posts for a game, newest first.
+func (sa *SteamAuther) GetNewsForApp(ctx context.Context, appid int, filter NewsFilter) ([]NewsItem, error) {
+	q := url.Values{"appid": {strconv.Itoa(appid)}}
+	if filter.Count > 0 {
+		q.Set("count", strconv.Itoa(filter.Count))
+	}
+	if filter.MaxLength > 0 {
+		q.Set("maxlength", strconv.Itoa(filter.MaxLength))
+	}
+	if !filter.Before.IsZero() {
+		q.Set("enddate", strconv.FormatInt(filter.Before.Unix(), 10))
+	}
+	if len(filter.Feeds) > 0 {
+		q.Set("feeds", strings.Join(filter.Feeds, ","))
+	}
+	if len(filter.Tags) > 0 {
+		q.Set("tags", strings.Join(filter.Tags, ","))
+	}
+
+	var data struct {
+		AppNews struct {
+			NewsItems []NewsItem `json:"newsitems"`
+		} `json:"appnews"`
+	}
+	if err := sa.getApi(ctx, "ISteamNews/GetNewsForApp/v2", q, &data); err != nil {
+		return nil, fmt.Errorf("get news for app (%d): %w", appid, err)
+	}
+
+	return data.AppNews.NewsItems, nil
+}
@@ -0,0 +1,62 @@
+package gosteamauth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGetNewsForApp(t *testing.T) {
+	var asked url.Values
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamNews/GetNewsForApp/v2" {
+			http.NotFound(w, r)
+			return
+		}
+		asked = r.URL.Query()
+
+		w.Write([]byte(`{"appnews":{"appid":440,"newsitems":[
+			{"gid":"1","title":"Team Fortress 2 Update Released","url":"https://steamstore-a.akamaihd.net/news/externalpost/tf2_blog/1","is_external_url":true,"author":"erics","contents":"An update has been released.","feedlabel":"TF2 Blog","feedname":"tf2_blog","feed_type":0,"date":1700000000,"appid":440,"tags":["patchnotes"]}
+		],"count":1}}`))
+	})
+	sa := New("key", "https://example.com")
+
+	before := time.Unix(1710000000, 0)
+	tests := []struct {
+		name   string
+		filter NewsFilter
+		want   url.Values
+	}{
+		{"defaults", NewsFilter{}, url.Values{"appid": {"440"}, "key": {"key"}}},
+		{"everything", NewsFilter{Count: 5, MaxLength: 300, Before: before, Feeds: []string{"tf2_blog", "steam_community_announcements"}, Tags: []string{"patchnotes"}}, url.Values{
+			"appid":     {"440"},
+			"key":       {"key"},
+			"count":     {"5"},
+			"maxlength": {"300"},
+			"enddate":   {"1710000000"},
+			"feeds":     {"tf2_blog,steam_community_announcements"},
+			"tags":      {"patchnotes"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news, err := sa.GetNewsForApp(context.Background(), 440, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if asked.Encode() != tt.want.Encode() {
+				t.Errorf("asked for %s, want %s", asked.Encode(), tt.want.Encode())
+			}
+
+			if len(news) != 1 || news[0].FeedName != "tf2_blog" || !news[0].IsExternalUrl || news[0].Tags[0] != "patchnotes" {
+				t.Fatalf("news = %+v, want the update post", news)
+			}
+			if !news[0].Posted().Equal(time.Unix(1700000000, 0)) {
+				t.Errorf("Posted = %v, want it from date", news[0].Posted())
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/liondadev/go-steam-auth/paseto
+
+go 1.24.3
+
+require github.com/liondadev/go-steam-auth v0.0.0-00010101000000-000000000000
+
+require (
+	golang.org/x/crypto v0.48.0
+	golang.org/x/sys v0.41.0 // indirect
+)
+
+replace github.com/liondadev/go-steam-auth => ../
@@ -0,0 +1,302 @@
+// Package paseto implements PASETO v4 tokens as an alternative to JWTs for gosteamauth.TokenIssuer and
+// gosteamauth.TokenVerifier, for teams that would rather not deal with JWT's algorithm confusion pitfalls.
+//
+// Use Local (v4.local, symmetric and encrypted) when the same service issues and checks tokens, and Public
+// (v4.public, Ed25519 signed) when other services need to verify them without being able to mint their own:
+//
+//	issuer := gosteamauth.NewIssuer(local)
+//	verifier := gosteamauth.NewVerifier(local)
+package paseto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+const (
+	headerLocal  = "v4.local."
+	headerPublic = "v4.public."
+)
+
+// ErrMalformed is returned when a token isn't a well formed v4 token of the expected purpose.
+var ErrMalformed = errors.New("malformed paseto token")
+
+// ErrUnauthenticated is returned when a token's tag or signature doesn't check out.
+var ErrUnauthenticated = errors.New("paseto token failed authentication")
+
+// b64 is the unpadded base64url encoding PASETO uses.
+var b64 = base64.RawURLEncoding
+
+// Local mints and parses v4.local tokens. The claims are encrypted, so they can't be read by the client.
+type Local struct {
+	key []byte
+
+	// Footer is added to every token unencrypted (but authenticated). Tokens with a different footer are rejected.
+	Footer string
+}
+
+// NewLocal returns a new Local using the provided 32 byte key.
+func NewLocal(key []byte) (*Local, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("new v4.local: key must be 32 bytes, got %d", len(key))
+	}
+
+	return &Local{key: key}, nil
+}
+
+// Sign implements gosteamauth.TokenSigner.
+func (l *Local) Sign(c *gosteamauth.Claims) (string, error) {
+	m, err := encodeClaims(c)
+	if err != nil {
+		return "", err
+	}
+
+	n := make([]byte, 32)
+	if _, err := rand.Read(n); err != nil {
+		return "", fmt.Errorf("v4.local: generate nonce: %w", err)
+	}
+
+	ek, n2, ak := l.splitKey(n)
+
+	ct := make([]byte, len(m))
+	s, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		return "", fmt.Errorf("v4.local: %w", err)
+	}
+	s.XORKeyStream(ct, m)
+
+	t := mac(ak, pae([]byte(headerLocal), n, ct, []byte(l.Footer), nil))
+
+	return appendFooter(headerLocal+b64.EncodeToString(concat(n, ct, t)), l.Footer), nil
+}
+
+// Parse implements gosteamauth.TokenParser.
+func (l *Local) Parse(token string) (*gosteamauth.Claims, error) {
+	body, err := splitToken(token, headerLocal, l.Footer)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) < 32+32 {
+		return nil, ErrMalformed
+	}
+	n, ct, t := body[:32], body[32:len(body)-32], body[len(body)-32:]
+
+	ek, n2, ak := l.splitKey(n)
+	if subtle.ConstantTimeCompare(t, mac(ak, pae([]byte(headerLocal), n, ct, []byte(l.Footer), nil))) != 1 {
+		return nil, ErrUnauthenticated
+	}
+
+	m := make([]byte, len(ct))
+	s, err := chacha20.NewUnauthenticatedCipher(ek, n2)
+	if err != nil {
+		return nil, fmt.Errorf("v4.local: %w", err)
+	}
+	s.XORKeyStream(m, ct)
+
+	return decodeClaims(m)
+}
+
+// splitKey derives the encryption key, xchacha nonce and authentication key for nonce n.
+func (l *Local) splitKey(n []byte) (ek, n2, ak []byte) {
+	h, _ := blake2b.New(56, l.key)
+	h.Write([]byte("paseto-encryption-key"))
+	h.Write(n)
+	tmp := h.Sum(nil)
+
+	h, _ = blake2b.New(32, l.key)
+	h.Write([]byte("paseto-auth-key-for-aead"))
+	h.Write(n)
+
+	return tmp[:32], tmp[32:], h.Sum(nil)
+}
+
+func mac(key, msg []byte) []byte {
+	h, _ := blake2b.New(32, key)
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// Public mints and parses v4.public tokens. The claims are signed but not encrypted.
+type Public struct {
+	priv ed25519.PrivateKey
+	pub  ed25519.PublicKey
+
+	// Footer is added to every token unencrypted (but signed). Tokens with a different footer are rejected.
+	Footer string
+}
+
+// NewPublic returns a new Public that can both sign and parse tokens.
+func NewPublic(priv ed25519.PrivateKey) *Public {
+	return &Public{
+		priv: priv,
+		pub:  priv.Public().(ed25519.PublicKey),
+	}
+}
+
+// NewPublicVerifier returns a new Public that can only parse tokens. Calling Sign on it returns an error.
+func NewPublicVerifier(pub ed25519.PublicKey) *Public {
+	return &Public{pub: pub}
+}
+
+// Sign implements gosteamauth.TokenSigner.
+func (p *Public) Sign(c *gosteamauth.Claims) (string, error) {
+	if p.priv == nil {
+		return "", errors.New("v4.public: no private key to sign with")
+	}
+
+	m, err := encodeClaims(c)
+	if err != nil {
+		return "", err
+	}
+
+	sig := ed25519.Sign(p.priv, pae([]byte(headerPublic), m, []byte(p.Footer), nil))
+
+	return appendFooter(headerPublic+b64.EncodeToString(concat(m, sig)), p.Footer), nil
+}
+
+// Parse implements gosteamauth.TokenParser.
+func (p *Public) Parse(token string) (*gosteamauth.Claims, error) {
+	body, err := splitToken(token, headerPublic, p.Footer)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) < ed25519.SignatureSize {
+		return nil, ErrMalformed
+	}
+	m, sig := body[:len(body)-ed25519.SignatureSize], body[len(body)-ed25519.SignatureSize:]
+
+	if !ed25519.Verify(p.pub, pae([]byte(headerPublic), m, []byte(p.Footer), nil), sig) {
+		return nil, ErrUnauthenticated
+	}
+
+	return decodeClaims(m)
+}
+
+// splitToken checks the header and footer of token and returns its decoded body.
+func splitToken(token, header, footer string) ([]byte, error) {
+	if !strings.HasPrefix(token, header) {
+		return nil, ErrMalformed
+	}
+
+	body, f, hasFooter := strings.Cut(token[len(header):], ".")
+	if hasFooter {
+		fb, err := b64.DecodeString(f)
+		if err != nil {
+			return nil, ErrMalformed
+		}
+		f = string(fb)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(f), []byte(footer)) != 1 {
+		return nil, ErrUnauthenticated
+	}
+
+	b, err := b64.DecodeString(body)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+
+	return b, nil
+}
+
+func appendFooter(token, footer string) string {
+	if footer == "" {
+		return token
+	}
+
+	return token + "." + b64.EncodeToString([]byte(footer))
+}
+
+// pae is the pre-authentication encoding from the PASETO spec.
+func pae(pieces ...[]byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint64(len(pieces)))
+	for _, p := range pieces {
+		binary.Write(&buf, binary.LittleEndian, uint64(len(p)))
+		buf.Write(p)
+	}
+
+	return buf.Bytes()
+}
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+
+	return out
+}
+
+// timeClaims are the claims PASETO requires to be RFC 3339 strings, where gosteamauth.Claims (like JWT) uses unix
+// timestamps.
+var timeClaims = []string{"exp", "iat", "nbf"}
+
+// encodeClaims marshals c, converting the time claims to the format PASETO expects.
+func encodeClaims(c *gosteamauth.Claims) ([]byte, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("encode claims: %w", err)
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("encode claims: %w", err)
+	}
+
+	for _, k := range timeClaims {
+		if v, ok := m[k].(float64); ok {
+			m[k] = time.Unix(int64(v), 0).UTC().Format(time.RFC3339)
+		}
+	}
+
+	return json.Marshal(m)
+}
+
+// decodeClaims is the inverse of encodeClaims.
+func decodeClaims(b []byte) (*gosteamauth.Claims, error) {
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("decode claims: %w", err)
+	}
+
+	for _, k := range timeClaims {
+		s, ok := m[k].(string)
+		if !ok {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return nil, fmt.Errorf("decode claims: parse %s: %w", k, err)
+		}
+		m[k] = t.Unix()
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("decode claims: %w", err)
+	}
+
+	var c gosteamauth.Claims
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("decode claims: %w", err)
+	}
+
+	return &c, nil
+}
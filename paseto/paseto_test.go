@@ -0,0 +1,163 @@
+package paseto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+const steamid = "76561197960287930"
+
+func newLocal(t *testing.T) *Local {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := NewLocal(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func newPublic(t *testing.T) *Public {
+	t.Helper()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPublic(priv)
+}
+
+func TestRoundTrip(t *testing.T) {
+	local := newLocal(t)
+	local.Footer = "kid-1"
+	public := newPublic(t)
+
+	tests := []struct {
+		name   string
+		signer gosteamauth.TokenSigner
+		parser gosteamauth.TokenParser
+		prefix string
+	}{
+		{"local", local, local, headerLocal},
+		{"public", public, public, headerPublic},
+		{"public verifier", public, NewPublicVerifier(public.pub), headerPublic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := gosteamauth.NewIssuer(tt.signer)
+			ti.Issuer = "https://example.com"
+			tv := gosteamauth.NewVerifier(tt.parser)
+			tv.Issuer = "https://example.com"
+
+			tok, err := ti.Issue(steamid, &gosteamauth.SteamUser{PersonaName: "Rabscuttle"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(tok, tt.prefix) {
+				t.Errorf("token = %q, want it to start with %q", tok, tt.prefix)
+			}
+
+			c, err := tv.Verify(tok)
+			if err != nil {
+				t.Fatalf("Verify err = %v", err)
+			}
+			if c.Subject != steamid || c.PersonaName != "Rabscuttle" || c.ExpiresAt == 0 {
+				t.Errorf("claims = %+v, want the issued ones back", c)
+			}
+		})
+	}
+}
+
+func TestLocalEncryptsClaims(t *testing.T) {
+	l := newLocal(t)
+
+	tok, err := gosteamauth.NewIssuer(l).Issue(steamid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := b64.DecodeString(strings.TrimPrefix(tok, headerLocal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), steamid) {
+		t.Error("v4.local token carries the claims in the clear")
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	local := newLocal(t)
+	public := newPublic(t)
+
+	localTok, err := gosteamauth.NewIssuer(local).Issue(steamid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicTok, err := gosteamauth.NewIssuer(public).Issue(steamid, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// flip a bit in the middle of the body, so the token still decodes
+	tamper := func(tok string) string {
+		b := []byte(tok)
+		i := len(b) / 2
+		if b[i] == 'A' {
+			b[i] = 'B'
+		} else {
+			b[i] = 'A'
+		}
+		return string(b)
+	}
+
+	withFooter := *local
+	withFooter.Footer = "kid-1"
+
+	tests := []struct {
+		name    string
+		parser  gosteamauth.TokenParser
+		token   string
+		wantErr error
+	}{
+		{"local tampered", local, tamper(localTok), ErrUnauthenticated},
+		{"local other key", newLocal(t), localTok, ErrUnauthenticated},
+		{"local unexpected footer", &withFooter, localTok, ErrUnauthenticated},
+		{"local given public", local, publicTok, ErrMalformed},
+		{"public tampered", public, tamper(publicTok), ErrUnauthenticated},
+		{"public other key", newPublic(t), publicTok, ErrUnauthenticated},
+		{"public given local", public, localTok, ErrMalformed},
+		{"truncated", local, headerLocal + "AAAA", ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.parser.Parse(tt.token); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublicVerifierCantSign(t *testing.T) {
+	p := newPublic(t)
+	if _, err := NewPublicVerifier(p.pub).Sign(&gosteamauth.Claims{Subject: steamid}); err == nil {
+		t.Error("Sign on a verifier only Public succeeded")
+	}
+}
+
+func TestNewLocalKeySize(t *testing.T) {
+	if _, err := NewLocal(make([]byte, 16)); err == nil {
+		t.Error("NewLocal accepted a 16 byte key")
+	}
+}
@@ -0,0 +1,116 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// GetSteamLevel gets the user's steam level, for showing on their profile. To gate logins on it, see MinSteamLevel.
+// Returns ErrPrivateProfile if the user's profile is private.
+func (sa *SteamAuther) GetSteamLevel(ctx context.Context, steamid64 string) (int, error) {
+	var data struct {
+		Response struct {
+			// Steam leaves this out for private profiles, rather than saying so.
+			PlayerLevel *int `json:"player_level"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IPlayerService/GetSteamLevel/v1", url.Values{"steamid": {steamid64}}, &data); err != nil {
+		return 0, fmt.Errorf("get steam level (%s): %w", steamid64, err)
+	}
+
+	if data.Response.PlayerLevel == nil {
+		return 0, fmt.Errorf("get steam level (%s): %w", steamid64, ErrPrivateProfile)
+	}
+
+	return *data.Response.PlayerLevel, nil
+}
+
+// Badge is a badge the user has earned, as represented in the response from the GetBadges web api.
+type Badge struct {
+	// BadgeID is the badge's id. For game badges it's only unique within the game.
+	BadgeID int `json:"badgeid"`
+	// AppID is the game the badge is for, or 0 for steam's own badges.
+	AppID int `json:"appid"`
+	// Level is the badge's level.
+	Level int `json:"level"`
+	// CompletionTime is when the user earned the badge's current level, as a unix timestamp.
+	CompletionTime int64 `json:"completion_time"`
+	// XP is how much xp the badge is worth.
+	XP int `json:"xp"`
+	// Scarcity is how many people have the badge.
+	Scarcity int `json:"scarcity"`
+	// CommunityItemID is the id of the badge's item, for game badges.
+	CommunityItemID string `json:"communityitemid"`
+	// BorderColor is 1 for foil game badges.
+	BorderColor int `json:"border_color"`
+}
+
+// Completed is when the user earned the badge's current level.
+func (b Badge) Completed() time.Time {
+	return time.Unix(b.CompletionTime, 0)
+}
+
+// Badges is the user's badges and the xp they add up to, as represented in the response from the GetBadges web api.
+type Badges struct {
+	Badges []Badge `json:"badges"`
+	// PlayerXP is the user's total xp.
+	PlayerXP int `json:"player_xp"`
+	// PlayerLevel is the user's steam level.
+	PlayerLevel int `json:"player_level"`
+	// PlayerXPNeededToLevelUp is how much more xp the user needs to get to the next level.
+	PlayerXPNeededToLevelUp int `json:"player_xp_needed_to_level_up"`
+	// PlayerXPNeededCurrentLevel is how much xp the user's current level took.
+	PlayerXPNeededCurrentLevel int `json:"player_xp_needed_current_level"`
+}
+
+// GetBadges gets the user's badges. Returns ErrPrivateProfile if the user's profile is private.
+func (sa *SteamAuther) GetBadges(ctx context.Context, steamid64 string) (*Badges, error) {
+	var data struct {
+		Response struct {
+			Badges
+			// Steam sends back an empty response for private profiles, rather than saying so.
+			PlayerXP *int `json:"player_xp"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IPlayerService/GetBadges/v1", url.Values{"steamid": {steamid64}}, &data); err != nil {
+		return nil, fmt.Errorf("get badges (%s): %w", steamid64, err)
+	}
+
+	if data.Response.PlayerXP == nil {
+		return nil, fmt.Errorf("get badges (%s): %w", steamid64, ErrPrivateProfile)
+	}
+	data.Response.Badges.PlayerXP = *data.Response.PlayerXP
+
+	return &data.Response.Badges, nil
+}
+
+// BadgeQuest is one of the tasks for a community badge, as represented in the response from the
+// GetCommunityBadgeProgress web api.
+type BadgeQuest struct {
+	QuestID   int  `json:"questid"`
+	Completed bool `json:"completed"`
+}
+
+// GetCommunityBadgeProgress gets the user's progress on the tasks for one of steam's community badges (ex. 2 for the
+// "Pillar of Community" badge). Returns ErrPrivateProfile if the user's profile is private.
+func (sa *SteamAuther) GetCommunityBadgeProgress(ctx context.Context, steamid64 string, badgeid int) ([]BadgeQuest, error) {
+	var data struct {
+		Response struct {
+			// Steam sends back an empty response for private profiles, rather than saying so.
+			Quests *[]BadgeQuest `json:"quests"`
+		} `json:"response"`
+	}
+	q := url.Values{"steamid": {steamid64}, "badgeid": {strconv.Itoa(badgeid)}}
+	if err := sa.getApi(ctx, "IPlayerService/GetCommunityBadgeProgress/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get community badge progress (%s, %d): %w", steamid64, badgeid, err)
+	}
+
+	if data.Response.Quests == nil {
+		return nil, fmt.Errorf("get community badge progress (%s, %d): %w", steamid64, badgeid, ErrPrivateProfile)
+	}
+
+	return *data.Response.Quests, nil
+}
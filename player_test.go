@@ -0,0 +1,121 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// steamLevels answers GetSteamLevel like steam would: 76561197960287930 is level 12, 76561197960287931 is private
+// and steam fails for anyone else.
+func steamLevels(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/IPlayerService/GetSteamLevel/v1" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.URL.Query().Get("steamid") {
+	case "76561197960287930":
+		w.Write([]byte(`{"response":{"player_level":12}}`))
+	case "76561197960287931":
+		w.Write([]byte(`{"response":{}}`))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func TestGetSteamLevel(t *testing.T) {
+	fakeSteam(t, steamLevels)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name    string
+		steamid string
+		want    int
+		wantErr error
+	}{
+		{"public", "76561197960287930", 12, nil},
+		{"private", "76561197960287931", 0, ErrPrivateProfile},
+		{"steam is down", "76561197960287932", 0, ErrSteamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sa.GetSteamLevel(context.Background(), tt.steamid)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetSteamLevel = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// badges answers GetBadges and GetCommunityBadgeProgress like steam would: 76561197960287930 has a couple of badges
+// and 76561197960287931 is private.
+func badges(w http.ResponseWriter, r *http.Request) {
+	private := r.URL.Query().Get("steamid") == "76561197960287931"
+
+	switch r.URL.Path {
+	case "/IPlayerService/GetBadges/v1":
+		if private {
+			w.Write([]byte(`{"response":{}}`))
+			return
+		}
+		w.Write([]byte(`{"response":{"badges":[
+			{"badgeid":1,"level":5,"completion_time":1700000000,"xp":250,"scarcity":1000},
+			{"badgeid":1,"appid":440,"level":1,"completion_time":1600000000,"xp":100,"communityitemid":"123","border_color":1}
+		],"player_xp":350,"player_level":3,"player_xp_needed_to_level_up":50,"player_xp_needed_current_level":300}}`))
+	case "/IPlayerService/GetCommunityBadgeProgress/v1":
+		if private {
+			w.Write([]byte(`{"response":{}}`))
+			return
+		}
+		w.Write([]byte(`{"response":{"quests":[{"questid":115,"completed":true},{"questid":116,"completed":false}]}}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestGetBadges(t *testing.T) {
+	fakeSteam(t, badges)
+	sa := New("key", "https://example.com")
+
+	b, err := sa.GetBadges(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.PlayerXP != 350 || b.PlayerLevel != 3 || b.PlayerXPNeededToLevelUp != 50 || b.PlayerXPNeededCurrentLevel != 300 {
+		t.Errorf("badges = %+v, want the user's xp and level", b)
+	}
+	if len(b.Badges) != 2 || b.Badges[1].AppID != 440 || b.Badges[1].BorderColor != 1 {
+		t.Fatalf("badges = %+v, want the community badge and the foil tf2 one", b.Badges)
+	}
+	if !b.Badges[0].Completed().Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Completed = %v, want it from completion_time", b.Badges[0].Completed())
+	}
+
+	if _, err := sa.GetBadges(context.Background(), "76561197960287931"); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private profile err = %v, want ErrPrivateProfile", err)
+	}
+}
+
+func TestGetCommunityBadgeProgress(t *testing.T) {
+	fakeSteam(t, badges)
+	sa := New("key", "https://example.com")
+
+	quests, err := sa.GetCommunityBadgeProgress(context.Background(), "76561197960287930", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(quests) != 2 || !quests[0].Completed || quests[1].Completed {
+		t.Errorf("quests = %+v, want one done and one not", quests)
+	}
+
+	if _, err := sa.GetCommunityBadgeProgress(context.Background(), "76561197960287931", 2); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private profile err = %v, want ErrPrivateProfile", err)
+	}
+}
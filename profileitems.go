@@ -0,0 +1,139 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// CommunityImagesBaseUrl is where the images and movies of profile items are, ProfileItem's paths are relative to it.
+const CommunityImagesBaseUrl = "https://cdn.akamai.steamstatic.com/steamcommunity/public/images/"
+
+// ProfileItem is a cosmetic a user has equipped on their profile (an animated avatar, avatar frame, or background), as
+// represented in the responses from the IPlayerService web apis.
+type ProfileItem struct {
+	CommunityItemID string `json:"communityitemid"`
+	// ImageSmall and ImageLarge are paths relative to CommunityImagesBaseUrl, see ProfileItem.Url.
+	ImageSmall string `json:"image_small"`
+	ImageLarge string `json:"image_large"`
+	// Name is the item's internal name, ItemTitle is the one shown to users.
+	Name            string `json:"name"`
+	ItemTitle       string `json:"item_title"`
+	ItemDescription string `json:"item_description"`
+	// AppID is the game the item is from.
+	AppID     int `json:"appid"`
+	ItemType  int `json:"item_type"`
+	ItemClass int `json:"item_class"`
+	// MovieWebm, MovieMp4, MovieWebmSmall and MovieMp4Small are paths of animated backgrounds, relative to
+	// CommunityImagesBaseUrl.
+	MovieWebm      string `json:"movie_webm"`
+	MovieMp4       string `json:"movie_mp4"`
+	MovieWebmSmall string `json:"movie_webm_small"`
+	MovieMp4Small  string `json:"movie_mp4_small"`
+}
+
+// Url turns one of the item's paths into a full url, or returns "" if the path is empty.
+func (*ProfileItem) Url(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	return CommunityImagesBaseUrl + path
+}
+
+// GetAnimatedAvatar gets the user's animated avatar, or nil if they don't have one equipped.
+func (sa *SteamAuther) GetAnimatedAvatar(ctx context.Context, steamid64 string) (*ProfileItem, error) {
+	item, err := sa.getProfileItem(ctx, "IPlayerService/GetAnimatedAvatar/v1", steamid64, "avatar")
+	if err != nil {
+		return nil, fmt.Errorf("get animated avatar (%s): %w", steamid64, err)
+	}
+
+	return item, nil
+}
+
+// GetAvatarFrame gets the user's avatar frame, or nil if they don't have one equipped.
+func (sa *SteamAuther) GetAvatarFrame(ctx context.Context, steamid64 string) (*ProfileItem, error) {
+	item, err := sa.getProfileItem(ctx, "IPlayerService/GetAvatarFrame/v1", steamid64, "avatar_frame")
+	if err != nil {
+		return nil, fmt.Errorf("get avatar frame (%s): %w", steamid64, err)
+	}
+
+	return item, nil
+}
+
+// GetProfileBackground gets the user's profile background, or nil if they don't have one equipped.
+func (sa *SteamAuther) GetProfileBackground(ctx context.Context, steamid64 string) (*ProfileItem, error) {
+	item, err := sa.getProfileItem(ctx, "IPlayerService/GetProfileBackground/v1", steamid64, "profile_background")
+	if err != nil {
+		return nil, fmt.Errorf("get profile background (%s): %w", steamid64, err)
+	}
+
+	return item, nil
+}
+
+// GetMiniProfileBackground gets the background of the user's mini profile (the card that pops up when hovering over
+// them), or nil if they don't have one equipped.
+func (sa *SteamAuther) GetMiniProfileBackground(ctx context.Context, steamid64 string) (*ProfileItem, error) {
+	item, err := sa.getProfileItem(ctx, "IPlayerService/GetMiniProfileBackground/v1", steamid64, "profile_background")
+	if err != nil {
+		return nil, fmt.Errorf("get mini profile background (%s): %w", steamid64, err)
+	}
+
+	return item, nil
+}
+
+// getProfileItem calls one of the single item IPlayerService methods, which send the item back under key.
+func (sa *SteamAuther) getProfileItem(ctx context.Context, method, steamid64, key string) (*ProfileItem, error) {
+	var data struct {
+		Response map[string]*ProfileItem `json:"response"`
+	}
+	if err := sa.getApi(ctx, method, url.Values{"steamid": {steamid64}}, &data); err != nil {
+		return nil, err
+	}
+
+	return equipped(data.Response[key]), nil
+}
+
+// equipped returns nil for items steam sent back empty, which it does when nothing is equipped.
+func equipped(item *ProfileItem) *ProfileItem {
+	if item == nil || item.CommunityItemID == "" {
+		return nil
+	}
+
+	return item
+}
+
+// ProfileItemsEquipped is everything a user has equipped on their profile. Items they don't have equipped are nil.
+type ProfileItemsEquipped struct {
+	ProfileBackground     *ProfileItem `json:"profile_background"`
+	MiniProfileBackground *ProfileItem `json:"mini_profile_background"`
+	AvatarFrame           *ProfileItem `json:"avatar_frame"`
+	AnimatedAvatar        *ProfileItem `json:"animated_avatar"`
+	ProfileModifier       *ProfileItem `json:"profile_modifier"`
+	SteamDeckKeyboardSkin *ProfileItem `json:"steam_deck_keyboard_skin"`
+}
+
+// GetProfileItemsEquipped gets everything the user has equipped on their profile in one call, rather than asking for
+// each item separately.
+func (sa *SteamAuther) GetProfileItemsEquipped(ctx context.Context, steamid64 string) (*ProfileItemsEquipped, error) {
+	var data struct {
+		Response ProfileItemsEquipped `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IPlayerService/GetProfileItemsEquipped/v1", url.Values{"steamid": {steamid64}}, &data); err != nil {
+		return nil, fmt.Errorf("get profile items equipped (%s): %w", steamid64, err)
+	}
+
+	items := &data.Response
+	for _, item := range []**ProfileItem{
+		&items.ProfileBackground,
+		&items.MiniProfileBackground,
+		&items.AvatarFrame,
+		&items.AnimatedAvatar,
+		&items.ProfileModifier,
+		&items.SteamDeckKeyboardSkin,
+	} {
+		*item = equipped(*item)
+	}
+
+	return items, nil
+}
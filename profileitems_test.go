@@ -0,0 +1,102 @@
+package gosteamauth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// profileItems answers the IPlayerService profile item methods like steam would: 76561197960287930 has everything
+// equipped and anyone else has nothing.
+func profileItems(w http.ResponseWriter, r *http.Request) {
+	keys := map[string]string{
+		"/IPlayerService/GetAnimatedAvatar/v1":        "avatar",
+		"/IPlayerService/GetAvatarFrame/v1":           "avatar_frame",
+		"/IPlayerService/GetProfileBackground/v1":     "profile_background",
+		"/IPlayerService/GetMiniProfileBackground/v1": "profile_background",
+	}
+	key, ok := keys[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.URL.Query().Get("steamid") != "76561197960287930" {
+		// steam sends back an empty item rather than leaving it out
+		w.Write([]byte(`{"response":{"` + key + `":{}}}`))
+		return
+	}
+	w.Write([]byte(`{"response":{"` + key + `":{"communityitemid":"42","image_small":"items/440/small.png","image_large":"items/440/large.png","item_title":"` + r.URL.Path + `","appid":440,"movie_webm":"items/440/bg.webm"}}}`))
+}
+
+func TestGetProfileItems(t *testing.T) {
+	fakeSteam(t, profileItems)
+	sa := New("key", "https://example.com")
+
+	gets := map[string]func(context.Context, string) (*ProfileItem, error){
+		"/IPlayerService/GetAnimatedAvatar/v1":        sa.GetAnimatedAvatar,
+		"/IPlayerService/GetAvatarFrame/v1":           sa.GetAvatarFrame,
+		"/IPlayerService/GetProfileBackground/v1":     sa.GetProfileBackground,
+		"/IPlayerService/GetMiniProfileBackground/v1": sa.GetMiniProfileBackground,
+	}
+
+	for method, get := range gets {
+		t.Run(method, func(t *testing.T) {
+			item, err := get(context.Background(), "76561197960287930")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if item == nil || item.ItemTitle != method || item.AppID != 440 {
+				t.Fatalf("item = %+v, want the one from %s", item, method)
+			}
+
+			item, err = get(context.Background(), "76561197960287931")
+			if err != nil || item != nil {
+				t.Errorf("nothing equipped = %+v, %v, want nil, nil", item, err)
+			}
+		})
+	}
+}
+
+func TestProfileItemUrl(t *testing.T) {
+	item := &ProfileItem{ImageLarge: "items/440/large.png"}
+
+	if got, want := item.Url(item.ImageLarge), CommunityImagesBaseUrl+"items/440/large.png"; got != want {
+		t.Errorf("Url = %q, want %q", got, want)
+	}
+	if got := item.Url(item.MovieWebm); got != "" {
+		t.Errorf("Url of an empty path = %q, want \"\"", got)
+	}
+}
+
+func TestGetProfileItemsEquipped(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/IPlayerService/GetProfileItemsEquipped/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"response":{
+			"profile_background":{"communityitemid":"1","item_title":"Background"},
+			"mini_profile_background":{},
+			"avatar_frame":{"communityitemid":"3","item_title":"Frame"},
+			"animated_avatar":{},
+			"profile_modifier":{},
+			"steam_deck_keyboard_skin":{}
+		}}`))
+	})
+
+	items, err := New("key", "https://example.com").GetProfileItemsEquipped(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if items.ProfileBackground == nil || items.ProfileBackground.ItemTitle != "Background" {
+		t.Errorf("ProfileBackground = %+v, want the background", items.ProfileBackground)
+	}
+	if items.AvatarFrame == nil || items.AvatarFrame.ItemTitle != "Frame" {
+		t.Errorf("AvatarFrame = %+v, want the frame", items.AvatarFrame)
+	}
+	if items.MiniProfileBackground != nil || items.AnimatedAvatar != nil || items.ProfileModifier != nil || items.SteamDeckKeyboardSkin != nil {
+		t.Errorf("items = %+v, want the empty ones nil", items)
+	}
+}
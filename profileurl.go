@@ -0,0 +1,78 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidProfileURL is returned by ParseProfileURL when the url isn't a steam community profile url.
+var ErrInvalidProfileURL = errors.New("not a steam profile url")
+
+// ParseProfileURL returns the steamid64 of the profile a url points to, for when users paste their profile link into a
+// form. Both https://steamcommunity.com/profiles/<steamid64> and https://steamcommunity.com/id/<vanity> work, the
+// scheme can be left off, and anything after the id (ex. /games) is ignored. Vanity urls are resolved with
+// ResolveVanityURL, so they cost a web api request.
+func (sa *SteamAuther) ParseProfileURL(ctx context.Context, profileUrl string) (string, error) {
+	kind, id, ok := splitProfileURL(profileUrl)
+	if !ok {
+		return "", fmt.Errorf("parse profile url (%s): %w", profileUrl, ErrInvalidProfileURL)
+	}
+
+	switch kind {
+	case "profiles":
+		if !isIndividualSteamID64(id) {
+			return "", fmt.Errorf("parse profile url (%s): %w: invalid steamid64", profileUrl, ErrInvalidProfileURL)
+		}
+		return id, nil
+	case "id":
+		steamid, err := sa.ResolveVanityURL(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("parse profile url (%s): %w", profileUrl, err)
+		}
+		if !isIndividualSteamID64(steamid) {
+			return "", fmt.Errorf("parse profile url (%s): steam resolved it to an invalid steamid64 (%s)", profileUrl, steamid)
+		}
+		return steamid, nil
+	}
+
+	return "", fmt.Errorf("parse profile url (%s): %w", profileUrl, ErrInvalidProfileURL)
+}
+
+// splitProfileURL splits a steam community profile url into whether it's a "profiles" or "id" url, and the steamid64
+// or vanity name in it.
+func splitProfileURL(profileUrl string) (kind, id string, ok bool) {
+	raw := strings.TrimSpace(profileUrl)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if (u.Scheme != "http" && u.Scheme != "https") || (host != "steamcommunity.com" && host != "www.steamcommunity.com") {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 || parts[1] == "" || (parts[0] != "profiles" && parts[0] != "id") {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+// isIndividualSteamID64 reports whether s is the steamid64 of a user's account in the public universe.
+func isIndividualSteamID64(s string) bool {
+	id, err := ParseSteamID64(s)
+	if err != nil {
+		return false
+	}
+
+	return id.IsValid() && id.IsIndividual() && id.Universe() == UniversePublic && id.Instance() == InstanceDesktop
+}
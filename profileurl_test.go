@@ -0,0 +1,65 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestParseProfileURL(t *testing.T) {
+	fakeSteam(t, vanityUrls)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr error
+	}{
+		{"profiles", "https://steamcommunity.com/profiles/76561197960287930", "76561197960287930", nil},
+		{"no scheme", "steamcommunity.com/profiles/76561197960287930/", "76561197960287930", nil},
+		{"www and a subpage", " http://www.steamcommunity.com/profiles/76561197960287930/games?tab=all ", "76561197960287930", nil},
+		{"vanity", "https://steamcommunity.com/id/gaben", "76561197960287930", nil},
+		{"unknown vanity", "https://steamcommunity.com/id/nobody", "", ErrVanityNotFound},
+		{"group", "https://steamcommunity.com/profiles/103582791429521412", "", ErrInvalidProfileURL},
+		{"not a number", "https://steamcommunity.com/profiles/gaben", "", ErrInvalidProfileURL},
+		{"groups page", "https://steamcommunity.com/groups/steamworks", "", ErrInvalidProfileURL},
+		{"no id", "https://steamcommunity.com/profiles/", "", ErrInvalidProfileURL},
+		{"other site", "https://steamcommunity.com.evil.example/profiles/76561197960287930", "", ErrInvalidProfileURL},
+		{"other scheme", "javascript://steamcommunity.com/profiles/76561197960287930", "", ErrInvalidProfileURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sa.ParseProfileURL(context.Background(), tt.url)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseProfileURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsIndividualSteamID64(t *testing.T) {
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{"76561197960287930", true},
+		{"76561197960265728", false}, // account id 0
+		{"103582791429521412", false},
+		{"76561193665298437", false}, // public individual account, but instance 0
+		{"-76561197960287930", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := isIndividualSteamID64(tt.id); got != tt.want {
+				t.Errorf("isIndividualSteamID64(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
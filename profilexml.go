@@ -0,0 +1,92 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// profileXML is the parts of the community profile xml document we use.
+type profileXML struct {
+	XMLName         xml.Name `xml:"profile"`
+	SteamID64       string   `xml:"steamID64"`
+	SteamID         string   `xml:"steamID"`
+	OnlineState     string   `xml:"onlineState"`
+	PrivacyMessage  string   `xml:"privacyMessage"`
+	VisibilityState int      `xml:"visibilityState"`
+	AvatarIcon      string   `xml:"avatarIcon"`
+	AvatarMedium    string   `xml:"avatarMedium"`
+	AvatarFull      string   `xml:"avatarFull"`
+	CustomURL       string   `xml:"customURL"`
+	MemberSince     string   `xml:"memberSince"`
+}
+
+// GetSteamUserXML gets a user from their community profile's xml, rather than the web api. It doesn't need an api
+// key, and keeps working when the web api is down, but it's missing some things GetSteamUser has: PersonaState is
+// only ever online or offline, CommentPermission is never set, and TimeCreated is only to the day. Returns ErrNoData
+// if the profile doesn't exist.
+func (sa *SteamAuther) GetSteamUserXML(ctx context.Context, steamid64 string) (*SteamUser, error) {
+	u := fmt.Sprintf("%s/profiles/%s/", CommunityBaseUrl, url.PathEscape(steamid64))
+	res, err := sa.get(ctx, u, url.Values{"xml": {"1"}})
+	if err != nil {
+		return nil, fmt.Errorf("get steam user xml (%s): %w", steamid64, err)
+	}
+	defer res.Body.Close()
+
+	// Profiles that don't exist come back as a <response> with an error in it, rather than a 404.
+	var doc profileXML
+	if err := xml.NewDecoder(res.Body).Decode(&doc); err != nil {
+		if _, ok := err.(xml.UnmarshalError); ok {
+			return nil, fmt.Errorf("get steam user xml (%s): %w", steamid64, ErrNoData)
+		}
+		return nil, fmt.Errorf("get steam user xml (%s): decode response body: %w", steamid64, err)
+	}
+
+	return doc.steamUser(), nil
+}
+
+// steamUser maps the profile onto a SteamUser, as close to GetPlayerSummaries as the xml allows.
+func (p *profileXML) steamUser() *SteamUser {
+	user := &SteamUser{
+		SteamID:                   p.SteamID64,
+		PersonaName:               p.SteamID,
+		ProfileUrl:                fmt.Sprintf("%s/profiles/%s/", CommunityBaseUrl, p.SteamID64),
+		ProfileState:              ProfileStateConfigured,
+		CommunityVisibilityStatus: p.VisibilityState,
+		Avatar:                    p.AvatarIcon,
+		AvatarMedium:              p.AvatarMedium,
+		AvatarFull:                p.AvatarFull,
+	}
+
+	if p.CustomURL != "" {
+		user.ProfileUrl = fmt.Sprintf("%s/id/%s/", CommunityBaseUrl, p.CustomURL)
+	}
+
+	// Steam only tells us why there's nothing to show, and an unconfigured profile is one of the reasons.
+	if strings.Contains(p.PrivacyMessage, "not yet set up") {
+		user.ProfileState = ProfileStateNotConfigured
+	}
+
+	// Like the web api, private profiles always look offline.
+	if p.OnlineState == "online" || p.OnlineState == "in-game" {
+		user.PersonaState = PersonaStateOnline
+	}
+
+	// memberSince leaves the year off for this year.
+	for _, layout := range []string{"January 2, 2006", "January 2"} {
+		t, err := time.Parse(layout, p.MemberSince)
+		if err != nil {
+			continue
+		}
+		if layout == "January 2" {
+			t = t.AddDate(time.Now().Year(), 0, 0)
+		}
+		user.TimeCreated = t.Unix()
+		break
+	}
+
+	return user
+}
@@ -0,0 +1,123 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// profileXMLs answers community profile xml like steam would for 76561197960287930, and GetPlayerSummaries with a
+// 503 like it does during an outage.
+func profileXMLs(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/profiles/76561197960287930/":
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<profile>
+	<steamID64>76561197960287930</steamID64>
+	<steamID><![CDATA[Rabscuttle]]></steamID>
+	<onlineState>in-game</onlineState>
+	<privacyState>public</privacyState>
+	<visibilityState>3</visibilityState>
+	<avatarIcon><![CDATA[https://avatars.steamstatic.com/abc.jpg]]></avatarIcon>
+	<avatarMedium><![CDATA[https://avatars.steamstatic.com/abc_medium.jpg]]></avatarMedium>
+	<avatarFull><![CDATA[https://avatars.steamstatic.com/abc_full.jpg]]></avatarFull>
+	<customURL><![CDATA[rabscuttle]]></customURL>
+	<memberSince>September 12, 2003</memberSince>
+</profile>`))
+	case "/profiles/76561197960287931/":
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><response><error><![CDATA[The specified profile could not be found.]]></error></response>`))
+	case "/ISteamUser/GetPlayerSummaries/v0002":
+		http.Error(w, "oh no", http.StatusServiceUnavailable)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestGetSteamUserXML(t *testing.T) {
+	fakeSteam(t, profileXMLs)
+	sa := New("", "https://example.com")
+
+	u, err := sa.GetSteamUserXML(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := SteamUser{
+		SteamID:                   "76561197960287930",
+		PersonaName:               "Rabscuttle",
+		ProfileUrl:                "https://steamcommunity.com/id/rabscuttle/",
+		ProfileState:              ProfileStateConfigured,
+		CommunityVisibilityStatus: 3,
+		PersonaState:              PersonaStateOnline,
+		Avatar:                    "https://avatars.steamstatic.com/abc.jpg",
+		AvatarMedium:              "https://avatars.steamstatic.com/abc_medium.jpg",
+		AvatarFull:                "https://avatars.steamstatic.com/abc_full.jpg",
+		TimeCreated:               time.Date(2003, time.September, 12, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	if *u != want {
+		t.Errorf("user = %+v, want %+v", u, want)
+	}
+
+	if _, err := sa.GetSteamUserXML(context.Background(), "76561197960287931"); !errors.Is(err, ErrNoData) {
+		t.Errorf("missing profile err = %v, want ErrNoData", err)
+	}
+}
+
+func TestProfileXMLSteamUser(t *testing.T) {
+	tests := []struct {
+		name         string
+		profile      profileXML
+		wantUrl      string
+		wantState    int
+		wantPersona  int
+		wantThisYear bool
+	}{
+		{"no custom url", profileXML{SteamID64: "76561197960287930", OnlineState: "offline"}, "https://steamcommunity.com/profiles/76561197960287930/", ProfileStateConfigured, PersonaStateOffline, false},
+		{"not set up", profileXML{SteamID64: "76561197960287930", PrivacyMessage: "This user has not yet set up their Steam Community profile."}, "https://steamcommunity.com/profiles/76561197960287930/", ProfileStateNotConfigured, PersonaStateOffline, false},
+		{"online this year", profileXML{SteamID64: "76561197960287930", OnlineState: "online", MemberSince: "March 3"}, "https://steamcommunity.com/profiles/76561197960287930/", ProfileStateConfigured, PersonaStateOnline, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := tt.profile.steamUser()
+			if u.ProfileUrl != tt.wantUrl || u.ProfileState != tt.wantState || u.PersonaState != tt.wantPersona {
+				t.Errorf("user = %+v, want url %q, profile state %d and persona state %d", u, tt.wantUrl, tt.wantState, tt.wantPersona)
+			}
+			if tt.wantThisYear && time.Unix(u.TimeCreated, 0).UTC().Year() != time.Now().Year() {
+				t.Errorf("created %v, want this year", time.Unix(u.TimeCreated, 0))
+			}
+		})
+	}
+}
+
+func TestGetSteamUserXMLFallback(t *testing.T) {
+	fakeSteam(t, profileXMLs)
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		fallback bool
+		wantErr  error
+	}{
+		{"no api key", "", false, nil},
+		{"web api down", "key", true, nil},
+		{"web api down, no fallback", "key", false, ErrSteamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sa := New(tt.apiKey, "https://example.com")
+			sa.XMLFallback = tt.fallback
+
+			u, err := sa.GetSteamUser("76561197960287930")
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && u.PersonaName != "Rabscuttle" {
+				t.Errorf("user = %+v, want the one from the xml", u)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package provider
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+// supportsOIDC reports whether ID tokens can be issued. Relying parties verify them with the public key from /jwks,
+// so it takes an RS256 issuer: an HS256 secret would have to be shared with every client, and custom signers don't
+// make JWTs.
+func (p *Provider) supportsOIDC() bool {
+	return p.issuer.Algorithm() == gosteamauth.AlgRS256
+}
+
+// Discovery serves the OpenID Connect discovery document, so relying parties only need to be given baseUrl. Without
+// an RS256 issuer (see supportsOIDC) it only describes the OAuth2 side.
+func (p *Provider) Discovery(w http.ResponseWriter, r *http.Request) {
+	doc := map[string]any{
+		"issuer":                                p.baseUrl,
+		"authorization_endpoint":                p.baseUrl + "/authorize",
+		"token_endpoint":                        p.baseUrl + "/token",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code"},
+		"scopes_supported":                      []string{"profile"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"S256", "plain"},
+	}
+	if p.supportsOIDC() {
+		doc["subject_types_supported"] = []string{"public"}
+		doc["id_token_signing_alg_values_supported"] = []string{p.issuer.Algorithm()}
+		doc["scopes_supported"] = []string{"openid", "profile"}
+		doc["claims_supported"] = []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "name", "preferred_username", "picture", "profile"}
+	}
+	if p.Refresh != nil {
+		doc["grant_types_supported"] = []string{"authorization_code", "refresh_token"}
+	}
+	if p.Verifier != nil {
+		doc["userinfo_endpoint"] = p.baseUrl + "/userinfo"
+	}
+	if p.JWKS != nil {
+		doc["jwks_uri"] = p.baseUrl + "/jwks"
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	writeToken(w, doc)
+}
+
+// UserInfo serves the OpenID Connect userinfo endpoint. The user's profile is looked up from steam on every call,
+// so it's always current, unlike the copy in the ID token.
+func (p *Provider) UserInfo(w http.ResponseWriter, r *http.Request) {
+	p.Verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		steamid, _ := gosteamauth.SteamIDFromContext(r.Context())
+
+		u, err := p.auther.GetSteamUser(steamid)
+		if errors.Is(err, gosteamauth.ErrNoData) {
+			// Nothing to add, but sub on its own is still a valid response.
+			writeToken(w, map[string]any{"sub": steamid})
+			return
+		}
+		if err != nil {
+			http.Error(w, "couldn't get the user's steam profile", http.StatusBadGateway)
+			return
+		}
+
+		writeToken(w, UserClaims(u))
+	})).ServeHTTP(w, r)
+}
+
+// UserClaims maps a steam user onto the standard OpenID Connect claims.
+func UserClaims(u *gosteamauth.SteamUser) map[string]any {
+	return map[string]any{
+		"sub":                u.SteamID,
+		"name":               u.PersonaName,
+		"preferred_username": u.PersonaName,
+		"picture":            u.AvatarFull,
+		"profile":            u.ProfileUrl,
+	}
+}
+
+// idToken mints the ID token for an exchanged authorization code. It's typed as an ID token, so the Verifier won't
+// accept it at /userinfo.
+func (p *Provider) idToken(code authorizationCode) (string, error) {
+	now := time.Now()
+
+	c := &gosteamauth.Claims{
+		Subject:   code.SteamID,
+		Issuer:    p.baseUrl,
+		Audience:  code.ClientID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(p.issuer.TTL).Unix(),
+		Extra:     map[string]any{"auth_time": code.AuthTime},
+	}
+	if code.Nonce != "" {
+		c.Extra["nonce"] = code.Nonce
+	}
+
+	if code.User != nil && hasScope(code.Scope, "profile") {
+		c.PersonaName = code.User.PersonaName
+		c.Avatar = code.User.AvatarFull
+		c.Extra["preferred_username"] = code.User.PersonaName
+		c.Extra["profile"] = code.User.ProfileUrl
+	}
+
+	return p.issuer.SignIDToken(c)
+}
+
+// hasScope reports whether the space separated scope list contains want.
+func hasScope(scope, want string) bool {
+	return slices.Contains(strings.Fields(scope), want)
+}
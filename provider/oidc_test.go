@@ -0,0 +1,172 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+// newOIDCProvider returns a provider signing with RS256, along with a verifier for its tokens.
+func newOIDCProvider(t *testing.T) (*Provider, *gosteamauth.TokenVerifier) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := gosteamauth.NewMemoryStore()
+	t.Cleanup(store.Close)
+
+	p := New(gosteamauth.New("", "https://auth.example.com"), gosteamauth.NewRS256Issuer(key), store, "https://auth.example.com/oauth",
+		Client{ID: "backend", Secret: "hunter2", RedirectUris: []string{redirectUri}},
+	)
+	p.Verifier = gosteamauth.NewRS256Verifier(&key.PublicKey)
+	p.JWKS = gosteamauth.NewJWKS()
+	p.JWKS.AddKey("", &key.PublicKey)
+
+	return p, gosteamauth.NewRS256Verifier(&key.PublicKey)
+}
+
+func discovery(t *testing.T, p *Provider) map[string]any {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestDiscovery(t *testing.T) {
+	hs := newProvider(t)
+	rs, _ := newOIDCProvider(t)
+
+	tests := []struct {
+		name     string
+		p        *Provider
+		wantOIDC bool
+	}{
+		{"HS256", hs, false},
+		{"RS256", rs, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := discovery(t, tt.p)
+
+			if doc["token_endpoint"] != "https://auth.example.com/oauth/token" {
+				t.Errorf("token_endpoint = %v", doc["token_endpoint"])
+			}
+
+			_, hasAlgs := doc["id_token_signing_alg_values_supported"]
+			if hasAlgs != tt.wantOIDC {
+				t.Errorf("id_token_signing_alg_values_supported present = %v, want %v", hasAlgs, tt.wantOIDC)
+			}
+
+			scopes, _ := json.Marshal(doc["scopes_supported"])
+			if strings.Contains(string(scopes), "openid") != tt.wantOIDC {
+				t.Errorf("scopes_supported = %s, want openid only when ID tokens can be issued", scopes)
+			}
+		})
+	}
+}
+
+func TestAuthorizeOpenIDNeedsRS256(t *testing.T) {
+	p := newProvider(t)
+
+	q := url.Values{
+		"client_id":     {"backend"},
+		"redirect_uri":  {redirectUri},
+		"response_type": {"code"},
+		"scope":         {"openid profile"},
+	}
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/authorize?"+q.Encode(), nil))
+
+	loc, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loc.Query().Get("error"); got != "invalid_scope" {
+		t.Errorf("error = %q, want invalid_scope", got)
+	}
+}
+
+func TestIDToken(t *testing.T) {
+	p, tv := newOIDCProvider(t)
+
+	code, err := p.saveJSON(context.Background(), "oauth-code:", authorizationCode{
+		authorizeRequest: authorizeRequest{
+			ClientID:    "backend",
+			RedirectUri: redirectUri,
+			Scope:       "openid profile",
+			Nonce:       "n-0S6_WzA2Mj",
+		},
+		SteamID: steamid,
+		User:    &gosteamauth.SteamUser{SteamID: steamid, PersonaName: "Rabscuttle"},
+	}, p.CodeTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := exchangeForm("backend", code, "")
+	form.Set("client_secret", "hunter2")
+	rec, body := token(p, form)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (%v), want 200", rec.Code, body)
+	}
+
+	idToken, _ := body["id_token"].(string)
+	if idToken == "" {
+		t.Fatalf("body = %v, want an id_token", body)
+	}
+
+	// ID tokens aren't access tokens, so the verifier used for the api and /userinfo has to refuse them.
+	if _, err := tv.Verify(idToken); !errors.Is(err, gosteamauth.ErrInvalidToken) {
+		t.Errorf("Verify(id token) err = %v, want ErrInvalidToken", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/userinfo", nil)
+	req.Header.Set("Authorization", "Bearer "+idToken)
+	rec = httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("/userinfo with an id token status = %d, want 401", rec.Code)
+	}
+
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		t.Fatalf("id token has %d parts, want 3", len(parts))
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c gosteamauth.Claims
+	if err := json.Unmarshal(payload, &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Subject != steamid || c.Audience != "backend" || c.Issuer != "https://auth.example.com/oauth" {
+		t.Errorf("claims = %+v, want sub, aud and iss set for the client", c)
+	}
+	if c.Extra["nonce"] != "n-0S6_WzA2Mj" || c.PersonaName != "Rabscuttle" {
+		t.Errorf("claims = %+v, want the nonce and profile", c)
+	}
+}
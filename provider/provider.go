@@ -0,0 +1,455 @@
+// Package provider is an OAuth2 authorization server (and OpenID Connect provider) that logs users in with steam, so
+// existing OAuth2/OIDC client libraries can "log in with steam" without any steam specific code.
+//
+// Only the authorization code grant (with optional PKCE) and refresh token grant are supported. The access tokens
+// are minted by a gosteamauth.TokenIssuer, with the steamid64 as the subject. Requests with the "openid" scope also
+// get an ID token (when the issuer signs with RS256), see oidc.go.
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+const (
+	// DefaultCodeTTL is how long an authorization code can be exchanged for, per the RFC's recommendation.
+	DefaultCodeTTL = time.Minute
+	// DefaultAuthorizeTTL is how long the user has to log in with steam after hitting /authorize.
+	DefaultAuthorizeTTL = 10 * time.Minute
+)
+
+// Client is an application allowed to use the provider.
+type Client struct {
+	ID string
+	// Secret authenticates confidential clients at the token endpoint. Leave it empty for public clients (SPAs,
+	// mobile apps), which then have to use PKCE instead.
+	Secret string
+	// RedirectUris are the only redirect_uri values accepted from this client. They're compared exactly.
+	RedirectUris []string
+}
+
+// Provider serves the /authorize, /callback and /token endpoints.
+type Provider struct {
+	auther  *gosteamauth.SteamAuther
+	issuer  *gosteamauth.TokenIssuer
+	store   gosteamauth.Store
+	baseUrl string
+	clients map[string]Client
+
+	// Refresh, if set, is used to hand out refresh tokens and enables the refresh_token grant.
+	Refresh *gosteamauth.RefreshTokens
+	// FetchUser makes the provider look up the user's profile after login, so their name and avatar end up in the
+	// access token. This costs one web api request per login.
+	FetchUser bool
+	// CodeTTL is how long authorization codes are valid for. Defaults to DefaultCodeTTL.
+	CodeTTL time.Duration
+	// AuthorizeTTL is how long a user has to complete the steam login. Defaults to DefaultAuthorizeTTL.
+	AuthorizeTTL time.Duration
+
+	// Verifier checks the access tokens sent to the userinfo endpoint. The endpoint isn't served without it.
+	Verifier *gosteamauth.TokenVerifier
+	// JWKS, if set, is served at /jwks and advertised in the discovery document. OIDC relying parties need this to
+	// verify RS256 ID tokens.
+	JWKS *gosteamauth.JWKS
+}
+
+// New returns a new Provider. baseUrl is the public URL the provider's Handler is mounted at
+// (ex. https://auth.example.com/oauth), and is used to build the url steam sends users back to.
+// State is kept in store, so every instance of the provider needs to share it.
+func New(auther *gosteamauth.SteamAuther, issuer *gosteamauth.TokenIssuer, store gosteamauth.Store, baseUrl string, clients ...Client) *Provider {
+	p := &Provider{
+		auther:       auther,
+		issuer:       issuer,
+		store:        store,
+		baseUrl:      baseUrl,
+		clients:      make(map[string]Client, len(clients)),
+		CodeTTL:      DefaultCodeTTL,
+		AuthorizeTTL: DefaultAuthorizeTTL,
+	}
+	for _, c := range clients {
+		p.clients[c.ID] = c
+	}
+
+	return p
+}
+
+// Handler returns an http.Handler serving all of the provider's endpoints. Mount it at baseUrl's path, with the
+// prefix stripped (ex. mux.Handle("/oauth/", http.StripPrefix("/oauth", p.Handler()))).
+func (p *Provider) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /authorize", p.Authorize)
+	mux.HandleFunc("GET /callback", p.Callback)
+	mux.HandleFunc("POST /token", p.Token)
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.Discovery)
+	if p.Verifier != nil {
+		mux.HandleFunc("GET /userinfo", p.UserInfo)
+		mux.HandleFunc("POST /userinfo", p.UserInfo)
+	}
+	if p.JWKS != nil {
+		mux.Handle("GET /jwks", p.JWKS)
+	}
+
+	return mux
+}
+
+// authorizeRequest is a validated /authorize request, kept in the store while the user logs in with steam.
+type authorizeRequest struct {
+	ClientID            string `json:"client_id"`
+	RedirectUri         string `json:"redirect_uri"`
+	State               string `json:"state,omitempty"`
+	Scope               string `json:"scope,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+}
+
+// authorizationCode is what's kept in the store for each issued code.
+type authorizationCode struct {
+	authorizeRequest
+	SteamID  string                 `json:"steamid"`
+	User     *gosteamauth.SteamUser `json:"user,omitempty"`
+	AuthTime int64                  `json:"auth_time"`
+}
+
+// Authorize handles the authorization endpoint. It checks the client's request and sends the user off to steam.
+func (p *Provider) Authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// Until the client and redirect_uri are known to be good, errors can't be redirected anywhere.
+	c, ok := p.clients[q.Get("client_id")]
+	if !ok {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+
+	redirectUri := q.Get("redirect_uri")
+	if !slices.Contains(c.RedirectUris, redirectUri) {
+		http.Error(w, "redirect_uri is not registered for this client", http.StatusBadRequest)
+		return
+	}
+
+	req := authorizeRequest{
+		ClientID:            c.ID,
+		RedirectUri:         redirectUri,
+		State:               q.Get("state"),
+		Scope:               q.Get("scope"),
+		CodeChallenge:       q.Get("code_challenge"),
+		CodeChallengeMethod: q.Get("code_challenge_method"),
+		Nonce:               q.Get("nonce"),
+	}
+
+	if q.Get("response_type") != "code" {
+		redirectError(w, r, req, "unsupported_response_type", "only the code response type is supported")
+		return
+	}
+
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != "S256" && req.CodeChallengeMethod != "plain" && req.CodeChallengeMethod != "" {
+		redirectError(w, r, req, "invalid_request", "unsupported code_challenge_method")
+		return
+	}
+
+	if hasScope(req.Scope, "openid") && !p.supportsOIDC() {
+		redirectError(w, r, req, "invalid_scope", "openid needs the provider to sign with RS256")
+		return
+	}
+
+	if c.Secret == "" && req.CodeChallenge == "" {
+		redirectError(w, r, req, "invalid_request", "public clients must use PKCE")
+		return
+	}
+
+	id, err := p.saveJSON(r.Context(), "oauth-authorize:", req, p.AuthorizeTTL)
+	if err != nil {
+		redirectError(w, r, req, "server_error", "")
+		return
+	}
+
+	u, err := p.auther.GetAuthUrl(p.baseUrl + "/callback?state=" + url.QueryEscape(id))
+	if err != nil {
+		redirectError(w, r, req, "server_error", "")
+		return
+	}
+
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+// Callback handles steam sending the user back. It validates the login and sends the user back to the client with
+// an authorization code.
+func (p *Provider) Callback(w http.ResponseWriter, r *http.Request) {
+	// The state is read from the return url steam signed, so it has to be validated first.
+	steamid, returnTo, err := p.auther.ValidateCallbackFor(r.Context(), r.URL.Query(), p.baseUrl+"/callback")
+	if err != nil {
+		http.Error(w, "steam login could not be validated", http.StatusUnauthorized)
+		return
+	}
+
+	var req authorizeRequest
+	if err := p.takeJSON(r.Context(), "oauth-authorize:"+returnTo.Get("state"), &req); err != nil {
+		http.Error(w, "unknown or expired login attempt, please try again", http.StatusBadRequest)
+		return
+	}
+
+	code := authorizationCode{authorizeRequest: req, SteamID: steamid, AuthTime: time.Now().Unix()}
+	if p.FetchUser {
+		code.User, err = p.auther.GetSteamUser(steamid)
+		if err != nil {
+			redirectError(w, r, req, "temporarily_unavailable", "couldn't get the user's steam profile")
+			return
+		}
+	}
+
+	c, err := p.saveJSON(r.Context(), "oauth-code:", code, p.CodeTTL)
+	if err != nil {
+		redirectError(w, r, req, "server_error", "")
+		return
+	}
+
+	redirectWith(w, r, req, url.Values{"code": {c}})
+}
+
+// Token handles the token endpoint.
+func (p *Provider) Token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_request", "couldn't parse form")
+		return
+	}
+
+	c, ok := p.authenticateClient(r)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Basic realm="token"`)
+		tokenError(w, http.StatusUnauthorized, "invalid_client", "")
+		return
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		p.exchangeCode(w, r, c)
+	case "refresh_token":
+		if p.Refresh == nil {
+			tokenError(w, http.StatusBadRequest, "unsupported_grant_type", "")
+			return
+		}
+
+		pair, err := p.Refresh.RefreshForClient(r.Context(), r.PostForm.Get("refresh_token"), c.ID)
+		if err != nil {
+			tokenError(w, http.StatusBadRequest, "invalid_grant", "")
+			return
+		}
+		writeToken(w, pair)
+	default:
+		tokenError(w, http.StatusBadRequest, "unsupported_grant_type", "")
+	}
+}
+
+// exchangeCode handles the authorization_code grant.
+func (p *Provider) exchangeCode(w http.ResponseWriter, r *http.Request, c Client) {
+	key := "oauth-code:" + r.PostForm.Get("code")
+
+	// The code is only looked at until the request is known to be from the client it was issued to, so someone else
+	// sending it (ex. with a wrong verifier) can't burn it before the real client gets to exchange it.
+	var code authorizationCode
+	if err := p.getJSON(r.Context(), key, &code); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "")
+		return
+	}
+
+	if code.ClientID != c.ID || code.RedirectUri != r.PostForm.Get("redirect_uri") {
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "")
+		return
+	}
+
+	if !verifyPKCE(code.CodeChallenge, code.CodeChallengeMethod, r.PostForm.Get("code_verifier")) {
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "code_verifier doesn't match")
+		return
+	}
+
+	// Codes are single use, so only whoever takes it out of the store first gets tokens for it.
+	if err := p.takeJSON(r.Context(), key, &code); err != nil {
+		tokenError(w, http.StatusBadRequest, "invalid_grant", "")
+		return
+	}
+
+	pair, err := p.issue(r.Context(), code)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	if !hasScope(code.Scope, "openid") {
+		writeToken(w, pair)
+		return
+	}
+
+	idToken, err := p.idToken(code)
+	if err != nil {
+		tokenError(w, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	writeToken(w, struct {
+		*gosteamauth.TokenPair
+		IDToken string `json:"id_token"`
+	}{pair, idToken})
+}
+
+// issue mints the tokens handed out for an exchanged authorization code.
+func (p *Provider) issue(ctx context.Context, code authorizationCode) (*gosteamauth.TokenPair, error) {
+	if p.Refresh != nil {
+		return p.Refresh.IssueForClient(ctx, code.SteamID, code.User, code.ClientID)
+	}
+
+	access, err := p.issuer.Issue(code.SteamID, code.User)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gosteamauth.TokenPair{
+		AccessToken: access,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(p.issuer.TTL.Seconds()),
+	}, nil
+}
+
+// authenticateClient figures out which client is calling the token endpoint, using either HTTP basic auth or the
+// client_id/client_secret form values.
+func (p *Provider) authenticateClient(r *http.Request) (Client, bool) {
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	c, ok := p.clients[id]
+	if !ok {
+		return Client{}, false
+	}
+
+	if c.Secret == "" {
+		return c, true
+	}
+
+	return c, subtle.ConstantTimeCompare([]byte(secret), []byte(c.Secret)) == 1
+}
+
+// verifyPKCE checks verifier against the challenge from the authorization request (RFC 7636).
+func verifyPKCE(challenge, method, verifier string) bool {
+	if challenge == "" {
+		return true
+	}
+
+	if method == "S256" {
+		sum := sha256.Sum256([]byte(verifier))
+		verifier = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+
+	return subtle.ConstantTimeCompare([]byte(challenge), []byte(verifier)) == 1
+}
+
+// saveJSON stores v under prefix+<random id> and returns the id.
+func (p *Provider) saveJSON(ctx context.Context, prefix string, v any, ttl time.Duration) (string, error) {
+	id, err := randomID()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	if err := p.store.Set(ctx, prefix+id, b, ttl); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// getJSON decodes key from the store into v, leaving it there.
+func (p *Provider) getJSON(ctx context.Context, key string, v any) error {
+	b, err := p.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// takeJSON takes key out of the store and decodes it into v.
+func (p *Provider) takeJSON(ctx context.Context, key string, v any) error {
+	b, err := p.store.Take(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// randomID returns a random, url safe identifier.
+func randomID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate id: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// redirectError sends the user back to the client with an error, as described in RFC 6749 section 4.1.2.1.
+func redirectError(w http.ResponseWriter, r *http.Request, req authorizeRequest, code, desc string) {
+	v := url.Values{"error": {code}}
+	if desc != "" {
+		v.Set("error_description", desc)
+	}
+
+	redirectWith(w, r, req, v)
+}
+
+// redirectWith sends the user back to the client's redirect_uri with v (and the client's state) added.
+func redirectWith(w http.ResponseWriter, r *http.Request, req authorizeRequest, v url.Values) {
+	u, err := url.Parse(req.RedirectUri)
+	if err != nil {
+		http.Error(w, "bad redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	q := u.Query()
+	for k := range v {
+		q.Set(k, v.Get(k))
+	}
+	if req.State != "" {
+		q.Set("state", req.State)
+	}
+	u.RawQuery = q.Encode()
+
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// writeToken writes a successful token response.
+func writeToken(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(v)
+}
+
+// tokenError writes an error response from the token endpoint, as described in RFC 6749 section 5.2.
+func tokenError(w http.ResponseWriter, status int, code, desc string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	body := map[string]string{"error": code}
+	if desc != "" {
+		body["error_description"] = desc
+	}
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	gosteamauth "github.com/liondadev/go-steam-auth"
+)
+
+const (
+	steamid     = "76561197960287930"
+	redirectUri = "https://app.example.com/cb"
+	verifier    = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r0wW1gFWFOEjXk"
+)
+
+func newProvider(t *testing.T) *Provider {
+	t.Helper()
+
+	store := gosteamauth.NewMemoryStore()
+	t.Cleanup(store.Close)
+
+	issuer := gosteamauth.NewHS256Issuer([]byte("0123456789abcdef0123456789abcdef"))
+	p := New(gosteamauth.New("", "https://auth.example.com"), issuer, store, "https://auth.example.com/oauth",
+		Client{ID: "spa", RedirectUris: []string{redirectUri}},
+		Client{ID: "backend", Secret: "hunter2", RedirectUris: []string{redirectUri}},
+	)
+	p.Refresh = gosteamauth.NewRefreshTokens(store, issuer)
+	return p
+}
+
+// seedCode stores an authorization code for clientId, as Callback would after a steam login.
+func seedCode(t *testing.T, p *Provider, clientId string) string {
+	t.Helper()
+
+	sum := sha256.Sum256([]byte(verifier))
+	code, err := p.saveJSON(context.Background(), "oauth-code:", authorizationCode{
+		authorizeRequest: authorizeRequest{
+			ClientID:            clientId,
+			RedirectUri:         redirectUri,
+			CodeChallenge:       base64.RawURLEncoding.EncodeToString(sum[:]),
+			CodeChallengeMethod: "S256",
+		},
+		SteamID: steamid,
+	}, p.CodeTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func token(p *Provider, form url.Values) (*httptest.ResponseRecorder, map[string]any) {
+	req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	p.Handler().ServeHTTP(rec, req)
+
+	var body map[string]any
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	return rec, body
+}
+
+func exchangeForm(clientId, code, codeVerifier string) url.Values {
+	return url.Values{
+		"grant_type":    {"authorization_code"},
+		"client_id":     {clientId},
+		"code":          {code},
+		"redirect_uri":  {redirectUri},
+		"code_verifier": {codeVerifier},
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	p := newProvider(t)
+
+	tests := []struct {
+		name      string
+		query     url.Values
+		want      int
+		wantError string
+	}{
+		{"unknown client", url.Values{"client_id": {"nope"}, "redirect_uri": {redirectUri}}, http.StatusBadRequest, ""},
+		{"unregistered redirect", url.Values{"client_id": {"spa"}, "redirect_uri": {"https://evil.example.com"}}, http.StatusBadRequest, ""},
+		{"wrong response type", url.Values{"client_id": {"spa"}, "redirect_uri": {redirectUri}, "response_type": {"token"}}, http.StatusFound, "unsupported_response_type"},
+		{"public client without pkce", url.Values{"client_id": {"spa"}, "redirect_uri": {redirectUri}, "response_type": {"code"}}, http.StatusFound, "invalid_request"},
+		{"public client with pkce", url.Values{"client_id": {"spa"}, "redirect_uri": {redirectUri}, "response_type": {"code"}, "code_challenge": {"abc"}, "code_challenge_method": {"S256"}}, http.StatusFound, ""},
+		{"confidential client", url.Values{"client_id": {"backend"}, "redirect_uri": {redirectUri}, "response_type": {"code"}}, http.StatusFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			p.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/authorize?"+tt.query.Encode(), nil))
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if rec.Code != http.StatusFound {
+				return
+			}
+
+			loc, err := url.Parse(rec.Header().Get("Location"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantError != "" {
+				if got := loc.Query().Get("error"); got != tt.wantError {
+					t.Errorf("error = %q, want %q", got, tt.wantError)
+				}
+				return
+			}
+			if loc.Host != "steamcommunity.com" {
+				t.Errorf("redirected to %q, want steam", loc)
+			}
+		})
+	}
+}
+
+func TestExchangeCode(t *testing.T) {
+	p := newProvider(t)
+	code := seedCode(t, p, "spa")
+
+	rec, body := token(p, exchangeForm("spa", code, verifier))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (%v), want 200", rec.Code, body)
+	}
+	if body["access_token"] == "" || body["refresh_token"] == "" {
+		t.Errorf("body = %v, want an access and refresh token", body)
+	}
+
+	// codes are single use
+	if rec, _ := token(p, exchangeForm("spa", code, verifier)); rec.Code != http.StatusBadRequest {
+		t.Errorf("second exchange status = %d, want 400", rec.Code)
+	}
+
+	// the refresh token is bound to the client it was issued to
+	refresh := url.Values{"grant_type": {"refresh_token"}, "client_id": {"backend"}, "client_secret": {"hunter2"}, "refresh_token": {body["refresh_token"].(string)}}
+	if rec, _ := token(p, refresh); rec.Code != http.StatusBadRequest {
+		t.Errorf("other client's refresh status = %d, want 400", rec.Code)
+	}
+}
+
+func TestExchangeCodeRejectedAttemptsDontBurnIt(t *testing.T) {
+	p := newProvider(t)
+	code := seedCode(t, p, "spa")
+
+	tests := []struct {
+		name string
+		form url.Values
+		want int
+	}{
+		{"wrong verifier", exchangeForm("spa", code, "not-the-verifier"), http.StatusBadRequest},
+		{"other client", func() url.Values {
+			f := exchangeForm("backend", code, verifier)
+			f.Set("client_secret", "hunter2")
+			return f
+		}(), http.StatusBadRequest},
+		{"wrong redirect_uri", func() url.Values {
+			f := exchangeForm("spa", code, verifier)
+			f.Set("redirect_uri", "https://app.example.com/other")
+			return f
+		}(), http.StatusBadRequest},
+		{"bad client secret", func() url.Values {
+			f := exchangeForm("backend", code, verifier)
+			f.Set("client_secret", "wrong")
+			return f
+		}(), http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, body := token(p, tt.form)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d (%v), want %d", rec.Code, body, tt.want)
+			}
+		})
+	}
+
+	if rec, body := token(p, exchangeForm("spa", code, verifier)); rec.Code != http.StatusOK {
+		t.Errorf("real client's exchange status = %d (%v), want 200", rec.Code, body)
+	}
+}
@@ -0,0 +1,121 @@
+package gosteamauth
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket per key (usually a client IP). Each key can make burst requests straight away, and
+// then gets another one every interval.
+type RateLimiter struct {
+	interval time.Duration
+	burst    float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a new RateLimiter allowing perMinute requests a minute per key, with bursts of up to burst.
+// Idle keys are forgotten in the background until Close is called.
+func NewRateLimiter(perMinute, burst int) *RateLimiter {
+	rl := &RateLimiter{
+		interval: time.Minute / time.Duration(max(perMinute, 1)),
+		burst:    float64(max(burst, 1)),
+		buckets:  make(map[string]*bucket),
+		stop:     make(chan struct{}),
+	}
+	go rl.janitor()
+
+	return rl
+}
+
+// janitor forgets buckets that have filled back up, since they're no different to a key that was never seen.
+func (rl *RateLimiter) janitor() {
+	tick := time.NewTicker(time.Minute)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			now := time.Now()
+			rl.mu.Lock()
+			for k, b := range rl.buckets {
+				if rl.refill(b, now) >= rl.burst {
+					delete(rl.buckets, k)
+				}
+			}
+			rl.mu.Unlock()
+		case <-rl.stop:
+			return
+		}
+	}
+}
+
+// Close stops forgetting idle keys in the background.
+func (rl *RateLimiter) Close() {
+	rl.stopOnce.Do(func() { close(rl.stop) })
+}
+
+// Allow takes a token from key's bucket. If there isn't one, it returns false and how long until there will be.
+func (rl *RateLimiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &bucket{tokens: rl.burst, last: now}
+		rl.buckets[key] = b
+	}
+
+	if rl.refill(b, now) < 1 {
+		return false, time.Duration((1 - b.tokens) * float64(rl.interval))
+	}
+
+	b.tokens--
+	return true, 0
+}
+
+// refill tops up b with the tokens earned since it was last touched, and returns how many it has.
+func (rl *RateLimiter) refill(b *bucket, now time.Time) float64 {
+	b.tokens = math.Min(rl.burst, b.tokens+float64(now.Sub(b.last))/float64(rl.interval))
+	b.last = now
+	return b.tokens
+}
+
+// Middleware responds with a 429 to requests from clients that are over the limit, keyed by resolver's idea of the
+// client IP. resolver may be nil, in which case the connection's address is used.
+func (rl *RateLimiter) Middleware(resolver *ClientIPResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, wait := rl.Allow(resolver.ClientIP(r))
+		if !ok {
+			tooManyRequests(w, wait)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tooManyRequests writes a 429 telling the client how long to wait.
+func tooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", retryAfter(wait))
+	http.Error(w, "too many requests, slow down", http.StatusTooManyRequests)
+}
+
+// retryAfter formats wait for the Retry-After header, in whole seconds.
+func retryAfter(wait time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(wait.Seconds())))
+}
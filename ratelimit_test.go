@@ -0,0 +1,92 @@
+package gosteamauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	rl := NewRateLimiter(1, 2)
+	defer rl.Close()
+
+	for i := range 2 {
+		if ok, _ := rl.Allow("a"); !ok {
+			t.Fatalf("request %d within the burst was refused", i+1)
+		}
+	}
+
+	ok, wait := rl.Allow("a")
+	if ok {
+		t.Fatal("request over the burst was allowed")
+	}
+	if wait <= 0 || wait > time.Minute {
+		t.Errorf("wait = %v, want up to a minute", wait)
+	}
+
+	if ok, _ := rl.Allow("b"); !ok {
+		t.Error("another key was limited by a's requests")
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	rl := NewRateLimiter(60, 1)
+	defer rl.Close()
+
+	rl.Allow("a")
+	if ok, _ := rl.Allow("a"); ok {
+		t.Fatal("second request was allowed straight away")
+	}
+
+	// pretend a second went by, which earns one token at 60 a minute
+	rl.mu.Lock()
+	rl.buckets["a"].last = rl.buckets["a"].last.Add(-time.Second)
+	rl.mu.Unlock()
+
+	if ok, _ := rl.Allow("a"); !ok {
+		t.Error("request after the refill was refused")
+	}
+}
+
+func TestHandlerRateLimit(t *testing.T) {
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.RateLimit = NewRateLimiter(1, 1)
+	defer h.RateLimit.Close()
+
+	tests := []struct {
+		name  string
+		serve func(w http.ResponseWriter, r *http.Request)
+	}{
+		{"Login", h.Login},
+		{"Callback", h.Callback},
+		{"ButtonHandler", h.ButtonHandler().ServeHTTP},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a fresh client for each handler, all of them share the limiter
+			remote := netip.AddrFrom4([4]byte{203, 0, 113, byte(i + 1)}).String() + ":1234"
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = remote
+			rec := httptest.NewRecorder()
+			tt.serve(rec, req)
+			if rec.Code == http.StatusTooManyRequests {
+				t.Fatal("first request was rate limited")
+			}
+
+			req = httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = remote
+			rec = httptest.NewRecorder()
+			tt.serve(rec, req)
+			if rec.Code != http.StatusTooManyRequests {
+				t.Fatalf("second request status = %d, want 429", rec.Code)
+			}
+			if rec.Header().Get("Retry-After") == "" {
+				t.Error("429 without a Retry-After")
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package gosteamauth
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrInvalidRealm is returned when a realm isn't a valid openid 2 realm.
+var ErrInvalidRealm = errors.New("invalid openid realm")
+
+// ErrReturnUrlOutsideRealm is returned by GetAuthUrl when the return url isn't covered by the realm, which steam
+// would refuse anyway, and wrapped by ValidateCallback when a callback's openid.return_to isn't.
+var ErrReturnUrlOutsideRealm = errors.New("return url is outside of the realm")
+
+// ValidateRealm checks realm is a valid openid 2 realm. A realm is an http(s) url without a fragment, and its host may
+// start with a "*." wildcard (ex. https://*.example.com) to cover every subdomain, for apps running a subdomain per
+// community.
+func ValidateRealm(realm string) error {
+	u, err := url.Parse(realm)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRealm, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be http or https", ErrInvalidRealm)
+	}
+	if u.Fragment != "" {
+		return fmt.Errorf("%w: can't have a fragment", ErrInvalidRealm)
+	}
+
+	host := u.Hostname()
+	if rest, ok := strings.CutPrefix(host, "*."); ok {
+		host = rest
+	}
+	if host == "" || strings.Contains(host, "*") {
+		return fmt.Errorf("%w: a wildcard can only be the first part of the host", ErrInvalidRealm)
+	}
+
+	return nil
+}
+
+// RealmMatches reports whether returnUrl is covered by realm, following the openid 2 rules: the scheme and port have
+// to be the same, the host has to be the same (or a subdomain, for a wildcard realm), and the path has to be the same
+// as the realm's or under it.
+func RealmMatches(realm, returnUrl string) bool {
+	if ValidateRealm(realm) != nil {
+		return false
+	}
+
+	ru, _ := url.Parse(realm)
+	u, err := url.Parse(returnUrl)
+	if err != nil || u.Fragment != "" {
+		return false
+	}
+
+	if !strings.EqualFold(ru.Scheme, u.Scheme) || portOf(ru) != portOf(u) {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	realmHost := strings.ToLower(ru.Hostname())
+	if rest, ok := strings.CutPrefix(realmHost, "*."); ok {
+		if host != rest && !strings.HasSuffix(host, "."+rest) {
+			return false
+		}
+	} else if host != realmHost {
+		return false
+	}
+
+	realmPath := ru.Path
+	if realmPath == "" {
+		realmPath = "/"
+	}
+	path := u.Path
+	if path == "" {
+		path = "/"
+	}
+
+	if strings.HasSuffix(realmPath, "/") {
+		return strings.HasPrefix(path, realmPath)
+	}
+
+	return path == realmPath || strings.HasPrefix(path, realmPath+"/")
+}
+
+// portOf returns the url's port, filling in the scheme's default.
+func portOf(u *url.URL) string {
+	if p := u.Port(); p != "" {
+		return p
+	}
+
+	if strings.EqualFold(u.Scheme, "https") {
+		return "443"
+	}
+
+	return "80"
+}
@@ -0,0 +1,65 @@
+package gosteamauth
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRealmMatches(t *testing.T) {
+	tests := []struct {
+		realm, returnUrl string
+		want             bool
+	}{
+		{"https://example.com", "https://example.com/callback", true},
+		{"https://example.com/", "https://example.com/", true},
+		{"https://example.com", "https://EXAMPLE.com/callback", true},
+		{"https://example.com", "http://example.com/callback", false},
+		{"https://example.com", "https://example.com:8443/callback", false},
+		{"https://example.com:443", "https://example.com/callback", true},
+		{"https://example.com", "https://evil.example.net/callback", false},
+		{"https://example.com", "https://example.com.evil.net/callback", false},
+		{"https://example.com", "https://sub.example.com/callback", false},
+		{"https://example.com", "https://example.com/callback#frag", false},
+		{"https://*.example.com", "https://a.example.com/callback", true},
+		{"https://*.example.com", "https://a.b.example.com/callback", true},
+		{"https://*.example.com", "https://example.com/callback", true},
+		{"https://*.example.com", "https://evilexample.com/callback", false},
+		{"https://example.com/auth", "https://example.com/auth/callback", true},
+		{"https://example.com/auth", "https://example.com/auth", true},
+		{"https://example.com/auth", "https://example.com/authority", false},
+		{"https://example.com/auth/", "https://example.com/auth/callback", true},
+		{"https://example.com/auth/", "https://example.com/other", false},
+		{"not a realm", "https://example.com/callback", false},
+		{"https://example.com#frag", "https://example.com/callback", false},
+	}
+
+	for _, tt := range tests {
+		if got := RealmMatches(tt.realm, tt.returnUrl); got != tt.want {
+			t.Errorf("RealmMatches(%q, %q) = %v, want %v", tt.realm, tt.returnUrl, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCallbackOutsideRealm(t *testing.T) {
+	var asked bool
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		asked = true
+		validOpenId(w, r)
+	})
+
+	sa := New("", "https://example.com")
+
+	// a real assertion steam signed for another site, replayed against this one
+	_, err := sa.ValidateCallback(steamCallback("https://other.example.net/callback", "76561197960287930").URL.Query())
+	if !errors.Is(err, ErrInvalidAuthRequest) || !errors.Is(err, ErrReturnUrlOutsideRealm) {
+		t.Errorf("err = %v, want ErrInvalidAuthRequest and ErrReturnUrlOutsideRealm", err)
+	}
+	if asked {
+		t.Error("steam was asked about a callback for another site")
+	}
+
+	if _, err := sa.ValidateCallback(steamCallback("https://example.com/callback", "76561197960287930").URL.Query()); err != nil {
+		t.Errorf("callback inside the realm err = %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package gosteamauth
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// NextParam is the query parameter carrying where to send the user after logging in, ex. /login?next=/settings.
+const NextParam = "next"
+
+// RedirectPolicy decides where users can be sent after logging in, so the next parameter can't be used to bounce
+// users off your domain to somewhere nasty (an open redirect).
+type RedirectPolicy struct {
+	// AllowedPaths are the path prefixes relative redirects (ex. "/settings") may go to. A prefix matches whole path
+	// segments, so "/app" allows "/app" and "/app/x" but not "/apples". If empty, any relative path is allowed.
+	AllowedPaths []string
+	// AllowedHosts are the hosts absolute redirects (ex. "https://app.example.com/") may go to. They're compared
+	// exactly, including the port. If empty, absolute redirects are never allowed.
+	AllowedHosts []string
+}
+
+// Check returns the target if it's allowed, and false otherwise.
+func (p *RedirectPolicy) Check(target string) (string, bool) {
+	if target == "" {
+		return "", false
+	}
+
+	// Browsers treat backslashes like forward slashes, so /\evil.com is really //evil.com. Control characters get
+	// stripped by browsers too, so they can hide a scheme. Nothing legit needs either.
+	if strings.ContainsAny(target, "\\") || strings.IndexFunc(target, func(r rune) bool { return r < 0x20 || r == 0x7f }) != -1 {
+		return "", false
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", false
+	}
+
+	if u.Scheme == "" && u.Host == "" {
+		// A relative redirect. It has to be rooted, and "//host" is not relative at all.
+		if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") {
+			return "", false
+		}
+
+		// Check the path the browser will actually end up at, so /app/../admin doesn't count as being under /app.
+		if len(p.AllowedPaths) == 0 || p.pathAllowed(path.Clean(u.Path)) {
+			return target, true
+		}
+
+		return "", false
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.User != nil {
+		return "", false
+	}
+
+	for _, h := range p.AllowedHosts {
+		if strings.EqualFold(u.Host, h) {
+			return target, true
+		}
+	}
+
+	return "", false
+}
+
+func (p *RedirectPolicy) pathAllowed(target string) bool {
+	for _, prefix := range p.AllowedPaths {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if prefix == "" || target == prefix || strings.HasPrefix(target, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
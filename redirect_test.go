@@ -0,0 +1,137 @@
+package gosteamauth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRedirectPolicyCheck(t *testing.T) {
+	p := &RedirectPolicy{
+		AllowedPaths: []string{"/app", "/settings/"},
+		AllowedHosts: []string{"app.example.com", "localhost:3000"},
+	}
+
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{"/app", true},
+		{"/app/inventory?tab=2", true},
+		{"/settings", true},
+		{"/apples", false},
+		{"/app/../admin", false},
+		{"/", false},
+		{"", false},
+		{"app", false},
+		{"//evil.com", false},
+		{"/\\evil.com", false},
+		{"/app\t/x", false},
+		{"https://app.example.com/dashboard", true},
+		{"http://APP.example.com/", true},
+		{"https://localhost:3000/", true},
+		{"https://localhost/", false},
+		{"https://evil.com/", false},
+		{"https://user@app.example.com/", false},
+		{"javascript:alert(1)", false},
+		{"ftp://app.example.com/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got, ok := p.Check(tt.target)
+			if ok != tt.want {
+				t.Fatalf("Check(%q) = %q, %v, want %v", tt.target, got, ok, tt.want)
+			}
+			if ok && got != tt.target {
+				t.Errorf("Check(%q) = %q, want the target back", tt.target, got)
+			}
+		})
+	}
+}
+
+func TestRedirectPolicyNoPaths(t *testing.T) {
+	p := &RedirectPolicy{}
+
+	if _, ok := p.Check("/anywhere/at/all"); !ok {
+		t.Error("relative redirect refused without AllowedPaths")
+	}
+	if _, ok := p.Check("https://app.example.com/"); ok {
+		t.Error("absolute redirect allowed without AllowedHosts")
+	}
+}
+
+func TestLoginCarriesNext(t *testing.T) {
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.Redirects = &RedirectPolicy{AllowedPaths: []string{"/app"}}
+
+	tests := []struct {
+		next string
+		want string
+	}{
+		{"/app/settings", "/app/settings"},
+		{"https://evil.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.next, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.Login(rec, httptest.NewRequest(http.MethodGet, "/login?"+url.Values{NextParam: {tt.next}}.Encode(), nil))
+
+			steamUrl, err := url.Parse(rec.Header().Get("Location"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			returnTo, err := url.Parse(steamUrl.Query().Get("openid.return_to"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := returnTo.Query().Get(NextParam); got != tt.want {
+				t.Errorf("next in return_to = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallbackRedirectsToNext(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	tests := []struct {
+		name string
+		next string
+		want string
+	}{
+		{"allowed", "/app/settings", "/app/settings"},
+		// someone crafting their own callback url doesn't get past the policy either
+		{"not allowed", "https://evil.com", "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+			h.Redirects = &RedirectPolicy{AllowedPaths: []string{"/app"}}
+			h.Attempts = NewAttemptTracker(time.Minute)
+			defer h.Attempts.Close()
+
+			returnTo, cookie := startLogin(t, h)
+			u, _ := url.Parse(returnTo)
+			q := u.Query()
+			q.Set(NextParam, tt.next)
+			u.RawQuery = q.Encode()
+
+			req := steamCallback(u.String(), "76561197960287930")
+			req.AddCookie(cookie)
+			rec := httptest.NewRecorder()
+			h.Callback(rec, req)
+
+			if rec.Code != http.StatusFound {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusFound)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Errorf("redirected to %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package gosteamauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidRefreshToken is returned by RefreshTokens.Refresh when the refresh token is unknown, expired, revoked,
+// or has already been used.
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+// DefaultRefreshTokenTTL is how long refresh tokens are valid for, unless RefreshTokens.TTL is changed.
+const DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// TokenPair is an access token along with the refresh token that can be used to get the next one.
+// The json tags match the token response from RFC 6749, so it can be written straight to clients.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// RefreshTokens hands out opaque, single use refresh tokens next to the access tokens minted by a TokenIssuer,
+// so long-lived clients don't have to go through steam again every time their access token expires.
+//
+// Every refresh rotates the refresh token. If an already used refresh token is ever presented again, it has most
+// likely been stolen, so every token descended from the same login is revoked.
+type RefreshTokens struct {
+	store  Store
+	issuer *TokenIssuer
+
+	// TTL is how long each refresh token is valid for. Defaults to DefaultRefreshTokenTTL.
+	// Rotating doesn't extend the login forever, a rotated token expires when the one it replaced would have.
+	TTL time.Duration
+}
+
+// refreshRecord is what's kept in the store for each refresh token.
+type refreshRecord struct {
+	SteamID string     `json:"steamid"`
+	User    *SteamUser `json:"user,omitempty"`
+	// ClientID is the oauth client the token was issued to, if any. Only that client can redeem it.
+	ClientID string `json:"client_id,omitempty"`
+	// Family is shared by every refresh token descended from the same login.
+	Family    string    `json:"family"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewRefreshTokens returns a new RefreshTokens keeping its state in store and minting access tokens with issuer.
+func NewRefreshTokens(store Store, issuer *TokenIssuer) *RefreshTokens {
+	return &RefreshTokens{
+		store:  store,
+		issuer: issuer,
+		TTL:    DefaultRefreshTokenTTL,
+	}
+}
+
+// Issue mints a new access token and refresh token for steamid64, starting a new token family.
+// This is what you call after ValidateCallback.
+func (rt *RefreshTokens) Issue(ctx context.Context, steamid64 string, user *SteamUser) (*TokenPair, error) {
+	return rt.IssueForClient(ctx, steamid64, user, "")
+}
+
+// IssueForClient is Issue for tokens handed to an oauth client. The refresh token is bound to clientId, so it can
+// only be redeemed with RefreshForClient by the same client.
+func (rt *RefreshTokens) IssueForClient(ctx context.Context, steamid64 string, user *SteamUser, clientId string) (*TokenPair, error) {
+	family, err := randomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("issue refresh token (%s): generate family: %w", steamid64, err)
+	}
+
+	rec := refreshRecord{
+		SteamID:   steamid64,
+		User:      user,
+		ClientID:  clientId,
+		Family:    family,
+		ExpiresAt: time.Now().Add(rt.TTL),
+	}
+
+	pair, err := rt.issue(ctx, rec)
+	if err != nil {
+		return nil, fmt.Errorf("issue refresh token (%s): %w", steamid64, err)
+	}
+
+	return pair, nil
+}
+
+// Refresh exchanges refreshToken for a new token pair. The old refresh token can't be used again.
+// All failures caused by the token itself wrap ErrInvalidRefreshToken. Tokens issued to an oauth client can't be
+// redeemed here, see RefreshForClient.
+func (rt *RefreshTokens) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	return rt.RefreshForClient(ctx, refreshToken, "")
+}
+
+// RefreshForClient is Refresh for the oauth client clientId, which has to be the client the token was issued to
+// (RFC 6749 section 6). A token presented by any other client is treated as stolen, and its family is revoked.
+func (rt *RefreshTokens) RefreshForClient(ctx context.Context, refreshToken, clientId string) (*TokenPair, error) {
+	h := hashToken(refreshToken)
+
+	b, err := rt.store.Take(ctx, refreshKey(h))
+	if errors.Is(err, ErrNotFound) {
+		// Someone is replaying a token that was already rotated. Kill the whole family so whoever has
+		// the newer token has to log in again too, since we can't tell which one of them is the thief.
+		if fam, err := rt.store.Get(ctx, refreshUsedKey(h)); err == nil {
+			if err := rt.revokeFamily(ctx, string(fam)); err != nil {
+				return nil, fmt.Errorf("refresh token: revoke reused family: %w", err)
+			}
+		}
+
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("refresh token: take from store: %w", err)
+	}
+
+	var rec refreshRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("refresh token: decode record: %w", err)
+	}
+
+	if rec.ClientID != clientId {
+		if err := rt.revokeFamily(ctx, rec.Family); err != nil {
+			return nil, fmt.Errorf("refresh token: revoke family presented by another client: %w", err)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	remaining := time.Until(rec.ExpiresAt)
+	if remaining <= 0 {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if _, err := rt.store.Get(ctx, familyRevokedKey(rec.Family)); err == nil {
+		return nil, ErrInvalidRefreshToken
+	} else if !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("refresh token: check revocation: %w", err)
+	}
+
+	// Remember this token was used, so reuse can be detected for as long as it would have been valid.
+	if err := rt.store.Set(ctx, refreshUsedKey(h), []byte(rec.Family), remaining); err != nil {
+		return nil, fmt.Errorf("refresh token: mark used: %w", err)
+	}
+
+	pair, err := rt.issue(ctx, rec)
+	if err != nil {
+		return nil, fmt.Errorf("refresh token: %w", err)
+	}
+
+	return pair, nil
+}
+
+// Revoke revokes refreshToken and every other refresh token from the same login. This is what logging out should call.
+// Revoking a token that is unknown or already expired is not an error.
+func (rt *RefreshTokens) Revoke(ctx context.Context, refreshToken string) error {
+	h := hashToken(refreshToken)
+
+	b, err := rt.store.Take(ctx, refreshKey(h))
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("revoke refresh token: take from store: %w", err)
+	}
+
+	var rec refreshRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return fmt.Errorf("revoke refresh token: decode record: %w", err)
+	}
+
+	if err := rt.revokeFamily(ctx, rec.Family); err != nil {
+		return fmt.Errorf("revoke refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// revokeFamily puts family on the revocation list. It only needs to stay there as long as a token could live.
+func (rt *RefreshTokens) revokeFamily(ctx context.Context, family string) error {
+	return rt.store.Set(ctx, familyRevokedKey(family), []byte{1}, rt.TTL)
+}
+
+// issue mints an access token and stores a new refresh token for rec.
+func (rt *RefreshTokens) issue(ctx context.Context, rec refreshRecord) (*TokenPair, error) {
+	access, err := rt.issuer.Issue(rec.SteamID, rec.User)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("encode record: %w", err)
+	}
+
+	if err := rt.store.Set(ctx, refreshKey(hashToken(refresh)), b, time.Until(rec.ExpiresAt)); err != nil {
+		return nil, fmt.Errorf("store refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(rt.issuer.TTL.Seconds()),
+		RefreshToken: refresh,
+	}, nil
+}
+
+// hashToken hashes a token before it's used as a store key, so leaking the store doesn't leak usable tokens.
+func hashToken(tok string) string {
+	sum := sha256.Sum256([]byte(tok))
+	return hex.EncodeToString(sum[:])
+}
+
+func refreshKey(hash string) string      { return "refresh:" + hash }
+func refreshUsedKey(hash string) string  { return "refresh-used:" + hash }
+func familyRevokedKey(fam string) string { return "refresh-family-revoked:" + fam }
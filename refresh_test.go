@@ -0,0 +1,103 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	const steamid = "76561197960287930"
+
+	tests := []struct {
+		name string
+		// run does something with the login's refresh token and returns the token its owner holds afterwards.
+		run func(t *testing.T, rt *RefreshTokens, tok string) string
+		// wantValid is whether the owner's token still works afterwards.
+		wantValid bool
+	}{
+		{"rotate", func(t *testing.T, rt *RefreshTokens, tok string) string {
+			pair, err := rt.Refresh(ctx, tok)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pair.RefreshToken == tok {
+				t.Error("refresh token wasn't rotated")
+			}
+			return pair.RefreshToken
+		}, true},
+		{"reuse revokes the family", func(t *testing.T, rt *RefreshTokens, tok string) string {
+			pair, err := rt.Refresh(ctx, tok)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The old token turning up again means it was stolen.
+			if _, err := rt.Refresh(ctx, tok); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("reused token err = %v, want ErrInvalidRefreshToken", err)
+			}
+			return pair.RefreshToken
+		}, false},
+		{"revoke", func(t *testing.T, rt *RefreshTokens, tok string) string {
+			pair, err := rt.Refresh(ctx, tok)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := rt.Revoke(ctx, pair.RefreshToken); err != nil {
+				t.Fatal(err)
+			}
+			return pair.RefreshToken
+		}, false},
+		{"unknown token", func(t *testing.T, rt *RefreshTokens, tok string) string {
+			if _, err := rt.Refresh(ctx, "not-a-token"); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("unknown token err = %v, want ErrInvalidRefreshToken", err)
+			}
+			return tok
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewRefreshTokens(NewMemoryStore(), NewHS256Issuer([]byte("0123456789abcdef0123456789abcdef")))
+			pair, err := rt.Issue(ctx, steamid, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tok := tt.run(t, rt, pair.RefreshToken)
+
+			_, err = rt.Refresh(ctx, tok)
+			if tt.wantValid && err != nil {
+				t.Errorf("owner's token err = %v, want it to still work", err)
+			}
+			if !tt.wantValid && !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("owner's token err = %v, want ErrInvalidRefreshToken", err)
+			}
+		})
+	}
+}
+
+func TestRefreshTokensBoundToClient(t *testing.T) {
+	ctx := context.Background()
+	rt := NewRefreshTokens(NewMemoryStore(), NewHS256Issuer([]byte("0123456789abcdef0123456789abcdef")))
+
+	pair, err := rt.IssueForClient(ctx, "76561197960287930", nil, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rt.RefreshForClient(ctx, pair.RefreshToken, "b"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("other client err = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := rt.RefreshForClient(ctx, pair.RefreshToken, "a"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("owning client after theft err = %v, want ErrInvalidRefreshToken", err)
+	}
+
+	pair, err = rt.IssueForClient(ctx, "76561197960287930", nil, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rt.Refresh(ctx, pair.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh of a client's token err = %v, want ErrInvalidRefreshToken", err)
+	}
+}
@@ -0,0 +1,361 @@
+package gosteamauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrInvalidRememberToken is returned by RememberMe.Restore when the request's remember me cookie is missing, expired,
+// revoked or forged.
+var ErrInvalidRememberToken = errors.New("invalid remember me token")
+
+const (
+	// DefaultRememberCookieName is the name of the remember me cookie, unless RememberMe.CookieName is changed.
+	DefaultRememberCookieName = "steam_remember"
+	// DefaultRememberTTL is how long remember me tokens last, unless RememberMe.TTL is changed.
+	DefaultRememberTTL = 30 * 24 * time.Hour
+	// RememberParam is the query parameter Handler.Login takes to remember the user, ex. /login?remember=1.
+	RememberParam = "remember"
+)
+
+// Device is what's known about the device a remember me token was issued to, so users can tell their tokens apart.
+type Device struct {
+	UserAgent string `json:"user_agent"`
+	IP        string `json:"ip"`
+}
+
+// RememberToken is a long-lived login on one device.
+type RememberToken struct {
+	// ID identifies the token, for listing and revoking it. It's not a secret.
+	ID         string    `json:"id"`
+	SteamID    string    `json:"steamid"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Device is the device the token was issued to, updated every time it's used.
+	Device Device `json:"device"`
+}
+
+// rememberRecord is a RememberToken as it's kept in the store.
+type rememberRecord struct {
+	RememberToken
+	SecretHash string `json:"secret_hash"`
+}
+
+// RememberMe keeps users logged in across sessions with a long-lived cookie. The cookie holds a token id and a
+// secret, only the secret's hash is kept in the Store. The secret changes every time the token is used.
+type RememberMe struct {
+	store Store
+
+	// CookieName defaults to DefaultRememberCookieName.
+	CookieName string
+	// TTL is how long a token lasts after it's issued. Using it doesn't extend it. Defaults to DefaultRememberTTL.
+	TTL time.Duration
+	// Path and Domain are set on the cookie. Path defaults to "/".
+	Path   string
+	Domain string
+	// Insecure stops the cookie being marked Secure. Only turn this on for local development over plain http.
+	Insecure bool
+}
+
+// NewRememberMe returns a new RememberMe keeping tokens in store.
+func NewRememberMe(store Store) *RememberMe {
+	return &RememberMe{
+		store:      store,
+		CookieName: DefaultRememberCookieName,
+		TTL:        DefaultRememberTTL,
+		Path:       "/",
+	}
+}
+
+// Issue issues a new token for steamid64 and sets its cookie on w.
+func (rm *RememberMe) Issue(ctx context.Context, w http.ResponseWriter, steamid64 string, device Device) (*RememberToken, error) {
+	id, err := randomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("issue remember token (%s): generate id: %w", steamid64, err)
+	}
+
+	now := time.Now()
+	tok := &RememberToken{
+		ID:         id,
+		SteamID:    steamid64,
+		CreatedAt:  now,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(rm.TTL),
+		Device:     device,
+	}
+
+	if err := rm.save(ctx, w, tok); err != nil {
+		return nil, fmt.Errorf("issue remember token (%s): %w", steamid64, err)
+	}
+
+	if err := rm.index(ctx, steamid64, func(ids []string) []string { return append(ids, id) }); err != nil {
+		return nil, fmt.Errorf("issue remember token (%s): %w", steamid64, err)
+	}
+
+	return tok, nil
+}
+
+// save gives tok a new secret, stores it and sets the cookie.
+func (rm *RememberMe) save(ctx context.Context, w http.ResponseWriter, tok *RememberToken) error {
+	secret, err := randomToken(32)
+	if err != nil {
+		return fmt.Errorf("generate secret: %w", err)
+	}
+	b, err := json.Marshal(rememberRecord{RememberToken: *tok, SecretHash: hashToken(secret)})
+	if err != nil {
+		return fmt.Errorf("encode: %w", err)
+	}
+
+	if err := rm.store.Set(ctx, rememberKey(tok.ID), b, time.Until(tok.ExpiresAt)); err != nil {
+		return err
+	}
+
+	rm.setCookie(w, tok.ID+"."+secret, tok.ExpiresAt)
+	return nil
+}
+
+// Restore checks the request's remember me cookie and returns its token, giving it a new secret (and cookie) and
+// updating its device. Returns ErrInvalidRememberToken if there's no valid token.
+func (rm *RememberMe) Restore(w http.ResponseWriter, r *http.Request, device Device) (*RememberToken, error) {
+	c, err := r.Cookie(rm.CookieName)
+	if err != nil {
+		return nil, ErrInvalidRememberToken
+	}
+
+	id, secret, ok := strings.Cut(c.Value, ".")
+	if !ok {
+		return nil, ErrInvalidRememberToken
+	}
+
+	// Taking the token means two requests racing with the same cookie can't both rotate it.
+	b, err := rm.store.Take(r.Context(), rememberKey(id))
+	if errors.Is(err, ErrNotFound) {
+		rm.setCookie(w, "", time.Unix(0, 0))
+		return nil, ErrInvalidRememberToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("restore remember token: %w", err)
+	}
+
+	var rec rememberRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("restore remember token: decode: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(rec.SecretHash), []byte(hashToken(secret))) != 1 {
+		// Someone has the id but not the secret. Put it back so the real owner isn't logged out.
+		if err := rm.store.Set(r.Context(), rememberKey(id), b, time.Until(rec.ExpiresAt)); err != nil {
+			return nil, fmt.Errorf("restore remember token: %w", err)
+		}
+		return nil, ErrInvalidRememberToken
+	}
+
+	if time.Now().After(rec.ExpiresAt) {
+		rm.setCookie(w, "", time.Unix(0, 0))
+		return nil, ErrInvalidRememberToken
+	}
+
+	revoked, err := rm.revokedBefore(r.Context(), rec.SteamID)
+	if err != nil {
+		return nil, fmt.Errorf("restore remember token: %w", err)
+	}
+	if !rec.CreatedAt.After(revoked) {
+		rm.setCookie(w, "", time.Unix(0, 0))
+		return nil, ErrInvalidRememberToken
+	}
+
+	tok := rec.RememberToken
+	tok.LastUsedAt = time.Now()
+	tok.Device = device
+	if err := rm.save(r.Context(), w, &tok); err != nil {
+		return nil, fmt.Errorf("restore remember token: %w", err)
+	}
+
+	return &tok, nil
+}
+
+// List returns the user's tokens, ex. for a "devices you're logged in on" page.
+func (rm *RememberMe) List(ctx context.Context, steamid64 string) ([]*RememberToken, error) {
+	var toks []*RememberToken
+	err := rm.index(ctx, steamid64, func(ids []string) []string {
+		live := ids[:0]
+		for _, id := range ids {
+			b, err := rm.store.Get(ctx, rememberKey(id))
+			if err != nil {
+				continue
+			}
+
+			var rec rememberRecord
+			if err := json.Unmarshal(b, &rec); err != nil {
+				continue
+			}
+
+			live = append(live, id)
+			toks = append(toks, &rec.RememberToken)
+		}
+		return live
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list remember tokens (%s): %w", steamid64, err)
+	}
+
+	return toks, nil
+}
+
+// Revoke revokes one of the user's tokens. Returns ErrNotFound if there's no such token, or it belongs to someone
+// else.
+func (rm *RememberMe) Revoke(ctx context.Context, steamid64, id string) error {
+	b, err := rm.store.Get(ctx, rememberKey(id))
+	if err != nil {
+		return fmt.Errorf("revoke remember token (%s): %w", steamid64, err)
+	}
+
+	var rec rememberRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return fmt.Errorf("revoke remember token (%s): decode: %w", steamid64, err)
+	}
+	if rec.SteamID != steamid64 {
+		return fmt.Errorf("revoke remember token (%s): %w", steamid64, ErrNotFound)
+	}
+
+	err = rm.index(ctx, steamid64, func(ids []string) []string {
+		for i, got := range ids {
+			if got == id {
+				return append(ids[:i], ids[i+1:]...)
+			}
+		}
+		return ids
+	})
+	if err != nil {
+		return fmt.Errorf("revoke remember token (%s): %w", steamid64, err)
+	}
+
+	if err := rm.store.Delete(ctx, rememberKey(id)); err != nil {
+		return fmt.Errorf("revoke remember token (%s): %w", steamid64, err)
+	}
+
+	return nil
+}
+
+// RevokeAll revokes all of the user's tokens, logging them out everywhere once their sessions end. Every token issued
+// before it's called stops working, even ones the index lost track of.
+func (rm *RememberMe) RevokeAll(ctx context.Context, steamid64 string) error {
+	// Tokens can't outlive the ttl, so neither does the cutoff.
+	cutoff := time.Now().UTC().Format(time.RFC3339Nano)
+	if err := rm.store.Set(ctx, rememberRevokedKey(steamid64), []byte(cutoff), rm.TTL); err != nil {
+		return fmt.Errorf("revoke all remember tokens (%s): %w", steamid64, err)
+	}
+
+	var revoke []string
+	err := rm.index(ctx, steamid64, func(ids []string) []string {
+		revoke = ids
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("revoke all remember tokens (%s): %w", steamid64, err)
+	}
+
+	for _, id := range revoke {
+		if err := rm.store.Delete(ctx, rememberKey(id)); err != nil {
+			return fmt.Errorf("revoke all remember tokens (%s): %w", steamid64, err)
+		}
+	}
+
+	return nil
+}
+
+// Forget revokes the request's token, if it has one, and clears its cookie. Use it when the user logs out.
+func (rm *RememberMe) Forget(w http.ResponseWriter, r *http.Request) error {
+	rm.setCookie(w, "", time.Unix(0, 0))
+
+	c, err := r.Cookie(rm.CookieName)
+	if err != nil {
+		return nil
+	}
+
+	id, _, _ := strings.Cut(c.Value, ".")
+	if err := rm.store.Delete(r.Context(), rememberKey(id)); err != nil {
+		return fmt.Errorf("forget remember token: %w", err)
+	}
+
+	return nil
+}
+
+// revokedBefore returns when RevokeAll was last called for the user, or the zero time if it hasn't been.
+func (rm *RememberMe) revokedBefore(ctx context.Context, steamid64 string) (time.Time, error) {
+	b, err := rm.store.Get(ctx, rememberRevokedKey(steamid64))
+	if errors.Is(err, ErrNotFound) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, string(b))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("decode revoke time: %w", err)
+	}
+	return t, nil
+}
+
+// index updates the list of the user's token ids. Ids of tokens that have expired or been forgotten are left in the
+// list until List notices.
+//
+// The read-modify-write isn't atomic, so two updates racing for the same user can lose an id. That only hides the
+// token from List. Revoke looks up the token itself, and RevokeAll doesn't rely on the index (see revokedBefore).
+func (rm *RememberMe) index(ctx context.Context, steamid64 string, update func(ids []string) []string) error {
+	var ids []string
+	b, err := rm.store.Get(ctx, rememberUserKey(steamid64))
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err == nil {
+		if err := json.Unmarshal(b, &ids); err != nil {
+			return fmt.Errorf("decode token list: %w", err)
+		}
+	}
+
+	ids = update(ids)
+	if len(ids) == 0 {
+		return rm.store.Delete(ctx, rememberUserKey(steamid64))
+	}
+
+	b, err = json.Marshal(ids)
+	if err != nil {
+		return fmt.Errorf("encode token list: %w", err)
+	}
+
+	return rm.store.Set(ctx, rememberUserKey(steamid64), b, 0)
+}
+
+func (rm *RememberMe) setCookie(w http.ResponseWriter, value string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     rm.CookieName,
+		Value:    value,
+		Path:     rm.Path,
+		Domain:   rm.Domain,
+		Expires:  expires,
+		Secure:   !rm.Insecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func rememberKey(id string) string {
+	return "remember:" + id
+}
+
+func rememberUserKey(steamid64 string) string {
+	return "remember-user:" + steamid64
+}
+
+func rememberRevokedKey(steamid64 string) string {
+	return "remember-revoked:" + steamid64
+}
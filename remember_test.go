@@ -0,0 +1,193 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// issueRemember issues a token for steamid64 and returns it with its cookie.
+func issueRemember(t *testing.T, rm *RememberMe, steamid64 string) (*RememberToken, *http.Cookie) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	tok, err := rm.Issue(context.Background(), rec, steamid64, Device{UserAgent: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tok, rec.Result().Cookies()[0]
+}
+
+// restore runs Restore with cookie, returning the token and the replacement cookie.
+func restore(rm *RememberMe, cookie *http.Cookie) (*RememberToken, *http.Cookie, error) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	rec := httptest.NewRecorder()
+
+	tok, err := rm.Restore(rec, req, Device{UserAgent: "restored"})
+	var next *http.Cookie
+	if cs := rec.Result().Cookies(); len(cs) > 0 {
+		next = cs[0]
+	}
+	return tok, next, err
+}
+
+func TestRememberRestoreRotates(t *testing.T) {
+	rm := NewRememberMe(NewMemoryStore())
+	issued, cookie := issueRemember(t, rm, "76561197960287930")
+
+	tok, next, err := restore(rm, cookie)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.ID != issued.ID || tok.SteamID != "76561197960287930" || tok.Device.UserAgent != "restored" {
+		t.Errorf("tok = %+v, want the issued token on the new device", tok)
+	}
+	if next == nil || next.Value == cookie.Value {
+		t.Fatalf("cookie = %v, want a new secret", next)
+	}
+
+	// a stolen copy of the old cookie is no good once the owner has used it
+	if _, _, err := restore(rm, cookie); !errors.Is(err, ErrInvalidRememberToken) {
+		t.Errorf("old cookie err = %v, want ErrInvalidRememberToken", err)
+	}
+	if _, _, err := restore(rm, next); err != nil {
+		t.Errorf("new cookie err = %v", err)
+	}
+}
+
+func TestRememberForgedSecret(t *testing.T) {
+	rm := NewRememberMe(NewMemoryStore())
+	issued, cookie := issueRemember(t, rm, "76561197960287930")
+
+	forged := &http.Cookie{Name: cookie.Name, Value: issued.ID + ".guess"}
+	if _, _, err := restore(rm, forged); !errors.Is(err, ErrInvalidRememberToken) {
+		t.Fatalf("forged cookie err = %v, want ErrInvalidRememberToken", err)
+	}
+
+	// guessing wrong doesn't log the real owner out
+	if _, _, err := restore(rm, cookie); err != nil {
+		t.Errorf("real cookie after a forged one err = %v", err)
+	}
+}
+
+func TestRememberRevoke(t *testing.T) {
+	rm := NewRememberMe(NewMemoryStore())
+	tok, cookie := issueRemember(t, rm, "76561197960287930")
+
+	tests := []struct {
+		name    string
+		steamid string
+		id      string
+		wantErr error
+	}{
+		{"someone else's token", "76561197960287931", tok.ID, ErrNotFound},
+		{"unknown token", "76561197960287930", "nope", ErrNotFound},
+		{"own token", "76561197960287930", tok.ID, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rm.Revoke(context.Background(), tt.steamid, tt.id)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, _, err := restore(rm, cookie); !errors.Is(err, ErrInvalidRememberToken) {
+		t.Errorf("revoked token err = %v, want ErrInvalidRememberToken", err)
+	}
+}
+
+func TestRememberRevokeAll(t *testing.T) {
+	store := NewMemoryStore()
+	rm := NewRememberMe(store)
+
+	_, first := issueRemember(t, rm, "76561197960287930")
+	_, lost := issueRemember(t, rm, "76561197960287930")
+	_, other := issueRemember(t, rm, "76561197960287931")
+
+	// lose track of the tokens, like a racing index update would
+	if err := store.Delete(context.Background(), rememberUserKey("76561197960287930")); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond)
+	if err := rm.RevokeAll(context.Background(), "76561197960287930"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []*http.Cookie{first, lost} {
+		if _, _, err := restore(rm, c); !errors.Is(err, ErrInvalidRememberToken) {
+			t.Errorf("token issued before RevokeAll err = %v, want ErrInvalidRememberToken", err)
+		}
+	}
+	if _, _, err := restore(rm, other); err != nil {
+		t.Errorf("another user's token err = %v", err)
+	}
+
+	_, after := issueRemember(t, rm, "76561197960287930")
+	if _, _, err := restore(rm, after); err != nil {
+		t.Errorf("token issued after RevokeAll err = %v", err)
+	}
+}
+
+func TestRequireAuthRemembered(t *testing.T) {
+	store := NewMemoryStore()
+	h := NewHandler(New("", "https://example.com"), NewSessions(store), "https://example.com/callback")
+	h.Remember = NewRememberMe(store)
+
+	_, cookie := issueRemember(t, h.Remember, "76561197960287930")
+	_, banned := issueRemember(t, h.Remember, "76561197960287931")
+	h.Access = []AccessChecker{NewDenylist("76561197960287931")}
+
+	var got string
+	protected := h.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess, _ := SessionFromContext(r.Context())
+		got = sess.SteamID
+	}))
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+		want   int
+	}{
+		{"remembered", cookie, http.StatusOK},
+		{"remembered but banned since", banned, http.StatusUnauthorized},
+		{"nothing", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = ""
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+			protected.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK {
+				if got != "76561197960287930" {
+					t.Errorf("session steamid = %q", got)
+				}
+
+				var gotSession bool
+				for _, c := range rec.Result().Cookies() {
+					gotSession = gotSession || c.Name == h.Sessions().CookieName
+				}
+				if !gotSession {
+					t.Error("no new session cookie was set")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AccountTooNewError is returned by MinAccountAge when the user's account hasn't been around long enough.
+// It's also an AccessDeniedError, so errors.Is(err, ErrAccessDenied) works.
+type AccountTooNewError struct {
+	AccessDeniedError
+	// Created is when the account was made.
+	Created time.Time
+	// MinAge is how old the account needed to be.
+	MinAge time.Duration
+}
+
+// Unwrap lets errors.As find the AccessDeniedError.
+func (e *AccountTooNewError) Unwrap() error {
+	return &e.AccessDeniedError
+}
+
+// LevelTooLowError is returned by MinSteamLevel when the user's steam level is too low.
+// It's also an AccessDeniedError, so errors.Is(err, ErrAccessDenied) works.
+type LevelTooLowError struct {
+	AccessDeniedError
+	// Level is the user's steam level.
+	Level int
+	// MinLevel is the level they needed.
+	MinLevel int
+}
+
+// Unwrap lets errors.As find the AccessDeniedError.
+func (e *LevelTooLowError) Unwrap() error {
+	return &e.AccessDeniedError
+}
+
+// MinAccountAge returns an AccessChecker that refuses accounts younger than age, a common way to keep out alts and
+// throwaway accounts. Steam only says when private profiles were made to their owner, so they're refused too.
+func (sa *SteamAuther) MinAccountAge(age time.Duration) AccessChecker {
+	return AccessCheckerFunc(func(ctx context.Context, steamid64 string) error {
+		user, err := sa.GetSteamUser(steamid64)
+		if err != nil {
+			return fmt.Errorf("min account age: %w", err)
+		}
+
+		created, ok := user.Created()
+		if !ok {
+			return &AccessDeniedError{SteamID: steamid64, Reason: "your profile is private, so we can't see how old your account is"}
+		}
+
+		if time.Since(created) < age {
+			return &AccountTooNewError{
+				AccessDeniedError: AccessDeniedError{SteamID: steamid64, Reason: "your account is too new"},
+				Created:           created,
+				MinAge:            age,
+			}
+		}
+
+		return nil
+	})
+}
+
+// MinSteamLevel returns an AccessChecker that refuses users below the steam level. Private profiles hide their level,
+// so they're refused too.
+func (sa *SteamAuther) MinSteamLevel(level int) AccessChecker {
+	return AccessCheckerFunc(func(ctx context.Context, steamid64 string) error {
+		got, err := sa.GetSteamLevel(ctx, steamid64)
+		if errors.Is(err, ErrPrivateProfile) {
+			return &AccessDeniedError{SteamID: steamid64, Reason: "your profile is private, so we can't see your steam level"}
+		}
+		if err != nil {
+			return fmt.Errorf("min steam level: %w", err)
+		}
+
+		if got < level {
+			return &LevelTooLowError{
+				AccessDeniedError: AccessDeniedError{SteamID: steamid64, Reason: fmt.Sprintf("you need to be at least steam level %d", level)},
+				Level:             got,
+				MinLevel:          level,
+			}
+		}
+
+		return nil
+	})
+}
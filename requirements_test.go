@@ -0,0 +1,89 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMinAccountAge(t *testing.T) {
+	created := time.Now().Add(-30 * 24 * time.Hour).Unix()
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("steamids") {
+		case "76561197960287930":
+			w.Write([]byte(`{"response":{"players":[{"steamid":"76561197960287930","timecreated":` + strconv.FormatInt(created, 10) + `}]}}`))
+		default:
+			// private profiles leave timecreated out
+			w.Write([]byte(`{"response":{"players":[{"steamid":"76561197960287931"}]}}`))
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name       string
+		steamid    string
+		age        time.Duration
+		wantDenied bool
+		wantTooNew bool
+	}{
+		{"old enough", "76561197960287930", 7 * 24 * time.Hour, false, false},
+		{"too new", "76561197960287930", 90 * 24 * time.Hour, true, true},
+		{"private", "76561197960287931", time.Hour, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sa.MinAccountAge(tt.age).CheckAccess(context.Background(), tt.steamid)
+			if errors.Is(err, ErrAccessDenied) != tt.wantDenied {
+				t.Fatalf("err = %v, want denied %v", err, tt.wantDenied)
+			}
+
+			var tooNew *AccountTooNewError
+			if errors.As(err, &tooNew) != tt.wantTooNew {
+				t.Errorf("err = %v, want an AccountTooNewError %v", err, tt.wantTooNew)
+			}
+			if tt.wantTooNew && tooNew.Created.Unix() != created {
+				t.Errorf("Created = %v, want %v", tooNew.Created, time.Unix(created, 0))
+			}
+		})
+	}
+}
+
+func TestMinSteamLevel(t *testing.T) {
+	fakeSteam(t, steamLevels)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name       string
+		steamid    string
+		level      int
+		wantDenied bool
+		wantErr    bool
+	}{
+		{"high enough", "76561197960287930", 10, false, false},
+		{"exactly", "76561197960287930", 12, false, false},
+		{"too low", "76561197960287930", 20, true, false},
+		{"private", "76561197960287931", 1, true, false},
+		{"steam is down", "76561197960287932", 1, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sa.MinSteamLevel(tt.level).CheckAccess(context.Background(), tt.steamid)
+			if errors.Is(err, ErrAccessDenied) != tt.wantDenied {
+				t.Fatalf("err = %v, want denied %v", err, tt.wantDenied)
+			}
+			if got := err != nil && !tt.wantDenied; got != tt.wantErr {
+				t.Errorf("err = %v, want a failed check %v", err, tt.wantErr)
+			}
+
+			var low *LevelTooLowError
+			if errors.As(err, &low) && (low.Level != 12 || low.MinLevel != tt.level) {
+				t.Errorf("err = %+v, want level 12 of %d", low, tt.level)
+			}
+		})
+	}
+}
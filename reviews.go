@@ -0,0 +1,239 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ReviewSort is how GetAppReviews orders reviews, steam's filter parameter.
+type ReviewSort string
+
+const (
+	// ReviewSortHelpful orders reviews by how helpful they've been voted, over ReviewsFilter.DayRange. It's steam's
+	// default.
+	ReviewSortHelpful     ReviewSort = "all"
+	ReviewSortRecent      ReviewSort = "recent"
+	ReviewSortLastUpdated ReviewSort = "updated"
+)
+
+// ReviewsFilter narrows down the reviews GetAppReviews returns. The zero value gets every review, in every
+// language, ordered by helpfulness.
+type ReviewsFilter struct {
+	Sort ReviewSort
+	// Language only gets reviews in this language (ex. "english"). "" gets every language.
+	Language string
+	// ReviewType is "positive" or "negative" to only get those, "" gets both.
+	ReviewType string
+	// PurchaseType is "steam" or "non_steam_purchase" to only get reviews by people who got the app that way, ""
+	// gets both.
+	PurchaseType string
+	// From and To only get reviews written between them. Either can be left zero for no limit on that side.
+	From, To time.Time
+	// DayRange is how many days back ReviewSortHelpful looks, up to 365. 0 leaves it up to steam.
+	DayRange int
+	// IncludeOffTopic includes reviews from off topic review bombs, which steam leaves out by default.
+	IncludeOffTopic bool
+	// PerPage is how many reviews to get a page, up to 100. 0 leaves it up to steam (20).
+	PerPage int
+}
+
+// ReviewAuthor is the person who wrote a review, as represented in the response from the appreviews api.
+type ReviewAuthor struct {
+	SteamID       string `json:"steamid"`
+	NumGamesOwned int    `json:"num_games_owned"`
+	NumReviews    int    `json:"num_reviews"`
+	// Playtimes are in minutes.
+	PlaytimeForever      int `json:"playtime_forever"`
+	PlaytimeLastTwoWeeks int `json:"playtime_last_two_weeks"`
+	PlaytimeAtReview     int `json:"playtime_at_review"`
+	DeckPlaytimeAtReview int `json:"deck_playtime_at_review"`
+	// LastPlayed is when they last played the app, as a unix timestamp.
+	LastPlayed int64 `json:"last_played"`
+}
+
+// Review is a user's review of an app, as represented in the response from the appreviews api.
+type Review struct {
+	RecommendationID string       `json:"recommendationid"`
+	Author           ReviewAuthor `json:"author"`
+	Language         string       `json:"language"`
+	// Review is the review's text, in steam's bbcode.
+	Review string `json:"review"`
+	// TimestampCreated and TimestampUpdated are unix timestamps.
+	TimestampCreated int64 `json:"timestamp_created"`
+	TimestampUpdated int64 `json:"timestamp_updated"`
+	// VotedUp is true for positive reviews.
+	VotedUp    bool `json:"voted_up"`
+	VotesUp    int  `json:"votes_up"`
+	VotesFunny int  `json:"votes_funny"`
+	// WeightedVoteScore is steam's helpfulness score, from 0 to 1.
+	WeightedVoteScore        json.Number `json:"weighted_vote_score"`
+	CommentCount             int         `json:"comment_count"`
+	SteamPurchase            bool        `json:"steam_purchase"`
+	ReceivedForFree          bool        `json:"received_for_free"`
+	WrittenDuringEarlyAccess bool        `json:"written_during_early_access"`
+	PrimarilySteamDeck       bool        `json:"primarily_steam_deck"`
+}
+
+// Created returns when the review was written.
+func (r *Review) Created() time.Time {
+	return time.Unix(r.TimestampCreated, 0)
+}
+
+// Updated returns when the review was last changed.
+func (r *Review) Updated() time.Time {
+	return time.Unix(r.TimestampUpdated, 0)
+}
+
+// ReviewSummary is the totals for an app's reviews (that match the filter), as represented in the response from the
+// appreviews api.
+type ReviewSummary struct {
+	// ReviewScore is steam's 1 to 9 review score, with ReviewScoreDesc being its name (ex. "Very Positive").
+	ReviewScore     int    `json:"review_score"`
+	ReviewScoreDesc string `json:"review_score_desc"`
+	TotalPositive   int    `json:"total_positive"`
+	TotalNegative   int    `json:"total_negative"`
+	TotalReviews    int    `json:"total_reviews"`
+}
+
+// PercentPositive returns the percentage of reviews that are positive, or 0 if there aren't any.
+func (s *ReviewSummary) PercentPositive() float64 {
+	if s.TotalReviews == 0 {
+		return 0
+	}
+
+	return float64(s.TotalPositive) / float64(s.TotalReviews) * 100
+}
+
+// ReviewsPage is a page of an app's reviews.
+type ReviewsPage struct {
+	// Summary is the totals for the reviews matching the filter. Steam only sends it with the first page, so it's nil
+	// on the rest.
+	Summary *ReviewSummary
+	Reviews []Review
+	// Cursor is what to pass to get the next page, or "" if this is the last one.
+	Cursor string
+}
+
+// GetAppReviews gets a page of an app's reviews. Pass "" as cursor to get the first page, and the page's Cursor to
+// get the next one. There's no web api for this, so it comes from the store.
+func (sa *SteamAuther) GetAppReviews(ctx context.Context, appid int, filter ReviewsFilter, cursor string) (*ReviewsPage, error) {
+	if cursor == "" {
+		cursor = "*"
+	}
+
+	q := url.Values{
+		"json":          {"1"},
+		"cursor":        {cursor},
+		"filter":        {string(filter.Sort)},
+		"language":      {"all"},
+		"review_type":   {"all"},
+		"purchase_type": {"all"},
+	}
+	if filter.Sort == "" {
+		q.Set("filter", string(ReviewSortHelpful))
+	}
+	if filter.Language != "" {
+		q.Set("language", filter.Language)
+	}
+	if filter.ReviewType != "" {
+		q.Set("review_type", filter.ReviewType)
+	}
+	if filter.PurchaseType != "" {
+		q.Set("purchase_type", filter.PurchaseType)
+	}
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		to := filter.To
+		if to.IsZero() {
+			to = time.Now()
+		}
+		q.Set("date_range_type", "include")
+		q.Set("start_date", strconv.FormatInt(filter.From.Unix(), 10))
+		q.Set("end_date", strconv.FormatInt(to.Unix(), 10))
+	}
+	if filter.DayRange > 0 {
+		q.Set("day_range", strconv.Itoa(filter.DayRange))
+	}
+	if filter.IncludeOffTopic {
+		q.Set("filter_offtopic_activity", "0")
+	}
+	if filter.PerPage > 0 {
+		q.Set("num_per_page", strconv.Itoa(filter.PerPage))
+	}
+
+	res, err := sa.get(ctx, fmt.Sprintf("%s/appreviews/%d", StoreBaseUrl, appid), q)
+	if err != nil {
+		return nil, fmt.Errorf("get app reviews (%d): %w", appid, err)
+	}
+	defer res.Body.Close()
+
+	var data struct {
+		Success      int            `json:"success"`
+		QuerySummary *ReviewSummary `json:"query_summary"`
+		Reviews      []Review       `json:"reviews"`
+		Cursor       string         `json:"cursor"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("get app reviews (%d): decode response body: %w", appid, err)
+	}
+
+	if data.Success != 1 {
+		return nil, fmt.Errorf("get app reviews (%d): %w", appid, ErrNoData)
+	}
+
+	page := &ReviewsPage{Reviews: data.Reviews}
+	if cursor == "*" {
+		page.Summary = data.QuerySummary
+	}
+	// Steam keeps handing back the same cursor once there's nothing left.
+	if len(data.Reviews) > 0 && data.Cursor != cursor {
+		page.Cursor = data.Cursor
+	}
+
+	return page, nil
+}
+
+// GetReviewSummary gets just the totals for an app's reviews that match the filter, without any reviews.
+func (sa *SteamAuther) GetReviewSummary(ctx context.Context, appid int, filter ReviewsFilter) (*ReviewSummary, error) {
+	// The summary comes with the first page, so get as little of it as possible.
+	filter.PerPage = 1
+	page, err := sa.GetAppReviews(ctx, appid, filter, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if page.Summary == nil {
+		return nil, fmt.Errorf("get review summary (%d): %w", appid, ErrNoData)
+	}
+
+	return page.Summary, nil
+}
+
+// AppReviews is every one of an app's reviews matching the filter as an iterator, fetching a page at a time as it's
+// consumed. Errors are yielded with an empty Review, and end the iteration.
+func (sa *SteamAuther) AppReviews(ctx context.Context, appid int, filter ReviewsFilter) iter.Seq2[Review, error] {
+	return func(yield func(Review, error) bool) {
+		for cursor := ""; ; {
+			page, err := sa.GetAppReviews(ctx, appid, filter, cursor)
+			if err != nil {
+				yield(Review{}, err)
+				return
+			}
+
+			for _, review := range page.Reviews {
+				if !yield(review, nil) {
+					return
+				}
+			}
+
+			if page.Cursor == "" {
+				return
+			}
+			cursor = page.Cursor
+		}
+	}
+}
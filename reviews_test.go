@@ -0,0 +1,133 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// appReviews answers the store's appreviews like steam would for app 440, with three reviews over two pages. Any
+// other app fails.
+func appReviews(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if r.URL.Path != "/appreviews/440" || q.Get("json") != "1" {
+		w.Write([]byte(`{"success":2}`))
+		return
+	}
+
+	switch q.Get("cursor") {
+	case "*":
+		w.Write([]byte(`{"success":1,"query_summary":{"num_reviews":2,"review_score":9,"review_score_desc":"Overwhelmingly Positive","total_positive":3,"total_negative":1,"total_reviews":4},"reviews":[
+			{"recommendationid":"1","author":{"steamid":"76561197960287930","playtime_forever":600},"language":"english","review":"good","timestamp_created":1700000000,"voted_up":true,"weighted_vote_score":"0.9"},
+			{"recommendationid":"2","author":{"steamid":"76561197960287931"},"language":"english","review":"bad","voted_up":false,"weighted_vote_score":0}
+		],"cursor":"AoJ1"}`))
+	case "AoJ1":
+		w.Write([]byte(`{"success":1,"query_summary":{"num_reviews":1},"reviews":[{"recommendationid":"3","voted_up":true}],"cursor":"AoJ2"}`))
+	default:
+		// steam keeps handing the same cursor back past the end
+		w.Write([]byte(`{"success":1,"query_summary":{"num_reviews":0},"reviews":[],"cursor":"` + q.Get("cursor") + `"}`))
+	}
+}
+
+func TestGetAppReviews(t *testing.T) {
+	fakeSteam(t, appReviews)
+	sa := New("key", "https://example.com")
+
+	page, err := sa.GetAppReviews(context.Background(), 440, ReviewsFilter{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Reviews) != 2 || page.Cursor != "AoJ1" || page.Summary == nil || page.Summary.TotalReviews != 4 {
+		t.Fatalf("page = %+v, want the first two reviews and the summary", page)
+	}
+	if r := page.Reviews[0]; r.Author.PlaytimeForever != 600 || r.WeightedVoteScore != "0.9" || r.Created().Unix() != 1700000000 {
+		t.Errorf("review = %+v, want the details decoded", r)
+	}
+
+	page, err = sa.GetAppReviews(context.Background(), 440, ReviewsFilter{}, "AoJ1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Reviews) != 1 || page.Summary != nil {
+		t.Errorf("second page = %+v, want one review and no summary", page)
+	}
+
+	page, err = sa.GetAppReviews(context.Background(), 440, ReviewsFilter{}, "AoJ2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Cursor != "" {
+		t.Errorf("last page cursor = %q, want none", page.Cursor)
+	}
+
+	if _, err := sa.GetAppReviews(context.Background(), 1, ReviewsFilter{}, ""); !errors.Is(err, ErrNoData) {
+		t.Errorf("unknown app err = %v, want ErrNoData", err)
+	}
+}
+
+func TestGetAppReviewsFilter(t *testing.T) {
+	var got map[string]string
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		got = map[string]string{}
+		for k := range r.URL.Query() {
+			got[k] = r.URL.Query().Get(k)
+		}
+		w.Write([]byte(`{"success":1,"reviews":[]}`))
+	})
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name   string
+		filter ReviewsFilter
+		want   map[string]string
+	}{
+		{"defaults", ReviewsFilter{}, map[string]string{"filter": "all", "language": "all", "review_type": "all", "purchase_type": "all"}},
+		{"recent english", ReviewsFilter{Sort: ReviewSortRecent, Language: "english", PerPage: 100}, map[string]string{"filter": "recent", "language": "english", "num_per_page": "100"}},
+		{"negative, off topic too", ReviewsFilter{ReviewType: "negative", IncludeOffTopic: true}, map[string]string{"review_type": "negative", "filter_offtopic_activity": "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sa.GetAppReviews(context.Background(), 440, tt.filter, ""); err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestGetReviewSummary(t *testing.T) {
+	fakeSteam(t, appReviews)
+
+	s, err := New("key", "https://example.com").GetReviewSummary(context.Background(), 440, ReviewsFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ReviewScoreDesc != "Overwhelmingly Positive" || s.PercentPositive() != 75 {
+		t.Errorf("summary = %+v (%v%%), want 75%% positive", s, s.PercentPositive())
+	}
+
+	if (&ReviewSummary{}).PercentPositive() != 0 {
+		t.Error("PercentPositive without reviews != 0")
+	}
+}
+
+func TestAppReviews(t *testing.T) {
+	fakeSteam(t, appReviews)
+
+	var ids []string
+	for r, err := range New("key", "https://example.com").AppReviews(context.Background(), 440, ReviewsFilter{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, r.RecommendationID)
+	}
+	if len(ids) != 3 || ids[0] != "1" || ids[2] != "3" {
+		t.Errorf("reviews = %v, want [1 2 3]", ids)
+	}
+}
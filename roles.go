@@ -0,0 +1,118 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RoleResolver works out a user's roles (ex. "admin", "moderator") when they log in. The roles are kept in their
+// session, so it isn't called on every request.
+type RoleResolver interface {
+	// ResolveRoles returns the user's roles. user is only set if the Handler has FetchUser set.
+	ResolveRoles(ctx context.Context, steamid64 string, user *SteamUser) ([]string, error)
+}
+
+// RoleResolverFunc lets a plain function be used as a RoleResolver.
+type RoleResolverFunc func(ctx context.Context, steamid64 string, user *SteamUser) ([]string, error)
+
+// ResolveRoles implements RoleResolver.
+func (f RoleResolverFunc) ResolveRoles(ctx context.Context, steamid64 string, user *SteamUser) ([]string, error) {
+	return f(ctx, steamid64, user)
+}
+
+// StaticRoles returns a RoleResolver handing out roles from a map of steamid64 to roles. Handy for a handful of admins.
+func StaticRoles(roles map[string][]string) RoleResolver {
+	return RoleResolverFunc(func(_ context.Context, steamid64 string, _ *SteamUser) ([]string, error) {
+		return slices.Clone(roles[steamid64]), nil
+	})
+}
+
+// GroupRoles returns a RoleResolver giving users a role for each steam group they're in, from a map of group id (see
+// RequireGroup for the formats it can be in) to role. Users with private profiles get no roles. Returns an error if
+// one of the group ids isn't a number, see MustGroupRoles for hardcoded ones.
+func (sa *SteamAuther) GroupRoles(groups map[string]string) (RoleResolver, error) {
+	byGid := make(map[string]string, len(groups))
+	for groupId, role := range groups {
+		gid, err := ClanAccountId(groupId)
+		if err != nil {
+			return nil, fmt.Errorf("group roles: %w", err)
+		}
+		byGid[gid] = role
+	}
+
+	return RoleResolverFunc(func(ctx context.Context, steamid64 string, _ *SteamUser) ([]string, error) {
+		gids, err := sa.GetUserGroupList(ctx, steamid64)
+		if errors.Is(err, ErrPrivateProfile) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("group roles: %w", err)
+		}
+
+		var roles []string
+		for _, gid := range gids {
+			if role, ok := byGid[gid]; ok && !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+
+		return roles, nil
+	}), nil
+}
+
+// MustGroupRoles is GroupRoles, panicking if one of the group ids isn't a number.
+func (sa *SteamAuther) MustGroupRoles(groups map[string]string) RoleResolver {
+	r, err := sa.GroupRoles(groups)
+	if err != nil {
+		panic("gosteamauth: " + err.Error())
+	}
+
+	return r
+}
+
+// HasRole reports whether the session's user has the role.
+func (s *Session) HasRole(role string) bool {
+	return slices.Contains(s.Roles, role)
+}
+
+// resolveRoles resolves the session's roles with the Handler's RoleResolver and saves them.
+func (h *Handler) resolveRoles(ctx context.Context, sess *Session) error {
+	roles, err := h.Roles.ResolveRoles(ctx, sess.SteamID, sess.User)
+	if err != nil {
+		return fmt.Errorf("resolve roles (%s): %w", sess.SteamID, err)
+	}
+
+	sess.Roles = roles
+	sess.RolesResolvedAt = time.Now()
+	if err := h.sessions.Save(ctx, sess); err != nil {
+		return fmt.Errorf("resolve roles (%s): %w", sess.SteamID, err)
+	}
+
+	return nil
+}
+
+// rolesStale reports whether the session's roles should be resolved again.
+func (h *Handler) rolesStale(sess *Session) bool {
+	return h.Roles != nil && h.RolesTTL > 0 && time.Since(sess.RolesResolvedAt) > h.RolesTTL
+}
+
+// RequireRole is RequireAuth, but only lets through users with at least one of the roles. Everyone else gets a 403.
+func (h *Handler) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return h.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sess, _ := SessionFromContext(r.Context())
+			for _, role := range roles {
+				if sess.HasRole(role) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			h.fail(w, r, &HandlerError{Kind: ErrorKindDenied, Status: http.StatusForbidden, Message: "you don't have permission to do that"})
+		}))
+	}
+}
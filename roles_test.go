@@ -0,0 +1,171 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGroupRoles(t *testing.T) {
+	sa := New("key", "https://example.com")
+
+	if _, err := sa.GroupRoles(map[string]string{"4": "member", "abc": "admin"}); err == nil {
+		t.Error("GroupRoles with a bad group id err = nil, want an error")
+	}
+
+	roles := sa.MustGroupRoles(map[string]string{
+		"4":                  "member",
+		"103582791429521415": "admin",
+		"9":                  "moderator",
+	})
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"two groups", `{"response":{"success":true,"groups":[{"gid":"4"},{"gid":"7"},{"gid":"8"}]}}`, []string{"admin", "member"}},
+		{"no groups", `{"response":{"success":true,"groups":[]}}`, nil},
+		{"private", `{"response":{"success":false,"error":"Private profile"}}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSteam(t, groupList(http.StatusOK, tt.body))
+
+			got, err := roles.ResolveRoles(context.Background(), "76561197960287930", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("roles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustGroupRolesPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGroupRoles with a bad group id didn't panic")
+		}
+	}()
+	New("", "https://example.com").MustGroupRoles(map[string]string{"abc": "admin"})
+}
+
+func TestRequireRole(t *testing.T) {
+	fakeSteam(t, validOpenId)
+
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.Roles = StaticRoles(map[string][]string{"76561197960287930": {"admin"}})
+
+	// login through the callback, so the roles are resolved like they would be for real
+	login := func(steamid64 string) *http.Cookie {
+		rec := httptest.NewRecorder()
+		h.Callback(rec, steamCallback("https://example.com/callback", steamid64))
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == h.Sessions().CookieName {
+				return c
+			}
+		}
+		t.Fatalf("login as %s didn't start a session", steamid64)
+		return nil
+	}
+	admin := login("76561197960287930")
+	user := login("76561197960287931")
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	tests := []struct {
+		name   string
+		roles  []string
+		cookie *http.Cookie
+		want   int
+	}{
+		{"has the role", []string{"admin"}, admin, http.StatusOK},
+		{"has one of the roles", []string{"moderator", "admin"}, admin, http.StatusOK},
+		{"doesn't have the role", []string{"admin"}, user, http.StatusForbidden},
+		{"not logged in", []string{"admin"}, nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != nil {
+				req.AddCookie(tt.cookie)
+			}
+			rec := httptest.NewRecorder()
+			h.RequireRole(tt.roles...)(ok).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRolesTTL(t *testing.T) {
+	h := NewHandler(New("", "https://example.com"), NewSessions(NewMemoryStore()), "https://example.com/callback")
+	h.RolesTTL = time.Minute
+
+	var fail bool
+	roles := []string{"member"}
+	h.Roles = RoleResolverFunc(func(context.Context, string, *SteamUser) ([]string, error) {
+		if fail {
+			return nil, errors.New("resolver is down")
+		}
+		return roles, nil
+	})
+
+	rec := httptest.NewRecorder()
+	sess, err := h.Sessions().Create(context.Background(), rec, "76561197960287930", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cookie := rec.Result().Cookies()[0]
+
+	current := func() []string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookie)
+		sess, err := h.CurrentSession(httptest.NewRecorder(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sess.Roles
+	}
+
+	// a session that's never been resolved is stale straight away
+	if got := current(); !slices.Equal(got, []string{"member"}) {
+		t.Fatalf("roles = %v, want them resolved", got)
+	}
+
+	roles = []string{"admin"}
+	if got := current(); !slices.Equal(got, []string{"member"}) {
+		t.Errorf("roles = %v, want them kept until the ttl is up", got)
+	}
+
+	age := func() {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.AddCookie(cookie)
+		sess, _ = h.Sessions().Get(req)
+		sess.RolesResolvedAt = time.Now().Add(-2 * time.Minute)
+		if err := h.Sessions().Save(context.Background(), sess); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	age()
+	if got := current(); !slices.Equal(got, []string{"admin"}) {
+		t.Errorf("roles = %v, want them resolved again after the ttl", got)
+	}
+
+	age()
+	fail = true
+	if got := current(); !slices.Equal(got, []string{"admin"}) {
+		t.Errorf("roles = %v, want the old ones kept when resolving fails", got)
+	}
+}
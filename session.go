@@ -0,0 +1,183 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrNoSession is returned by Sessions.Get when the request doesn't have a valid session.
+var ErrNoSession = errors.New("no session")
+
+const (
+	// DefaultSessionCookieName is the name of the session cookie, unless Sessions.CookieName is changed.
+	DefaultSessionCookieName = "steam_session"
+	// DefaultSessionTTL is how long sessions last, unless Sessions.TTL is changed.
+	DefaultSessionTTL = 24 * time.Hour
+)
+
+// Session is a logged in steam user.
+type Session struct {
+	id string
+
+	SteamID string `json:"steamid"`
+	// User is a snapshot of the user's profile from when they logged in, if it was fetched.
+	User      *SteamUser `json:"user,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	// Tenant is the id of the tenant the session was started for, if it was started by a TenantRegistry.
+	Tenant string `json:"tenant,omitempty"`
+	// Device is the device the session was started on, if it was started by a Handler.
+	Device Device `json:"device,omitzero"`
+	// Roles are the user's roles, from the Handler's RoleResolver. See HasRole.
+	Roles []string `json:"roles,omitempty"`
+	// RolesResolvedAt is when Roles were last resolved.
+	RolesResolvedAt time.Time `json:"roles_resolved_at,omitzero"`
+	// ImpersonatedBy is the steamid64 of whoever started the session with Handler.Impersonate, and empty for real logins.
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
+	// Values is any extra data the app wants to keep in the session. Call Sessions.Save after changing it.
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// Sessions keeps users logged in with a cookie holding a random session id, and the session itself in a Store.
+type Sessions struct {
+	store Store
+
+	// CookieName defaults to DefaultSessionCookieName.
+	CookieName string
+	// TTL is how long a session lasts after login. Defaults to DefaultSessionTTL.
+	TTL time.Duration
+	// Path and Domain are set on the cookie. Path defaults to "/".
+	Path   string
+	Domain string
+	// Insecure stops the cookie being marked Secure. Only turn this on for local development over plain http.
+	Insecure bool
+}
+
+// NewSessions returns a new Sessions keeping sessions in store.
+func NewSessions(store Store) *Sessions {
+	return &Sessions{
+		store:      store,
+		CookieName: DefaultSessionCookieName,
+		TTL:        DefaultSessionTTL,
+		Path:       "/",
+	}
+}
+
+// Create starts a new session for steamid64 and sets its cookie on w.
+func (s *Sessions) Create(ctx context.Context, w http.ResponseWriter, steamid64 string, user *SteamUser) (*Session, error) {
+	return s.create(ctx, w, steamid64, user, Device{}, "")
+}
+
+// create is Create, recording the device and tenant the session was started on.
+func (s *Sessions) create(ctx context.Context, w http.ResponseWriter, steamid64 string, user *SteamUser, device Device, tenant string) (*Session, error) {
+	id, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("create session (%s): generate id: %w", steamid64, err)
+	}
+
+	now := time.Now()
+	sess := &Session{
+		id:        id,
+		SteamID:   steamid64,
+		User:      user,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.TTL),
+		Device:    device,
+		Tenant:    tenant,
+	}
+
+	if err := s.Save(ctx, sess); err != nil {
+		return nil, fmt.Errorf("create session (%s): %w", steamid64, err)
+	}
+
+	s.setCookie(w, id, sess.ExpiresAt)
+	return sess, nil
+}
+
+// Get returns the request's session, or ErrNoSession if it doesn't have one.
+func (s *Sessions) Get(r *http.Request) (*Session, error) {
+	c, err := r.Cookie(s.CookieName)
+	if err != nil || c.Value == "" {
+		return nil, ErrNoSession
+	}
+
+	b, err := s.store.Get(r.Context(), sessionKey(c.Value))
+	if errors.Is(err, ErrNotFound) {
+		return nil, ErrNoSession
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	var sess Session
+	if err := json.Unmarshal(b, &sess); err != nil {
+		return nil, fmt.Errorf("get session: decode: %w", err)
+	}
+	sess.id = c.Value
+
+	if time.Now().After(sess.ExpiresAt) {
+		return nil, ErrNoSession
+	}
+
+	return &sess, nil
+}
+
+// Save writes changes to sess back to the store. It doesn't extend the session, so saving one that has already
+// expired returns ErrNoSession.
+func (s *Sessions) Save(ctx context.Context, sess *Session) error {
+	// Stores keep values with a ttl <= 0 forever, which is the opposite of what an expired session should get.
+	ttl := time.Until(sess.ExpiresAt)
+	if ttl <= 0 {
+		return fmt.Errorf("save session: %w", ErrNoSession)
+	}
+
+	b, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("save session: encode: %w", err)
+	}
+
+	if err := s.store.Set(ctx, sessionKey(sess.id), b, ttl); err != nil {
+		return fmt.Errorf("save session: %w", err)
+	}
+
+	return nil
+}
+
+// Destroy ends the request's session, if it has one, and clears its cookie.
+func (s *Sessions) Destroy(w http.ResponseWriter, r *http.Request) error {
+	s.setCookie(w, "", time.Unix(0, 0))
+
+	c, err := r.Cookie(s.CookieName)
+	if err != nil || c.Value == "" {
+		return nil
+	}
+
+	if err := s.store.Delete(r.Context(), sessionKey(c.Value)); err != nil {
+		return fmt.Errorf("destroy session: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Sessions) setCookie(w http.ResponseWriter, value string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.CookieName,
+		Value:    value,
+		Path:     s.Path,
+		Domain:   s.Domain,
+		Expires:  expires,
+		Secure:   !s.Insecure,
+		HttpOnly: true,
+		// Lax, not strict, so the cookie is still sent when the user follows a link into the app.
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// sessionKey is where a session is kept in the store. The id is hashed, so the store can't be used to hijack sessions.
+func sessionKey(id string) string {
+	return "session:" + hashToken(id)
+}
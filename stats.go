@@ -0,0 +1,345 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PlayerAchievement is one of a game's achievements and whether the user has it, as represented in the response from
+// the GetPlayerAchievements web api.
+type PlayerAchievement struct {
+	// ApiName is the achievement's internal name.
+	ApiName string `json:"apiname"`
+	// Achieved is 1 if the user has the achievement.
+	Achieved int `json:"achieved"`
+	// UnlockTime is when the user got the achievement, as a unix timestamp.
+	UnlockTime int64 `json:"unlocktime"`
+	// Name and Description are in the language asked for. They're only set if a language was.
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Unlocked returns when the user got the achievement, and false if they haven't.
+func (a PlayerAchievement) Unlocked() (time.Time, bool) {
+	if a.Achieved == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(a.UnlockTime, 0), true
+}
+
+// PlayerAchievements is a user's achievements for a game.
+type PlayerAchievements struct {
+	SteamID      string              `json:"steamID"`
+	GameName     string              `json:"gameName"`
+	Achievements []PlayerAchievement `json:"achievements"`
+}
+
+// GetPlayerAchievements gets the user's achievements for a game. lang is the language to get achievement names and
+// descriptions in (ex. "english"), they're left out if it's "". Returns ErrPrivateProfile if the user's game details
+// are private.
+func (sa *SteamAuther) GetPlayerAchievements(ctx context.Context, steamid64 string, appid int, lang string) (*PlayerAchievements, error) {
+	q := url.Values{"steamid": {steamid64}, "appid": {strconv.Itoa(appid)}}
+	if lang != "" {
+		q.Set("l", lang)
+	}
+
+	var data struct {
+		PlayerStats struct {
+			PlayerAchievements
+			Success bool   `json:"success"`
+			Error   string `json:"error"`
+		} `json:"playerstats"`
+	}
+	if err := sa.getPlayerStatsApi(ctx, "ISteamUserStats/GetPlayerAchievements/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get player achievements (%s, %d): %w", steamid64, appid, err)
+	}
+
+	if err := playerStatsError(data.PlayerStats.Success, data.PlayerStats.Error); err != nil {
+		return nil, fmt.Errorf("get player achievements (%s, %d): %w", steamid64, appid, err)
+	}
+
+	return &data.PlayerStats.PlayerAchievements, nil
+}
+
+// getPlayerStatsApi is getApi for the ISteamUserStats methods about a user, which answer with a 400 or 403 when they
+// won't hand the user's stats over, with the reason in the body as a playerstats error.
+func (sa *SteamAuther) getPlayerStatsApi(ctx context.Context, method string, params url.Values, out any) error {
+	base, q := sa.apiQuery(WebApiBaseUrl, params)
+	res, err := sa.get(ctx, base+"/"+method, q, http.StatusBadRequest, http.StatusForbidden)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var data struct {
+			PlayerStats struct {
+				Error string `json:"error"`
+			} `json:"playerstats"`
+		}
+		// Anything else (like the page steam sends for a bad api key) is down to the status.
+		if err := json.NewDecoder(res.Body).Decode(&data); err != nil || data.PlayerStats.Error == "" {
+			return statusError(res)
+		}
+
+		return playerStatsError(false, data.PlayerStats.Error)
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// playerStatsError turns the success and error ISteamUserStats methods send back about a user into an error.
+func playerStatsError(success bool, msg string) error {
+	if success {
+		return nil
+	}
+
+	// Steam usually says this with a 403, but not always.
+	if strings.Contains(strings.ToLower(msg), "not public") {
+		return fmt.Errorf("%w (%s)", ErrPrivateProfile, msg)
+	}
+
+	return fmt.Errorf("steam said %q", msg)
+}
+
+// UserStat is one of a user's stats for a game.
+type UserStat struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// UserStatAchievement is an achievement the user has, as listed by GetUserStatsForGame.
+type UserStatAchievement struct {
+	Name     string `json:"name"`
+	Achieved int    `json:"achieved"`
+}
+
+// UserStats is a user's stats and achievements for a game, as represented in the response from the
+// GetUserStatsForGame web api. Names are the raw api names, see GetSchemaForGame for what they're shown as.
+type UserStats struct {
+	SteamID      string                `json:"steamID"`
+	GameName     string                `json:"gameName"`
+	Stats        []UserStat            `json:"stats"`
+	Achievements []UserStatAchievement `json:"achievements"`
+}
+
+// Stat returns the value of the stat called name, and false if the user doesn't have it.
+func (s *UserStats) Stat(name string) (float64, bool) {
+	for _, stat := range s.Stats {
+		if stat.Name == name {
+			return stat.Value, true
+		}
+	}
+
+	return 0, false
+}
+
+// GetUserStatsForGame gets the user's stats and achievements for a game. Returns ErrPrivateProfile if the user's game
+// details are private.
+func (sa *SteamAuther) GetUserStatsForGame(ctx context.Context, steamid64 string, appid int) (*UserStats, error) {
+	var data struct {
+		PlayerStats struct {
+			UserStats
+			// Steam only sends these when something's wrong.
+			Success *bool  `json:"success"`
+			Error   string `json:"error"`
+		} `json:"playerstats"`
+	}
+	q := url.Values{"steamid": {steamid64}, "appid": {strconv.Itoa(appid)}}
+	if err := sa.getPlayerStatsApi(ctx, "ISteamUserStats/GetUserStatsForGame/v2", q, &data); err != nil {
+		return nil, fmt.Errorf("get user stats for game (%s, %d): %w", steamid64, appid, err)
+	}
+
+	if s := data.PlayerStats.Success; s != nil {
+		if err := playerStatsError(*s, data.PlayerStats.Error); err != nil {
+			return nil, fmt.Errorf("get user stats for game (%s, %d): %w", steamid64, appid, err)
+		}
+	}
+
+	return &data.PlayerStats.UserStats, nil
+}
+
+// StatSchema is what a game's stat is called, as listed by GetSchemaForGame.
+type StatSchema struct {
+	// Name is the stat's api name.
+	Name         string  `json:"name"`
+	DefaultValue float64 `json:"defaultvalue"`
+	DisplayName  string  `json:"displayName"`
+}
+
+// AchievementSchema is what a game's achievement is called and looks like, as listed by GetSchemaForGame.
+type AchievementSchema struct {
+	// Name is the achievement's api name.
+	Name         string `json:"name"`
+	DefaultValue int    `json:"defaultvalue"`
+	DisplayName  string `json:"displayName"`
+	// Hidden is 1 for achievements that are secret until unlocked. Their Description is left out.
+	Hidden      int    `json:"hidden"`
+	Description string `json:"description"`
+	// Icon is the url of the achievement's icon, and IconGray the one shown while it's locked.
+	Icon     string `json:"icon"`
+	IconGray string `json:"icongray"`
+}
+
+// GameSchema is a game's stats and achievements, as represented in the response from the GetSchemaForGame web api.
+type GameSchema struct {
+	GameName     string              `json:"gameName"`
+	GameVersion  string              `json:"gameVersion"`
+	Stats        []StatSchema        `json:"-"`
+	Achievements []AchievementSchema `json:"-"`
+}
+
+// Achievement returns the achievement with the api name, and false if the game doesn't have it.
+func (s *GameSchema) Achievement(name string) (*AchievementSchema, bool) {
+	for i, a := range s.Achievements {
+		if a.Name == name {
+			return &s.Achievements[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// GetSchemaForGame gets the names, descriptions and icons of a game's stats and achievements, for showing the api
+// names GetPlayerAchievements and GetUserStatsForGame return. lang is the language to get them in (ex. "english"),
+// "" leaves it up to steam. Games without stats or achievements get an empty schema.
+func (sa *SteamAuther) GetSchemaForGame(ctx context.Context, appid int, lang string) (*GameSchema, error) {
+	q := url.Values{"appid": {strconv.Itoa(appid)}}
+	if lang != "" {
+		q.Set("l", lang)
+	}
+
+	var data struct {
+		Game struct {
+			GameSchema
+			AvailableGameStats struct {
+				Stats        []StatSchema        `json:"stats"`
+				Achievements []AchievementSchema `json:"achievements"`
+			} `json:"availableGameStats"`
+		} `json:"game"`
+	}
+	if err := sa.getApi(ctx, "ISteamUserStats/GetSchemaForGame/v2", q, &data); err != nil {
+		return nil, fmt.Errorf("get schema for game (%d): %w", appid, err)
+	}
+
+	schema := &data.Game.GameSchema
+	schema.Stats = data.Game.AvailableGameStats.Stats
+	schema.Achievements = data.Game.AvailableGameStats.Achievements
+
+	return schema, nil
+}
+
+// AchievementPercentage is how many players have one of a game's achievements.
+type AchievementPercentage struct {
+	// Name is the achievement's api name.
+	Name string
+	// Percent is the percentage of players that have it, from 0 to 100.
+	Percent float64
+}
+
+// GetGlobalAchievementPercentagesForApp gets what percentage of players have each of a game's achievements, for
+// showing how rare they are. They're sorted from most to least common.
+func (sa *SteamAuther) GetGlobalAchievementPercentagesForApp(ctx context.Context, appid int) ([]AchievementPercentage, error) {
+	var data struct {
+		AchievementPercentages struct {
+			Achievements []struct {
+				Name string `json:"name"`
+				// Steam has sent this as both a number and a string.
+				Percent json.Number `json:"percent"`
+			} `json:"achievements"`
+		} `json:"achievementpercentages"`
+	}
+	q := url.Values{"gameid": {strconv.Itoa(appid)}}
+	if err := sa.getApi(ctx, "ISteamUserStats/GetGlobalAchievementPercentagesForApp/v2", q, &data); err != nil {
+		return nil, fmt.Errorf("get global achievement percentages (%d): %w", appid, err)
+	}
+
+	percentages := make([]AchievementPercentage, len(data.AchievementPercentages.Achievements))
+	for i, a := range data.AchievementPercentages.Achievements {
+		percent, err := a.Percent.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("get global achievement percentages (%d): parse percent of %s: %w", appid, a.Name, err)
+		}
+		percentages[i] = AchievementPercentage{Name: a.Name, Percent: percent}
+	}
+
+	return percentages, nil
+}
+
+// GlobalStat is the total of one of a game's global stats, as represented in the response from the
+// GetGlobalStatsForGame web api.
+type GlobalStat struct {
+	Total int64 `json:"total,string"`
+	// History is the stat's daily totals, only set if a date range was asked for.
+	History []GlobalStatDay `json:"history"`
+}
+
+// GlobalStatDay is a global stat's total for a day.
+type GlobalStatDay struct {
+	// Date is the day, as a unix timestamp.
+	Date  int64 `json:"date"`
+	Total int64 `json:"total,string"`
+}
+
+// GetGlobalStatsForGame gets the totals of a game's global stats, keyed by name. The stats have to be marked as
+// aggregated in the game's stats config. If from and to aren't zero, the daily totals between them are returned too.
+func (sa *SteamAuther) GetGlobalStatsForGame(ctx context.Context, appid int, names []string, from, to time.Time) (map[string]GlobalStat, error) {
+	q := url.Values{"appid": {strconv.Itoa(appid)}, "count": {strconv.Itoa(len(names))}}
+	for i, name := range names {
+		q.Set("name["+strconv.Itoa(i)+"]", name)
+	}
+	if !from.IsZero() {
+		q.Set("startdate", strconv.FormatInt(from.Unix(), 10))
+	}
+	if !to.IsZero() {
+		q.Set("enddate", strconv.FormatInt(to.Unix(), 10))
+	}
+
+	var data struct {
+		Response struct {
+			Result      int                   `json:"result"`
+			Error       string                `json:"error"`
+			GlobalStats map[string]GlobalStat `json:"globalstats"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "ISteamUserStats/GetGlobalStatsForGame/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get global stats for game (%d): %w", appid, err)
+	}
+
+	if data.Response.Result != 1 {
+		return nil, fmt.Errorf("get global stats for game (%d): steam said %q", appid, data.Response.Error)
+	}
+
+	return data.Response.GlobalStats, nil
+}
+
+// GetNumberOfCurrentPlayers gets how many people are playing a game right now.
+func (sa *SteamAuther) GetNumberOfCurrentPlayers(ctx context.Context, appid int) (int, error) {
+	var data struct {
+		Response struct {
+			PlayerCount int `json:"player_count"`
+			Result      int `json:"result"`
+		} `json:"response"`
+	}
+	q := url.Values{"appid": {strconv.Itoa(appid)}}
+	if err := sa.getApi(ctx, "ISteamUserStats/GetNumberOfCurrentPlayers/v1", q, &data); err != nil {
+		return 0, fmt.Errorf("get number of current players (%d): %w", appid, err)
+	}
+
+	if data.Response.Result != 1 {
+		return 0, fmt.Errorf("get number of current players (%d): %w", appid, ErrNoData)
+	}
+
+	return data.Response.PlayerCount, nil
+}
@@ -0,0 +1,284 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// playerAchievements answers GetPlayerAchievements like steam would: 76561197960287930 has one of tf2's two
+// achievements, names only come back when a language is asked for, and anyone else is private.
+func playerAchievements(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/ISteamUserStats/GetPlayerAchievements/v1" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.URL.Query().Get("steamid") != "76561197960287930" {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"playerstats":{"error":"Profile is not public","success":false}}`))
+		return
+	}
+
+	name := ""
+	if r.URL.Query().Get("l") == "english" {
+		name = `,"name":"Head of the Class"`
+	}
+	w.Write([]byte(`{"playerstats":{"steamID":"76561197960287930","gameName":"Team Fortress 2","achievements":[
+		{"apiname":"TF_PLAY_GAME_EVERYCLASS","achieved":1,"unlocktime":1700000000` + name + `},
+		{"apiname":"TF_KILL_NEMESIS","achieved":0,"unlocktime":0` + name + `}
+	],"success":true}}`))
+}
+
+func TestGetPlayerAchievements(t *testing.T) {
+	fakeSteam(t, playerAchievements)
+	sa := New("key", "https://example.com")
+
+	a, err := sa.GetPlayerAchievements(context.Background(), "76561197960287930", 440, "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.GameName != "Team Fortress 2" || len(a.Achievements) != 2 || a.Achievements[0].Name != "Head of the Class" {
+		t.Fatalf("achievements = %+v, want tf2's, named", a)
+	}
+	if at, ok := a.Achievements[0].Unlocked(); !ok || !at.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Unlocked = %v, %v, want when it was unlocked", at, ok)
+	}
+	if _, ok := a.Achievements[1].Unlocked(); ok {
+		t.Error("Unlocked ok for an achievement the user doesn't have, want false")
+	}
+
+	a, err = sa.GetPlayerAchievements(context.Background(), "76561197960287930", 440, "")
+	if err != nil || a.Achievements[0].Name != "" {
+		t.Errorf("without a language = %+v, %v, want the names left out", a, err)
+	}
+}
+
+func TestPlayerStatsErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantPrivate bool
+		wantErr     error
+	}{
+		{"private profile", http.StatusForbidden, `{"playerstats":{"error":"Profile is not public","success":false}}`, true, ErrPrivateProfile},
+		{"private profile with a 200", http.StatusOK, `{"playerstats":{"error":"Profile is not public","success":false}}`, true, ErrPrivateProfile},
+		{"no stats", http.StatusBadRequest, `{"playerstats":{"error":"Requested app has no stats","success":false}}`, false, nil},
+		{"bad api key", http.StatusForbidden, `<html><body>Forbidden</body></html>`, false, ErrSteamForbidden},
+		{"steam is down", http.StatusServiceUnavailable, ``, false, ErrSteamUnavailable},
+	}
+
+	sa := New("key", "https://example.com")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			_, err := sa.GetPlayerAchievements(context.Background(), "76561197960287930", 440, "")
+			if err == nil {
+				t.Fatal("err = nil, want an error")
+			}
+			if got := errors.Is(err, ErrPrivateProfile); got != tt.wantPrivate {
+				t.Errorf("errors.Is(%v, ErrPrivateProfile) = %v, want %v", err, got, tt.wantPrivate)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetUserStatsForGame(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUserStats/GetUserStatsForGame/v2" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.URL.Query().Get("steamid") != "76561197960287930" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"playerstats":{"error":"Profile is not public","success":false}}`))
+			return
+		}
+		w.Write([]byte(`{"playerstats":{"steamID":"76561197960287930","gameName":"Team Fortress 2",
+			"stats":[{"name":"Scout.accum.iNumberOfKills","value":1234},{"name":"Scout.max.iPointsScored","value":42.5}],
+			"achievements":[{"name":"TF_PLAY_GAME_EVERYCLASS","achieved":1}]}}`))
+	})
+	sa := New("key", "https://example.com")
+
+	s, err := sa.GetUserStatsForGame(context.Background(), "76561197960287930", 440)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.GameName != "Team Fortress 2" || len(s.Achievements) != 1 || s.Achievements[0].Achieved != 1 {
+		t.Errorf("stats = %+v, want tf2's", s)
+	}
+	if v, ok := s.Stat("Scout.max.iPointsScored"); !ok || v != 42.5 {
+		t.Errorf("Stat = %v, %v, want 42.5, true", v, ok)
+	}
+	if _, ok := s.Stat("Spy.accum.iBackstabs"); ok {
+		t.Error("Stat ok for a stat the user doesn't have, want false")
+	}
+
+	if _, err := sa.GetUserStatsForGame(context.Background(), "76561197960287931", 440); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private profile err = %v, want ErrPrivateProfile", err)
+	}
+}
+
+func TestGetSchemaForGame(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUserStats/GetSchemaForGame/v2" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Query().Get("appid") {
+		case "440":
+			name := "Head of the Class"
+			if r.URL.Query().Get("l") == "french" {
+				name = "Premier de la classe"
+			}
+			w.Write([]byte(`{"game":{"gameName":"Team Fortress 2","gameVersion":"123","availableGameStats":{
+				"stats":[{"name":"Scout.accum.iNumberOfKills","defaultvalue":0,"displayName":""}],
+				"achievements":[
+					{"name":"TF_PLAY_GAME_EVERYCLASS","defaultvalue":0,"displayName":"` + name + `","hidden":0,"description":"Play a complete round with every class.","icon":"https://example.com/icon.jpg","icongray":"https://example.com/gray.jpg"},
+					{"name":"TF_SECRET","defaultvalue":0,"displayName":"Secret","hidden":1,"icon":"https://example.com/secret.jpg","icongray":"https://example.com/secret_gray.jpg"}
+				]}}}`))
+		default:
+			// steam's answer for games without stats
+			w.Write([]byte(`{"game":{}}`))
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	s, err := sa.GetSchemaForGame(context.Background(), 440, "french")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.GameName != "Team Fortress 2" || len(s.Stats) != 1 || len(s.Achievements) != 2 {
+		t.Fatalf("schema = %+v, want tf2's stat and achievements", s)
+	}
+	a, ok := s.Achievement("TF_PLAY_GAME_EVERYCLASS")
+	if !ok || a.DisplayName != "Premier de la classe" || a.IconGray == "" {
+		t.Errorf("Achievement = %+v, %v, want it in french", a, ok)
+	}
+	if a, ok := s.Achievement("TF_SECRET"); !ok || a.Hidden != 1 || a.Description != "" {
+		t.Errorf("Achievement = %+v, %v, want the hidden one without a description", a, ok)
+	}
+	if _, ok := s.Achievement("TF_NOPE"); ok {
+		t.Error("Achievement ok for one the game doesn't have, want false")
+	}
+
+	s, err = sa.GetSchemaForGame(context.Background(), 4000, "")
+	if err != nil || len(s.Stats) != 0 || len(s.Achievements) != 0 {
+		t.Errorf("game without stats = %+v, %v, want an empty schema", s, err)
+	}
+}
+
+func TestGetGlobalAchievementPercentagesForApp(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUserStats/GetGlobalAchievementPercentagesForApp/v2" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Query().Get("gameid") {
+		case "440":
+			// steam has sent percent as both
+			w.Write([]byte(`{"achievementpercentages":{"achievements":[{"name":"TF_PLAY_GAME_EVERYCLASS","percent":"61.5"},{"name":"TF_KILL_NEMESIS","percent":2.3}]}}`))
+		default:
+			w.Write([]byte(`{"achievementpercentages":{"achievements":[{"name":"BROKEN","percent":"lots"}]}}`))
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	got, err := sa.GetGlobalAchievementPercentagesForApp(context.Background(), 440)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []AchievementPercentage{{"TF_PLAY_GAME_EVERYCLASS", 61.5}, {"TF_KILL_NEMESIS", 2.3}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("percentages = %+v, want %+v", got, want)
+	}
+
+	if _, err := sa.GetGlobalAchievementPercentagesForApp(context.Background(), 4000); err == nil {
+		t.Error("err = nil for a percent that isn't a number, want an error")
+	}
+}
+
+func TestGetGlobalStatsForGame(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/ISteamUserStats/GetGlobalStatsForGame/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if q.Get("appid") != "440" || q.Get("count") != "2" || q.Get("name[0]") != "kills" || q.Get("name[1]") != "deaths" {
+			w.Write([]byte(`{"response":{"result":8,"error":"Missing or invalid stat names"}}`))
+			return
+		}
+		if q.Get("startdate") == "" {
+			w.Write([]byte(`{"response":{"result":1,"globalstats":{"kills":{"total":"1000"},"deaths":{"total":"900"}}}}`))
+			return
+		}
+		if q.Get("startdate") != "1700000000" || q.Get("enddate") != "1700172800" {
+			http.Error(w, "wrong dates", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"response":{"result":1,"globalstats":{
+			"kills":{"total":"1000","history":[{"date":1700000000,"total":"600"},{"date":1700086400,"total":"400"}]},
+			"deaths":{"total":"900"}
+		}}}`))
+	})
+	sa := New("key", "https://example.com")
+
+	stats, err := sa.GetGlobalStatsForGame(context.Background(), 440, []string{"kills", "deaths"}, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats["kills"].Total != 1000 || stats["deaths"].Total != 900 || stats["kills"].History != nil {
+		t.Errorf("stats = %+v, want the totals without history", stats)
+	}
+
+	from := time.Unix(1700000000, 0)
+	stats, err = sa.GetGlobalStatsForGame(context.Background(), 440, []string{"kills", "deaths"}, from, from.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := stats["kills"].History; len(h) != 2 || h[0].Total != 600 || h[1].Date != 1700086400 {
+		t.Errorf("history = %+v, want the daily totals", h)
+	}
+
+	if _, err := sa.GetGlobalStatsForGame(context.Background(), 440, []string{"nope"}, time.Time{}, time.Time{}); err == nil {
+		t.Error("err = nil for a stat the game doesn't aggregate, want an error")
+	}
+}
+
+func TestGetNumberOfCurrentPlayers(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUserStats/GetNumberOfCurrentPlayers/v1" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if r.URL.Query().Get("appid") == "440" {
+			w.Write([]byte(`{"response":{"player_count":53421,"result":1}}`))
+			return
+		}
+		w.Write([]byte(`{"response":{"result":42}}`))
+	})
+	sa := New("key", "https://example.com")
+
+	if n, err := sa.GetNumberOfCurrentPlayers(context.Background(), 440); err != nil || n != 53421 {
+		t.Errorf("GetNumberOfCurrentPlayers = %d, %v, want 53421", n, err)
+	}
+	if _, err := sa.GetNumberOfCurrentPlayers(context.Background(), 4000); !errors.Is(err, ErrNoData) {
+		t.Errorf("unknown app err = %v, want ErrNoData", err)
+	}
+}
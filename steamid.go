@@ -0,0 +1,422 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidSteamID is returned when parsing a steamid that isn't one.
+var ErrInvalidSteamID = errors.New("invalid steamid")
+
+// Universe is the steam universe an account is in. Everything real is in UniversePublic.
+type Universe uint8
+
+const (
+	UniverseInvalid  Universe = 0
+	UniversePublic   Universe = 1
+	UniverseBeta     Universe = 2
+	UniverseInternal Universe = 3
+	UniverseDev      Universe = 4
+)
+
+// AccountType is the kind of account a steamid is for.
+type AccountType uint8
+
+const (
+	AccountTypeInvalid        AccountType = 0
+	AccountTypeIndividual     AccountType = 1
+	AccountTypeMultiseat      AccountType = 2
+	AccountTypeGameServer     AccountType = 3
+	AccountTypeAnonGameServer AccountType = 4
+	AccountTypePending        AccountType = 5
+	AccountTypeContentServer  AccountType = 6
+	AccountTypeClan           AccountType = 7
+	AccountTypeChat           AccountType = 8
+	AccountTypeConsoleUser    AccountType = 9
+	AccountTypeAnonUser       AccountType = 10
+)
+
+// InstanceDesktop is the instance of every user's steamid. Clans are instance 0.
+const InstanceDesktop uint32 = 1
+
+// SteamID is a steamid, in its 64-bit form. The web api and the rest of the package pass steamid64s around as
+// strings, String and ParseSteamID64 go between the two.
+//
+// From the top, the bits are the universe (8), account type (4), instance (20) and account id (32).
+type SteamID uint64
+
+// NewSteamID returns the steamid made up of the parts.
+func NewSteamID(universe Universe, accountType AccountType, instance, accountId uint32) SteamID {
+	return SteamID(uint64(universe)<<56 | uint64(accountType&0xf)<<52 | uint64(instance&0xfffff)<<32 | uint64(accountId))
+}
+
+// NewIndividualSteamID returns the steamid of the user with the account id, in the public universe.
+func NewIndividualSteamID(accountId uint32) SteamID {
+	return NewSteamID(UniversePublic, AccountTypeIndividual, InstanceDesktop, accountId)
+}
+
+// NewClanSteamID returns the steamid of the group with the account id, in the public universe.
+func NewClanSteamID(accountId uint32) SteamID {
+	return NewSteamID(UniversePublic, AccountTypeClan, 0, accountId)
+}
+
+// ParseSteamID64 parses a steamid64 (ex. "76561197960287930"). Returns ErrInvalidSteamID if it isn't a number, or
+// its universe or account type don't exist. For stricter checks, see IsValid.
+func ParseSteamID64(s string) (SteamID, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse steamid64 %q: %w", s, ErrInvalidSteamID)
+	}
+
+	id := SteamID(n)
+	if id.Universe() == UniverseInvalid || id.Universe() > UniverseDev {
+		return 0, fmt.Errorf("parse steamid64 %q: %w: unknown universe %d", s, ErrInvalidSteamID, id.Universe())
+	}
+	if id.AccountType() == AccountTypeInvalid || id.AccountType() > AccountTypeAnonUser {
+		return 0, fmt.Errorf("parse steamid64 %q: %w: unknown account type %d", s, ErrInvalidSteamID, id.AccountType())
+	}
+
+	return id, nil
+}
+
+// ParseSteamID2 parses a user's legacy steamid (ex. "STEAM_0:0:11101"), which goldsrc and source servers still use.
+// The leading number is the universe, but older games always put 0 there, so both 0 and 1 mean UniversePublic.
+func ParseSteamID2(s string) (SteamID, error) {
+	rest, ok := strings.CutPrefix(strings.ToUpper(strings.TrimSpace(s)), "STEAM_")
+	parts := strings.Split(rest, ":")
+	if !ok || len(parts) != 3 {
+		return 0, fmt.Errorf("parse steamid2 %q: %w", s, ErrInvalidSteamID)
+	}
+
+	universe, err1 := strconv.ParseUint(parts[0], 10, 8)
+	y, err2 := strconv.ParseUint(parts[1], 10, 1)
+	z, err3 := strconv.ParseUint(parts[2], 10, 31)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return 0, fmt.Errorf("parse steamid2 %q: %w", s, ErrInvalidSteamID)
+	}
+	if universe == 0 {
+		universe = uint64(UniversePublic)
+	}
+	if universe > uint64(UniverseDev) {
+		return 0, fmt.Errorf("parse steamid2 %q: %w: unknown universe %d", s, ErrInvalidSteamID, universe)
+	}
+
+	return NewSteamID(Universe(universe), AccountTypeIndividual, InstanceDesktop, uint32(z<<1|y)), nil
+}
+
+// SteamID2 returns the legacy form of a user's steamid (ex. "STEAM_0:0:11101"). The public universe is written as 0,
+// like goldsrc and source games do (newer ones, like cs:go, write 1, ParseSteamID2 takes either).
+func (id SteamID) SteamID2() string {
+	universe := id.Universe()
+	if universe == UniversePublic {
+		universe = 0
+	}
+
+	return fmt.Sprintf("STEAM_%d:%d:%d", universe, id.AccountID()&1, id.AccountID()>>1)
+}
+
+// steam3Letters are the letters steam3 ids use for each account type.
+var steam3Letters = map[AccountType]byte{
+	AccountTypeInvalid:        'I',
+	AccountTypeIndividual:     'U',
+	AccountTypeMultiseat:      'M',
+	AccountTypeGameServer:     'G',
+	AccountTypeAnonGameServer: 'A',
+	AccountTypePending:        'P',
+	AccountTypeContentServer:  'C',
+	AccountTypeClan:           'g',
+	AccountTypeChat:           'T',
+	AccountTypeAnonUser:       'a',
+}
+
+// Chat steamids use the top bits of the instance to say what the chat is for, which steam3 ids show with their own
+// letters.
+const (
+	chatInstanceFlagClan  uint32 = 0x80000
+	chatInstanceFlagLobby uint32 = 0x40000
+)
+
+// ParseSteam3 parses a steam3 id (ex. "[U:1:22202]" or "[g:1:4]"), for any account type. The brackets can be left
+// off, and the instance can be given as a fourth part (ex. "[A:1:123:456]"). Without one, users are InstanceDesktop
+// and everything else is instance 0.
+func ParseSteam3(s string) (SteamID, error) {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "[") != strings.HasSuffix(trimmed, "]") {
+		return 0, fmt.Errorf("parse steam3 %q: %w", s, ErrInvalidSteamID)
+	}
+	parts := strings.Split(strings.Trim(trimmed, "[]"), ":")
+	if len(parts) != 3 && len(parts) != 4 || len(parts[0]) != 1 {
+		return 0, fmt.Errorf("parse steam3 %q: %w", s, ErrInvalidSteamID)
+	}
+
+	var accountType AccountType
+	var instance uint32
+	switch letter := parts[0][0]; letter {
+	case 'c':
+		accountType, instance = AccountTypeChat, chatInstanceFlagClan
+	case 'L':
+		accountType, instance = AccountTypeChat, chatInstanceFlagLobby
+	default:
+		found := false
+		for t, l := range steam3Letters {
+			if l == letter {
+				accountType, found = t, true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("parse steam3 %q: %w: unknown account type %q", s, ErrInvalidSteamID, letter)
+		}
+	}
+	if accountType == AccountTypeIndividual {
+		instance = InstanceDesktop
+	}
+
+	universe, err1 := strconv.ParseUint(parts[1], 10, 8)
+	accountId, err2 := strconv.ParseUint(parts[2], 10, 32)
+	if err := errors.Join(err1, err2); err != nil {
+		return 0, fmt.Errorf("parse steam3 %q: %w", s, ErrInvalidSteamID)
+	}
+	if universe > uint64(UniverseDev) {
+		return 0, fmt.Errorf("parse steam3 %q: %w: unknown universe %d", s, ErrInvalidSteamID, universe)
+	}
+	if len(parts) == 4 {
+		n, err := strconv.ParseUint(parts[3], 10, 20)
+		if err != nil {
+			return 0, fmt.Errorf("parse steam3 %q: %w", s, ErrInvalidSteamID)
+		}
+		instance = uint32(n)
+	}
+
+	return NewSteamID(Universe(universe), accountType, instance, uint32(accountId)), nil
+}
+
+// Steam3 returns the steam3 form of the steamid (ex. "[U:1:22202]"). The instance is only written when it can't be
+// worked out from the account type, so ParseSteam3 gets the same steamid back (for every account type steam3 has a
+// letter for).
+func (id SteamID) Steam3() string {
+	accountType, instance := id.AccountType(), id.Instance()
+
+	letter, ok := steam3Letters[accountType]
+	if !ok {
+		letter = 'i'
+	}
+
+	var defaultInstance uint32
+	switch {
+	case accountType == AccountTypeIndividual:
+		defaultInstance = InstanceDesktop
+	case accountType == AccountTypeChat && instance&chatInstanceFlagClan != 0:
+		letter, defaultInstance = 'c', chatInstanceFlagClan
+	case accountType == AccountTypeChat && instance&chatInstanceFlagLobby != 0:
+		letter, defaultInstance = 'L', chatInstanceFlagLobby
+	}
+
+	if !ok || instance != defaultInstance || accountType == AccountTypeAnonGameServer || accountType == AccountTypeMultiseat {
+		return fmt.Sprintf("[%c:%d:%d:%d]", letter, id.Universe(), id.AccountID(), instance)
+	}
+
+	return fmt.Sprintf("[%c:%d:%d]", letter, id.Universe(), id.AccountID())
+}
+
+// SteamIDFromAccountID returns the steamid of the account with the 32-bit account id (the short form databases and
+// game mods often store), given the universe and account type it's in. Users get InstanceDesktop, everything else
+// instance 0.
+func SteamIDFromAccountID(accountId uint32, universe Universe, accountType AccountType) SteamID {
+	var instance uint32
+	if accountType == AccountTypeIndividual {
+		instance = InstanceDesktop
+	}
+
+	return NewSteamID(universe, accountType, instance, accountId)
+}
+
+// AccountId turns a user's id, either their steamid64 or their account id, into the account id (the 32-bit id some
+// community pages and trade offers use).
+func AccountId(steamid string) (string, error) {
+	id, err := parseIndividualID(steamid)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatUint(uint64(id.AccountID()), 10), nil
+}
+
+// IndividualSteamID64 turns a user's id, either their account id or their steamid64, into the steamid64 (in the public
+// universe).
+func IndividualSteamID64(steamid string) (string, error) {
+	id, err := parseIndividualID(steamid)
+	if err != nil {
+		return "", err
+	}
+
+	return id.String(), nil
+}
+
+// parseIndividualID parses a user's id, either their account id or their steamid64.
+func parseIndividualID(steamid string) (SteamID, error) {
+	if n, err := strconv.ParseUint(steamid, 10, 32); err == nil {
+		return NewIndividualSteamID(uint32(n)), nil
+	}
+
+	if !isIndividualSteamID64(steamid) {
+		return 0, fmt.Errorf("parse steamid %q: %w: not a user's steamid64", steamid, ErrInvalidSteamID)
+	}
+
+	return ParseSteamID64(steamid)
+}
+
+// ErrVanityName is returned by ParseSteamID when it's given a vanity name (or vanity profile url), which can only be
+// turned into a steamid by asking steam. See SteamAuther.ResolveSteamID.
+var ErrVanityName = errors.New("the id is a vanity name")
+
+// ParseSteamID parses a steamid in any of the forms people paste into admin tools: a steamid64, a steamid2
+// ("STEAM_0:0:11101"), a steam3 id ("[U:1:22202]") or a profile url (https://steamcommunity.com/profiles/<steamid64>).
+// Vanity names and vanity profile urls return ErrVanityName, resolve those with SteamAuther.ResolveSteamID.
+func ParseSteamID(s string) (SteamID, error) {
+	id, vanity, err := parseSteamID(s)
+	if err != nil {
+		return 0, err
+	}
+	if vanity != "" {
+		return 0, fmt.Errorf("parse steamid %q: %w", s, ErrVanityName)
+	}
+
+	return id, nil
+}
+
+// ResolveSteamID is ParseSteamID, resolving vanity names and vanity profile urls with ResolveVanityURL (which costs a
+// web api request).
+func (sa *SteamAuther) ResolveSteamID(ctx context.Context, s string) (SteamID, error) {
+	id, vanity, err := parseSteamID(s)
+	if err != nil || vanity == "" {
+		return id, err
+	}
+
+	steamid64, err := sa.ResolveVanityURL(ctx, vanity)
+	if err != nil {
+		return 0, fmt.Errorf("resolve steamid %q: %w", s, err)
+	}
+
+	return ParseSteamID64(steamid64)
+}
+
+// vanityNameRe is what steam allows in custom profile urls.
+var vanityNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]{2,32}$`)
+
+// parseSteamID parses s as any kind of steamid, returning the vanity name instead if it's one of those.
+func parseSteamID(s string) (id SteamID, vanity string, err error) {
+	trimmed := strings.TrimSpace(s)
+
+	switch {
+	case len(trimmed) >= 6 && strings.EqualFold(trimmed[:6], "STEAM_"):
+		id, err = ParseSteamID2(trimmed)
+		return id, "", err
+	case strings.HasPrefix(trimmed, "["):
+		id, err = ParseSteam3(trimmed)
+		return id, "", err
+	}
+
+	if kind, v, ok := splitProfileURL(trimmed); ok {
+		if kind == "id" {
+			return 0, v, nil
+		}
+		id, err = ParseSteamID64(v)
+		return id, "", err
+	}
+
+	if id, err := ParseSteamID64(trimmed); err == nil && id.IsValid() {
+		return id, "", nil
+	}
+
+	// Anything else that could be a custom url is taken to be one, numbers included (steam allows all digit ones).
+	if vanityNameRe.MatchString(trimmed) {
+		return 0, trimmed, nil
+	}
+
+	return 0, "", fmt.Errorf("parse steamid %q: %w", s, ErrInvalidSteamID)
+}
+
+// AccountID returns the 32-bit account id.
+func (id SteamID) AccountID() uint32 {
+	return uint32(id)
+}
+
+// Instance returns the instance, which is InstanceDesktop for users.
+func (id SteamID) Instance() uint32 {
+	return uint32(id>>32) & 0xfffff
+}
+
+// AccountType returns the kind of account the steamid is for.
+func (id SteamID) AccountType() AccountType {
+	return AccountType(id>>52) & 0xf
+}
+
+// Universe returns the universe the account is in.
+func (id SteamID) Universe() Universe {
+	return Universe(id >> 56)
+}
+
+// IsValid reports whether the steamid could be a real account: a known universe and account type, a non zero account
+// id for users, groups and game servers, and an instance that makes sense for users and groups.
+func (id SteamID) IsValid() bool {
+	if id.Universe() == UniverseInvalid || id.Universe() > UniverseDev {
+		return false
+	}
+
+	switch id.AccountType() {
+	case AccountTypeInvalid:
+		return false
+	case AccountTypeIndividual:
+		// Users are desktop (1), console (2) or web (4), or 0 for all of them.
+		return id.AccountID() != 0 && id.Instance() <= 4
+	case AccountTypeClan:
+		return id.AccountID() != 0 && id.Instance() == 0
+	case AccountTypeGameServer:
+		return id.AccountID() != 0
+	}
+
+	return id.AccountType() <= AccountTypeAnonUser
+}
+
+// IsIndividual reports whether the steamid is a user's.
+func (id SteamID) IsIndividual() bool {
+	return id.AccountType() == AccountTypeIndividual
+}
+
+// IsClan reports whether the steamid is a group's.
+func (id SteamID) IsClan() bool {
+	return id.AccountType() == AccountTypeClan
+}
+
+// IsGameServer reports whether the steamid is a game server's, either a persistent (logged in with a login token) or
+// an anonymous one.
+func (id SteamID) IsGameServer() bool {
+	return id.AccountType() == AccountTypeGameServer || id.AccountType() == AccountTypeAnonGameServer
+}
+
+// String returns the steamid64, the form the web api uses.
+func (id SteamID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// MarshalText marshals the steamid as its steamid64, so it's a string in json (a number would lose precision in
+// javascript).
+func (id SteamID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText parses a steamid64, see ParseSteamID64.
+func (id *SteamID) UnmarshalText(text []byte) error {
+	parsed, err := ParseSteamID64(string(text))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+	return nil
+}
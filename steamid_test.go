@@ -0,0 +1,398 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseSteamID64(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    SteamID
+		wantErr error
+	}{
+		{"76561197960287930", 76561197960287930, nil},
+		{"103582791429521412", 103582791429521412, nil},
+		{"85568392920039429", 85568392920039429, nil}, // a game server
+		{"22202", 0, ErrInvalidSteamID},               // an account id, universe 0
+		{"1152921504606846976", 0, ErrInvalidSteamID}, // universe 16
+		{"72057594037927936", 0, ErrInvalidSteamID},   // account type 0
+		{"-76561197960287930", 0, ErrInvalidSteamID},
+		{"abc", 0, ErrInvalidSteamID},
+		{"", 0, ErrInvalidSteamID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSteamID64(tt.in)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSteamID64(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSteamIDParts(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          SteamID
+		want        SteamID
+		universe    Universe
+		accountType AccountType
+		instance    uint32
+		accountId   uint32
+	}{
+		{"individual", NewIndividualSteamID(22202), 76561197960287930, UniversePublic, AccountTypeIndividual, InstanceDesktop, 22202},
+		{"clan", NewClanSteamID(4), 103582791429521412, UniversePublic, AccountTypeClan, 0, 4},
+		{"game server", NewSteamID(UniversePublic, AccountTypeGameServer, 0, 5), 85568392920039429, UniversePublic, AccountTypeGameServer, 0, 5},
+		{"beta", NewSteamID(UniverseBeta, AccountTypeIndividual, InstanceDesktop, 1), 148618791998193665, UniverseBeta, AccountTypeIndividual, InstanceDesktop, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.id != tt.want {
+				t.Fatalf("id = %d, want %d", tt.id, tt.want)
+			}
+			if tt.id.Universe() != tt.universe || tt.id.AccountType() != tt.accountType || tt.id.Instance() != tt.instance || tt.id.AccountID() != tt.accountId {
+				t.Errorf("parts = %d, %d, %d, %d, want %d, %d, %d, %d", tt.id.Universe(), tt.id.AccountType(), tt.id.Instance(), tt.id.AccountID(),
+					tt.universe, tt.accountType, tt.instance, tt.accountId)
+			}
+		})
+	}
+}
+
+func TestSteamIDJSON(t *testing.T) {
+	b, err := json.Marshal(map[string]SteamID{"steamid": 76561197960287930})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"steamid":"76561197960287930"}` {
+		t.Errorf("json = %s, want the steamid64 as a string", b)
+	}
+
+	var out map[string]SteamID
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["steamid"] != 76561197960287930 {
+		t.Errorf("steamid = %d, want 76561197960287930", out["steamid"])
+	}
+
+	if err := json.Unmarshal([]byte(`{"steamid":"22202"}`), &out); !errors.Is(err, ErrInvalidSteamID) {
+		t.Errorf("unmarshal err = %v, want ErrInvalidSteamID", err)
+	}
+}
+
+func TestParseSteamID2(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    SteamID
+		wantErr error
+	}{
+		{"STEAM_0:0:11101", 76561197960287930, nil},
+		{"STEAM_1:0:11101", 76561197960287930, nil},
+		{" steam_0:0:11101 ", 76561197960287930, nil},
+		{"STEAM_0:1:11101", 76561197960287931, nil},
+		{"STEAM_2:0:11101", NewSteamID(UniverseBeta, AccountTypeIndividual, InstanceDesktop, 22202), nil},
+		{"STEAM_0:2:11101", 0, ErrInvalidSteamID},
+		{"STEAM_5:0:11101", 0, ErrInvalidSteamID},
+		{"STEAM_0:0:2147483648", 0, ErrInvalidSteamID},
+		{"STEAM_0:0", 0, ErrInvalidSteamID},
+		{"[U:1:22202]", 0, ErrInvalidSteamID},
+		{"76561197960287930", 0, ErrInvalidSteamID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSteamID2(tt.in)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSteamID2(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSteamID2(t *testing.T) {
+	tests := []struct {
+		id   SteamID
+		want string
+	}{
+		{76561197960287930, "STEAM_0:0:11101"},
+		{76561197960287931, "STEAM_0:1:11101"},
+		{NewSteamID(UniverseBeta, AccountTypeIndividual, InstanceDesktop, 22202), "STEAM_2:0:11101"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.id.SteamID2(); got != tt.want {
+				t.Errorf("SteamID2() = %q, want %q", got, tt.want)
+			}
+			if back, err := ParseSteamID2(tt.want); err != nil || back != tt.id {
+				t.Errorf("ParseSteamID2(%q) = %d, %v, want it back", tt.want, back, err)
+			}
+		})
+	}
+}
+
+func TestParseSteam3(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    SteamID
+		wantErr error
+	}{
+		{"[U:1:22202]", 76561197960287930, nil},
+		{"U:1:22202", 76561197960287930, nil},
+		{" [U:1:22202] ", 76561197960287930, nil},
+		{"[g:1:4]", 103582791429521412, nil},
+		{"[G:1:5]", NewSteamID(UniversePublic, AccountTypeGameServer, 0, 5), nil},
+		{"[A:1:123:456]", NewSteamID(UniversePublic, AccountTypeAnonGameServer, 456, 123), nil},
+		{"[c:1:4]", NewSteamID(UniversePublic, AccountTypeChat, chatInstanceFlagClan, 4), nil},
+		{"[L:1:4]", NewSteamID(UniversePublic, AccountTypeChat, chatInstanceFlagLobby, 4), nil},
+		{"[U:1:22202", 0, ErrInvalidSteamID},
+		{"[X:1:22202]", 0, ErrInvalidSteamID},
+		{"[U:5:22202]", 0, ErrInvalidSteamID},
+		{"[U:1:abc]", 0, ErrInvalidSteamID},
+		{"[U:1:4294967296]", 0, ErrInvalidSteamID},
+		{"[U:1]", 0, ErrInvalidSteamID},
+		{"[A:1:123:1048576]", 0, ErrInvalidSteamID},
+		{"STEAM_0:0:11101", 0, ErrInvalidSteamID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSteam3(tt.in)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSteam3(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSteam3(t *testing.T) {
+	tests := []struct {
+		id   SteamID
+		want string
+	}{
+		{76561197960287930, "[U:1:22202]"},
+		{NewSteamID(UniversePublic, AccountTypeIndividual, 0, 22202), "[U:1:22202:0]"},
+		{NewSteamID(UniversePublic, AccountTypeGameServer, 0, 5), "[G:1:5]"},
+		{NewSteamID(UniversePublic, AccountTypeAnonGameServer, 456, 123), "[A:1:123:456]"},
+		{NewSteamID(UniversePublic, AccountTypeChat, chatInstanceFlagClan, 4), "[c:1:4]"},
+		{NewSteamID(UniversePublic, AccountTypeChat, chatInstanceFlagLobby, 4), "[L:1:4]"},
+		{NewSteamID(UniversePublic, AccountTypeConsoleUser, 0, 7), "[i:1:7:0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.id.Steam3(); got != tt.want {
+				t.Errorf("Steam3() = %q, want %q", got, tt.want)
+			}
+			if tt.id.AccountType() == AccountTypeConsoleUser {
+				return
+			}
+			if back, err := ParseSteam3(tt.want); err != nil || back != tt.id {
+				t.Errorf("ParseSteam3(%q) = %d, %v, want it back", tt.want, back, err)
+			}
+		})
+	}
+}
+
+func TestAccountId(t *testing.T) {
+	tests := []struct {
+		steamid string
+		want    string
+		wantErr bool
+	}{
+		{"76561197960287930", "22202", false},
+		{"22202", "22202", false},
+		{"4294967295", "4294967295", false},
+		{"4294967296", "", true},         // past 32 bits, but not a steamid64
+		{"103582791429521412", "", true}, // a group
+		{"nope", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.steamid, func(t *testing.T) {
+			got, err := AccountId(tt.steamid)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("AccountId(%q) = %q, want %q", tt.steamid, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndividualSteamID64(t *testing.T) {
+	tests := []struct {
+		steamid string
+		want    string
+		wantErr bool
+	}{
+		{"22202", "76561197960287930", false},
+		{"76561197960287930", "76561197960287930", false},
+		{"0", "76561197960265728", false},
+		{"103582791429521412", "", true},
+		{"-1", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.steamid, func(t *testing.T) {
+			got, err := IndividualSteamID64(tt.steamid)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IndividualSteamID64(%q) = %q, want %q", tt.steamid, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSteamIDFromAccountID(t *testing.T) {
+	tests := []struct {
+		universe    Universe
+		accountType AccountType
+		want        SteamID
+	}{
+		{UniversePublic, AccountTypeIndividual, 76561197960287930},
+		{UniversePublic, AccountTypeClan, 103582791429543610},
+		{UniverseBeta, AccountTypeIndividual, NewSteamID(UniverseBeta, AccountTypeIndividual, InstanceDesktop, 22202)},
+	}
+
+	for _, tt := range tests {
+		if got := SteamIDFromAccountID(22202, tt.universe, tt.accountType); got != tt.want {
+			t.Errorf("SteamIDFromAccountID(22202, %d, %d) = %d, want %d", tt.universe, tt.accountType, got, tt.want)
+		}
+	}
+}
+
+func TestSteamIDIsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		id   SteamID
+		want bool
+	}{
+		{"user", 76561197960287930, true},
+		{"group", 103582791429521412, true},
+		{"game server", NewSteamID(UniversePublic, AccountTypeGameServer, 0, 5), true},
+		{"anon game server", 90071996842377216, true},
+		{"zero", 0, false},
+		{"invalid universe", NewSteamID(UniverseInvalid, AccountTypeIndividual, InstanceDesktop, 22202), false},
+		{"unknown universe", NewSteamID(UniverseDev+1, AccountTypeIndividual, InstanceDesktop, 22202), false},
+		{"invalid account type", NewSteamID(UniversePublic, AccountTypeInvalid, 0, 22202), false},
+		{"unknown account type", NewSteamID(UniversePublic, AccountTypeAnonUser+1, 0, 22202), false},
+		{"user without an account id", NewSteamID(UniversePublic, AccountTypeIndividual, InstanceDesktop, 0), false},
+		{"user with a bad instance", NewSteamID(UniversePublic, AccountTypeIndividual, 5, 22202), false},
+		{"group with an instance", NewSteamID(UniversePublic, AccountTypeClan, 1, 4), false},
+		{"game server without an account id", NewSteamID(UniversePublic, AccountTypeGameServer, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.id.IsValid(); got != tt.want {
+				t.Errorf("IsValid(%d) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSteamIDTypeChecks(t *testing.T) {
+	tests := []struct {
+		id                           SteamID
+		individual, clan, gameServer bool
+	}{
+		{76561197960287930, true, false, false},
+		{103582791429521412, false, true, false},
+		{NewSteamID(UniversePublic, AccountTypeGameServer, 0, 5), false, false, true},
+		{90071996842377216, false, false, true},
+	}
+
+	for _, tt := range tests {
+		if tt.id.IsIndividual() != tt.individual || tt.id.IsClan() != tt.clan || tt.id.IsGameServer() != tt.gameServer {
+			t.Errorf("%d: IsIndividual %v, IsClan %v, IsGameServer %v, want %v, %v, %v", tt.id,
+				tt.id.IsIndividual(), tt.id.IsClan(), tt.id.IsGameServer(), tt.individual, tt.clan, tt.gameServer)
+		}
+	}
+}
+
+func TestParseSteamID(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    SteamID
+		wantErr error
+	}{
+		{"76561197960287930", 76561197960287930, nil},
+		{" 76561197960287930 ", 76561197960287930, nil},
+		{"STEAM_0:0:11101", 76561197960287930, nil},
+		{"STEAM_1:0:11101", 76561197960287930, nil},
+		{"steam_0:0:11101", 76561197960287930, nil},
+		{"[U:1:22202]", 76561197960287930, nil},
+		{"https://steamcommunity.com/profiles/76561197960287930", 76561197960287930, nil},
+		{"https://steamcommunity.com/profiles/76561197960287930/", 76561197960287930, nil},
+		{"steamcommunity.com/profiles/76561197960287930", 76561197960287930, nil},
+		{"[g:1:4]", 103582791429521412, nil},
+		{"103582791429521412", 103582791429521412, nil},
+		{"https://steamcommunity.com/id/gabelogannewell", 0, ErrVanityName},
+		{"gabelogannewell", 0, ErrVanityName},
+		{"1234", 0, ErrVanityName},
+		{"STEAM_0:2:11101", 0, ErrInvalidSteamID},
+		{"[U:1:abc]", 0, ErrInvalidSteamID},
+		{"https://steamcommunity.com/profiles/nope", 0, ErrInvalidSteamID},
+		{"", 0, ErrInvalidSteamID},
+		{"not a steamid!", 0, ErrInvalidSteamID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseSteamID(tt.in)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSteamID(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSteamID(t *testing.T) {
+	fakeSteam(t, vanityUrls)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		in      string
+		want    SteamID
+		wantErr error
+	}{
+		{"gaben", 76561197960287930, nil},
+		{"https://steamcommunity.com/id/gaben/", 76561197960287930, nil},
+		{"STEAM_0:0:11101", 76561197960287930, nil},
+		{"nobody", 0, ErrVanityNotFound},
+		{"not a steamid!", 0, ErrInvalidSteamID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := sa.ResolveSteamID(context.Background(), tt.in)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveSteamID(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
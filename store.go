@@ -0,0 +1,134 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by a Store when the key doesn't exist or has expired.
+var ErrNotFound = errors.New("not found in store")
+
+// Store is where server-side auth state (refresh tokens, revocations, sessions...) is kept.
+// MemoryStore works fine for a single instance, anything running more than one should back this with something
+// shared like redis or a database.
+type Store interface {
+	// Get returns the value stored at key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value at key, replacing anything already there. The value expires after ttl, or never if ttl <= 0.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key. Deleting a key that doesn't exist is not an error.
+	Delete(ctx context.Context, key string) error
+	// Take atomically gets and deletes key, returning ErrNotFound if it doesn't exist. This is what makes
+	// single-use tokens actually single-use, so it must not let two callers take the same key.
+	Take(ctx context.Context, key string) ([]byte, error)
+}
+
+// MemoryStore is an in-memory Store. Expired values are removed lazily and by a background sweep.
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[string]memoryValue
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type memoryValue struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (v memoryValue) expired(now time.Time) bool {
+	return !v.expiresAt.IsZero() && now.After(v.expiresAt)
+}
+
+// memorySweepInterval is how often MemoryStore removes expired values in the background.
+const memorySweepInterval = time.Minute
+
+// NewMemoryStore returns a new, empty MemoryStore. Call Close when done with it to stop the background sweep.
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		values: make(map[string]memoryValue),
+		stop:   make(chan struct{}),
+	}
+	go s.sweep()
+
+	return s
+}
+
+func (s *MemoryStore) sweep() {
+	tick := time.NewTicker(memorySweepInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			now := time.Now()
+			s.mu.Lock()
+			for k, v := range s.values {
+				if v.expired(now) {
+					delete(s.values, k)
+				}
+			}
+			s.mu.Unlock()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Close stops the background sweep.
+func (s *MemoryStore) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.values[key]
+	if !ok || v.expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+
+	return v.value, nil
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	v := memoryValue{value: value}
+	if ttl > 0 {
+		v.expiresAt = time.Now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	s.values[key] = v
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	delete(s.values, key)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Take implements Store.
+func (s *MemoryStore) Take(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.values[key]
+	delete(s.values, key)
+	if !ok || v.expired(time.Now()) {
+		return nil, ErrNotFound
+	}
+
+	return v.value, nil
+}
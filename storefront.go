@@ -0,0 +1,102 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// StoreBaseUrl is the base url of the steam store, which has a few unofficial (but widely used) apis of its own.
+const StoreBaseUrl = "https://store.steampowered.com"
+
+// AppDetails is an app's store page, as represented in the response from the store's appdetails api.
+type AppDetails struct {
+	// Type is what kind of app it is (ex. "game", "dlc", "music").
+	Type             string   `json:"type"`
+	Name             string   `json:"name"`
+	AppID            int      `json:"steam_appid"`
+	IsFree           bool     `json:"is_free"`
+	ShortDescription string   `json:"short_description"`
+	HeaderImage      string   `json:"header_image"`
+	CapsuleImage     string   `json:"capsule_image"`
+	Website          string   `json:"website"`
+	Developers       []string `json:"developers"`
+	Publishers       []string `json:"publishers"`
+	// PriceOverview is nil for free apps, and apps that aren't for sale in the country asked for.
+	PriceOverview *AppPrice `json:"price_overview"`
+	Platforms     struct {
+		Windows bool `json:"windows"`
+		Mac     bool `json:"mac"`
+		Linux   bool `json:"linux"`
+	} `json:"platforms"`
+	Categories  []AppTag `json:"categories"`
+	Genres      []AppTag `json:"genres"`
+	ReleaseDate struct {
+		ComingSoon bool `json:"coming_soon"`
+		// Date is as shown on the store page, in the language asked for (ex. "21 Aug, 2012").
+		Date string `json:"date"`
+	} `json:"release_date"`
+	// DLC is the appids of the app's dlc.
+	DLC []int `json:"dlc"`
+}
+
+// AppPrice is an app's price in a country.
+type AppPrice struct {
+	// Currency is the ISO 4217 code of the currency the price is in (ex. "USD").
+	Currency string `json:"currency"`
+	// Initial and Final are the price before and after any discount, in the currency's smallest unit (ex. cents).
+	Initial         int `json:"initial"`
+	Final           int `json:"final"`
+	DiscountPercent int `json:"discount_percent"`
+	// InitialFormatted and FinalFormatted are the prices as shown on the store page, FinalFormatted is "" if there's
+	// no discount.
+	InitialFormatted string `json:"initial_formatted"`
+	FinalFormatted   string `json:"final_formatted"`
+}
+
+// AppTag is one of an app's categories or genres.
+type AppTag struct {
+	ID          json.Number `json:"id"`
+	Description string      `json:"description"`
+}
+
+// GetAppDetails gets an app's store page details. cc is the country code to get prices for (ex. "us"), and lang the
+// language to get text in (ex. "english"), either can be "" to leave it up to steam. Returns ErrNoData if the app
+// doesn't have a store page (in that country). This isn't an official api, so steam rate limits it pretty heavily.
+func (sa *SteamAuther) GetAppDetails(ctx context.Context, appid int, cc, lang string) (*AppDetails, error) {
+	q := url.Values{"appids": {strconv.Itoa(appid)}}
+	if cc != "" {
+		q.Set("cc", cc)
+	}
+	if lang != "" {
+		q.Set("l", lang)
+	}
+
+	res, err := sa.get(ctx, StoreBaseUrl+"/api/appdetails", q)
+	if err != nil {
+		return nil, fmt.Errorf("get app details (%d): %w", appid, err)
+	}
+	defer res.Body.Close()
+
+	var data map[string]struct {
+		Success bool            `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("get app details (%d): decode response body: %w", appid, err)
+	}
+
+	app, ok := data[strconv.Itoa(appid)]
+	if !ok || !app.Success {
+		return nil, fmt.Errorf("get app details (%d): %w", appid, ErrNoData)
+	}
+
+	var details AppDetails
+	if err := json.Unmarshal(app.Data, &details); err != nil {
+		return nil, fmt.Errorf("get app details (%d): decode app data: %w", appid, err)
+	}
+
+	return &details, nil
+}
@@ -0,0 +1,57 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetAppDetails(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/api/appdetails" || q.Has("key") {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch q.Get("appids") {
+		case "440":
+			price := `"price_overview":{"currency":"USD","initial":999,"final":499,"discount_percent":50,"initial_formatted":"$9.99","final_formatted":"$4.99"},`
+			if q.Get("cc") == "de" {
+				price = ""
+			}
+			w.Write([]byte(`{"440":{"success":true,"data":{"type":"game","name":"Team Fortress 2","steam_appid":440,"is_free":false,` + price + `
+				"platforms":{"windows":true,"mac":false,"linux":true},
+				"categories":[{"id":1,"description":"Multi-player"}],"genres":[{"id":"1","description":"Action"}],
+				"release_date":{"coming_soon":false,"date":"10 Oct, 2007"},"dlc":[629330]}}}`))
+		default:
+			w.Write([]byte(`{"` + q.Get("appids") + `":{"success":false}}`))
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	app, err := sa.GetAppDetails(context.Background(), 440, "us", "english")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.Name != "Team Fortress 2" || !app.Platforms.Linux || app.Platforms.Mac || len(app.DLC) != 1 {
+		t.Errorf("app = %+v, want tf2", app)
+	}
+	if p := app.PriceOverview; p == nil || p.Final != 499 || p.DiscountPercent != 50 {
+		t.Errorf("price = %+v, want it half off", p)
+	}
+	// steam sends category ids as numbers and genre ids as strings
+	if app.Categories[0].ID != "1" || app.Genres[0].ID != "1" {
+		t.Errorf("categories = %+v, genres = %+v, want both ids read", app.Categories, app.Genres)
+	}
+
+	app, err = sa.GetAppDetails(context.Background(), 440, "de", "")
+	if err != nil || app.PriceOverview != nil {
+		t.Errorf("not for sale = %+v, %v, want no price", app, err)
+	}
+
+	if _, err := sa.GetAppDetails(context.Background(), 4000, "", ""); !errors.Is(err, ErrNoData) {
+		t.Errorf("no store page err = %v, want ErrNoData", err)
+	}
+}
@@ -0,0 +1,221 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownTenant is returned by TenantRegistry when no tenant matches.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// Tenant is one of the communities (or customers) a TenantRegistry serves, each with its own steam login.
+type Tenant struct {
+	// ID identifies the tenant. Sessions are stamped with it, so they only work for the tenant that started them.
+	ID string
+	// Hosts are the hostnames (without a port) requests for the tenant come in on.
+	Hosts []string
+	// ApiKey is the tenant's steam web api key.
+	ApiKey string
+	// Realm is the tenant's openid 2 realm, see New.
+	Realm string
+	// CallbackUrl is the full url the tenant's callback is reachable at, see NewHandler.
+	CallbackUrl string
+}
+
+// TenantRegistry runs steam login for many tenants out of one app, picking the tenant for each request by its host.
+// This is for platforms offering steam login to lots of communities, each with their own api key and domain. Every
+// tenant's callback only accepts assertions steam signed for that tenant's own callback url (and realm), so one
+// tenant's operator can't replay their users' logins at another tenant, even though they share Sessions.
+type TenantRegistry struct {
+	sessions *Sessions
+
+	// Configure, if set, is called with every tenant's Handler as it's added, to set its options (Attempts,
+	// FetchUser, Access...). Anything holding state, like a RememberMe, should be made per tenant.
+	Configure func(t *Tenant, h *Handler)
+
+	mu      sync.RWMutex
+	tenants map[string]*tenantEntry
+	hosts   map[string]*tenantEntry
+}
+
+type tenantEntry struct {
+	tenant  Tenant
+	handler *Handler
+}
+
+// NewTenantRegistry returns a new, empty TenantRegistry. Every tenant's sessions are kept by sessions.
+func NewTenantRegistry(sessions *Sessions) *TenantRegistry {
+	return &TenantRegistry{
+		sessions: sessions,
+		tenants:  make(map[string]*tenantEntry),
+		hosts:    make(map[string]*tenantEntry),
+	}
+}
+
+// Add adds a tenant, replacing any tenant with the same id.
+func (tr *TenantRegistry) Add(t Tenant) error {
+	if t.ID == "" {
+		return errors.New("add tenant: id is required")
+	}
+	if err := ValidateRealm(t.Realm); err != nil {
+		return fmt.Errorf("add tenant (%s): %w", t.ID, err)
+	}
+	if !RealmMatches(t.Realm, t.CallbackUrl) {
+		return fmt.Errorf("add tenant (%s): callback url: %w", t.ID, ErrReturnUrlOutsideRealm)
+	}
+
+	// Checked before Configure, so a tenant that can't be added never gets a Handler configured.
+	tr.mu.RLock()
+	err := tr.hostsTaken(t)
+	tr.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	h := NewHandler(New(t.ApiKey, t.Realm), tr.sessions, t.CallbackUrl)
+	h.Tenant = t.ID
+	if tr.Configure != nil {
+		tr.Configure(&t, h)
+	}
+	entry := &tenantEntry{tenant: t, handler: h}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	// Again, in case another Add took the host while this one was being configured.
+	if err := tr.hostsTaken(t); err != nil {
+		return err
+	}
+
+	tr.remove(t.ID)
+	tr.tenants[t.ID] = entry
+	for _, host := range t.Hosts {
+		tr.hosts[normalizeHost(host)] = entry
+	}
+
+	return nil
+}
+
+// hostsTaken returns an error if any of t's hosts belongs to another tenant. tr.mu has to be held.
+func (tr *TenantRegistry) hostsTaken(t Tenant) error {
+	for _, host := range t.Hosts {
+		if other, ok := tr.hosts[normalizeHost(host)]; ok && other.tenant.ID != t.ID {
+			return fmt.Errorf("add tenant (%s): host %s already belongs to tenant %s", t.ID, host, other.tenant.ID)
+		}
+	}
+
+	return nil
+}
+
+// Remove removes a tenant. Its users' sessions stop working straight away.
+func (tr *TenantRegistry) Remove(id string) {
+	tr.mu.Lock()
+	tr.remove(id)
+	tr.mu.Unlock()
+}
+
+func (tr *TenantRegistry) remove(id string) {
+	entry, ok := tr.tenants[id]
+	if !ok {
+		return
+	}
+
+	delete(tr.tenants, id)
+	for _, host := range entry.tenant.Hosts {
+		if tr.hosts[normalizeHost(host)] == entry {
+			delete(tr.hosts, normalizeHost(host))
+		}
+	}
+}
+
+// Handler returns the tenant's Handler.
+func (tr *TenantRegistry) Handler(id string) (*Handler, bool) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	entry, ok := tr.tenants[id]
+	if !ok {
+		return nil, false
+	}
+
+	return entry.handler, true
+}
+
+// Resolve returns the tenant a request is for, by its host, and its Handler. Returns ErrUnknownTenant if no tenant
+// has the host.
+func (tr *TenantRegistry) Resolve(r *http.Request) (*Tenant, *Handler, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	entry, ok := tr.hosts[normalizeHost(r.Host)]
+	if !ok {
+		return nil, nil, fmt.Errorf("resolve tenant (%s): %w", r.Host, ErrUnknownTenant)
+	}
+
+	t := entry.tenant
+	return &t, entry.handler, nil
+}
+
+// serve resolves the request's tenant and hands it to the tenant's handler, responding with a 404 for unknown hosts.
+func (tr *TenantRegistry) serve(w http.ResponseWriter, r *http.Request, serve func(h *Handler, w http.ResponseWriter, r *http.Request)) {
+	t, h, err := tr.Resolve(r)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	serve(h, w, r.WithContext(context.WithValue(r.Context(), tenantContextKey, t)))
+}
+
+// Login is Handler.Login for the request's tenant.
+func (tr *TenantRegistry) Login(w http.ResponseWriter, r *http.Request) {
+	tr.serve(w, r, (*Handler).Login)
+}
+
+// Callback is Handler.Callback for the request's tenant.
+func (tr *TenantRegistry) Callback(w http.ResponseWriter, r *http.Request) {
+	tr.serve(w, r, (*Handler).Callback)
+}
+
+// Logout is Handler.Logout for the request's tenant.
+func (tr *TenantRegistry) Logout(w http.ResponseWriter, r *http.Request) {
+	tr.serve(w, r, (*Handler).Logout)
+}
+
+// RequireAuth is Handler.RequireAuth for the request's tenant.
+func (tr *TenantRegistry) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tr.serve(w, r, func(h *Handler, w http.ResponseWriter, r *http.Request) {
+			h.RequireAuth(next).ServeHTTP(w, r)
+		})
+	})
+}
+
+// LoadSession is Handler.LoadSession for the request's tenant.
+func (tr *TenantRegistry) LoadSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tr.serve(w, r, func(h *Handler, w http.ResponseWriter, r *http.Request) {
+			h.LoadSession(next).ServeHTTP(w, r)
+		})
+	})
+}
+
+// TenantFromContext returns the request's tenant, if it went through one of TenantRegistry's handlers.
+func TenantFromContext(ctx context.Context) (*Tenant, bool) {
+	t, ok := ctx.Value(tenantContextKey).(*Tenant)
+	return t, ok
+}
+
+// normalizeHost lowercases host and strips its port.
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
@@ -0,0 +1,190 @@
+package gosteamauth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTenantCallbackRejectsOtherTenantsAssertion(t *testing.T) {
+	tr := NewTenantRegistry(NewSessions(NewMemoryStore()))
+	tr.Configure = func(_ *Tenant, h *Handler) {
+		h.Attempts = NewAttemptTracker(time.Minute)
+	}
+	for _, tenant := range []Tenant{
+		{ID: "a", Hosts: []string{"a.example.com"}, Realm: "https://a.example.com", CallbackUrl: "https://a.example.com/callback"},
+		{ID: "b", Hosts: []string{"b.example.com"}, Realm: "https://b.example.com", CallbackUrl: "https://b.example.com/callback"},
+	} {
+		if err := tr.Add(tenant); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Start a login on b, to get a state b will accept.
+	rec := httptest.NewRecorder()
+	tr.Login(rec, httptest.NewRequest(http.MethodGet, "https://b.example.com/login", nil))
+	steamUrl, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bReturnTo, err := url.Parse(steamUrl.Query().Get("openid.return_to"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := bReturnTo.Query().Get(AttemptStateParam)
+	if state == "" {
+		t.Fatal("login didn't add a state to the return url")
+	}
+
+	// An assertion steam made for a, replayed at b with b's state.
+	q := url.Values{
+		"openid.ns":             {OpenIdNamespace},
+		"openid.mode":           {"id_res"},
+		"openid.op_endpoint":    {OpenIdLoginUrl},
+		"openid.claimed_id":     {claimedIdPrefix + "76561197960287930"},
+		"openid.identity":       {claimedIdPrefix + "76561197960287930"},
+		"openid.return_to":      {"https://a.example.com/callback?" + AttemptStateParam + "=x"},
+		"openid.response_nonce": {"2024-01-01T00:00:00Zabc"},
+		"openid.assoc_handle":   {"1234567890"},
+		"openid.signed":         {"signed,op_endpoint,claimed_id,identity,return_to,response_nonce,assoc_handle"},
+		"openid.sig":            {"c2lnbmF0dXJl"},
+		AttemptStateParam:       {state},
+	}
+	rec = httptest.NewRecorder()
+	tr.Callback(rec, httptest.NewRequest(http.MethodGet, "https://b.example.com/callback?"+q.Encode(), nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+	if cookies := rec.Result().Cookies(); len(cookies) > 0 {
+		t.Errorf("callback set cookies %v, want none", cookies)
+	}
+}
+
+func TestTenantRegistryAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		tenant  Tenant
+		wantErr bool
+	}{
+		{"valid", Tenant{ID: "b", Hosts: []string{"b.example.com"}, Realm: "https://b.example.com", CallbackUrl: "https://b.example.com/callback"}, false},
+		{"same tenant again", Tenant{ID: "a", Hosts: []string{"a.example.com", "www.a.example.com"}, Realm: "https://*.a.example.com", CallbackUrl: "https://a.example.com/callback"}, false},
+		{"no id", Tenant{Hosts: []string{"b.example.com"}, Realm: "https://b.example.com", CallbackUrl: "https://b.example.com/callback"}, true},
+		{"bad realm", Tenant{ID: "b", Realm: "b.example.com", CallbackUrl: "https://b.example.com/callback"}, true},
+		{"callback outside realm", Tenant{ID: "b", Realm: "https://b.example.com", CallbackUrl: "https://evil.example.net/callback"}, true},
+		{"host taken", Tenant{ID: "b", Hosts: []string{"A.example.com:8080"}, Realm: "https://b.example.com", CallbackUrl: "https://b.example.com/callback"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := NewTenantRegistry(NewSessions(NewMemoryStore()))
+			if err := tr.Add(Tenant{ID: "a", Hosts: []string{"a.example.com"}, Realm: "https://a.example.com", CallbackUrl: "https://a.example.com/callback"}); err != nil {
+				t.Fatal(err)
+			}
+
+			configured := false
+			tr.Configure = func(*Tenant, *Handler) { configured = true }
+
+			err := tr.Add(tt.tenant)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Add err = %v, want error %v", err, tt.wantErr)
+			}
+			if configured == tt.wantErr {
+				t.Errorf("Configure called = %v, want %v", configured, !tt.wantErr)
+			}
+			if _, ok := tr.Handler("a"); !ok {
+				t.Error("tenant a is gone")
+			}
+		})
+	}
+}
+
+func TestTenantRegistryResolve(t *testing.T) {
+	tr := NewTenantRegistry(NewSessions(NewMemoryStore()))
+	if err := tr.Add(Tenant{ID: "a", Hosts: []string{"a.example.com"}, Realm: "https://a.example.com", CallbackUrl: "https://a.example.com/callback"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		host    string
+		wantErr error
+	}{
+		{"a.example.com", nil},
+		{"A.Example.com:8443", nil},
+		{"a.example.com.", nil},
+		{"b.example.com", ErrUnknownTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Host = tt.host
+
+			tenant, h, err := tr.Resolve(req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (tenant.ID != "a" || h.Tenant != "a") {
+				t.Errorf("Resolve = %v, %v, want tenant a", tenant, h)
+			}
+		})
+	}
+
+	tr.Remove("a")
+	if _, _, err := tr.Resolve(httptest.NewRequest(http.MethodGet, "https://a.example.com/", nil)); !errors.Is(err, ErrUnknownTenant) {
+		t.Errorf("after Remove err = %v, want ErrUnknownTenant", err)
+	}
+}
+
+func TestTenantSessionsStayWithTheirTenant(t *testing.T) {
+	tr := NewTenantRegistry(NewSessions(NewMemoryStore()))
+	for _, tenant := range []Tenant{
+		{ID: "a", Hosts: []string{"a.example.com"}, Realm: "https://a.example.com", CallbackUrl: "https://a.example.com/callback"},
+		{ID: "b", Hosts: []string{"b.example.com"}, Realm: "https://b.example.com", CallbackUrl: "https://b.example.com/callback"},
+	} {
+		if err := tr.Add(tenant); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, _ := tr.Handler("a")
+	rec := httptest.NewRecorder()
+	if _, err := a.sessions.create(t.Context(), rec, "76561197960287930", nil, Device{}, a.Tenant); err != nil {
+		t.Fatal(err)
+	}
+	cookie := rec.Result().Cookies()[0]
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tenant, ok := TenantFromContext(r.Context()); ok {
+			w.Write([]byte(tenant.ID))
+		}
+	})
+
+	tests := []struct {
+		host string
+		want int
+	}{
+		{"a.example.com", http.StatusOK},
+		{"b.example.com", http.StatusUnauthorized},
+		{"c.example.com", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "https://"+tt.host+"/me", nil)
+			req.AddCookie(cookie)
+			rec := httptest.NewRecorder()
+			tr.RequireAuth(next).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && rec.Body.String() != "a" {
+				t.Errorf("tenant in context = %q, want a", rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrInvalidTicket is returned by Tickets.Redeem when the ticket is unknown, expired or has already been used.
+var ErrInvalidTicket = errors.New("invalid ticket")
+
+const (
+	// DefaultTicketTTL is how long tickets last, unless Tickets.TTL is changed. Tickets are meant to be used straight
+	// away, so it's short.
+	DefaultTicketTTL = 30 * time.Second
+	// TicketParam is the query parameter Tickets.Middleware reads the ticket from, ex. wss://example.com/ws?ticket=...
+	TicketParam = "ticket"
+)
+
+// Tickets mints short-lived, single use tickets for logged in users. They're for connections that can't carry the
+// session cookie (browsers won't set headers on WebSocket handshakes, and cookies don't go cross-origin), so the
+// page fetches a ticket over a normal request and hands it over in the url instead.
+type Tickets struct {
+	store Store
+
+	// TTL is how long a ticket lasts. Defaults to DefaultTicketTTL.
+	TTL time.Duration
+}
+
+// NewTickets returns a new Tickets keeping tickets in store.
+func NewTickets(store Store) *Tickets {
+	return &Tickets{
+		store: store,
+		TTL:   DefaultTicketTTL,
+	}
+}
+
+// Mint returns a new ticket for steamid64.
+func (t *Tickets) Mint(ctx context.Context, steamid64 string) (string, error) {
+	ticket, err := randomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("mint ticket (%s): generate ticket: %w", steamid64, err)
+	}
+
+	if err := t.store.Set(ctx, ticketKey(ticket), []byte(steamid64), t.TTL); err != nil {
+		return "", fmt.Errorf("mint ticket (%s): %w", steamid64, err)
+	}
+
+	return ticket, nil
+}
+
+// Redeem uses up the ticket and returns the steamid64 it was minted for.
+func (t *Tickets) Redeem(ctx context.Context, ticket string) (string, error) {
+	if ticket == "" {
+		return "", ErrInvalidTicket
+	}
+
+	b, err := t.store.Take(ctx, ticketKey(ticket))
+	if errors.Is(err, ErrNotFound) {
+		return "", ErrInvalidTicket
+	}
+	if err != nil {
+		return "", fmt.Errorf("redeem ticket: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// Handler mints tickets for users logged in with h. Responds with:
+//
+//	{"ticket": "...", "expires_in": 30}
+//
+// Mount it on a POST route, so other sites can't mint tickets for your users with a link.
+func (t *Tickets) Handler(h *Handler) http.Handler {
+	return h.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess, _ := SessionFromContext(r.Context())
+
+		ticket, err := t.Mint(r.Context(), sess.SteamID)
+		if err != nil {
+			http.Error(w, "failed to mint ticket", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(struct {
+			Ticket    string `json:"ticket"`
+			ExpiresIn int    `json:"expires_in"`
+		}{ticket, int(t.TTL.Seconds())})
+	}))
+}
+
+// Middleware only lets requests with a valid ticket in their TicketParam query parameter through to next, and puts
+// the ticket's steamid in the request context (see SteamIDFromContext). Put it in front of the WebSocket or SSE
+// handler. Everything else gets a 401.
+func (t *Tickets) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		steamid, err := t.Redeem(r.Context(), r.URL.Query().Get(TicketParam))
+		if errors.Is(err, ErrInvalidTicket) {
+			http.Error(w, "invalid or expired ticket", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, "failed to check ticket", http.StatusInternalServerError)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ContextWithSteamID(r.Context(), steamid)))
+	})
+}
+
+// ticketKey is where a ticket is kept in the store. Hashed, like sessions, so the store can't be used to mint them.
+func ticketKey(ticket string) string {
+	return "ticket:" + hashToken(ticket)
+}
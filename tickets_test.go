@@ -0,0 +1,95 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTicketsRedeem(t *testing.T) {
+	tk := NewTickets(NewMemoryStore())
+
+	ticket, err := tk.Mint(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := tk.Redeem(context.Background(), ticket)
+	if err != nil || got != "76561197960287930" {
+		t.Fatalf("Redeem = %q, %v, want the steamid", got, err)
+	}
+
+	for _, bad := range []string{ticket, "", "nope"} {
+		if _, err := tk.Redeem(context.Background(), bad); !errors.Is(err, ErrInvalidTicket) {
+			t.Errorf("Redeem(%q) err = %v, want ErrInvalidTicket", bad, err)
+		}
+	}
+}
+
+func TestTicketsExpire(t *testing.T) {
+	tk := NewTickets(NewMemoryStore())
+	tk.TTL = time.Millisecond
+
+	ticket, err := tk.Mint(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, err := tk.Redeem(context.Background(), ticket); !errors.Is(err, ErrInvalidTicket) {
+		t.Errorf("expired ticket err = %v, want ErrInvalidTicket", err)
+	}
+}
+
+func TestTicketsHandlerAndMiddleware(t *testing.T) {
+	store := NewMemoryStore()
+	h := NewHandler(New("", "https://example.com"), NewSessions(store), "https://example.com/callback")
+	tk := NewTickets(store)
+
+	rec := httptest.NewRecorder()
+	if _, err := h.Sessions().Create(context.Background(), rec, "76561197960287930", nil); err != nil {
+		t.Fatal(err)
+	}
+	cookie := rec.Result().Cookies()[0]
+
+	rec = httptest.NewRecorder()
+	tk.Handler(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/ticket", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("minting without a session status = %d, want 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/ticket", nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	tk.Handler(h).ServeHTTP(rec, req)
+
+	var body struct {
+		Ticket    string `json:"ticket"`
+		ExpiresIn int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Ticket == "" || body.ExpiresIn != 30 {
+		t.Fatalf("body = %s (%v), want a ticket lasting 30s", rec.Body, err)
+	}
+
+	var got string
+	ws := tk.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = SteamIDFromContext(r.Context())
+	}))
+	connect := func(ticket string) int {
+		rec := httptest.NewRecorder()
+		ws.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ws?"+url.Values{TicketParam: {ticket}}.Encode(), nil))
+		return rec.Code
+	}
+
+	if code := connect(body.Ticket); code != http.StatusOK || got != "76561197960287930" {
+		t.Errorf("connect status = %d, steamid = %q, want 200 as the user", code, got)
+	}
+	if code := connect(body.Ticket); code != http.StatusUnauthorized {
+		t.Errorf("reusing the ticket status = %d, want 401", code)
+	}
+}
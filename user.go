@@ -1,5 +1,7 @@
 package gosteamauth
 
+import "time"
+
 const (
 	PersonaStateOffline        int = 0
 	PersonaStateOnline         int = 1
@@ -20,6 +22,16 @@ const (
 	CommunityVisibilityStatusPublic     int = 3
 )
 
+// CommentPermission is who is allowed to leave comments on a user's profile.
+// Steam leaves the field out entirely when comments aren't open, which decodes to CommentPermissionNone.
+type CommentPermission int
+
+const (
+	CommentPermissionNone        CommentPermission = 0
+	CommentPermissionPublic      CommentPermission = 1
+	CommentPermissionFriendsOnly CommentPermission = 2
+)
+
 // SteamUser is a steam user, as represented in the response from GetPlayerSummaries web api.
 type SteamUser struct {
 	// SteamID is the "steamid64" of the player.
@@ -40,10 +52,50 @@ type SteamUser struct {
 	// See the CommunityVisibilityStatus... enums
 	CommunityVisibilityStatus int `json:"communityvisibilitystate"`
 
+	// CommentPermission is who can comment on the user's profile.
+	// See the CommentPermission... enums
+	CommentPermission CommentPermission `json:"commentpermission"`
+
 	// Avatar is the user's 32x32 avatar URL
 	Avatar string `json:"avatar"`
 	// AvatarMedium is the user's 64x64 avatar URL
 	AvatarMedium string `json:"avatarmedium"`
 	// AvatarFull is the user's 128x128 avatar URL
 	AvatarFull string `json:"avatarfull"`
+
+	// TimeCreated is when the account was made, as a unix timestamp. Steam only includes it for public profiles,
+	// so it's 0 otherwise.
+	TimeCreated int64 `json:"timecreated"`
+
+	// LocCountryCode, LocStateCode and LocCityID are where the user says they're from, if they've set it. See
+	// Location.
+	LocCountryCode string `json:"loccountrycode"`
+	LocStateCode   string `json:"locstatecode"`
+	LocCityID      int    `json:"loccityid"`
+}
+
+// Location returns where the user says they're from as steam shows it (ex. "Washington, United States"), using
+// DefaultLocations. Returns "" if they haven't set it. For cities, use Locations.Describe with steam's full list.
+func (u *SteamUser) Location() string {
+	return DefaultLocations.Describe(u.LocCountryCode, u.LocStateCode, u.LocCityID)
+}
+
+// Created returns when the account was made, and false if steam didn't say (the profile is private).
+func (u *SteamUser) Created() (time.Time, bool) {
+	if u.TimeCreated == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(u.TimeCreated, 0), true
+}
+
+// IsProfileConfigured reports whether the user has gone through the steam community profile setup.
+func (u *SteamUser) IsProfileConfigured() bool {
+	return u.ProfileState == ProfileStateConfigured
+}
+
+// CanReceiveComments reports whether anyone is allowed to leave a comment on the user's profile.
+// Profiles limited to friends only return false, since we can't know if the viewer is a friend.
+func (u *SteamUser) CanReceiveComments() bool {
+	return u.CommentPermission == CommentPermissionPublic
 }
@@ -0,0 +1,96 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strings"
+)
+
+// MaxSteamIdsPerRequest is how many steamids the web api takes in one request, for the methods that take a list.
+const MaxSteamIdsPerRequest = 100
+
+// GetSteamUsers gets the users with the provided steamid64s, making one request per 100 users, BatchConcurrency of
+// them at a time. Every id asked for is in the returned map, ids steam didn't return anything for (ex. they don't
+// exist) map to nil. If some requests fail, the users from the rest are still returned, along with a *BatchError
+// saying which ids were left out.
+func (sa *SteamAuther) GetSteamUsers(ctx context.Context, steamid64s []string) (map[string]*SteamUser, error) {
+	chunks := chunkIds(steamid64s)
+	results, errs := fetchChunks(ctx, chunks, sa.BatchConcurrency, sa.getPlayerSummaries)
+
+	users := make(map[string]*SteamUser, len(steamid64s))
+	for i, chunk := range chunks {
+		if errs[i] != nil {
+			continue
+		}
+
+		for _, id := range chunk {
+			users[id] = nil
+		}
+		for j := range results[i] {
+			users[results[i][j].SteamID] = &results[i][j]
+		}
+	}
+
+	if err := batchError(chunks, errs); err != nil {
+		return users, fmt.Errorf("get steam users: %w", err)
+	}
+
+	return users, nil
+}
+
+// SteamUsers is GetSteamUsers as an iterator, fetching one chunk of 100 at a time as it's consumed, so huge lists of
+// users never have to be in memory all at once. Users steam doesn't return anything for are skipped. A chunk that
+// fails is yielded as an error (with a zero SteamUser), and iteration carries on with the next chunk unless the loop
+// breaks.
+//
+//	for user, err := range auther.SteamUsers(ctx, ids) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(user.PersonaName)
+//	}
+func (sa *SteamAuther) SteamUsers(ctx context.Context, steamid64s []string) iter.Seq2[SteamUser, error] {
+	return chunkSeq(ctx, steamid64s, sa.getPlayerSummaries, "steam users")
+}
+
+// getPlayerSummaries gets up to 100 users in a single request.
+func (sa *SteamAuther) getPlayerSummaries(ctx context.Context, steamid64s []string) ([]SteamUser, error) {
+	var data struct {
+		Response struct {
+			Players []SteamUser `json:"players"`
+		} `json:"response"`
+	}
+	ids := strings.Join(steamid64s, ",")
+	if err := sa.getApi(ctx, "ISteamUser/GetPlayerSummaries/v2", url.Values{"steamids": {ids}}, &data); err != nil {
+		return nil, fmt.Errorf("get player summaries (%s): %w", ids, err)
+	}
+
+	return data.Response.Players, nil
+}
+
+// chunkIds splits ids into chunks of up to MaxSteamIdsPerRequest, dropping duplicates.
+func chunkIds(ids []string) [][]string {
+	seen := make(map[string]struct{}, len(ids))
+
+	var chunks [][]string
+	var chunk []string
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		chunk = append(chunk, id)
+		if len(chunk) == MaxSteamIdsPerRequest {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
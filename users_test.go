@@ -0,0 +1,178 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// steamids returns n made up steamid64s.
+func steamids(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.FormatUint(76561197960265728+uint64(i)+1, 10)
+	}
+	return ids
+}
+
+// playerSummaries answers GetPlayerSummaries like steam would, for every steamid asked for except ones ending in 0,
+// which don't exist. It counts the requests made in calls.
+func playerSummaries(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUser/GetPlayerSummaries/v2" {
+			http.NotFound(w, r)
+			return
+		}
+		calls.Add(1)
+
+		ids := strings.Split(r.URL.Query().Get("steamids"), ",")
+		if len(ids) > MaxSteamIdsPerRequest {
+			http.Error(w, "too many steamids", http.StatusBadRequest)
+			return
+		}
+
+		var players []string
+		for _, id := range ids {
+			if !strings.HasSuffix(id, "0") {
+				players = append(players, `{"steamid":"`+id+`","personaname":"player `+id+`"}`)
+			}
+		}
+		w.Write([]byte(`{"response":{"players":[` + strings.Join(players, ",") + `]}}`))
+	}
+}
+
+func TestChunkIds(t *testing.T) {
+	tests := []struct {
+		name string
+		ids  []string
+		want []int
+	}{
+		{"none", nil, nil},
+		{"one chunk", steamids(100), []int{100}},
+		{"split", steamids(250), []int{100, 100, 50}},
+		{"duplicates dropped", append(steamids(100), steamids(3)...), []int{100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := chunkIds(tt.ids)
+
+			var got []int
+			for _, c := range chunks {
+				got = append(got, len(c))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("chunk sizes = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("chunk sizes = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetSteamUsers(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, playerSummaries(&calls))
+	sa := New("key", "https://example.com")
+
+	ids := steamids(250)
+	users, err := sa.GetSteamUsers(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("made %d requests, want 3", calls.Load())
+	}
+	if len(users) != len(ids) {
+		t.Fatalf("got %d users, want %d", len(users), len(ids))
+	}
+	for _, id := range ids {
+		u, ok := users[id]
+		if !ok {
+			t.Fatalf("%s missing from the result", id)
+		}
+		if strings.HasSuffix(id, "0") != (u == nil) {
+			t.Errorf("users[%s] = %+v, want nil only for users that don't exist", id, u)
+		}
+	}
+}
+
+func TestGetSteamUsersSteamDown(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oh no", http.StatusServiceUnavailable)
+	})
+
+	if _, err := New("key", "https://example.com").GetSteamUsers(context.Background(), steamids(3)); !errors.Is(err, ErrSteamUnavailable) {
+		t.Errorf("err = %v, want ErrSteamUnavailable", err)
+	}
+}
+
+func TestSteamUsers(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, playerSummaries(&calls))
+	sa := New("key", "https://example.com")
+
+	ids := steamids(250)
+	var got int
+	for u, err := range sa.SteamUsers(context.Background(), ids) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(u.SteamID, "0") {
+			t.Errorf("yielded %s, which doesn't exist", u.SteamID)
+		}
+		got++
+	}
+	if got != 225 || calls.Load() != 3 {
+		t.Errorf("yielded %d users from %d requests, want 225 from 3", got, calls.Load())
+	}
+
+	// breaking out of the loop stops fetching
+	calls.Store(0)
+	for range sa.SteamUsers(context.Background(), ids) {
+		break
+	}
+	if calls.Load() != 1 {
+		t.Errorf("made %d requests after breaking early, want 1", calls.Load())
+	}
+}
+
+func TestSteamUsersErrors(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oh no", http.StatusServiceUnavailable)
+	})
+	sa := New("key", "https://example.com")
+
+	// a failed chunk is yielded, and iteration carries on with the next one
+	var errs int
+	for _, err := range sa.SteamUsers(context.Background(), steamids(250)) {
+		if !errors.Is(err, ErrSteamUnavailable) {
+			t.Errorf("err = %v, want ErrSteamUnavailable", err)
+		}
+		errs++
+	}
+	if errs != 3 {
+		t.Errorf("yielded %d errors, want one per chunk", errs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	errs = 0
+	for _, err := range sa.SteamUsers(ctx, steamids(250)) {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("yielded %d errors with a cancelled ctx, want 1", errs)
+	}
+}
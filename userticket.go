@@ -0,0 +1,61 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ErrInvalidUserTicket is returned by AuthenticateUserTicket when steam says the ticket isn't valid.
+var ErrInvalidUserTicket = errors.New("invalid user session ticket")
+
+// UserTicket is who a session ticket from a game client belongs to, as represented in the response from the
+// AuthenticateUserTicket web api.
+type UserTicket struct {
+	// SteamID is the steamid64 of the user playing.
+	SteamID string `json:"steamid"`
+	// OwnerSteamID is the steamid64 of who owns the game, which is someone else if it's borrowed through family
+	// sharing.
+	OwnerSteamID    string `json:"ownersteamid"`
+	VACBanned       bool   `json:"vacbanned"`
+	PublisherBanned bool   `json:"publisherbanned"`
+}
+
+// AuthenticateUserTicket validates a session ticket a game client made with ISteamUser::GetAuthTicketForWebApi (or
+// GetAuthSessionTicket), hex encoded. identity is the identity the ticket was made for, and can be "" for tickets
+// that weren't made for one. This is a publisher only method, so it needs a publisher api key for the app (see
+// PublisherApiKey), and returns ErrPublisherKeyRequired if steam refuses the key. Returns an error wrapping
+// ErrInvalidUserTicket if steam doesn't accept the ticket.
+func (sa *SteamAuther) AuthenticateUserTicket(ctx context.Context, appid int, ticket, identity string) (*UserTicket, error) {
+	q := url.Values{"appid": {strconv.Itoa(appid)}, "ticket": {ticket}}
+	if identity != "" {
+		q.Set("identity", identity)
+	}
+
+	var data struct {
+		Response struct {
+			Params *struct {
+				UserTicket
+				Result string `json:"result"`
+			} `json:"params"`
+			Error *struct {
+				ErrorCode int    `json:"errorcode"`
+				ErrorDesc string `json:"errordesc"`
+			} `json:"error"`
+		} `json:"response"`
+	}
+	if err := sa.getPublisherApi(ctx, "ISteamUserAuth/AuthenticateUserTicket/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("authenticate user ticket (%d): %w", appid, err)
+	}
+
+	if e := data.Response.Error; e != nil {
+		return nil, fmt.Errorf("authenticate user ticket (%d): %w: %s (%d)", appid, ErrInvalidUserTicket, e.ErrorDesc, e.ErrorCode)
+	}
+	if p := data.Response.Params; p == nil || p.Result != "OK" {
+		return nil, fmt.Errorf("authenticate user ticket (%d): %w", appid, ErrInvalidUserTicket)
+	}
+
+	return &data.Response.Params.UserTicket, nil
+}
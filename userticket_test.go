@@ -0,0 +1,83 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// userTickets answers AuthenticateUserTicket like steam would, accepting ticket "14000000" for app 480 made for the
+// "backend" identity, and one borrowed through family sharing.
+func userTickets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if r.URL.Path != "/ISteamUserAuth/AuthenticateUserTicket/v1" || q.Get("appid") != "480" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case q.Get("ticket") == "14000000" && q.Get("identity") == "backend":
+		w.Write([]byte(`{"response":{"params":{"result":"OK","steamid":"76561197960287930","ownersteamid":"76561197960287930","vacbanned":false,"publisherbanned":false}}}`))
+	case q.Get("ticket") == "14000001" && !q.Has("identity"):
+		w.Write([]byte(`{"response":{"params":{"result":"OK","steamid":"76561197960287930","ownersteamid":"76561197960287931","vacbanned":true,"publisherbanned":false}}}`))
+	default:
+		w.Write([]byte(`{"response":{"error":{"errorcode":101,"errordesc":"Invalid ticket"}}}`))
+	}
+}
+
+func TestAuthenticateUserTicket(t *testing.T) {
+	fakeSteam(t, userTickets)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name     string
+		ticket   string
+		identity string
+		want     UserTicket
+		wantErr  error
+	}{
+		{"valid", "14000000", "backend", UserTicket{SteamID: "76561197960287930", OwnerSteamID: "76561197960287930"}, nil},
+		{"family shared", "14000001", "", UserTicket{SteamID: "76561197960287930", OwnerSteamID: "76561197960287931", VACBanned: true}, nil},
+		{"wrong identity", "14000000", "other", UserTicket{}, ErrInvalidUserTicket},
+		{"forged", "deadbeef", "", UserTicket{}, ErrInvalidUserTicket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sa.AuthenticateUserTicket(context.Background(), 480, tt.ticket, tt.identity)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && *got != tt.want {
+				t.Errorf("AuthenticateUserTicket = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthenticateUserTicketPublisherKey(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "partner.steam-api.com" || r.URL.Query().Get("key") != "pubkey" {
+			t.Errorf("called %s with key %q, want the partner host and the publisher key", r.Host, r.URL.Query().Get("key"))
+		}
+		userTickets(w, r)
+	})
+	sa := New("key", "https://example.com")
+	sa.PublisherApiKey = "pubkey"
+
+	if _, err := sa.AuthenticateUserTicket(context.Background(), 480, "14000000", "backend"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthenticateUserTicketNotPublisher(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	})
+
+	_, err := New("key", "https://example.com").AuthenticateUserTicket(context.Background(), 480, "14000000", "backend")
+	if !errors.Is(err, ErrPublisherKeyRequired) {
+		t.Errorf("err = %v, want ErrPublisherKeyRequired", err)
+	}
+}
@@ -0,0 +1,69 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ErrVanityNotFound is returned by ResolveVanityURL when nobody has the vanity url.
+var ErrVanityNotFound = errors.New("no match for vanity url")
+
+// VanityType is the kind of thing a vanity url belongs to.
+type VanityType int
+
+const (
+	// VanityIndividual is a user's profile, ex. https://steamcommunity.com/id/gaben
+	VanityIndividual VanityType = 1
+	// VanityGroup is a group, ex. https://steamcommunity.com/groups/steamworks
+	VanityGroup VanityType = 2
+	// VanityGameGroup is a game's official group, ex. https://steamcommunity.com/games/tf2
+	VanityGameGroup VanityType = 3
+)
+
+// VanityResult is a resolved vanity url.
+type VanityResult struct {
+	// SteamID is the steamid64 of the user or group.
+	SteamID string
+	// Type is what the vanity url belongs to.
+	Type VanityType
+}
+
+// ResolveVanityURL turns a custom profile url (the "gaben" in https://steamcommunity.com/id/gaben) into the user's
+// steamid64. Returns ErrVanityNotFound if nobody has it.
+func (sa *SteamAuther) ResolveVanityURL(ctx context.Context, vanity string) (string, error) {
+	res, err := sa.ResolveVanity(ctx, vanity, VanityIndividual)
+	if err != nil {
+		return "", err
+	}
+
+	return res.SteamID, nil
+}
+
+// ResolveVanity turns a vanity url of the given type into the steamid64 it belongs to, so group urls can be resolved
+// too. Returns ErrVanityNotFound if nothing of that type has it.
+func (sa *SteamAuther) ResolveVanity(ctx context.Context, vanity string, typ VanityType) (*VanityResult, error) {
+	var data struct {
+		Response struct {
+			SteamID string `json:"steamid"`
+			// Success is 1 for a match, and 42 for no match.
+			Success int    `json:"success"`
+			Message string `json:"message"`
+		} `json:"response"`
+	}
+	q := url.Values{"vanityurl": {vanity}, "url_type": {strconv.Itoa(int(typ))}}
+	if err := sa.getApi(ctx, "ISteamUser/ResolveVanityURL/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("resolve vanity url (%s): %w", vanity, err)
+	}
+
+	switch data.Response.Success {
+	case 1:
+		return &VanityResult{SteamID: data.Response.SteamID, Type: typ}, nil
+	case 42:
+		return nil, fmt.Errorf("resolve vanity url (%s): %w", vanity, ErrVanityNotFound)
+	}
+
+	return nil, fmt.Errorf("resolve vanity url (%s): steam said %d (%s)", vanity, data.Response.Success, data.Response.Message)
+}
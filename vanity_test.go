@@ -0,0 +1,98 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// vanityUrls answers ResolveVanityURL like steam would, knowing only the user "gaben", the group "steamworks", the
+// game group "tf2" (and "broken", which steam can't look up right now).
+func vanityUrls(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/ISteamUser/ResolveVanityURL/v1" {
+		http.NotFound(w, r)
+		return
+	}
+
+	// steam defaults to individuals when url_type isn't given
+	typ := r.URL.Query().Get("url_type")
+	if typ == "" {
+		typ = "1"
+	}
+
+	switch typ + "/" + r.URL.Query().Get("vanityurl") {
+	case "1/gaben":
+		w.Write([]byte(`{"response":{"steamid":"76561197960287930","success":1}}`))
+	case "2/steamworks":
+		w.Write([]byte(`{"response":{"steamid":"103582791429521412","success":1}}`))
+	case "3/tf2":
+		w.Write([]byte(`{"response":{"steamid":"103582791429521664","success":1}}`))
+	case "1/broken":
+		w.Write([]byte(`{"response":{"success":2,"message":"Internal error"}}`))
+	default:
+		w.Write([]byte(`{"response":{"success":42,"message":"No match"}}`))
+	}
+}
+
+func TestResolveVanityURL(t *testing.T) {
+	fakeSteam(t, vanityUrls)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		vanity  string
+		want    string
+		wantErr error
+	}{
+		{"gaben", "76561197960287930", nil},
+		{"nobody", "", ErrVanityNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vanity, func(t *testing.T) {
+			got, err := sa.ResolveVanityURL(context.Background(), tt.vanity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveVanityURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// anything else steam says is an error, but not a missing user
+	if _, err := sa.ResolveVanityURL(context.Background(), "broken"); err == nil || errors.Is(err, ErrVanityNotFound) {
+		t.Errorf("broken err = %v, want an error other than ErrVanityNotFound", err)
+	}
+}
+
+func TestResolveVanity(t *testing.T) {
+	fakeSteam(t, vanityUrls)
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		vanity  string
+		typ     VanityType
+		want    string
+		wantErr error
+	}{
+		{"gaben", VanityIndividual, "76561197960287930", nil},
+		{"steamworks", VanityGroup, "103582791429521412", nil},
+		{"tf2", VanityGameGroup, "103582791429521664", nil},
+		// vanity urls are per type, a group's isn't a user's
+		{"steamworks", VanityIndividual, "", ErrVanityNotFound},
+		{"gaben", VanityGroup, "", ErrVanityNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vanity, func(t *testing.T) {
+			res, err := sa.ResolveVanity(context.Background(), tt.vanity, tt.typ)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (res.SteamID != tt.want || res.Type != tt.typ) {
+				t.Errorf("ResolveVanity = %+v, want %s of type %d", res, tt.want, tt.typ)
+			}
+		})
+	}
+}
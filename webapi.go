@@ -0,0 +1,296 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// ErrPrivateProfile is returned by web api calls when the user's profile (or the part of it being asked for) is
+// private, so steam won't hand it over.
+var ErrPrivateProfile = errors.New("the user's profile is private")
+
+// ErrSteamForbidden is returned by calls to steam when it answers with a 401 or 403 that doesn't say anything more.
+// For web api calls that almost always means the api key is wrong, or isn't allowed to call the method. Methods that
+// can tell it apart from the user hiding their data return ErrPrivateProfile instead.
+var ErrSteamForbidden = errors.New("steam refused the request")
+
+// forbiddenError is a 401 or 403 from steam, keeping the status code so endpoints that use it to mean a private
+// profile can tell, see privateOn.
+type forbiddenError struct {
+	code   int
+	status string
+}
+
+func (e *forbiddenError) Error() string {
+	return fmt.Sprintf("%s (%s)", ErrSteamForbidden, e.status)
+}
+
+func (e *forbiddenError) Is(target error) bool {
+	return target == ErrSteamForbidden
+}
+
+// privateOn turns a 401 or 403 with one of codes into ErrPrivateProfile, for the endpoints that answer with a status
+// code when a user's data is hidden. Anything else is returned as is.
+func privateOn(err error, codes ...int) error {
+	var fe *forbiddenError
+	if errors.As(err, &fe) && slices.Contains(codes, fe.code) {
+		return fmt.Errorf("%w (%s)", ErrPrivateProfile, fe.status)
+	}
+
+	return err
+}
+
+// ErrSteamRateLimited is returned by calls to steam when it answers with a 429, because too many requests have been
+// made to it recently.
+var ErrSteamRateLimited = errors.New("steam is rate limiting requests")
+
+// WebApiBaseUrl is the base url of the steam web api.
+const WebApiBaseUrl = "https://api.steampowered.com"
+
+// PartnerApiBaseUrl is the base url of the steam web api for publisher only methods, which need a publisher api key.
+// Every other method works on it too, see SteamAuther.Partner.
+const PartnerApiBaseUrl = "https://partner.steam-api.com"
+
+// ErrPublisherKeyRequired is returned by publisher only methods (like CheckAppOwnership) when steam refuses the api
+// key, because it isn't a publisher key for the app. See SteamAuther.PublisherApiKey.
+var ErrPublisherKeyRequired = errors.New("the method needs a publisher api key")
+
+// Call calls any web api method, for the ones the package doesn't wrap, decoding the json response into out. The api
+// key is added to params for you, and errors are the same as the wrapped methods' (ex. ErrSteamForbidden,
+// ErrSteamUnavailable).
+//
+//	var out struct {
+//		Response struct {
+//			PlayerLevel int `json:"player_level"`
+//		} `json:"response"`
+//	}
+//	err := auther.Call(ctx, "IPlayerService", "GetSteamLevel", 1, url.Values{"steamid": {id}}, &out)
+func (sa *SteamAuther) Call(ctx context.Context, iface, method string, version int, params url.Values, out any) error {
+	path := fmt.Sprintf("%s/%s/v%d", iface, method, version)
+	if err := sa.getApi(ctx, path, params, out); err != nil {
+		return fmt.Errorf("call %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// CallPost is Call for methods that have to be POSTed.
+func (sa *SteamAuther) CallPost(ctx context.Context, iface, method string, version int, params url.Values, out any) error {
+	path := fmt.Sprintf("%s/%s/v%d", iface, method, version)
+	if err := sa.postApi(ctx, path, params, out); err != nil {
+		return fmt.Errorf("call %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// getApi calls a steam web api method (ex. "ISteamUser/GetUserGroupList/v1") and decodes the response into out.
+// The api key is added to params for you.
+func (sa *SteamAuther) getApi(ctx context.Context, method string, params url.Values, out any) error {
+	return sa.getApiAt(ctx, WebApiBaseUrl, method, params, out)
+}
+
+// getPublisherApi is getApi for publisher only methods, which are called on PartnerApiBaseUrl. Steam refusing the key
+// comes back as ErrPublisherKeyRequired.
+func (sa *SteamAuther) getPublisherApi(ctx context.Context, method string, params url.Values, out any) error {
+	err := sa.getApiAt(ctx, PartnerApiBaseUrl, method, params, out)
+	if errors.Is(err, ErrSteamForbidden) {
+		return fmt.Errorf("%w (%s)", ErrPublisherKeyRequired, method)
+	}
+
+	return err
+}
+
+// getApiAt is getApi against a different base url, see PartnerApiBaseUrl.
+func (sa *SteamAuther) getApiAt(ctx context.Context, base, method string, params url.Values, out any) error {
+	return sa.streamApiAt(ctx, base, method, params, func(dec *json.Decoder) error {
+		return dec.Decode(out)
+	})
+}
+
+// streamApi is getApi, handing the response to decode as it comes in rather than decoding it all at once. For
+// responses too big to want in memory all at once.
+func (sa *SteamAuther) streamApi(ctx context.Context, method string, params url.Values, decode func(dec *json.Decoder) error) error {
+	return sa.streamApiAt(ctx, WebApiBaseUrl, method, params, decode)
+}
+
+// streamApiAt is streamApi against a different base url.
+func (sa *SteamAuther) streamApiAt(ctx context.Context, base, method string, params url.Values, decode func(dec *json.Decoder) error) error {
+	base, q := sa.apiQuery(base, params)
+	res, err := sa.get(ctx, base+"/"+method, q)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := decode(json.NewDecoder(res.Body)); err != nil {
+		return fmt.Errorf("decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// apiQuery returns the base url to call a method meant for base on (see apiBase), and params with the api key for
+// it added, which is the PublisherApiKey on PartnerApiBaseUrl if there is one.
+func (sa *SteamAuther) apiQuery(base string, params url.Values) (string, url.Values) {
+	q := url.Values{}
+	for k, v := range params {
+		q[k] = v
+	}
+	base = sa.apiBase(base)
+	q.Set("key", sa.apiKey)
+	if base == PartnerApiBaseUrl && sa.PublisherApiKey != "" {
+		q.Set("key", sa.PublisherApiKey)
+	}
+
+	return base, q
+}
+
+// postApi is getApi for the few web api methods that have to be POSTed, with params sent as a form.
+func (sa *SteamAuther) postApi(ctx context.Context, method string, params url.Values, out any) error {
+	return sa.postApiAt(ctx, WebApiBaseUrl, method, params, out)
+}
+
+// postPublisherApi is getPublisherApi for publisher only methods that have to be POSTed.
+func (sa *SteamAuther) postPublisherApi(ctx context.Context, method string, params url.Values, out any) error {
+	err := sa.postApiAt(ctx, PartnerApiBaseUrl, method, params, out)
+	if errors.Is(err, ErrSteamForbidden) {
+		return fmt.Errorf("%w (%s)", ErrPublisherKeyRequired, method)
+	}
+
+	return err
+}
+
+// postApiAt is postApi against a different base url.
+func (sa *SteamAuther) postApiAt(ctx context.Context, base, method string, params url.Values, out any) error {
+	base, form := sa.apiQuery(base, params)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/"+method, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("make request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := sa.do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response body: %w", err)
+	}
+
+	return nil
+}
+
+// apiBase is the base url to call web api methods meant for base on, which is always PartnerApiBaseUrl in partner
+// mode.
+func (sa *SteamAuther) apiBase(base string) string {
+	if sa.Partner {
+		return PartnerApiBaseUrl
+	}
+
+	return base
+}
+
+// get makes a GET request to steam, returning the response if it's a 200 (or one of accept, for endpoints that put
+// something worth reading in their error responses). The caller has to close the body.
+func (sa *SteamAuther) get(ctx context.Context, rawUrl string, q url.Values, accept ...int) (*http.Response, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, fmt.Errorf("parse api url: %w", err)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("make request: %w", err)
+	}
+
+	return sa.do(req, accept...)
+}
+
+// do makes a request to steam, returning the response if it's a 200 (or one of accept). The caller has to close the
+// body.
+func (sa *SteamAuther) do(req *http.Request, accept ...int) (*http.Response, error) {
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("make %s request: %w: %w", strings.ToLower(req.Method), ErrSteamUnavailable, err)
+	}
+
+	if res.StatusCode == http.StatusOK || slices.Contains(accept, res.StatusCode) {
+		return res, nil
+	}
+	res.Body.Close()
+
+	return nil, statusError(res)
+}
+
+// statusError is the error for a response from steam that isn't a 200.
+func statusError(res *http.Response) error {
+	if res.StatusCode >= 500 {
+		return fmt.Errorf("%w (%s)", ErrSteamUnavailable, res.Status)
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		return ErrSteamRateLimited
+	}
+
+	// A bad api key gets a 403 too, so only the endpoint knows whether it means the user's data is hidden.
+	if res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden {
+		return &forbiddenError{code: res.StatusCode, status: res.Status}
+	}
+
+	return fmt.Errorf("status code is not 200 (%s)", res.Status)
+}
+
+// seekJSONArray moves dec to the start of the array at path, through nested objects, so its items can be decoded one
+// at a time with dec.More and dec.Decode.
+func seekJSONArray(dec *json.Decoder, path ...string) error {
+	for _, key := range path {
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+
+		for {
+			t, err := dec.Token()
+			if err != nil {
+				return err
+			}
+
+			k, ok := t.(string)
+			if !ok {
+				return fmt.Errorf("%q not found", key)
+			}
+			if k == key {
+				break
+			}
+
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+
+	return expectDelim(dec, '[')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := t.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected %s, got %v", want, t)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCall(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch {
+		case r.Form.Get("key") != "key":
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		case r.Method == http.MethodGet && r.URL.Path == "/IPlayerService/GetSteamLevel/v1" && r.Form.Get("steamid") == "76561197960287930":
+			w.Write([]byte(`{"response":{"player_level":42}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/IGameServersService/SetMemo/v1" && r.PostForm.Get("memo") == "hi":
+			w.Write([]byte(`{"response":{"ok":true}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	sa := New("key", "https://example.com")
+
+	var level struct {
+		Response struct {
+			PlayerLevel int `json:"player_level"`
+		} `json:"response"`
+	}
+	if err := sa.Call(context.Background(), "IPlayerService", "GetSteamLevel", 1, url.Values{"steamid": {"76561197960287930"}}, &level); err != nil {
+		t.Fatal(err)
+	}
+	if level.Response.PlayerLevel != 42 {
+		t.Errorf("level = %d, want 42", level.Response.PlayerLevel)
+	}
+
+	var memo struct {
+		Response struct {
+			Ok bool `json:"ok"`
+		} `json:"response"`
+	}
+	if err := sa.CallPost(context.Background(), "IGameServersService", "SetMemo", 1, url.Values{"memo": {"hi"}}, &memo); err != nil {
+		t.Fatal(err)
+	}
+	if !memo.Response.Ok {
+		t.Error("CallPost didn't decode the response")
+	}
+
+	err := New("wrong", "https://example.com").Call(context.Background(), "IPlayerService", "GetSteamLevel", 1, nil, &level)
+	if !errors.Is(err, ErrSteamForbidden) {
+		t.Errorf("wrong key err = %v, want ErrSteamForbidden", err)
+	}
+}
@@ -0,0 +1,48 @@
+package gosteamauth
+
+import (
+	"context"
+	"fmt"
+)
+
+// ApiInterface is a web api interface (ex. "ISteamUser"), as represented in the response from the
+// GetSupportedAPIList web api.
+type ApiInterface struct {
+	Name    string      `json:"name"`
+	Methods []ApiMethod `json:"methods"`
+}
+
+// ApiMethod is a method of a web api interface.
+type ApiMethod struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	// HttpMethod is "GET" or "POST".
+	HttpMethod  string         `json:"httpmethod"`
+	Description string         `json:"description"`
+	Parameters  []ApiParameter `json:"parameters"`
+}
+
+// ApiParameter is a parameter of a web api method.
+type ApiParameter struct {
+	// Name is the parameter's name. Array parameters end in "[0]".
+	Name string `json:"name"`
+	// Type is steam's name for the parameter's type (ex. "uint64", "string", "bool", "{enum}").
+	Type        string `json:"type"`
+	Optional    bool   `json:"optional"`
+	Description string `json:"description"`
+}
+
+// GetSupportedAPIList gets every web api interface and method the api key can call, with their parameters. Publisher
+// keys see more of them. cmd/genapi uses it to generate methods for the ones this package doesn't wrap.
+func (sa *SteamAuther) GetSupportedAPIList(ctx context.Context) ([]ApiInterface, error) {
+	var data struct {
+		ApiList struct {
+			Interfaces []ApiInterface `json:"interfaces"`
+		} `json:"apilist"`
+	}
+	if err := sa.getApi(ctx, "ISteamWebAPIUtil/GetSupportedAPIList/v1", nil, &data); err != nil {
+		return nil, fmt.Errorf("get supported api list: %w", err)
+	}
+
+	return data.ApiList.Interfaces, nil
+}
@@ -0,0 +1,33 @@
+package gosteamauth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+// apiList is a cut down GetSupportedAPIList response.
+const apiList = `{"apilist":{"interfaces":[{"name":"ISteamUser","methods":[{"name":"GetPlayerSummaries","version":2,"httpmethod":"GET","parameters":[{"name":"key","type":"string","optional":false,"description":"access key"},{"name":"steamids","type":"string","optional":false,"description":"Comma-delimited list of SteamIDs"}]}]},{"name":"IGameServersService","methods":[{"name":"SetMemo","version":1,"httpmethod":"POST","description":"Changes the memo","parameters":[{"name":"steamid","type":"uint64","optional":false},{"name":"memo","type":"string","optional":false}]}]}]}}`
+
+func TestGetSupportedAPIList(t *testing.T) {
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamWebAPIUtil/GetSupportedAPIList/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(apiList))
+	})
+
+	list, err := New("key", "https://example.com").GetSupportedAPIList(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(list) != 2 || list[0].Name != "ISteamUser" || list[1].Name != "IGameServersService" {
+		t.Fatalf("interfaces = %+v, want ISteamUser and IGameServersService", list)
+	}
+	m := list[1].Methods[0]
+	if m.Name != "SetMemo" || m.Version != 1 || m.HttpMethod != "POST" || len(m.Parameters) != 2 || m.Parameters[0].Type != "uint64" {
+		t.Errorf("method = %+v, want SetMemo with its parameters", m)
+	}
+}
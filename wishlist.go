@@ -0,0 +1,164 @@
+package gosteamauth
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// WishlistItem is an app on a user's wishlist, as represented in the store's wishlistdata response.
+type WishlistItem struct {
+	AppID int    `json:"-"`
+	Name  string `json:"name"`
+	// Capsule is the url of the app's capsule image.
+	Capsule string `json:"capsule"`
+	Type    string `json:"type"`
+	// ReviewScore is steam's 1 to 9 review score, with ReviewDesc being its name (ex. "Very Positive").
+	ReviewScore    int    `json:"review_score"`
+	ReviewDesc     string `json:"review_desc"`
+	ReviewsPercent int    `json:"reviews_percent"`
+	// ReleaseString is the app's release date as the store shows it (ex. "Coming soon").
+	ReleaseString string   `json:"release_string"`
+	Tags          []string `json:"tags"`
+	IsFreeGame    bool     `json:"is_free_game"`
+	// Priority is where the user has the app in their wishlist's order, starting at 1. 0 means it hasn't been ordered.
+	Priority int `json:"priority"`
+	// AddedAt is when the app was added to the wishlist, as a unix timestamp.
+	AddedAt int64 `json:"added"`
+}
+
+// Added returns when the app was added to the wishlist.
+func (w *WishlistItem) Added() time.Time {
+	return time.Unix(w.AddedAt, 0)
+}
+
+// WishlistPage is a page of a user's wishlist. Steam hands them out 100 apps at a time.
+type WishlistPage struct {
+	// Page is the page number, starting at 1.
+	Page int
+	// Items are the apps on the page, in the user's order.
+	Items []WishlistItem
+	// NextPage is the number of the next page, or 0 if this is the last one. Steam doesn't say how many pages there
+	// are, so this is only 0 once a page comes back empty.
+	NextPage int
+}
+
+// GetWishlistPage gets a page of the user's wishlist, pages start at 1. There's no web api for this, so it comes
+// from the store. Returns ErrPrivateProfile if the user's wishlist is private.
+func (sa *SteamAuther) GetWishlistPage(ctx context.Context, steamid64 string, page int) (*WishlistPage, error) {
+	u := fmt.Sprintf("%s/wishlist/profiles/%s/wishlistdata/", StoreBaseUrl, url.PathEscape(steamid64))
+	res, err := sa.get(ctx, u, url.Values{"p": {strconv.Itoa(page - 1)}})
+	if err != nil {
+		return nil, fmt.Errorf("get wishlist page (%s, %d): %w", steamid64, page, err)
+	}
+	defer res.Body.Close()
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("get wishlist page (%s, %d): decode response body: %w", steamid64, page, err)
+	}
+
+	p := &WishlistPage{Page: page}
+
+	// Past the last page steam sends an empty array rather than an empty object.
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		return p, nil
+	}
+
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("get wishlist page (%s, %d): decode response body: %w", steamid64, page, err)
+	}
+
+	// Private wishlists come back as {"success": 2}.
+	if _, ok := data["success"]; ok {
+		return nil, fmt.Errorf("get wishlist page (%s, %d): %w", steamid64, page, ErrPrivateProfile)
+	}
+
+	for id, itemRaw := range data {
+		appid, err := strconv.Atoi(id)
+		if err != nil {
+			continue
+		}
+
+		item := WishlistItem{AppID: appid}
+		if err := json.Unmarshal(itemRaw, &item); err != nil {
+			return nil, fmt.Errorf("get wishlist page (%s, %d): decode app %d: %w", steamid64, page, appid, err)
+		}
+		p.Items = append(p.Items, item)
+	}
+
+	// The response is keyed by appid, so the user's order has to be put back. Unordered apps go last.
+	slices.SortFunc(p.Items, func(a, b WishlistItem) int {
+		if (a.Priority == 0) != (b.Priority == 0) {
+			if a.Priority == 0 {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(a.Priority, b.Priority), cmp.Compare(a.AddedAt, b.AddedAt))
+	})
+
+	if len(p.Items) > 0 {
+		p.NextPage = page + 1
+	}
+
+	return p, nil
+}
+
+// Wishlist is the user's whole wishlist as an iterator, fetching a page at a time as it's consumed. Errors are
+// yielded with an empty WishlistItem, and end the iteration.
+func (sa *SteamAuther) Wishlist(ctx context.Context, steamid64 string) iter.Seq2[WishlistItem, error] {
+	return func(yield func(WishlistItem, error) bool) {
+		for page := 1; page != 0; {
+			p, err := sa.GetWishlistPage(ctx, steamid64, page)
+			if err != nil {
+				yield(WishlistItem{}, err)
+				return
+			}
+
+			for _, item := range p.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			page = p.NextPage
+		}
+	}
+}
+
+// GetWishlist is Wishlist, collected into a slice.
+func (sa *SteamAuther) GetWishlist(ctx context.Context, steamid64 string) ([]WishlistItem, error) {
+	var items []WishlistItem
+	for item, err := range sa.Wishlist(ctx, steamid64) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// OnWishlist reports whether the app is on the user's wishlist. Returns ErrPrivateProfile if the user's wishlist is
+// private.
+func (sa *SteamAuther) OnWishlist(ctx context.Context, steamid64 string, appid int) (bool, error) {
+	for item, err := range sa.Wishlist(ctx, steamid64) {
+		if err != nil {
+			return false, err
+		}
+		if item.AppID == appid {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,122 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// wishlists answers the store's wishlistdata like steam would, for a user with three apps over two pages and a user
+// with a private wishlist. It counts the requests made in calls.
+func wishlists(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/wishlist/profiles/76561197960287930/wishlistdata/":
+		case "/wishlist/profiles/76561197960287931/wishlistdata/":
+			w.Write([]byte(`{"success":2}`))
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Query().Get("p") {
+		case "0":
+			w.Write([]byte(`{
+				"440":{"name":"Team Fortress 2","priority":0,"added":1300000000,"is_free_game":true},
+				"620":{"name":"Portal 2","priority":2,"added":1200000000,"review_score":9,"review_desc":"Overwhelmingly Positive"},
+				"570":{"name":"Dota 2","priority":1,"added":1400000000}
+			}`))
+		case "1":
+			w.Write([]byte(`{"730":{"name":"Counter-Strike 2","priority":3,"added":1500000000}}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}
+}
+
+func TestGetWishlistPage(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, wishlists(&calls))
+	sa := New("key", "https://example.com")
+
+	p, err := sa.GetWishlistPage(context.Background(), "76561197960287930", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// in the user's order, with the unordered app last
+	var got []int
+	for _, item := range p.Items {
+		got = append(got, item.AppID)
+	}
+	if len(got) != 3 || got[0] != 570 || got[1] != 620 || got[2] != 440 || p.NextPage != 2 {
+		t.Fatalf("page = %v next %d, want [570 620 440] next 2", got, p.NextPage)
+	}
+	if p.Items[1].ReviewDesc != "Overwhelmingly Positive" || p.Items[2].Added().Unix() != 1300000000 {
+		t.Errorf("items = %+v, want the details decoded", p.Items)
+	}
+
+	p, err = sa.GetWishlistPage(context.Background(), "76561197960287930", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Items) != 0 || p.NextPage != 0 {
+		t.Errorf("page past the end = %+v, want an empty last page", p)
+	}
+
+	if _, err := sa.GetWishlistPage(context.Background(), "76561197960287931", 1); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private wishlist err = %v, want ErrPrivateProfile", err)
+	}
+}
+
+func TestGetWishlist(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, wishlists(&calls))
+	sa := New("key", "https://example.com")
+
+	items, err := sa.GetWishlist(context.Background(), "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 4 || items[3].AppID != 730 || calls.Load() != 3 {
+		t.Errorf("got %d items from %d requests, want 4 from 3", len(items), calls.Load())
+	}
+
+	if _, err := sa.GetWishlist(context.Background(), "76561197960287931"); !errors.Is(err, ErrPrivateProfile) {
+		t.Errorf("private wishlist err = %v, want ErrPrivateProfile", err)
+	}
+}
+
+func TestOnWishlist(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, wishlists(&calls))
+	sa := New("key", "https://example.com")
+
+	tests := []struct {
+		name      string
+		appid     int
+		want      bool
+		wantCalls int32
+	}{
+		// stops at the page it's found on
+		{"first page", 620, true, 1},
+		{"second page", 730, true, 2},
+		{"not on it", 10, false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			got, err := sa.OnWishlist(context.Background(), "76561197960287930", tt.appid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || calls.Load() != tt.wantCalls {
+				t.Errorf("OnWishlist = %v after %d requests, want %v after %d", got, calls.Load(), tt.want, tt.wantCalls)
+			}
+		})
+	}
+}
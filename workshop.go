@@ -0,0 +1,288 @@
+package gosteamauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PublishedFile is a workshop item, as represented in the responses from the workshop web apis.
+type PublishedFile struct {
+	PublishedFileID string `json:"publishedfileid"`
+	// Creator is the steamid64 of who made the item.
+	Creator string `json:"creator"`
+	// CreatorAppID is the app used to upload the item, and ConsumerAppID the game it's for.
+	CreatorAppID  int    `json:"creator_app_id"`
+	ConsumerAppID int    `json:"consumer_app_id"`
+	Filename      string `json:"filename"`
+	// FileSize is in bytes. Steam sends it as both a number and a string.
+	FileSize json.Number `json:"file_size"`
+	// FileUrl is where to download the item from. It's empty for items that are downloaded through steam.
+	FileUrl      string `json:"file_url"`
+	HContentFile string `json:"hcontent_file"`
+	PreviewUrl   string `json:"preview_url"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	// TimeCreated and TimeUpdated are unix timestamps.
+	TimeCreated int64 `json:"time_created"`
+	TimeUpdated int64 `json:"time_updated"`
+	// Visibility is 0 for public, 1 for friends only, 2 for private and 3 for unlisted.
+	Visibility            int    `json:"visibility"`
+	Banned                bool   `json:"banned"`
+	BanReason             string `json:"ban_reason"`
+	Subscriptions         int    `json:"subscriptions"`
+	Favorited             int    `json:"favorited"`
+	LifetimeSubscriptions int    `json:"lifetime_subscriptions"`
+	LifetimeFavorited     int    `json:"lifetime_favorited"`
+	Views                 int    `json:"views"`
+	Tags                  []struct {
+		Tag string `json:"tag"`
+	} `json:"tags"`
+}
+
+// Updated returns when the item was last updated.
+func (f *PublishedFile) Updated() time.Time {
+	return time.Unix(f.TimeUpdated, 0)
+}
+
+// GetPublishedFileDetails gets the workshop items with the provided ids, making one request per 100 items,
+// BatchConcurrency of them at a time. Every id asked for is in the returned map, items steam couldn't find (or won't
+// show, like private ones) map to nil. If some requests fail, the items from the rest are still returned, along with
+// a *BatchError saying which ids were left out.
+func (sa *SteamAuther) GetPublishedFileDetails(ctx context.Context, ids []string) (map[string]*PublishedFile, error) {
+	chunks := chunkIds(ids)
+	results, errs := fetchChunks(ctx, chunks, sa.BatchConcurrency, sa.getPublishedFileDetails)
+
+	files := make(map[string]*PublishedFile, len(ids))
+	for i, chunk := range chunks {
+		if errs[i] != nil {
+			continue
+		}
+
+		for _, id := range chunk {
+			files[id] = nil
+		}
+		for j := range results[i] {
+			files[results[i][j].PublishedFileID] = &results[i][j]
+		}
+	}
+
+	if err := batchError(chunks, errs); err != nil {
+		return files, fmt.Errorf("get published file details: %w", err)
+	}
+
+	return files, nil
+}
+
+// PublishedFiles is GetPublishedFileDetails as an iterator, fetching one chunk of 100 at a time as it's consumed.
+// Items steam couldn't find are skipped, and failed chunks are yielded as errors like SteamUsers.
+func (sa *SteamAuther) PublishedFiles(ctx context.Context, ids []string) iter.Seq2[PublishedFile, error] {
+	return chunkSeq(ctx, ids, sa.getPublishedFileDetails, "published files")
+}
+
+// getPublishedFileDetails gets up to 100 workshop items in a single request, leaving out ones steam couldn't find.
+func (sa *SteamAuther) getPublishedFileDetails(ctx context.Context, ids []string) ([]PublishedFile, error) {
+	form := url.Values{"itemcount": {strconv.Itoa(len(ids))}}
+	for i, id := range ids {
+		form.Set("publishedfileids["+strconv.Itoa(i)+"]", id)
+	}
+
+	var data struct {
+		Response struct {
+			PublishedFileDetails []struct {
+				PublishedFile
+				Result int `json:"result"`
+			} `json:"publishedfiledetails"`
+		} `json:"response"`
+	}
+	if err := sa.postApi(ctx, "ISteamRemoteStorage/GetPublishedFileDetails/v1", form, &data); err != nil {
+		return nil, fmt.Errorf("get published file details (%d items): %w", len(ids), err)
+	}
+
+	files := make([]PublishedFile, 0, len(data.Response.PublishedFileDetails))
+	for _, f := range data.Response.PublishedFileDetails {
+		if f.Result == 1 {
+			files = append(files, f.PublishedFile)
+		}
+	}
+
+	return files, nil
+}
+
+// WorkshopSort is how QueryFiles orders workshop items, steam's EPublishedFileQueryType.
+type WorkshopSort int
+
+const (
+	WorkshopSortVotes            WorkshopSort = 0
+	WorkshopSortPublicationDate  WorkshopSort = 1
+	WorkshopSortTrend            WorkshopSort = 3
+	WorkshopSortSubscriptions    WorkshopSort = 9
+	WorkshopSortTextSearch       WorkshopSort = 12
+	WorkshopSortPlaytimeTrend    WorkshopSort = 13
+	WorkshopSortLifetimePlaytime WorkshopSort = 15
+	WorkshopSortLastUpdated      WorkshopSort = 21
+)
+
+// WorkshopQuery is what to ask QueryFiles for.
+type WorkshopQuery struct {
+	// AppID is the game to search the workshop of.
+	AppID int
+	Sort  WorkshopSort
+	// SearchText only gets items matching it. Use WorkshopSortTextSearch to order them by how well they match.
+	SearchText string
+	// RequiredTags only gets items with any of these tags, or all of them if MatchAllTags is set.
+	RequiredTags []string
+	MatchAllTags bool
+	// ExcludedTags leaves out items with any of these tags.
+	ExcludedTags []string
+	// Days is how many days WorkshopSortTrend looks back over.
+	Days int
+	// PerPage is how many items to get a page, up to 100. 0 leaves it up to steam.
+	PerPage int
+}
+
+// WorkshopPage is a page of workshop items from QueryFiles.
+type WorkshopPage struct {
+	// Total is how many items match the query, across every page.
+	Total int
+	Files []PublishedFile
+	// NextCursor is what to pass to QueryFiles to get the next page, or "" if this is the last one.
+	NextCursor string
+}
+
+// QueryFiles searches a game's workshop, getting a page of items at a time. Pass "" as cursor to get the first page,
+// and the page's NextCursor to get the next one. AllWorkshopFiles does this for you.
+func (sa *SteamAuther) QueryFiles(ctx context.Context, query WorkshopQuery, cursor string) (*WorkshopPage, error) {
+	if cursor == "" {
+		cursor = "*"
+	}
+
+	q := url.Values{}
+	q.Set("appid", strconv.Itoa(query.AppID))
+	q.Set("query_type", strconv.Itoa(int(query.Sort)))
+	q.Set("cursor", cursor)
+	q.Set("return_tags", "true")
+	q.Set("return_metadata", "true")
+	q.Set("return_previews", "true")
+	if query.SearchText != "" {
+		q.Set("search_text", query.SearchText)
+	}
+	for i, tag := range query.RequiredTags {
+		q.Set("requiredtags["+strconv.Itoa(i)+"]", tag)
+	}
+	if query.MatchAllTags {
+		q.Set("match_all_tags", "true")
+	}
+	for i, tag := range query.ExcludedTags {
+		q.Set("excludedtags["+strconv.Itoa(i)+"]", tag)
+	}
+	if query.Days > 0 {
+		q.Set("days", strconv.Itoa(query.Days))
+	}
+	if query.PerPage > 0 {
+		q.Set("numperpage", strconv.Itoa(query.PerPage))
+	}
+
+	var data struct {
+		Response struct {
+			Total                int    `json:"total"`
+			NextCursor           string `json:"next_cursor"`
+			PublishedFileDetails []struct {
+				PublishedFile
+				// IPublishedFileService names some fields differently to ISteamRemoteStorage.
+				CreatorAppID    int    `json:"creator_appid"`
+				ConsumerAppID   int    `json:"consumer_appid"`
+				FileDescription string `json:"file_description"`
+				Result          int    `json:"result"`
+			} `json:"publishedfiledetails"`
+		} `json:"response"`
+	}
+	if err := sa.getApi(ctx, "IPublishedFileService/QueryFiles/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("query files (%d): %w", query.AppID, err)
+	}
+
+	page := &WorkshopPage{Total: data.Response.Total}
+	for _, f := range data.Response.PublishedFileDetails {
+		if f.Result != 1 {
+			continue
+		}
+
+		file := f.PublishedFile
+		file.CreatorAppID = f.CreatorAppID
+		file.ConsumerAppID = f.ConsumerAppID
+		file.Description = f.FileDescription
+		page.Files = append(page.Files, file)
+	}
+
+	// Steam hands back the same cursor once there's nothing left.
+	if len(data.Response.PublishedFileDetails) > 0 && data.Response.NextCursor != cursor {
+		page.NextCursor = data.Response.NextCursor
+	}
+
+	return page, nil
+}
+
+// AllWorkshopFiles is every workshop item matching query as an iterator, fetching a page at a time as it's consumed.
+// Errors are yielded with an empty PublishedFile, and end the iteration.
+func (sa *SteamAuther) AllWorkshopFiles(ctx context.Context, query WorkshopQuery) iter.Seq2[PublishedFile, error] {
+	return func(yield func(PublishedFile, error) bool) {
+		for cursor := ""; ; {
+			page, err := sa.QueryFiles(ctx, query, cursor)
+			if err != nil {
+				yield(PublishedFile{}, err)
+				return
+			}
+
+			for _, f := range page.Files {
+				if !yield(f, nil) {
+					return
+				}
+			}
+
+			if page.NextCursor == "" {
+				return
+			}
+			cursor = page.NextCursor
+		}
+	}
+}
+
+// UGCFile is a piece of user generated content (like a screenshot, or a workshop item's file), as represented in the
+// response from the GetUGCFileDetails web api.
+type UGCFile struct {
+	Filename string `json:"filename"`
+	// Url is where to download the file from.
+	Url string `json:"url"`
+	// Size is in bytes.
+	Size int64 `json:"size"`
+}
+
+// GetUGCFileDetails resolves a ugc handle (ex. a PublishedFile's HContentFile) of a game to its file. steamid64 is
+// who the file belongs to, which steam can use to find it quicker, and can be "". Returns ErrNoData if steam couldn't
+// find the file.
+func (sa *SteamAuther) GetUGCFileDetails(ctx context.Context, appid int, ugcid, steamid64 string) (*UGCFile, error) {
+	q := url.Values{"appid": {strconv.Itoa(appid)}, "ugcid": {ugcid}}
+	if steamid64 != "" {
+		q.Set("steamid", steamid64)
+	}
+
+	var data struct {
+		Data   *UGCFile `json:"data"`
+		Status struct {
+			Code int `json:"code"`
+		} `json:"status"`
+	}
+	if err := sa.getApi(ctx, "ISteamRemoteStorage/GetUGCFileDetails/v1", q, &data); err != nil {
+		return nil, fmt.Errorf("get ugc file details (%d, %s): %w", appid, ugcid, err)
+	}
+
+	if data.Data == nil {
+		return nil, fmt.Errorf("get ugc file details (%d, %s): %w (status %d)", appid, ugcid, ErrNoData, data.Status.Code)
+	}
+
+	return data.Data, nil
+}
@@ -0,0 +1,230 @@
+package gosteamauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// publishedFileDetails answers GetPublishedFileDetails like steam would, for every id asked for except ones ending in
+// 0, which don't exist. It counts the requests made in calls.
+func publishedFileDetails(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamRemoteStorage/GetPublishedFileDetails/v1" || r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("key") != "key" {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		calls.Add(1)
+
+		n, _ := strconv.Atoi(r.PostForm.Get("itemcount"))
+		if n > 100 {
+			http.Error(w, "too many items", http.StatusBadRequest)
+			return
+		}
+
+		var files []string
+		for i := range n {
+			id := r.PostForm.Get("publishedfileids[" + strconv.Itoa(i) + "]")
+			if strings.HasSuffix(id, "0") {
+				files = append(files, `{"publishedfileid":"`+id+`","result":9}`)
+				continue
+			}
+			files = append(files, `{"publishedfileid":"`+id+`","result":1,"title":"item `+id+`","consumer_app_id":4000,"file_size":"1024","time_updated":1700000000,"tags":[{"tag":"Map"}]}`)
+		}
+		w.Write([]byte(`{"response":{"result":1,"resultcount":` + strconv.Itoa(n) + `,"publishedfiledetails":[` + strings.Join(files, ",") + `]}}`))
+	}
+}
+
+// fileIds returns n made up workshop item ids.
+func fileIds(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.Itoa(100000 + i + 1)
+	}
+	return ids
+}
+
+func TestGetPublishedFileDetails(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, publishedFileDetails(&calls))
+	sa := New("key", "https://example.com")
+
+	ids := fileIds(150)
+	files, err := sa.GetPublishedFileDetails(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("made %d requests, want 2", calls.Load())
+	}
+	if len(files) != len(ids) {
+		t.Fatalf("got %d files, want %d", len(files), len(ids))
+	}
+	for _, id := range ids {
+		f, ok := files[id]
+		if !ok {
+			t.Fatalf("%s missing from the result", id)
+		}
+		if strings.HasSuffix(id, "0") != (f == nil) {
+			t.Errorf("files[%s] = %+v, want nil only for items that don't exist", id, f)
+		}
+	}
+
+	f := files[ids[0]]
+	if f.Title != "item "+ids[0] || f.FileSize != "1024" || len(f.Tags) != 1 || f.Tags[0].Tag != "Map" {
+		t.Errorf("file = %+v, want the item's details", f)
+	}
+	if !f.Updated().Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Updated = %v, want it from time_updated", f.Updated())
+	}
+}
+
+func TestPublishedFiles(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, publishedFileDetails(&calls))
+	sa := New("key", "https://example.com")
+
+	var got int
+	for f, err := range sa.PublishedFiles(context.Background(), fileIds(150)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(f.PublishedFileID, "0") {
+			t.Errorf("yielded %s, which doesn't exist", f.PublishedFileID)
+		}
+		got++
+	}
+	if got != 135 || calls.Load() != 2 {
+		t.Errorf("yielded %d files from %d requests, want 135 from 2", got, calls.Load())
+	}
+}
+
+// queryFiles answers QueryFiles like steam would, with 3 items over 2 pages (and an empty one steam sends at the
+// end). It counts the requests made in calls.
+func queryFiles(calls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/IPublishedFileService/QueryFiles/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		if q.Get("appid") != "4000" || q.Get("query_type") != "12" || q.Get("search_text") != "rp" || q.Get("requiredtags[0]") != "Map" || q.Get("excludedtags[0]") != "Addon" || q.Get("match_all_tags") != "true" {
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+			return
+		}
+		calls.Add(1)
+
+		switch q.Get("cursor") {
+		case "*":
+			w.Write([]byte(`{"response":{"total":3,"next_cursor":"c1","publishedfiledetails":[
+				{"publishedfileid":"1","result":1,"title":"rp_downtown","consumer_appid":4000,"creator_appid":4000,"file_description":"a city"},
+				{"publishedfileid":"2","result":1,"title":"rp_rockford","consumer_appid":4000}
+			]}}`))
+		case "c1":
+			w.Write([]byte(`{"response":{"total":3,"next_cursor":"c2","publishedfiledetails":[
+				{"publishedfileid":"3","result":1,"title":"rp_evocity","consumer_appid":4000},
+				{"publishedfileid":"4","result":9}
+			]}}`))
+		default:
+			w.Write([]byte(`{"response":{"total":3,"next_cursor":"` + q.Get("cursor") + `"}}`))
+		}
+	}
+}
+
+var rpMaps = WorkshopQuery{AppID: 4000, Sort: WorkshopSortTextSearch, SearchText: "rp", RequiredTags: []string{"Map"}, MatchAllTags: true, ExcludedTags: []string{"Addon"}}
+
+func TestQueryFiles(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, queryFiles(&calls))
+	sa := New("key", "https://example.com")
+
+	page, err := sa.QueryFiles(context.Background(), rpMaps, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 3 || len(page.Files) != 2 || page.NextCursor != "c1" {
+		t.Fatalf("first page = %+v, want 2 of 3 items", page)
+	}
+	if f := page.Files[0]; f.ConsumerAppID != 4000 || f.CreatorAppID != 4000 || f.Description != "a city" {
+		t.Errorf("file = %+v, want the differently named fields read", f)
+	}
+
+	page, err = sa.QueryFiles(context.Background(), rpMaps, "c2")
+	if err != nil || len(page.Files) != 0 || page.NextCursor != "" {
+		t.Errorf("past the end = %+v, %v, want no next page", page, err)
+	}
+}
+
+func TestAllWorkshopFiles(t *testing.T) {
+	var calls atomic.Int32
+	fakeSteam(t, queryFiles(&calls))
+	sa := New("key", "https://example.com")
+
+	var titles []string
+	for f, err := range sa.AllWorkshopFiles(context.Background(), rpMaps) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		titles = append(titles, f.Title)
+	}
+	if strings.Join(titles, ",") != "rp_downtown,rp_rockford,rp_evocity" || calls.Load() != 3 {
+		t.Errorf("got %v from %d requests, want the 3 maps from 3", titles, calls.Load())
+	}
+
+	var errs int
+	for _, err := range sa.AllWorkshopFiles(context.Background(), WorkshopQuery{AppID: 4000}) {
+		if err == nil {
+			t.Fatal("err = nil for a bad query, want an error")
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Errorf("yielded %d errors, want 1", errs)
+	}
+}
+
+func TestGetUGCFileDetails(t *testing.T) {
+	var steamid string
+	fakeSteam(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamRemoteStorage/GetUGCFileDetails/v1" {
+			http.NotFound(w, r)
+			return
+		}
+		steamid = r.URL.Query().Get("steamid")
+
+		if r.URL.Query().Get("ugcid") != "5000" {
+			w.Write([]byte(`{"status":{"code":9}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"filename":"screenshot.jpg","url":"https://cdn.example.com/ugc/5000/","size":204800}}`))
+	})
+	sa := New("key", "https://example.com")
+
+	f, err := sa.GetUGCFileDetails(context.Background(), 440, "5000", "76561197960287930")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *f != (UGCFile{Filename: "screenshot.jpg", Url: "https://cdn.example.com/ugc/5000/", Size: 204800}) {
+		t.Errorf("file = %+v, want the screenshot", f)
+	}
+	if steamid != "76561197960287930" {
+		t.Errorf("asked with steamid %q, want the owner's", steamid)
+	}
+
+	if _, err := sa.GetUGCFileDetails(context.Background(), 440, "5001", ""); !errors.Is(err, ErrNoData) {
+		t.Errorf("missing file err = %v, want ErrNoData", err)
+	}
+	if steamid != "" {
+		t.Errorf("asked with steamid %q, want it left out", steamid)
+	}
+}
@@ -0,0 +1,100 @@
+package gosteamauth
+
+import "time"
+
+// YearsOfServiceBadgeID is the BadgeID of steam's "Years of Service" badge, whose level is the account's age in years.
+const YearsOfServiceBadgeID = 1
+
+// XPForLevel returns the total xp it takes to reach a steam level. Every level takes 100xp more than the ones before
+// it, starting from 100xp a level for levels 1 to 10.
+func XPForLevel(level int) int {
+	if level <= 0 {
+		return 0
+	}
+
+	tens, rest := level/10, level%10
+	return 1000*tens*(tens+1)/2 + 100*rest*(tens+1)
+}
+
+// LevelForXP returns the steam level a user with xp total xp is.
+func LevelForXP(xp int) int {
+	level := 0
+	for XPForLevel(level+10) <= xp {
+		level += 10
+	}
+	for XPForLevel(level+1) <= xp {
+		level++
+	}
+
+	return level
+}
+
+// LevelProgress is how far a user is through their current steam level.
+type LevelProgress struct {
+	Level int
+	// XP is the user's total xp.
+	XP int
+	// LevelXP is the total xp it took to reach Level, and NextLevelXP the total it takes to reach the next one.
+	LevelXP     int
+	NextLevelXP int
+}
+
+// ProgressForXP works out a user's level and progress towards the next one from their total xp.
+func ProgressForXP(xp int) LevelProgress {
+	level := LevelForXP(xp)
+	return LevelProgress{
+		Level:       level,
+		XP:          xp,
+		LevelXP:     XPForLevel(level),
+		NextLevelXP: XPForLevel(level + 1),
+	}
+}
+
+// XPToNextLevel is how much more xp the user needs to level up.
+func (p LevelProgress) XPToNextLevel() int {
+	return p.NextLevelXP - p.XP
+}
+
+// Fraction is how far through the level the user is, from 0 to 1, for progress bars.
+func (p LevelProgress) Fraction() float64 {
+	return float64(p.XP-p.LevelXP) / float64(p.NextLevelXP-p.LevelXP)
+}
+
+// Progress is ProgressForXP for the user's xp.
+func (b *Badges) Progress() LevelProgress {
+	return ProgressForXP(b.PlayerXP)
+}
+
+// YearsOfService returns the level of the user's "Years of Service" badge, and false if they don't have it.
+func (b *Badges) YearsOfService() (int, bool) {
+	for _, badge := range b.Badges {
+		if badge.BadgeID == YearsOfServiceBadgeID && badge.AppID == 0 {
+			return badge.Level, true
+		}
+	}
+
+	return 0, false
+}
+
+// YearsOfService returns how many whole years it's been since created, which is what steam's "Years of Service"
+// badge shows.
+func YearsOfService(created time.Time) int {
+	now := time.Now()
+	years := now.Year() - created.Year()
+	if years > 0 && now.Before(created.AddDate(years, 0, 0)) {
+		years--
+	}
+
+	return max(years, 0)
+}
+
+// YearsOfService returns how many whole years old the account is, and false if steam didn't say when it was made (the
+// profile is private).
+func (u *SteamUser) YearsOfService() (int, bool) {
+	created, ok := u.Created()
+	if !ok {
+		return 0, false
+	}
+
+	return YearsOfService(created), true
+}
@@ -0,0 +1,97 @@
+package gosteamauth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestXPForLevel(t *testing.T) {
+	tests := []struct {
+		level, xp int
+	}{
+		{0, 0},
+		{1, 100},
+		{10, 1000},
+		{11, 1200},
+		{20, 3000},
+		{25, 4500},
+		{100, 55000},
+	}
+
+	for _, tt := range tests {
+		if got := XPForLevel(tt.level); got != tt.xp {
+			t.Errorf("XPForLevel(%d) = %d, want %d", tt.level, got, tt.xp)
+		}
+		if got := LevelForXP(tt.xp); got != tt.level {
+			t.Errorf("LevelForXP(%d) = %d, want %d", tt.xp, got, tt.level)
+		}
+		if tt.xp > 0 {
+			if got := LevelForXP(tt.xp - 1); got != tt.level-1 {
+				t.Errorf("LevelForXP(%d) = %d, want %d", tt.xp-1, got, tt.level-1)
+			}
+		}
+	}
+}
+
+func TestProgressForXP(t *testing.T) {
+	p := ProgressForXP(1100)
+	want := LevelProgress{Level: 10, XP: 1100, LevelXP: 1000, NextLevelXP: 1200}
+	if p != want {
+		t.Fatalf("ProgressForXP(1100) = %+v, want %+v", p, want)
+	}
+	if p.XPToNextLevel() != 100 || p.Fraction() != 0.5 {
+		t.Errorf("%d xp to go and %v of the way, want 100 and 0.5", p.XPToNextLevel(), p.Fraction())
+	}
+
+	// it should agree with what steam says in GetBadges
+	b := &Badges{PlayerXP: 350, PlayerLevel: 3, PlayerXPNeededToLevelUp: 50, PlayerXPNeededCurrentLevel: 300}
+	if p := b.Progress(); p.Level != b.PlayerLevel || p.XPToNextLevel() != b.PlayerXPNeededToLevelUp || p.LevelXP != b.PlayerXPNeededCurrentLevel {
+		t.Errorf("Progress = %+v, want it to match %+v", p, b)
+	}
+}
+
+func TestYearsOfService(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		created time.Time
+		want    int
+	}{
+		{"just made", now.Add(-time.Hour), 0},
+		{"almost a year", now.AddDate(-1, 0, 1), 0},
+		{"a year", now.AddDate(-1, 0, -1), 1},
+		{"ten years", now.AddDate(-10, -6, 0), 10},
+		{"in the future", now.AddDate(1, 0, 0), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := YearsOfService(tt.created); got != tt.want {
+				t.Errorf("YearsOfService(%v) = %d, want %d", tt.created, got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := (&SteamUser{}).YearsOfService(); ok {
+		t.Error("SteamUser.YearsOfService ok for a private profile, want false")
+	}
+	if years, ok := (&SteamUser{TimeCreated: now.AddDate(-3, 0, -1).Unix()}).YearsOfService(); !ok || years != 3 {
+		t.Errorf("SteamUser.YearsOfService = %d, %v, want 3, true", years, ok)
+	}
+}
+
+func TestBadgesYearsOfService(t *testing.T) {
+	b := &Badges{Badges: []Badge{
+		// a game badge that happens to share the id
+		{BadgeID: YearsOfServiceBadgeID, AppID: 440, Level: 2},
+		{BadgeID: YearsOfServiceBadgeID, Level: 7},
+	}}
+	if years, ok := b.YearsOfService(); !ok || years != 7 {
+		t.Errorf("YearsOfService = %d, %v, want 7, true", years, ok)
+	}
+
+	if _, ok := (&Badges{}).YearsOfService(); ok {
+		t.Error("YearsOfService ok without the badge, want false")
+	}
+}